@@ -1,6 +1,7 @@
 package app
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
 	"io"
@@ -68,6 +69,13 @@ type TabNewFunc func(tabID) TabModel
 var ModelDefaultKeymap = map[string]string{
 	"s":      "tab new scenes",
 	"g":      "tab new galleries",
+	"P":      "tab new performers",
+	"S":      "tab new studios",
+	"T":      "tab new tags",
+	"M":      "tab new markers",
+	"F":      "tab new movies",
+	"J":      "tab new jobs",
+	"ctrl+p": "preview",
 	"1":      "tab switch 1",
 	"2":      "tab switch 2",
 	"3":      "tab switch 3",
@@ -94,11 +102,14 @@ type Model struct {
 	mode              Mode
 	commandInput      ui.CommandInput
 	confirmation      *ui.Confirmation
+	form              *ui.Form
 	pendingDelete     *pendingDeleteState
 	tagsLoading       bool
 	studiosLoading    bool
 	performersLoading bool
-	err               error
+	// savedFiltersLoading is set while saved filters are loaded for autocomplete.
+	savedFiltersLoading bool
+	err                 error
 
 	footer ui.Footer
 
@@ -107,6 +118,13 @@ type Model struct {
 	sessionStore         SessionStore
 	sessionStashInstance string
 
+	// stream opens scenes from the stream with streamLabel rather than their file path.
+	stream      bool
+	streamLabel string
+
+	// preview is the image preview pane, which is nil if previews are not configured.
+	preview *previewPane
+
 	command command.Config
 }
 
@@ -132,12 +150,75 @@ func New(stash stash.Stash, opener config.Opener) *Model {
 			},
 			Name: "galleries",
 		},
+		{
+			NewFunc: func(id tabID) TabModel {
+				s := &cmdServiceWithID{s, id}
+				return NewPerformersModel(s, lookup)
+			},
+			Name: "performers",
+		},
+		{
+			NewFunc: func(id tabID) TabModel {
+				s := &cmdServiceWithID{s, id}
+				return NewStudiosModel(s, lookup)
+			},
+			Name: "studios",
+		},
+		{
+			NewFunc: func(id tabID) TabModel {
+				s := &cmdServiceWithID{s, id}
+				return NewTagsModel(s, lookup)
+			},
+			Name: "tags",
+		},
+		{
+			NewFunc: func(id tabID) TabModel {
+				s := &cmdServiceWithID{s, id}
+				return NewMarkersModel(s, lookup)
+			},
+			Name: "markers",
+		},
+		{
+			NewFunc: func(id tabID) TabModel {
+				s := &cmdServiceWithID{s, id}
+				return NewImagesModel(s, lookup)
+			},
+			Name: "images",
+		},
+		{
+			NewFunc: func(id tabID) TabModel {
+				s := &cmdServiceWithID{s, id}
+				return NewMoviesModel(s, lookup)
+			},
+			Name: "movies",
+		},
+		{
+			NewFunc: func(id tabID) TabModel {
+				s := &cmdServiceWithID{s, id}
+				return NewDuplicatesModel(s)
+			},
+			Name: "duplicates",
+		},
+		{
+			NewFunc: func(id tabID) TabModel {
+				s := &cmdServiceWithID{s, id}
+				return NewJobsModel(s)
+			},
+			Name: "jobs",
+		},
 	}
 
 	m := &Model{
 		cmdService: s,
 		opener:     opener,
 	}
+	if r, ok := stash.(restorer); ok {
+		s.restorer = r
+	}
+	if i, ok := stash.(indexer); ok {
+		s.indexer = i
+		s.rescan = make(chan struct{}, 1)
+	}
 
 	m.tabFuncs = make(map[string]TabNewFunc)
 	for _, mdl := range models {
@@ -152,7 +233,10 @@ func New(stash stash.Stash, opener config.Opener) *Model {
 	m.footer.Background = ColorBlack
 
 	m.command = command.Config{
-		"exit": static(appQuitMsg{}),
+		"exit":    static(appQuitMsg{}),
+		"preview": static(previewToggleMsg{}),
+		"refresh": static(refreshMsg{}),
+		"restore": static(restoreMsg{}),
 		"session": {
 			SubCommands: command.Config{
 				"new": static(sessionNewMsg{}),
@@ -188,6 +272,33 @@ func New(stash stash.Stash, opener config.Opener) *Model {
 	return m
 }
 
+// SetRecordPlay controls whether opening a scene records a play and activity in stash.  This is enabled by default.
+func (m *Model) SetRecordPlay(enabled bool) {
+	m.cmdService.noRecordPlay = !enabled
+}
+
+// SetStream controls whether scenes are opened from a stream rather than their file path.  label picks the stream to
+// open, and is the direct stream if empty.
+func (m *Model) SetStream(enabled bool, label string) {
+	m.stream = enabled
+	m.streamLabel = label
+}
+
+// SetPreview configures the image preview pane, which loads images with cache and draws them with renderer.  The pane
+// is shown at startup if enabled, and is otherwise toggled with the preview command.
+func (m *Model) SetPreview(cache ImageCache, renderer ui.ImageRenderer, enabled bool) {
+	m.preview = &previewPane{
+		enabled:  enabled,
+		cache:    cache,
+		renderer: renderer,
+	}
+}
+
+// SetUnmapPath sets the function used to map local paths given in commands to the paths used by stash.
+func (m *Model) SetUnmapPath(unmap func(string) string) {
+	m.cmdService.unmapPath = unmap
+}
+
 func (m *Model) nextTabID() tabID {
 	return tabID(atomic.AddUint64(&m.tabID, 1))
 }
@@ -204,6 +315,13 @@ type ModelTabNewMsg struct {
 	NewFunc TabNewFunc
 }
 
+// ModelTabOpenMsg opens a new tab of the named type.  Configure is called with the new TabModel before it is
+// initialised, allowing a tab to navigate to related content in a new tab, e.g. the scenes of a performer.
+type ModelTabOpenMsg struct {
+	Name      string
+	Configure func(TabModel)
+}
+
 type ModelTabSwitchMsg struct {
 	Index int `command:",positional"`
 }
@@ -214,6 +332,26 @@ type appQuitMsg struct{}
 type sessionNewMsg struct{}
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	next, cmd := m.update(msg)
+	if m.preview == nil {
+		return next, cmd
+	}
+
+	// The preview pane follows the current item of the active tab, which any message may have changed.
+	var active TabModel
+	switch next := next.(type) {
+	case Model:
+		active = next.tabs[next.active].model
+	case *Model:
+		active = next.tabs[next.active].model
+	}
+	if previewCmd := m.preview.follow(active); previewCmd != nil {
+		return next, tea.Batch(cmd, previewCmd)
+	}
+	return next, cmd
+}
+
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 	var cmd tea.Cmd
 
@@ -234,6 +372,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.confirmation, cmd = m.confirmation.Update(msg)
 			return m, cmd
 		}
+		if m.form != nil {
+			m.form, cmd = m.form.Update(msg)
+			return m, cmd
+		}
 
 		switch m.mode {
 		case ModeCommand, ModeFind:
@@ -277,7 +419,23 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.TabOpen(msg.NewFunc)
 		return m, tea.Batch(
 			m.tabs[m.active].model.Init(),
-			m.tabs[m.active].model.SetSize(Size{Width: m.screen.Width, Height: m.screen.Height - 5}))
+			m.tabs[m.active].model.SetSize(m.tabSize()))
+
+	case ModelTabOpenMsg:
+		newFunc, ok := m.tabFuncs[msg.Name]
+		if !ok {
+			return m, NewErrorCmd(fmt.Errorf("unknown tab type '%s'", msg.Name))
+		}
+		m.TabOpen(func(id tabID) TabModel {
+			model := newFunc(id)
+			if msg.Configure != nil {
+				msg.Configure(model)
+			}
+			return model
+		})
+		return m, tea.Batch(
+			m.tabs[m.active].model.Init(),
+			m.tabs[m.active].model.SetSize(m.tabSize()))
 
 	case ModelTabSwitchMsg:
 		m.TabSet(msg.Index - 1)
@@ -286,6 +444,68 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case appQuitMsg:
 		return m, m.quitCmd()
 
+	case previewToggleMsg:
+		if m.preview == nil {
+			return m, NewErrorCmd(fmt.Errorf("previews are not available"))
+		}
+		m.preview.toggle()
+		cmds := make([]tea.Cmd, len(m.tabs))
+		for i := range m.tabs {
+			cmds[i] = m.tabs[i].model.SetSize(m.tabSize())
+		}
+		return m, tea.Batch(cmds...)
+
+	case previewLoadedMsg:
+		if m.preview != nil {
+			m.preview.loaded(msg)
+		}
+		return m, nil
+
+	case taskStartedMsg:
+		cmd := m.showJob(msg)
+		return m, cmd
+
+	case scanCompletedMsg:
+		return m, m.refreshTabs()
+
+	case restoreMsg:
+		return m, m.cmdService.Restore()
+
+	case restoredMsg:
+		return m, m.refreshTabs()
+
+	case refreshMsg:
+		cmd := m.refresh()
+		return m, cmd
+
+	case indexProgressMsg:
+		m.footer.Status = indexStatus(msg.progress)
+		return m, nil
+
+	case indexedMsg:
+		m.footer.Status = ""
+		if msg.err != nil {
+			return m, tea.Batch(m.refreshTabs(), NewErrorCmd(fmt.Errorf("unable to index: %w", msg.err)))
+		}
+		return m, m.refreshTabs()
+
+	case jobUpdatedMsg:
+		for _, t := range m.tabs {
+			if jobs, ok := t.model.(*JobsModel); ok {
+				jobs.Update(msg)
+			}
+		}
+		return m, nil
+
+	case logMsg:
+		// Errors logged by stash are shown, since they are often the only sign that a job has failed.
+		for i := len(msg.entries) - 1; i >= 0; i-- {
+			if msg.entries[i].Level == stash.LogLevelError {
+				return m, NewErrorCmd(errors.New(msg.entries[i].Message))
+			}
+		}
+		return m, nil
+
 	case sessionNewMsg:
 		return m, m.resetSession()
 
@@ -294,6 +514,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.beginDelete(msg)
 		}
 		confirmation := ui.Confirmation{
+			Title:   "Confirm Delete",
 			Message: m.deleteConfirmationMessage(msg),
 			Options: []ui.ConfirmationOption{
 				{
@@ -314,8 +535,62 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case confirmDeleteMsg:
 		return m.beginDelete(msg.Request)
 
+	case taskRequestMsg:
+		if msg.SkipConfirm {
+			return m, msg.StartCmd
+		}
+		confirmation := ui.Confirmation{
+			Title:   "Confirm Task",
+			Message: fmt.Sprintf("Run %s over the whole library?\n\n%s", msg.Task, msg.Detail),
+			Options: []ui.ConfirmationOption{
+				{
+					Text: "Cancel",
+					Cmd:  func() tea.Msg { return dismissModalMsg{} },
+				},
+				{
+					Text: "Run",
+					Cmd: func() tea.Msg {
+						return confirmTaskMsg{Request: msg}
+					},
+				},
+			},
+		}
+		m.confirmation = &confirmation
+		return m, nil
+
+	case confirmTaskMsg:
+		m.confirmation = nil
+		return m, msg.Request.StartCmd
+
+	case scrapeReviewMsg:
+		confirmation := scrapeConfirmation(msg)
+		m.confirmation = &confirmation
+		return m, nil
+
+	case scrapeAcceptedMsg:
+		m.confirmation = nil
+		if len(msg.changes) == 0 {
+			return m, nil
+		}
+		return m, msg.apply(msg.changes)
+
+	case performerFormMsg:
+		form := newPerformerForm(msg)
+		m.form = &form
+		return m, nil
+
+	case performerFormSubmitMsg:
+		// The form is kept open when a value is invalid so that it can be corrected.
+		p, err := parsePerformerForm(msg.form.performer, msg.values)
+		if err != nil {
+			return m, NewErrorCmd(err)
+		}
+		m.form = nil
+		return m, msg.form.save(p)
+
 	case dismissModalMsg:
 		m.confirmation = nil
+		m.form = nil
 		return m, nil
 
 	case ui.CommandExecMsg:
@@ -369,6 +644,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case savedFiltersLoadedMsg:
+		m.savedFiltersLoading = false
+		if m.mode == ModeCommand {
+			m.commandInput.RefreshSuggestions()
+		}
+		return m, nil
+
 	case tea.WindowSizeMsg:
 		m.screen = Size{
 			Width:  msg.Width,
@@ -376,13 +658,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		m.commandInput.SetWidth(msg.Width)
 		// All tabs should be notified about the change in window size, as it will cause them to refetch their results.
-		tabSize := Size{
-			Width:  msg.Width,
-			Height: msg.Height - 5,
-		}
 		cmds := make([]tea.Cmd, len(m.tabs))
 		for i := range m.tabs {
-			cmds[i] = m.tabs[i].model.SetSize(tabSize)
+			cmds[i] = m.tabs[i].model.SetSize(m.tabSize())
 		}
 		return m, tea.Batch(cmds...)
 
@@ -399,8 +677,19 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case OpenMsg:
+		if m.stream {
+			switch target := msg.target.(type) {
+			case stash.Scene:
+				return m, m.cmdService.OpenStream(target, 0, m.streamLabel)
+			case stash.SceneMarker:
+				return m, m.cmdService.OpenStream(target.Scene, target.Seconds, m.streamLabel)
+			}
+		}
 		return m.openCmd(msg.target)
 
+	case openStreamMsg:
+		return m, m.cmdService.OpenStream(msg.scene, msg.start, cmp.Or(msg.label, m.streamLabel))
+
 	// loadingMsg handles routing of a return loading message to the correct tab located by ID.
 	case loadingMsg:
 		tab, ok := m.tabsByID[msg.id]
@@ -416,7 +705,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		_, cmd := tab.model.Update(msg.payload)
 		if m.pendingDelete != nil && m.pendingDelete.tabID == msg.id {
 			switch msg.payload.(type) {
-			case ErrorMsg, scenesMsg, galleriesMsg, scenesLoadedMsg, galleriesLoadedMsg:
+			case ErrorMsg, scenesMsg, galleriesMsg, scenesLoadedMsg, galleriesLoadedMsg, tagsListLoadedMsg, imagesListLoadedMsg, scenesDeletedMsg:
 				m.pendingDelete = nil
 			}
 		}
@@ -463,14 +752,31 @@ func (m Model) View() string {
 		}
 	}
 
+	content := truncateLines(m.tabs[m.active].model.View(), viewportHeight)
+	var clearPreview string
+	if m.preview != nil {
+		if m.preview.enabled {
+			width := m.tabSize().Width
+			content = lipgloss.JoinHorizontal(lipgloss.Top,
+				lipgloss.NewStyle().Width(width).MaxWidth(width).Height(viewportHeight).Render(content),
+				" ",
+				m.preview.View(max(m.screen.Width-width-1, 0), viewportHeight),
+			)
+		}
+		clearPreview = m.preview.clear()
+	}
+
 	view := lipgloss.JoinVertical(0,
-		tabBar.Render(m.screen.Width, titles, m.active),
-		viewportStyle.Render(truncateLines(m.tabs[m.active].model.View(), viewportHeight)),
+		clearPreview+tabBar.Render(m.screen.Width, titles, m.active),
+		viewportStyle.Render(content),
 		bottom,
 	)
 
 	if m.confirmation != nil {
-		return m.renderModal("Confirm Delete", m.confirmation.View())
+		return m.renderModal(m.confirmation.Title, m.confirmation.View())
+	}
+	if m.form != nil {
+		return m.renderModal(m.form.Title, m.form.View())
 	}
 	if m.pendingDelete != nil {
 		return m.renderModal("Deleting", m.deleteProgressMessage())
@@ -489,6 +795,46 @@ func (m *Model) openCmd(target any) (*Model, tea.Cmd) {
 	}
 }
 
+// showJob switches to the jobs tab to show a job that has just started, opening the tab if needed.
+func (m *Model) showJob(msg taskStartedMsg) tea.Cmd {
+	for i, t := range m.tabs {
+		if jobs, ok := t.model.(*JobsModel); ok {
+			m.active = i
+			_, cmd := jobs.Update(msg)
+			return cmd
+		}
+	}
+	m.TabOpen(m.tabFuncs["jobs"])
+	_, cmd := m.tabs[m.active].model.Update(msg)
+	return tea.Batch(
+		m.tabs[m.active].model.Init(),
+		m.tabs[m.active].model.SetSize(m.tabSize()),
+		cmd)
+}
+
+// manualRefresher is implemented by tabs that are too expensive to load to be refreshed whenever the library changes.
+// They are only refreshed when asked to directly.
+type manualRefresher interface {
+	manualRefresh()
+}
+
+// refreshTabs refreshes the content of every open tab, apart from those that are only refreshed manually.
+func (m *Model) refreshTabs() tea.Cmd {
+	var cmds []tea.Cmd
+	for _, t := range m.tabs {
+		if _, ok := t.model.(manualRefresher); ok {
+			continue
+		}
+		msg, err := t.model.CommandConfig().Resolve(command.Parser("refresh"))
+		if err != nil {
+			continue
+		}
+		_, cmd := t.model.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+	return tea.Batch(cmds...)
+}
+
 // TabOpen creates a new tab with the given TabModel and sets it as active.
 func (m *Model) TabOpen(newFunc TabNewFunc) {
 	id := m.nextTabID()
@@ -522,6 +868,16 @@ type Size struct {
 	Height int
 }
 
+// tabSize returns the space available to tabs, which share the width of the screen with the preview pane when it is
+// shown.
+func (m Model) tabSize() Size {
+	size := Size{Width: m.screen.Width, Height: m.screen.Height - 5}
+	if m.preview != nil && m.preview.enabled {
+		size.Width -= previewWidth(m.screen.Width) + 1
+	}
+	return size
+}
+
 // ErrorMsg is a message used to display an error to the user that dismisses after a few seconds.
 type ErrorMsg struct {
 	error
@@ -553,11 +909,16 @@ func (m *Model) beginDelete(request deleteRequestMsg) (*Model, tea.Cmd) {
 }
 
 func (m Model) deleteConfirmationMessage(request deleteRequestMsg) string {
+	detail := request.Detail
+	if detail == "" {
+		detail = "This will remove it from Stash and delete associated files."
+	}
 	return fmt.Sprintf(
-		"Delete %s?\n\n%s\n%s\n\nThis will remove it from Stash and delete associated files.",
+		"Delete %s?\n\n%s\n%s\n\n%s",
 		request.Entity,
 		request.Title,
 		request.Path,
+		detail,
 	)
 }
 
@@ -652,6 +1013,10 @@ func (m *Model) refreshCommandAutocomplete() tea.Cmd {
 		m.performersLoading = true
 		cmds = append(cmds, m.cmdService.PerformersAll())
 	}
+	if needs.savedFilters != "" && !m.cmdService.cache.SavedFiltersLoaded(needs.savedFilters) && !m.savedFiltersLoading {
+		m.savedFiltersLoading = true
+		cmds = append(cmds, m.cmdService.SavedFilters(needs.savedFilters))
+	}
 	return tea.Batch(cmds...)
 }
 
@@ -659,6 +1024,8 @@ type suggestionRequirements struct {
 	tags       bool
 	studios    bool
 	performers bool
+	// savedFilters is the mode of saved filters needed, if any.
+	savedFilters stash.FilterMode
 }
 
 func (m Model) commandSuggestionSet(prompt, input string, cursor int) (ui.SuggestionSet, suggestionRequirements) {
@@ -693,10 +1060,18 @@ func (m Model) commandSuggestionSet(prompt, input string, cursor int) (ui.Sugges
 		}, suggestionRequirements{}
 	}
 
-	if len(token.tokens) > 0 && token.tokens[0].raw == "tag" {
+	if len(token.tokens) > 0 && m.isTagCommand(token.tokens[0].raw) {
 		return m.tagCommandSuggestionSet(token, input, cursor)
 	}
 
+	if isSavedFilterCommand(token.tokens) {
+		return m.savedFilterSuggestionSet(token, input, cursor)
+	}
+
+	if isPerformerEditCommand(token.tokens) {
+		return m.performerEditSuggestionSet(token, input, cursor)
+	}
+
 	if len(token.tokens) == 0 || token.tokens[0].raw != "filter" {
 		return ui.SuggestionSet{}, suggestionRequirements{}
 	}
@@ -747,6 +1122,17 @@ func (m Model) commandSuggestionSet(prompt, input string, cursor int) (ui.Sugges
 	return ui.SuggestionSet{}, suggestionRequirements{}
 }
 
+// isTagCommand returns true if the arguments of the named command are tag names in the active tab.
+func (m Model) isTagCommand(name string) bool {
+	if name == "tag" || name == "untag" || name == "retag" {
+		return true
+	}
+	if _, ok := m.tabs[m.active].model.(*TagsModel); ok {
+		return name == "parent" || name == "merge"
+	}
+	return false
+}
+
 func (m Model) tagCommandSuggestionSet(token commandToken, input string, cursor int) (ui.SuggestionSet, suggestionRequirements) {
 	if token.index == 0 {
 		return ui.SuggestionSet{}, suggestionRequirements{}
@@ -755,6 +1141,12 @@ func (m Model) tagCommandSuggestionSet(token commandToken, input string, cursor
 		return ui.SuggestionSet{}, suggestionRequirements{}
 	}
 	searchPrefix := strings.TrimPrefix(input[token.start:cursor], "\"")
+
+	// Names given to the tag command may be prefixed with '-' to remove the tag, which is kept in the suggestion.
+	removal := false
+	if token.tokens[0].raw == "tag" {
+		searchPrefix, removal = strings.CutPrefix(searchPrefix, "-")
+	}
 	if searchPrefix == "" {
 		return ui.SuggestionSet{}, suggestionRequirements{}
 	}
@@ -762,9 +1154,53 @@ func (m Model) tagCommandSuggestionSet(token commandToken, input string, cursor
 		return ui.SuggestionSet{}, suggestionRequirements{tags: true}
 	}
 	tags := m.cmdService.cache.TagsByPrefix(searchPrefix, 6)
+	if removal {
+		suggestions := make([]ui.Suggestion, 0, len(tags))
+		for _, tag := range tags {
+			suggestions = append(suggestions, quotedSuggestion("-"+tag.Name))
+		}
+		return entitySuggestionSet(token.start, token.end, suggestions), suggestionRequirements{}
+	}
 	return entitySuggestionSet(token.start, token.end, tagSuggestions(tags)), suggestionRequirements{}
 }
 
+// savedFilterSuggestionSet suggests the names of saved filters for the filter sub-commands that take one.
+func (m Model) savedFilterSuggestionSet(token commandToken, input string, cursor int) (ui.SuggestionSet, suggestionRequirements) {
+	mode, ok := savedFilterMode(m.tabs[m.active].model)
+	if !ok || token.index != 2 || cursor < token.start {
+		return ui.SuggestionSet{}, suggestionRequirements{}
+	}
+	searchPrefix := strings.TrimPrefix(input[token.start:cursor], "\"")
+	if searchPrefix == "" {
+		return ui.SuggestionSet{}, suggestionRequirements{}
+	}
+	if !m.cmdService.cache.SavedFiltersLoaded(mode) {
+		return ui.SuggestionSet{}, suggestionRequirements{savedFilters: mode}
+	}
+	filters := m.cmdService.cache.SavedFiltersByPrefix(mode, searchPrefix, 6)
+	suggestions := make([]ui.Suggestion, 0, len(filters))
+	for _, filter := range filters {
+		suggestions = append(suggestions, quotedSuggestion(filter.Name))
+	}
+	return entitySuggestionSet(token.start, token.end, suggestions), suggestionRequirements{}
+}
+
+// performerEditSuggestionSet suggests the names of performers for the performer edit command.
+func (m Model) performerEditSuggestionSet(token commandToken, input string, cursor int) (ui.SuggestionSet, suggestionRequirements) {
+	if token.index != 2 || cursor < token.start {
+		return ui.SuggestionSet{}, suggestionRequirements{}
+	}
+	searchPrefix := strings.TrimPrefix(input[token.start:cursor], "\"")
+	if searchPrefix == "" {
+		return ui.SuggestionSet{}, suggestionRequirements{}
+	}
+	if !m.cmdService.cache.PerformersLoaded() {
+		return ui.SuggestionSet{}, suggestionRequirements{performers: true}
+	}
+	performers := m.cmdService.cache.PerformersByPrefix(searchPrefix, 6)
+	return entitySuggestionSet(token.start, token.end, performerSuggestions(performers)), suggestionRequirements{}
+}
+
 func (m Model) filterArgumentSuggestionSet(token commandToken, input string, cursor int) ui.SuggestionSet {
 	prefix := input[token.start:cursor]
 	if strings.TrimSpace(prefix) == "" {
@@ -796,6 +1232,18 @@ func (m Model) filterArgumentNames() []string {
 		return filterArgumentNamesFor[ScenesModelFilterMsg]()
 	case *GalleriesModel:
 		return filterArgumentNamesFor[GalleriesModelFilterMsg]()
+	case *PerformersModel:
+		return filterArgumentNamesFor[PerformersModelFilterMsg]()
+	case *StudiosModel:
+		return filterArgumentNamesFor[StudiosModelFilterMsg]()
+	case *TagsModel:
+		return filterArgumentNamesFor[TagsModelFilterMsg]()
+	case *MarkersModel:
+		return filterArgumentNamesFor[MarkersModelFilterMsg]()
+	case *ImagesModel:
+		return filterArgumentNamesFor[ImagesModelFilterMsg]()
+	case *MoviesModel:
+		return filterArgumentNamesFor[MoviesModelFilterMsg]()
 	default:
 		return nil
 	}
@@ -62,3 +62,43 @@ func TestCtrlCQuitsDuringPendingDelete(t *testing.T) {
 	_, ok := msg.(tea.QuitMsg)
 	require.True(t, ok)
 }
+
+// runCmd runs cmd and any commands batched by it, returning the messages they produce.
+func runCmd(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		var msgs []tea.Msg
+		for _, cmd := range batch {
+			msgs = append(msgs, runCmd(cmd)...)
+		}
+		return msgs
+	}
+	return []tea.Msg{msg}
+}
+
+func TestInitLocalStash(t *testing.T) {
+	s, err := stash.NewLocalStash(t.TempDir(), "")
+	require.NoError(t, err)
+	var model tea.Model = New(s, nil)
+
+	for _, msg := range runCmd(model.Init()) {
+		_, failed := msg.(ErrorMsg)
+		require.False(t, failed, "%v", msg)
+		model, _ = model.Update(msg)
+	}
+
+	// Opening a tab of galleries loads its default filter too.
+	model, cmd := model.Update(ui.CommandExecMsg{Command: "tab new galleries"})
+	for _, msg := range runCmd(cmd) {
+		model, cmd = model.Update(msg)
+		for _, msg := range runCmd(cmd) {
+			_, failed := msg.(ErrorMsg)
+			require.False(t, failed, "%v", msg)
+			model, _ = model.Update(msg)
+		}
+	}
+	require.Len(t, model.(Model).tabs, 2)
+}
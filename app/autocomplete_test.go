@@ -117,6 +117,27 @@ func TestCommandSuggestionSetTagCommandAutocomplete(t *testing.T) {
 	require.Equal(t, `"Foo Bar"`, set.Suggestions[1].Value)
 }
 
+func TestCommandSuggestionSetTagCommandAutocompleteRemoval(t *testing.T) {
+	m := New(&stash.LocalStash{}, nil)
+	m.cmdService.cache.CacheTags([]stash.Tag{
+		{ID: "1", Name: "Foo"},
+		{ID: "2", Name: "Foo Bar"},
+	})
+
+	input := "tag Baz -Fo"
+	set, _ := m.commandSuggestionSet(":", input, len(input))
+
+	require.Equal(t, len("tag Baz "), set.Start)
+	require.Len(t, set.Suggestions, 2)
+	require.Equal(t, "-Foo", set.Suggestions[0].Value)
+	require.Equal(t, `"-Foo Bar"`, set.Suggestions[1].Value)
+
+	input = "untag Fo"
+	set, _ = m.commandSuggestionSet(":", input, len(input))
+	require.Len(t, set.Suggestions, 2)
+	require.Equal(t, "Foo", set.Suggestions[0].Value)
+}
+
 func TestCommandSuggestionSetTagCommandAutocompleteNeedsLoadedTags(t *testing.T) {
 	m := New(&stash.LocalStash{}, nil)
 
@@ -212,3 +233,40 @@ func TestCommandSuggestionSetPerformerAutocompletePrefersCloserMatches(t *testin
 	require.Equal(t, "Jade", set.Suggestions[0].Display)
 	require.Equal(t, "Jayden", set.Suggestions[1].Display)
 }
+
+func TestCommandSuggestionSetSavedFilterAutocomplete(t *testing.T) {
+	m := New(&stash.LocalStash{}, nil)
+
+	input := "filter load Fa"
+	_, needs := m.commandSuggestionSet(":", input, len(input))
+	require.Equal(t, suggestionRequirements{savedFilters: stash.FilterModeScenes}, needs)
+
+	m.cmdService.cache.CacheSavedFilters(stash.FilterModeScenes, []stash.SavedFilter{
+		{ID: "1", Mode: stash.FilterModeScenes, Name: "Favourites"},
+		{ID: "2", Mode: stash.FilterModeScenes, Name: "Family Friendly"},
+		{ID: "3", Mode: stash.FilterModeScenes, Name: "Recent"},
+	})
+	set, needs := m.commandSuggestionSet(":", input, len(input))
+
+	require.Equal(t, suggestionRequirements{}, needs)
+	require.Equal(t, len("filter load "), set.Start)
+	require.Len(t, set.Suggestions, 2)
+	require.Equal(t, `"Family Friendly"`, set.Suggestions[0].Value)
+	require.Equal(t, "Favourites", set.Suggestions[1].Display)
+}
+
+func TestCommandSuggestionSetPerformerEditAutocomplete(t *testing.T) {
+	m := New(&stash.LocalStash{}, nil)
+	m.cmdService.cache.CachePerformerSummaries([]stash.PerformerSummary{
+		{ID: "1", Name: "Jane Doe"},
+		{ID: "2", Name: "Anna"},
+	})
+
+	input := "performer edit Ja"
+	set, needs := m.commandSuggestionSet(":", input, len(input))
+
+	require.Equal(t, suggestionRequirements{}, needs)
+	require.Equal(t, len("performer edit "), set.Start)
+	require.Len(t, set.Suggestions, 1)
+	require.Equal(t, `"Jane Doe"`, set.Suggestions[0].Value)
+}
@@ -22,12 +22,74 @@ func (s *cachingStash) Scenes(ctx context.Context, f stash.FindFilter, sf stash.
 	return scenes, count, err
 }
 
+func (s *cachingStash) SceneUpdate(ctx context.Context, input stash.SceneUpdate) (stash.Scene, error) {
+	scene, err := s.Stash.SceneUpdate(ctx, input)
+	if err == nil {
+		s.cache.CacheScenes([]stash.Scene{scene})
+	}
+	return scene, err
+}
+
+func (s *cachingStash) BulkSceneUpdate(ctx context.Context, input stash.BulkSceneUpdate) ([]stash.Scene, error) {
+	scenes, err := s.Stash.BulkSceneUpdate(ctx, input)
+	if err == nil {
+		s.cache.CacheScenes(scenes)
+	}
+	return scenes, err
+}
+
 func (s *cachingStash) Galleries(ctx context.Context, f stash.FindFilter, gf stash.GalleryFilter) ([]stash.Gallery, int, error) {
 	galleries, count, err := s.Stash.Galleries(ctx, f, gf)
 	s.cache.CacheGalleries(galleries)
 	return galleries, count, err
 }
 
+func (s *cachingStash) Movies(ctx context.Context, f stash.FindFilter, mf stash.MovieFilter) ([]stash.MovieDetail, int, error) {
+	movies, count, err := s.Stash.Movies(ctx, f, mf)
+	s.cache.CacheMovieDetails(movies)
+	return movies, count, err
+}
+
+func (s *cachingStash) MovieCreate(ctx context.Context, input stash.MovieCreate) (stash.MovieDetail, error) {
+	movie, err := s.Stash.MovieCreate(ctx, input)
+	if err == nil {
+		s.cache.CacheMovieDetails([]stash.MovieDetail{movie})
+	}
+	return movie, err
+}
+
+func (s *cachingStash) MovieUpdate(ctx context.Context, input stash.MovieUpdate) (stash.MovieDetail, error) {
+	movie, err := s.Stash.MovieUpdate(ctx, input)
+	if err == nil {
+		s.cache.CacheMovieDetails([]stash.MovieDetail{movie})
+	}
+	return movie, err
+}
+
+func (s *cachingStash) Images(ctx context.Context, f stash.FindFilter, imf stash.ImageFilter) ([]stash.Image, int, error) {
+	images, count, err := s.Stash.Images(ctx, f, imf)
+	s.cache.CacheImages(images)
+	return images, count, err
+}
+
+func (s *cachingStash) Performers(ctx context.Context, f stash.FindFilter, pf stash.PerformerFilter) ([]stash.Performer, int, error) {
+	performers, count, err := s.Stash.Performers(ctx, f, pf)
+	s.cache.CachePerformers(performers)
+	return performers, count, err
+}
+
+func (s *cachingStash) Studios(ctx context.Context, f stash.FindFilter, sf stash.StudioFilter) ([]stash.StudioDetail, int, error) {
+	studios, count, err := s.Stash.Studios(ctx, f, sf)
+	s.cache.CacheStudioDetails(studios)
+	return studios, count, err
+}
+
+func (s *cachingStash) SceneMarkers(ctx context.Context, f stash.FindFilter, mf stash.SceneMarkerFilter) ([]stash.SceneMarker, int, error) {
+	markers, count, err := s.Stash.SceneMarkers(ctx, f, mf)
+	s.cache.CacheSceneMarkers(markers)
+	return markers, count, err
+}
+
 func (s *cachingStash) PerformersAll(ctx context.Context) ([]stash.PerformerSummary, error) {
 	performers, err := s.Stash.PerformersAll(ctx)
 	if err == nil {
@@ -60,6 +122,87 @@ func (s *cachingStash) TagCreate(ctx context.Context, input stash.TagCreate) (st
 	return tag, err
 }
 
+func (s *cachingStash) PerformerCreate(ctx context.Context, input stash.PerformerCreate) (stash.Performer, error) {
+	performer, err := s.Stash.PerformerCreate(ctx, input)
+	if err == nil {
+		s.cache.CachePerformers([]stash.Performer{performer})
+	}
+	return performer, err
+}
+
+func (s *cachingStash) PerformerUpdate(ctx context.Context, input stash.PerformerUpdate) (stash.Performer, error) {
+	performer, err := s.Stash.PerformerUpdate(ctx, input)
+	if err == nil {
+		s.cache.CachePerformers([]stash.Performer{performer})
+	}
+	return performer, err
+}
+
+func (s *cachingStash) StudioCreate(ctx context.Context, input stash.StudioCreate) (stash.Studio, error) {
+	studio, err := s.Stash.StudioCreate(ctx, input)
+	if err == nil {
+		s.cache.CacheStudio(studio)
+	}
+	return studio, err
+}
+
+func (s *cachingStash) Tags(ctx context.Context, f stash.FindFilter, tf stash.TagFilter) ([]stash.TagDetail, int, error) {
+	tags, count, err := s.Stash.Tags(ctx, f, tf)
+	s.cache.CacheTagDetails(tags)
+	return tags, count, err
+}
+
+func (s *cachingStash) TagUpdate(ctx context.Context, input stash.TagUpdate) (stash.TagDetail, error) {
+	tag, err := s.Stash.TagUpdate(ctx, input)
+	if err == nil {
+		s.cache.CacheTag(tag.Tag())
+	}
+	return tag, err
+}
+
+func (s *cachingStash) TagsMerge(ctx context.Context, input stash.TagsMerge) (stash.TagDetail, error) {
+	tag, err := s.Stash.TagsMerge(ctx, input)
+	if err == nil {
+		for _, id := range input.Source {
+			s.cache.UncacheTag(string(id))
+		}
+		s.cache.CacheTag(tag.Tag())
+	}
+	return tag, err
+}
+
+func (s *cachingStash) TagDelete(ctx context.Context, id string) (bool, error) {
+	ok, err := s.Stash.TagDelete(ctx, id)
+	if err == nil {
+		s.cache.UncacheTag(id)
+	}
+	return ok, err
+}
+
+func (s *cachingStash) SavedFilters(ctx context.Context, mode stash.FilterMode) ([]stash.SavedFilter, error) {
+	filters, err := s.Stash.SavedFilters(ctx, mode)
+	if err == nil {
+		s.cache.CacheSavedFilters(mode, filters)
+	}
+	return filters, err
+}
+
+func (s *cachingStash) SaveFilter(ctx context.Context, input stash.SaveFilter) (stash.SavedFilter, error) {
+	filter, err := s.Stash.SaveFilter(ctx, input)
+	if err == nil {
+		s.cache.CacheSavedFilter(filter)
+	}
+	return filter, err
+}
+
+func (s *cachingStash) DestroySavedFilter(ctx context.Context, id string) (bool, error) {
+	ok, err := s.Stash.DestroySavedFilter(ctx, id)
+	if err == nil {
+		s.cache.UncacheSavedFilter(id)
+	}
+	return ok, err
+}
+
 // cacheLookup is a StashLookup implementation that caches entities by ID.
 type cacheLookup struct {
 	mu sync.RWMutex
@@ -73,6 +216,8 @@ type cacheLookup struct {
 	tags             map[string]stash.Tag
 	tagNames         map[string]string
 	tagsLoaded       bool
+	movies           map[string]stash.Movie
+	savedFilters     map[stash.FilterMode][]stash.SavedFilter
 }
 
 func newCacheLookup() *cacheLookup {
@@ -83,6 +228,8 @@ func newCacheLookup() *cacheLookup {
 		studioNames:    make(map[string]string),
 		tags:           make(map[string]stash.Tag),
 		tagNames:       make(map[string]string),
+		movies:         make(map[string]stash.Movie),
+		savedFilters:   make(map[stash.FilterMode][]stash.SavedFilter),
 	}
 	return c
 }
@@ -104,7 +251,39 @@ func (s *cacheLookup) CacheScenes(scenes []stash.Scene) {
 		}
 
 		s.cacheStudioLocked(sc.Studio)
+
+		for _, m := range sc.Movies {
+			s.movies[m.Movie.ID] = m.Movie
+		}
+	}
+}
+
+func (s *cacheLookup) CacheImages(images []stash.Image) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, i := range images {
+		for _, p := range i.Performers {
+			s.cachePerformerLocked(p)
+		}
+		for _, t := range i.Tags {
+			s.cacheTagLocked(t)
+		}
+		s.cacheStudioLocked(i.Studio)
+	}
+}
+
+func (s *cacheLookup) CacheSceneMarkers(markers []stash.SceneMarker) {
+	scenes := make([]stash.Scene, 0, len(markers))
+	s.mu.Lock()
+	for _, m := range markers {
+		s.cacheTagLocked(m.PrimaryTag)
+		for _, t := range m.Tags {
+			s.cacheTagLocked(t)
+		}
+		scenes = append(scenes, m.Scene)
 	}
+	s.mu.Unlock()
+	s.CacheScenes(scenes)
 }
 
 func (s *cacheLookup) CacheGalleries(galleries []stash.Gallery) {
@@ -126,6 +305,17 @@ func (s *cacheLookup) CacheGalleries(galleries []stash.Gallery) {
 	}
 }
 
+func (s *cacheLookup) CachePerformers(performers []stash.Performer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, p := range performers {
+		s.cachePerformerLocked(p)
+		for _, t := range p.Tags {
+			s.cacheTagLocked(t)
+		}
+	}
+}
+
 func (s *cacheLookup) CachePerformerSummaries(performers []stash.PerformerSummary) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -147,6 +337,41 @@ func (s *cacheLookup) CacheStudios(studios []stash.Studio) {
 	s.studiosLoaded = true
 }
 
+// CacheStudioDetails caches each studio along with its parent and child studios.  Unlike CacheStudios this does not
+// mark studios as loaded, as only a subset of studios is expected.
+func (s *cacheLookup) CacheStudioDetails(studios []stash.StudioDetail) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, studio := range studios {
+		s.cacheStudioLocked(stash.Studio{ID: studio.ID, Name: studio.Name})
+		if studio.ParentStudio.ID != "" {
+			s.cacheStudioLocked(studio.ParentStudio)
+		}
+		for _, child := range studio.ChildStudios {
+			s.cacheStudioLocked(child)
+		}
+	}
+}
+
+func (s *cacheLookup) CacheMovieDetails(movies []stash.MovieDetail) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, movie := range movies {
+		s.movies[movie.ID] = movie.Movie()
+		s.cacheStudioLocked(movie.Studio)
+	}
+}
+
+func (s *cacheLookup) GetMovie(id string) (stash.Movie, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if movie, ok := s.movies[id]; ok {
+		return movie, nil
+	}
+	return stash.Movie{}, fmt.Errorf("movie not cached")
+}
+
 func (s *cacheLookup) GetStudio(id string) (stash.Studio, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -197,12 +422,45 @@ func (s *cacheLookup) GetTagByName(name string) (stash.Tag, error) {
 	return tag, nil
 }
 
+// CacheStudio caches a single studio, such as one just created.  Unlike CacheStudios it does not mark all studios as
+// loaded.
+func (s *cacheLookup) CacheStudio(studio stash.Studio) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cacheStudioLocked(studio)
+}
+
 func (s *cacheLookup) CacheTag(tag stash.Tag) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.cacheTagLocked(tag)
 }
 
+// CacheTagDetails caches each tag along with its parent and child tags.
+func (s *cacheLookup) CacheTagDetails(tags []stash.TagDetail) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, tag := range tags {
+		s.cacheTagLocked(tag.Tag())
+		for _, t := range tag.Parents {
+			s.cacheTagLocked(t)
+		}
+		for _, t := range tag.Children {
+			s.cacheTagLocked(t)
+		}
+	}
+}
+
+// UncacheTag removes a tag that no longer exists so that it is neither suggested nor resolved by name.
+func (s *cacheLookup) UncacheTag(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if tag, ok := s.tags[id]; ok {
+		delete(s.tagNames, tag.Name)
+		delete(s.tags, id)
+	}
+}
+
 func (s *cacheLookup) StudiosLoaded() bool {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -239,6 +497,10 @@ func (s *cacheLookup) CacheTags(tags []stash.Tag) {
 }
 
 func (s *cacheLookup) cacheTagLocked(tag stash.Tag) {
+	// A renamed tag should no longer resolve by its old name.
+	if cached, ok := s.tags[tag.ID]; ok && tag.Name != "" && cached.Name != tag.Name && s.tagNames[cached.Name] == tag.ID {
+		delete(s.tagNames, cached.Name)
+	}
 	s.tags[tag.ID] = tag
 	if tag.Name != "" {
 		s.tagNames[tag.Name] = tag.ID
@@ -323,6 +585,10 @@ func (s *cacheLookup) PerformersByPrefix(prefix string, limit int) []stash.Perfo
 }
 
 func (s *cacheLookup) cachePerformerLocked(performer stash.Performer) {
+	// A renamed performer should no longer be found by its old name.
+	if old, ok := s.performers[performer.ID]; ok && old.Name != performer.Name && s.performerNames[old.Name] == performer.ID {
+		delete(s.performerNames, old.Name)
+	}
 	s.performers[performer.ID] = performer
 	if performer.Name != "" {
 		s.performerNames[performer.Name] = performer.ID
@@ -355,6 +621,64 @@ func wordPrefixMatch(candidate, query string) bool {
 	return true
 }
 
+// CacheSavedFilters replaces the saved filters cached for mode.
+func (s *cacheLookup) CacheSavedFilters(mode stash.FilterMode, filters []stash.SavedFilter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.savedFilters[mode] = slices.Clone(filters)
+}
+
+// CacheSavedFilter adds or replaces a saved filter, if the filters for its mode have been loaded.
+func (s *cacheLookup) CacheSavedFilter(filter stash.SavedFilter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	filters, ok := s.savedFilters[filter.Mode]
+	if !ok {
+		return
+	}
+	i := slices.IndexFunc(filters, func(f stash.SavedFilter) bool { return f.ID == filter.ID })
+	if i < 0 {
+		s.savedFilters[filter.Mode] = append(filters, filter)
+		return
+	}
+	filters[i] = filter
+}
+
+func (s *cacheLookup) UncacheSavedFilter(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for mode, filters := range s.savedFilters {
+		s.savedFilters[mode] = slices.DeleteFunc(filters, func(f stash.SavedFilter) bool { return f.ID == id })
+	}
+}
+
+func (s *cacheLookup) SavedFiltersLoaded(mode stash.FilterMode) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.savedFilters[mode]
+	return ok
+}
+
+func (s *cacheLookup) SavedFiltersByPrefix(mode stash.FilterMode, prefix string, limit int) []stash.SavedFilter {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	matches := make([]stash.SavedFilter, 0, limit)
+	for _, filter := range s.savedFilters[mode] {
+		if wordPrefixMatch(filter.Name, prefix) {
+			matches = append(matches, filter)
+		}
+	}
+
+	slices.SortFunc(matches, func(a, b stash.SavedFilter) int {
+		return compareWordPrefixMatches(a.Name, b.Name, prefix)
+	})
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
 func compareWordPrefixMatches(a, b, query string) int {
 	scoreA := wordPrefixScore(a, query)
 	scoreB := wordPrefixScore(b, query)
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/drakenstar/stash-cli/stash"
@@ -19,6 +21,23 @@ type cmdService struct {
 	mu           sync.RWMutex
 	loadingCount uint
 	cache        *cacheLookup
+
+	// noRecordPlay disables recording play counts and activity when scenes are opened.
+	noRecordPlay bool
+	// unmapPath maps local paths given in commands to the paths used by stash.  Paths are used as given when nil.
+	unmapPath func(string) string
+	// restorer restores deleted content, and is nil if the stash can't.
+	restorer restorer
+	// indexer indexes content, and is nil if the stash doesn't index content itself.  Content is indexed again when
+	// rescan is signalled.
+	indexer indexer
+	rescan  chan struct{}
+}
+
+// restorer is implemented by stashes that delete content to a trash, from which the most recently deleted item can be
+// restored.
+type restorer interface {
+	Restore(context.Context) (string, error)
 }
 
 func (s *cmdService) loadBegin() {
@@ -73,38 +92,206 @@ func (s *cmdService) DeleteScene(id string) tea.Cmd {
 	})
 }
 
+// Restore restores the most recently deleted scene or gallery.
+func (s *cmdService) Restore() tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		if s.restorer == nil {
+			return ErrorMsg{errors.New("deleted content can't be restored from this stash instance")}
+		}
+		path, err := s.restorer.Restore(context.Background())
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		return restoredMsg{path}
+	})
+}
+
+// TagScene adds tags to a scene, or removes those with names prefixed with '-'.
 func (s *cmdService) TagScene(scene stash.Scene, names []string) tea.Cmd {
+	return s.tagScene(scene, names, false)
+}
+
+// RetagScene replaces all tags of a scene.
+func (s *cmdService) RetagScene(scene stash.Scene, names []string) tea.Cmd {
+	return s.tagScene(scene, names, true)
+}
+
+func (s *cmdService) tagScene(scene stash.Scene, names []string, replace bool) tea.Cmd {
 	return s.withLoadingCount(func() tea.Msg {
-		tags, err := s.resolveOrCreateTags(context.Background(), names)
+		tags, err := s.applyTagChanges(context.Background(), scene.Tags, names, replace)
 		if err != nil {
 			return ErrorMsg{fmt.Errorf("tag resolution failed: %w", err)}
 		}
-		tagIDs := mergeTagIDs(scene.Tags, tags)
-		updated, err := s.Stash.SceneUpdate(context.Background(), stash.SceneUpdate{
-			ID:     graphql.ID(scene.ID),
-			TagIDs: tagIDs,
-		})
+		updated := scene
+		updated.Tags = tags
+		result, err := s.Stash.SceneUpdate(context.Background(), stash.NewSceneUpdate(scene, updated))
 		if err != nil {
 			return ErrorMsg{err}
 		}
-		return sceneTaggedMsg{scene: updated}
+		return sceneTaggedMsg{scene: result}
 	})
 }
 
-func (s *cmdService) TagGallery(gallery stash.Gallery, names []string) tea.Cmd {
+// RecordPlay increments the play count of a scene.  Returns nil if recording plays is disabled.
+func (s *cmdService) RecordPlay(scene stash.Scene) tea.Cmd {
+	if s.noRecordPlay {
+		return nil
+	}
 	return s.withLoadingCount(func() tea.Msg {
-		tags, err := s.resolveOrCreateTags(context.Background(), names)
+		count, err := s.Stash.RecordPlay(context.Background(), scene.ID)
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		return scenePlayedMsg{id: scene.ID, count: count, at: time.Now()}
+	})
+}
+
+// SaveSceneActivity records the resume time and play duration in seconds of a scene.  Returns nil if recording plays
+// is disabled.
+func (s *cmdService) SaveSceneActivity(scene stash.Scene, resumeTime, playDuration *float64) tea.Cmd {
+	if s.noRecordPlay {
+		return nil
+	}
+	return s.withLoadingCount(func() tea.Msg {
+		_, err := s.Stash.SceneSaveActivity(context.Background(), scene.ID, resumeTime, playDuration)
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		return sceneActivitySavedMsg{id: scene.ID, resumeTime: resumeTime}
+	})
+}
+
+// TagScenes adds tags to a number of scenes, or removes those with names prefixed with '-'.
+func (s *cmdService) TagScenes(ids []string, names []string) tea.Cmd {
+	return s.tagScenes(ids, names, false)
+}
+
+// RetagScenes replaces all tags of a number of scenes.
+func (s *cmdService) RetagScenes(ids []string, names []string) tea.Cmd {
+	return s.tagScenes(ids, names, true)
+}
+
+func (s *cmdService) tagScenes(ids []string, names []string, replace bool) tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		changes, err := s.bulkTagChanges(context.Background(), names, replace)
 		if err != nil {
 			return ErrorMsg{fmt.Errorf("tag resolution failed: %w", err)}
 		}
-		updatedTags := append([]stash.Tag(nil), gallery.Tags...)
-		for _, tag := range tags {
-			if !tagInList(updatedTags, tag.ID) {
-				updatedTags = append(updatedTags, tag)
+		var scenes []stash.Scene
+		for _, change := range changes {
+			update := stash.NewBulkSceneUpdate(ids)
+			update.TagIDs = &change
+			scenes, err = s.Stash.BulkSceneUpdate(context.Background(), update)
+			if err != nil {
+				return ErrorMsg{err}
 			}
 		}
+		return scenesUpdatedMsg{scenes}
+	})
+}
+
+// UpdateScenes applies the same changes to a number of scenes in a single request.
+func (s *cmdService) UpdateScenes(update stash.BulkSceneUpdate) tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		scenes, err := s.Stash.BulkSceneUpdate(context.Background(), update)
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		return scenesUpdatedMsg{scenes}
+	})
+}
+
+// DeleteScenes deletes a number of scenes in a single request.
+func (s *cmdService) DeleteScenes(ids []string) tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		_, err := s.Stash.ScenesDestroy(context.Background(), ids)
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		return scenesDeletedMsg{ids}
+	})
+}
+
+// UpdateScene saves the differences between an old and updated scene.
+func (s *cmdService) UpdateScene(old, updated stash.Scene) tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		scene, err := s.Stash.SceneUpdate(context.Background(), stash.NewSceneUpdate(old, updated))
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		return sceneUpdatedMsg{scene: scene}
+	})
+}
+
+// StudioScene sets the studio of a scene, where studio is either the name or ID of an existing studio.
+func (s *cmdService) StudioScene(scene stash.Scene, studio string) tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		ids, err := resolveEntityInputs([]string{studio}, s.StudioFindByName)
+		if err != nil {
+			return ErrorMsg{fmt.Errorf("studio resolution failed: %w", err)}
+		}
+		if len(ids) == 0 {
+			return ErrorMsg{fmt.Errorf("no studio specified")}
+		}
+		updated := scene
+		updated.Studio = stash.Studio{ID: ids[0]}
+		result, err := s.Stash.SceneUpdate(context.Background(), stash.NewSceneUpdate(scene, updated))
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		return sceneUpdatedMsg{scene: result}
+	})
+}
+
+// oCounterChange is a change that can be made to the o-counter of a scene or image.
+type oCounterChange int
+
+const (
+	oCounterIncrement oCounterChange = iota
+	oCounterDecrement
+	oCounterReset
+)
+
+// OCounterScene applies an o-counter change to a scene.
+func (s *cmdService) OCounterScene(scene stash.Scene, change oCounterChange) tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		var count int
+		var err error
+		switch change {
+		case oCounterIncrement:
+			count, err = s.Stash.SceneIncrementO(context.Background(), scene.ID)
+		case oCounterDecrement:
+			count, err = s.Stash.SceneDecrementO(context.Background(), scene.ID)
+		case oCounterReset:
+			count, err = s.Stash.SceneResetO(context.Background(), scene.ID)
+		}
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		updated := scene
+		updated.OCounter = count
+		return sceneUpdatedMsg{scene: updated}
+	})
+}
+
+// TagGallery adds tags to a gallery, or removes those with names prefixed with '-'.
+func (s *cmdService) TagGallery(gallery stash.Gallery, names []string) tea.Cmd {
+	return s.tagGallery(gallery, names, false)
+}
+
+// RetagGallery replaces all tags of a gallery.
+func (s *cmdService) RetagGallery(gallery stash.Gallery, names []string) tea.Cmd {
+	return s.tagGallery(gallery, names, true)
+}
+
+func (s *cmdService) tagGallery(gallery stash.Gallery, names []string, replace bool) tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		tags, err := s.applyTagChanges(context.Background(), gallery.Tags, names, replace)
+		if err != nil {
+			return ErrorMsg{fmt.Errorf("tag resolution failed: %w", err)}
+		}
 		updated := gallery
-		updated.Tags = updatedTags
+		updated.Tags = tags
 		g, err := s.Stash.GalleryUpdate(context.Background(), stash.NewGalleryUpdate(gallery, updated))
 		if err != nil {
 			return ErrorMsg{err}
@@ -113,46 +300,542 @@ func (s *cmdService) TagGallery(gallery stash.Gallery, names []string) tea.Cmd {
 	})
 }
 
-func (s *cmdService) DeleteGallery(id string) tea.Cmd {
+// TagGalleries adds tags to a number of galleries, or removes those with names prefixed with '-'.
+func (s *cmdService) TagGalleries(ids []string, names []string) tea.Cmd {
+	return s.tagGalleries(ids, names, false)
+}
+
+// RetagGalleries replaces all tags of a number of galleries.
+func (s *cmdService) RetagGalleries(ids []string, names []string) tea.Cmd {
+	return s.tagGalleries(ids, names, true)
+}
+
+func (s *cmdService) tagGalleries(ids []string, names []string, replace bool) tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		changes, err := s.bulkTagChanges(context.Background(), names, replace)
+		if err != nil {
+			return ErrorMsg{fmt.Errorf("tag resolution failed: %w", err)}
+		}
+		var galleries []stash.Gallery
+		for _, change := range changes {
+			update := stash.NewBulkGalleryUpdate(ids)
+			update.TagIDs = &change
+			galleries, err = s.Stash.BulkGalleryUpdate(context.Background(), update)
+			if err != nil {
+				return ErrorMsg{err}
+			}
+		}
+		return galleriesUpdatedMsg{galleries}
+	})
+}
+
+// UpdateGalleries applies the same changes to a number of galleries in a single request.
+func (s *cmdService) UpdateGalleries(update stash.BulkGalleryUpdate) tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		galleries, err := s.Stash.BulkGalleryUpdate(context.Background(), update)
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		return galleriesUpdatedMsg{galleries}
+	})
+}
+
+// DeleteGalleries deletes a number of galleries in a single request.
+func (s *cmdService) DeleteGalleries(ids []string) tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		_, err := s.Stash.GalleriesDestroy(context.Background(), ids)
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		return galleriesDeletedMsg{ids}
+	})
+}
+
+func (s *cmdService) DeleteGallery(id string) tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		_, err := s.Stash.GalleryDelete(context.Background(), id)
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		return galleryDeletedMsg{id}
+	})
+}
+
+// TagImage adds tags to an image, or removes those with names prefixed with '-'.
+func (s *cmdService) TagImage(image stash.Image, names []string) tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		tags, err := s.applyTagChanges(context.Background(), image.Tags, names, false)
+		if err != nil {
+			return ErrorMsg{fmt.Errorf("tag resolution failed: %w", err)}
+		}
+		updated := image
+		updated.Tags = tags
+		i, err := s.Stash.ImageUpdate(context.Background(), stash.NewImageUpdate(image, updated))
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		return imageUpdatedMsg{image: i}
+	})
+}
+
+func (s *cmdService) RateImage(image stash.Image, rating int) tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		updated := image
+		updated.Rating = rating
+		i, err := s.Stash.ImageUpdate(context.Background(), stash.NewImageUpdate(image, updated))
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		return imageUpdatedMsg{image: i}
+	})
+}
+
+// OCounterImage applies an o-counter change to an image.
+func (s *cmdService) OCounterImage(image stash.Image, change oCounterChange) tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		var count int
+		var err error
+		switch change {
+		case oCounterIncrement:
+			count, err = s.Stash.ImageIncrementO(context.Background(), image.ID)
+		case oCounterDecrement:
+			count, err = s.Stash.ImageDecrementO(context.Background(), image.ID)
+		case oCounterReset:
+			count, err = s.Stash.ImageResetO(context.Background(), image.ID)
+		}
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		updated := image
+		updated.OCounter = count
+		return imageUpdatedMsg{image: updated}
+	})
+}
+
+func (s *cmdService) DeleteImage(id string) tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		_, err := s.Stash.ImageDelete(context.Background(), id)
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		return imageDeletedMsg{id}
+	})
+}
+
+func (s *cmdService) TagsAll() tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		_, err := s.Stash.TagsAll(context.Background())
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		return tagsLoadedMsg{}
+	})
+}
+
+func (s *cmdService) StudiosAll() tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		_, err := s.Stash.StudiosAll(context.Background())
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		return studiosLoadedMsg{}
+	})
+}
+
+func (s *cmdService) PerformersAll() tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		_, err := s.Stash.PerformersAll(context.Background())
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		return performersLoadedMsg{}
+	})
+}
+
+func (s *cmdService) Performers(f stash.FindFilter, pf stash.PerformerFilter) tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		performers, total, err := s.Stash.Performers(context.Background(), f, pf)
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		return performersMsg{
+			performers: performers,
+			total:      total,
+		}
+	})
+}
+
+func (s *cmdService) Studios(f stash.FindFilter, sf stash.StudioFilter) tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		studios, total, err := s.Stash.Studios(context.Background(), f, sf)
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		return studiosMsg{
+			studios: studios,
+			total:   total,
+		}
+	})
+}
+
+func (s *cmdService) Tags(f stash.FindFilter, tf stash.TagFilter) tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		tags, total, err := s.Stash.Tags(context.Background(), f, tf)
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		return tagsMsg{
+			tags:  tags,
+			total: total,
+		}
+	})
+}
+
+func (s *cmdService) Movies(f stash.FindFilter, mf stash.MovieFilter) tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		movies, total, err := s.Stash.Movies(context.Background(), f, mf)
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		return moviesMsg{
+			movies: movies,
+			total:  total,
+		}
+	})
+}
+
+func (s *cmdService) CreateMovie(name string) tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		movie, err := s.Stash.MovieCreate(context.Background(), stash.MovieCreate{Name: name})
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		return movieCreatedMsg{movie}
+	})
+}
+
+func (s *cmdService) UpdateMovie(update stash.MovieUpdate) tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		movie, err := s.Stash.MovieUpdate(context.Background(), update)
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		return movieUpdatedMsg{movie}
+	})
+}
+
+// CreateTag creates a new tag.  An error is returned if a tag of the same name already exists.
+func (s *cmdService) CreateTag(name string) tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		if _, err := s.TagFindByName(context.Background(), name); err == nil {
+			return ErrorMsg{fmt.Errorf("tag '%s' already exists", name)}
+		} else if !errors.Is(err, stash.ErrTagNotFound) {
+			return ErrorMsg{err}
+		}
+		tag, err := s.Stash.TagCreate(context.Background(), stash.TagCreate{Name: name})
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		return tagCreatedMsg{tag}
+	})
+}
+
+func (s *cmdService) UpdateTag(update stash.TagUpdate) tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		tag, err := s.Stash.TagUpdate(context.Background(), update)
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		return tagUpdatedMsg{tag}
+	})
+}
+
+// SetTagParents replaces the parents of a tag with the named tags.  No parents makes the tag a top level tag.
+func (s *cmdService) SetTagParents(id string, parents []string) tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		parentIDs, err := resolveEntityInputs(parents, func(name string) (stash.Tag, error) {
+			return s.TagFindByName(context.Background(), name)
+		})
+		if err != nil {
+			return ErrorMsg{fmt.Errorf("tag resolution failed: %w", err)}
+		}
+		ids := stash.GraphQLIDs(parentIDs)
+		tag, err := s.Stash.TagUpdate(context.Background(), stash.TagUpdate{
+			ID:        graphql.ID(id),
+			ParentIDs: &ids,
+		})
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		return tagParentsUpdatedMsg{tag}
+	})
+}
+
+// MergeTags merges the source tags into the named destination tag.
+func (s *cmdService) MergeTags(sourceIDs []string, destination string) tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		dest, err := resolveEntityInputs([]string{destination}, func(name string) (stash.Tag, error) {
+			return s.TagFindByName(context.Background(), name)
+		})
+		if err != nil {
+			return ErrorMsg{fmt.Errorf("tag resolution failed: %w", err)}
+		}
+		if len(dest) == 0 {
+			return ErrorMsg{fmt.Errorf("no destination tag specified")}
+		}
+		if containsString(sourceIDs, dest[0]) {
+			return ErrorMsg{fmt.Errorf("cannot merge a tag into itself")}
+		}
+		tag, err := s.Stash.TagsMerge(context.Background(), stash.TagsMerge{
+			Source:      stash.GraphQLIDs(sourceIDs),
+			Destination: graphql.ID(dest[0]),
+		})
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		return tagsMergedMsg{tag}
+	})
+}
+
+func (s *cmdService) DeleteTag(id string) tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		_, err := s.Stash.TagDelete(context.Background(), id)
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		return tagDeletedMsg{id}
+	})
+}
+
+func (s *cmdService) SceneMarkers(f stash.FindFilter, mf stash.SceneMarkerFilter) tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		markers, total, err := s.Stash.SceneMarkers(context.Background(), f, mf)
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		return markersMsg{
+			markers: markers,
+			total:   total,
+		}
+	})
+}
+
+// DuplicateScenes finds groups of scenes with matching perceptual hashes.
+func (s *cmdService) DuplicateScenes(distance int, durationDiff float64) tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		groups, err := s.Stash.FindDuplicateScenes(context.Background(), distance, durationDiff)
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		return duplicateScenesMsg{groups}
+	})
+}
+
+// MergeScenes merges the source scenes into the destination scene, which takes on their files.
+func (s *cmdService) MergeScenes(source []string, destination string) tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		scene, err := s.Stash.SceneMerge(context.Background(), stash.SceneMerge{
+			Source:      stash.GraphQLIDs(source),
+			Destination: graphql.ID(destination),
+		})
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		return scenesMergedMsg{scene: scene, source: source}
+	})
+}
+
+// StartTask starts a stash task.  Local paths of the request are mapped to the paths used by stash.
+func (s *cmdService) StartTask(req taskRequest) tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		paths := append([]string(nil), req.Paths...)
+		for _, p := range req.LocalPaths {
+			if s.unmapPath != nil {
+				p = s.unmapPath(p)
+			}
+			paths = append(paths, p)
+		}
+
+		ctx := context.Background()
+		var id string
+		var err error
+		switch req.Task {
+		case taskScan:
+			id, err = s.Stash.MetadataScan(ctx, stash.ScanMetadata{Paths: paths})
+		case taskGenerate:
+			id, err = s.Stash.MetadataGenerate(ctx, stash.GenerateMetadata{
+				Covers:    true,
+				Sprites:   true,
+				Previews:  true,
+				Markers:   true,
+				Phashes:   true,
+				SceneIDs:  stash.GraphQLIDs(req.SceneIDs),
+				Overwrite: req.Overwrite,
+			})
+		case taskAutoTag:
+			id, err = s.Stash.MetadataAutoTag(ctx, stash.AutoTagMetadata{
+				Paths:      paths,
+				Performers: []string{"*"},
+				Studios:    []string{"*"},
+				Tags:       []string{"*"},
+			})
+		case taskIdentify:
+			sources := make([]stash.IdentifySource, len(req.Sources))
+			for i, source := range req.Sources {
+				sources[i] = stash.IdentifySource{Source: scraperSource(source)}
+			}
+			id, err = s.Stash.MetadataIdentify(ctx, stash.IdentifyMetadata{
+				Sources:  sources,
+				SceneIDs: stash.GraphQLIDs(req.SceneIDs),
+				Paths:    paths,
+			})
+		case taskClean:
+			id, err = s.Stash.MetadataClean(ctx, stash.CleanMetadata{Paths: paths, DryRun: req.DryRun})
+		default:
+			err = fmt.Errorf("unknown task '%s'", req.Task)
+		}
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		return taskStartedMsg{task: req.Task, jobID: id}
+	})
+}
+
+// scraperSource returns a source for identifying scenes.  Sources that look like URLs are stash-box endpoints, and
+// anything else is the ID of a scraper.
+func scraperSource(source string) stash.ScraperSource {
+	if strings.Contains(source, "://") {
+		return stash.ScraperSource{StashBoxEndpoint: &source}
+	}
+	return stash.ScraperSource{ScraperID: &source}
+}
+
+// Jobs returns the queued and running jobs, along with any jobs in ids that have since left the queue and can still
+// be found.  Jobs are polled regularly, so this does not count towards loading.
+func (s *cmdService) Jobs(ids []string) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		jobs, err := s.Stash.JobQueue(ctx)
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		queued := make(map[string]bool, len(jobs))
+		for _, job := range jobs {
+			queued[job.ID] = true
+		}
+		for _, id := range ids {
+			if queued[id] {
+				continue
+			}
+			job, ok, err := s.Stash.FindJob(ctx, id)
+			if err != nil {
+				return ErrorMsg{err}
+			}
+			if ok {
+				jobs = append(jobs, job)
+			}
+		}
+		return jobsMsg{jobs}
+	}
+}
+
+func (s *cmdService) StopJob(id string) tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		_, err := s.Stash.StopJob(context.Background(), id)
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		return jobStoppedMsg{id}
+	})
+}
+
+// SavedFilters loads the saved filters for mode into the cache.
+func (s *cmdService) SavedFilters(mode stash.FilterMode) tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		_, err := s.Stash.SavedFilters(context.Background(), mode)
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		return savedFiltersLoadedMsg{}
+	})
+}
+
+// DefaultFilter loads the default filter for mode.  Nothing is returned when there is no default filter.
+func (s *cmdService) DefaultFilter(mode stash.FilterMode) tea.Cmd {
 	return s.withLoadingCount(func() tea.Msg {
-		_, err := s.Stash.GalleryDelete(context.Background(), id)
+		filter, ok, err := s.Stash.DefaultFilter(context.Background(), mode)
 		if err != nil {
 			return ErrorMsg{err}
 		}
-		return galleryDeletedMsg{id}
+		if !ok {
+			return nil
+		}
+		return defaultFilterMsg{filter}
 	})
 }
 
-func (s *cmdService) TagsAll() tea.Cmd {
+func (s *cmdService) LoadFilter(mode stash.FilterMode, name string) tea.Cmd {
 	return s.withLoadingCount(func() tea.Msg {
-		_, err := s.Stash.TagsAll(context.Background())
+		filter, ok, err := s.savedFilter(context.Background(), mode, name)
 		if err != nil {
 			return ErrorMsg{err}
 		}
-		return tagsLoadedMsg{}
+		if !ok {
+			return ErrorMsg{fmt.Errorf("no saved filter named '%s'", name)}
+		}
+		return savedFilterMsg{filter}
 	})
 }
 
-func (s *cmdService) StudiosAll() tea.Cmd {
+// SaveFilter saves a filter, replacing any filter with the same name and mode.
+func (s *cmdService) SaveFilter(input stash.SaveFilter) tea.Cmd {
 	return s.withLoadingCount(func() tea.Msg {
-		_, err := s.Stash.StudiosAll(context.Background())
+		existing, ok, err := s.savedFilter(context.Background(), input.Mode, input.Name)
 		if err != nil {
 			return ErrorMsg{err}
 		}
-		return studiosLoadedMsg{}
+		if ok {
+			input.ID = &existing.ID
+		}
+		filter, err := s.Stash.SaveFilter(context.Background(), input)
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		return filterSavedMsg{filter}
 	})
 }
 
-func (s *cmdService) PerformersAll() tea.Cmd {
+func (s *cmdService) DeleteFilter(mode stash.FilterMode, name string) tea.Cmd {
 	return s.withLoadingCount(func() tea.Msg {
-		_, err := s.Stash.PerformersAll(context.Background())
+		filter, ok, err := s.savedFilter(context.Background(), mode, name)
 		if err != nil {
 			return ErrorMsg{err}
 		}
-		return performersLoadedMsg{}
+		if !ok {
+			return ErrorMsg{fmt.Errorf("no saved filter named '%s'", name)}
+		}
+		_, err = s.Stash.DestroySavedFilter(context.Background(), filter.ID)
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		return filterDeletedMsg{filter.ID}
 	})
 }
 
+// savedFilter finds a saved filter by name.  False is returned if there is no filter with the name.
+func (s *cmdService) savedFilter(ctx context.Context, mode stash.FilterMode, name string) (stash.SavedFilter, bool, error) {
+	filters, err := s.Stash.SavedFilters(ctx, mode)
+	if err != nil {
+		return stash.SavedFilter{}, false, err
+	}
+	for _, filter := range filters {
+		if filter.Name == name {
+			return filter, true, nil
+		}
+	}
+	return stash.SavedFilter{}, false, nil
+}
+
 func (s *cmdService) Galleries(f stash.FindFilter, gf stash.GalleryFilter) tea.Cmd {
 	return s.withLoadingCount(func() tea.Msg {
 		galleries, total, err := s.Stash.Galleries(context.Background(), f, gf)
@@ -166,6 +849,19 @@ func (s *cmdService) Galleries(f stash.FindFilter, gf stash.GalleryFilter) tea.C
 	})
 }
 
+func (s *cmdService) Images(f stash.FindFilter, imf stash.ImageFilter) tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		images, total, err := s.Stash.Images(context.Background(), f, imf)
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		return imagesMsg{
+			images: images,
+			total:  total,
+		}
+	})
+}
+
 type galleriesMsg struct {
 	galleries []stash.Gallery
 	total     int
@@ -177,6 +873,17 @@ type galleriesLoadedMsg struct {
 	total     int
 }
 
+type imagesMsg struct {
+	images []stash.Image
+	total  int
+}
+
+type imagesListLoadedMsg struct {
+	requestID uint64
+	images    []stash.Image
+	total     int
+}
+
 type scenesMsg struct {
 	scenes []stash.Scene
 	total  int
@@ -188,14 +895,187 @@ type scenesLoadedMsg struct {
 	total     int
 }
 
+type performersMsg struct {
+	performers []stash.Performer
+	total      int
+}
+
+type performersListLoadedMsg struct {
+	requestID  uint64
+	performers []stash.Performer
+	total      int
+}
+
+type studiosMsg struct {
+	studios []stash.StudioDetail
+	total   int
+}
+
+type studiosListLoadedMsg struct {
+	requestID uint64
+	studios   []stash.StudioDetail
+	total     int
+}
+
+type studioChildrenLoadedMsg struct {
+	parentID string
+	studios  []stash.StudioDetail
+}
+
+type moviesMsg struct {
+	movies []stash.MovieDetail
+	total  int
+}
+
+type moviesListLoadedMsg struct {
+	requestID uint64
+	movies    []stash.MovieDetail
+	total     int
+}
+
+type movieCreatedMsg struct {
+	movie stash.MovieDetail
+}
+
+type movieUpdatedMsg struct {
+	movie stash.MovieDetail
+}
+
+type tagsMsg struct {
+	tags  []stash.TagDetail
+	total int
+}
+
+type tagsListLoadedMsg struct {
+	requestID uint64
+	tags      []stash.TagDetail
+	total     int
+}
+
+type tagChildrenLoadedMsg struct {
+	parentID string
+	tags     []stash.TagDetail
+}
+
+type tagCreatedMsg struct {
+	tag stash.Tag
+}
+
+type tagUpdatedMsg struct {
+	tag stash.TagDetail
+}
+
+type tagParentsUpdatedMsg struct {
+	tag stash.TagDetail
+}
+
+type tagsMergedMsg struct {
+	tag stash.TagDetail
+}
+
+type tagDeletedMsg struct {
+	id string
+}
+
+type markersMsg struct {
+	markers []stash.SceneMarker
+	total   int
+}
+
+type markersListLoadedMsg struct {
+	requestID uint64
+	markers   []stash.SceneMarker
+	total     int
+}
+
 type sceneDeletedMsg struct {
 	id string
 }
 
+// restoredMsg is returned when deleted content has been restored to path.
+type restoredMsg struct {
+	path string
+}
+
+type duplicateScenesMsg struct {
+	groups [][]stash.Scene
+}
+
+// scenesMergedMsg is returned when the source scenes have been merged into scene and no longer exist.
+type scenesMergedMsg struct {
+	scene  stash.Scene
+	source []string
+}
+
 type sceneTaggedMsg struct {
 	scene stash.Scene
 }
 
+// scenesUpdatedMsg is returned when a number of scenes have been changed together and should be replaced in place.
+type scenesUpdatedMsg struct {
+	scenes []stash.Scene
+}
+
+type scenesDeletedMsg struct {
+	ids []string
+}
+
+// sceneUpdatedMsg is returned when a scene has been changed and should be replaced in place.
+type sceneUpdatedMsg struct {
+	scene stash.Scene
+}
+
+type scenePlayedMsg struct {
+	id    string
+	count int
+	at    time.Time
+}
+
+type sceneActivitySavedMsg struct {
+	id         string
+	resumeTime *float64
+}
+
+// taskStartedMsg is returned when a stash task has been queued as the job with jobID.
+type taskStartedMsg struct {
+	task  string
+	jobID string
+}
+
+type jobsMsg struct {
+	jobs []stash.Job
+}
+
+type jobsLoadedMsg struct {
+	requestID uint64
+	jobs      []stash.Job
+}
+
+type jobStoppedMsg struct {
+	id string
+}
+
+// defaultFilterMsg is received with the default filter for a tab, which is applied if the tab has not been filtered
+// since it was opened.
+type defaultFilterMsg struct {
+	filter stash.SavedFilter
+}
+
+// savedFilterMsg is received with a saved filter to apply to a tab.
+type savedFilterMsg struct {
+	filter stash.SavedFilter
+}
+
+type filterSavedMsg struct {
+	filter stash.SavedFilter
+}
+
+type filterDeletedMsg struct {
+	id string
+}
+
+type savedFiltersLoadedMsg struct{}
+
 type galleryDeletedMsg struct {
 	id string
 }
@@ -204,6 +1084,23 @@ type galleryTaggedMsg struct {
 	gallery stash.Gallery
 }
 
+// galleriesUpdatedMsg is returned when a number of galleries have been changed together and should be replaced in place.
+type galleriesUpdatedMsg struct {
+	galleries []stash.Gallery
+}
+
+type galleriesDeletedMsg struct {
+	ids []string
+}
+
+type imageDeletedMsg struct {
+	id string
+}
+
+type imageUpdatedMsg struct {
+	image stash.Image
+}
+
 type tagsLoadedMsg struct{}
 type studiosLoadedMsg struct{}
 type performersLoadedMsg struct{}
@@ -221,6 +1118,9 @@ type loadingMsg struct {
 }
 
 func (s *cmdServiceWithID) withID(cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
 	return func() tea.Msg {
 		return loadingMsg{
 			id:      s.id,
@@ -237,10 +1137,87 @@ func (s *cmdServiceWithID) DeleteScene(id string) tea.Cmd {
 	return s.withID(s.s.DeleteScene(id))
 }
 
+func (s *cmdServiceWithID) DuplicateScenes(distance int, durationDiff float64) tea.Cmd {
+	return s.withID(s.s.DuplicateScenes(distance, durationDiff))
+}
+
+func (s *cmdServiceWithID) MergeScenes(source []string, destination string) tea.Cmd {
+	return s.withID(s.s.MergeScenes(source, destination))
+}
+
+// StartTask is not routed to the tab, since the app opens the jobs tab once a task has started.
+func (s *cmdServiceWithID) StartTask(req taskRequest) tea.Cmd {
+	return s.s.StartTask(req)
+}
+
+func (s *cmdServiceWithID) Jobs(ids []string) tea.Cmd {
+	return s.withID(s.s.Jobs(ids))
+}
+
+func (s *cmdServiceWithID) StopJob(id string) tea.Cmd {
+	return s.withID(s.s.StopJob(id))
+}
+
+func (s *cmdServiceWithID) DefaultFilter(mode stash.FilterMode) tea.Cmd {
+	return s.withID(s.s.DefaultFilter(mode))
+}
+
+func (s *cmdServiceWithID) LoadFilter(mode stash.FilterMode, name string) tea.Cmd {
+	return s.withID(s.s.LoadFilter(mode, name))
+}
+
+func (s *cmdServiceWithID) SaveFilter(input stash.SaveFilter) tea.Cmd {
+	return s.withID(s.s.SaveFilter(input))
+}
+
+func (s *cmdServiceWithID) DeleteFilter(mode stash.FilterMode, name string) tea.Cmd {
+	return s.withID(s.s.DeleteFilter(mode, name))
+}
+
+func (s *cmdServiceWithID) TagScenes(ids []string, names []string) tea.Cmd {
+	return s.withID(s.s.TagScenes(ids, names))
+}
+
+func (s *cmdServiceWithID) RetagScenes(ids []string, names []string) tea.Cmd {
+	return s.withID(s.s.RetagScenes(ids, names))
+}
+
+func (s *cmdServiceWithID) UpdateScenes(update stash.BulkSceneUpdate) tea.Cmd {
+	return s.withID(s.s.UpdateScenes(update))
+}
+
+func (s *cmdServiceWithID) DeleteScenes(ids []string) tea.Cmd {
+	return s.withID(s.s.DeleteScenes(ids))
+}
+
 func (s *cmdServiceWithID) TagScene(scene stash.Scene, names []string) tea.Cmd {
 	return s.withID(s.s.TagScene(scene, names))
 }
 
+func (s *cmdServiceWithID) UpdateScene(old, updated stash.Scene) tea.Cmd {
+	return s.withID(s.s.UpdateScene(old, updated))
+}
+
+func (s *cmdServiceWithID) StudioScene(scene stash.Scene, studio string) tea.Cmd {
+	return s.withID(s.s.StudioScene(scene, studio))
+}
+
+func (s *cmdServiceWithID) OCounterScene(scene stash.Scene, change oCounterChange) tea.Cmd {
+	return s.withID(s.s.OCounterScene(scene, change))
+}
+
+func (s *cmdServiceWithID) RecordPlay(scene stash.Scene) tea.Cmd {
+	return s.withID(s.s.RecordPlay(scene))
+}
+
+func (s *cmdServiceWithID) SaveSceneActivity(scene stash.Scene, resumeTime, playDuration *float64) tea.Cmd {
+	return s.withID(s.s.SaveSceneActivity(scene, resumeTime, playDuration))
+}
+
+func (s *cmdServiceWithID) RetagScene(scene stash.Scene, names []string) tea.Cmd {
+	return s.withID(s.s.RetagScene(scene, names))
+}
+
 func (s *cmdServiceWithID) DeleteGallery(id string) tea.Cmd {
 	return s.withID(s.s.DeleteGallery(id))
 }
@@ -249,10 +1226,98 @@ func (s *cmdServiceWithID) TagGallery(gallery stash.Gallery, names []string) tea
 	return s.withID(s.s.TagGallery(gallery, names))
 }
 
+func (s *cmdServiceWithID) RetagGallery(gallery stash.Gallery, names []string) tea.Cmd {
+	return s.withID(s.s.RetagGallery(gallery, names))
+}
+
+func (s *cmdServiceWithID) TagGalleries(ids []string, names []string) tea.Cmd {
+	return s.withID(s.s.TagGalleries(ids, names))
+}
+
+func (s *cmdServiceWithID) RetagGalleries(ids []string, names []string) tea.Cmd {
+	return s.withID(s.s.RetagGalleries(ids, names))
+}
+
+func (s *cmdServiceWithID) UpdateGalleries(update stash.BulkGalleryUpdate) tea.Cmd {
+	return s.withID(s.s.UpdateGalleries(update))
+}
+
+func (s *cmdServiceWithID) DeleteGalleries(ids []string) tea.Cmd {
+	return s.withID(s.s.DeleteGalleries(ids))
+}
+
 func (s *cmdServiceWithID) Galleries(f stash.FindFilter, gf stash.GalleryFilter) tea.Cmd {
 	return s.withID(s.s.Galleries(f, gf))
 }
 
+func (s *cmdServiceWithID) Images(f stash.FindFilter, imf stash.ImageFilter) tea.Cmd {
+	return s.withID(s.s.Images(f, imf))
+}
+
+func (s *cmdServiceWithID) OCounterImage(image stash.Image, change oCounterChange) tea.Cmd {
+	return s.withID(s.s.OCounterImage(image, change))
+}
+
+func (s *cmdServiceWithID) DeleteImage(id string) tea.Cmd {
+	return s.withID(s.s.DeleteImage(id))
+}
+
+func (s *cmdServiceWithID) TagImage(image stash.Image, names []string) tea.Cmd {
+	return s.withID(s.s.TagImage(image, names))
+}
+
+func (s *cmdServiceWithID) RateImage(image stash.Image, rating int) tea.Cmd {
+	return s.withID(s.s.RateImage(image, rating))
+}
+
+func (s *cmdServiceWithID) Performers(f stash.FindFilter, pf stash.PerformerFilter) tea.Cmd {
+	return s.withID(s.s.Performers(f, pf))
+}
+
+func (s *cmdServiceWithID) Studios(f stash.FindFilter, sf stash.StudioFilter) tea.Cmd {
+	return s.withID(s.s.Studios(f, sf))
+}
+
+func (s *cmdServiceWithID) Movies(f stash.FindFilter, mf stash.MovieFilter) tea.Cmd {
+	return s.withID(s.s.Movies(f, mf))
+}
+
+func (s *cmdServiceWithID) CreateMovie(name string) tea.Cmd {
+	return s.withID(s.s.CreateMovie(name))
+}
+
+func (s *cmdServiceWithID) UpdateMovie(update stash.MovieUpdate) tea.Cmd {
+	return s.withID(s.s.UpdateMovie(update))
+}
+
+func (s *cmdServiceWithID) SceneMarkers(f stash.FindFilter, mf stash.SceneMarkerFilter) tea.Cmd {
+	return s.withID(s.s.SceneMarkers(f, mf))
+}
+
+func (s *cmdServiceWithID) Tags(f stash.FindFilter, tf stash.TagFilter) tea.Cmd {
+	return s.withID(s.s.Tags(f, tf))
+}
+
+func (s *cmdServiceWithID) CreateTag(name string) tea.Cmd {
+	return s.withID(s.s.CreateTag(name))
+}
+
+func (s *cmdServiceWithID) UpdateTag(update stash.TagUpdate) tea.Cmd {
+	return s.withID(s.s.UpdateTag(update))
+}
+
+func (s *cmdServiceWithID) SetTagParents(id string, parents []string) tea.Cmd {
+	return s.withID(s.s.SetTagParents(id, parents))
+}
+
+func (s *cmdServiceWithID) MergeTags(sourceIDs []string, destination string) tea.Cmd {
+	return s.withID(s.s.MergeTags(sourceIDs, destination))
+}
+
+func (s *cmdServiceWithID) DeleteTag(id string) tea.Cmd {
+	return s.withID(s.s.DeleteTag(id))
+}
+
 func (s *cmdService) resolveOrCreateTags(ctx context.Context, names []string) ([]stash.Tag, error) {
 	tags := make([]stash.Tag, 0, len(names))
 	for _, name := range names {
@@ -284,27 +1349,95 @@ func (s *cmdService) resolveOrCreateTags(ctx context.Context, names []string) ([
 	return tags, nil
 }
 
-func mergeTagIDs(existing []stash.Tag, added []stash.Tag) []graphql.ID {
-	ids := make([]graphql.ID, 0, len(existing)+len(added))
-	seen := make(map[string]struct{}, len(existing)+len(added))
-	for _, tag := range existing {
-		if tag.ID == "" {
-			continue
+// applyTagChanges returns the tags resulting from applying the names given to a tag command to a list of existing tags.
+// Names prefixed with '-' are removed, matching existing tags by name or ID.  All other names are added, creating any
+// tags that do not yet exist.  If replace is set the existing tags are discarded rather than added to.
+func (s *cmdService) applyTagChanges(ctx context.Context, existing []stash.Tag, names []string, replace bool) ([]stash.Tag, error) {
+	add, remove := tagChanges(names)
+
+	var tags []stash.Tag
+	if !replace {
+		for _, tag := range existing {
+			if !tagMatchesAny(tag, remove) {
+				tags = append(tags, tag)
+			}
 		}
-		seen[tag.ID] = struct{}{}
-		ids = append(ids, graphql.ID(tag.ID))
+	}
+
+	added, err := s.resolveOrCreateTags(ctx, add)
+	if err != nil {
+		return nil, err
 	}
 	for _, tag := range added {
-		if tag.ID == "" {
-			continue
+		if !tagInList(tags, tag.ID) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
+
+// bulkTagChanges returns the tag changes to apply in turn to each entity of a bulk update for the names given to a tag
+// command.  A bulk update can only apply a single mode to its tags, so adding and removing tags takes two updates.
+func (s *cmdService) bulkTagChanges(ctx context.Context, names []string, replace bool) ([]stash.BulkUpdateIDs, error) {
+	add, remove := tagChanges(names)
+
+	added, err := s.resolveOrCreateTags(ctx, add)
+	if err != nil {
+		return nil, err
+	}
+	addIDs := make([]graphql.ID, len(added))
+	for i, tag := range added {
+		addIDs[i] = graphql.ID(tag.ID)
+	}
+	if replace {
+		return []stash.BulkUpdateIDs{{IDs: addIDs, Mode: stash.BulkUpdateIDModeSet}}, nil
+	}
+
+	var changes []stash.BulkUpdateIDs
+	if len(addIDs) > 0 {
+		changes = append(changes, stash.BulkUpdateIDs{IDs: addIDs, Mode: stash.BulkUpdateIDModeAdd})
+	}
+	if len(remove) > 0 {
+		removeIDs, err := resolveEntityInputs(remove, func(name string) (stash.Tag, error) {
+			return s.TagFindByName(ctx, name)
+		})
+		if err != nil {
+			return nil, err
 		}
-		if _, ok := seen[tag.ID]; ok {
+		changes = append(changes, stash.BulkUpdateIDs{IDs: stash.GraphQLIDs(removeIDs), Mode: stash.BulkUpdateIDModeRemove})
+	}
+	return changes, nil
+}
+
+// tagChanges splits the names given to a tag command into those to add and those to remove, which are prefixed with
+// '-'.
+func tagChanges(names []string) (add, remove []string) {
+	for _, name := range names {
+		if rest, ok := strings.CutPrefix(name, "-"); ok && rest != "" {
+			remove = append(remove, rest)
 			continue
 		}
-		seen[tag.ID] = struct{}{}
-		ids = append(ids, graphql.ID(tag.ID))
+		add = append(add, name)
+	}
+	return add, remove
+}
+
+// untagNames prefixes each name with '-' so that the tags are removed when given to a tag command.
+func untagNames(names []string) []string {
+	prefixed := make([]string, len(names))
+	for i, name := range names {
+		prefixed[i] = "-" + name
+	}
+	return prefixed
+}
+
+func tagMatchesAny(tag stash.Tag, names []string) bool {
+	for _, name := range names {
+		if tag.ID == name || strings.EqualFold(tag.Name, name) {
+			return true
+		}
 	}
-	return ids
+	return false
 }
 
 func tagInList(tags []stash.Tag, id string) bool {
@@ -3,9 +3,11 @@ package app
 import tea "github.com/charmbracelet/bubbletea"
 
 type deleteRequestMsg struct {
-	Entity      string
-	Title       string
-	Path        string
+	Entity string
+	Title  string
+	Path   string
+	// Detail replaces the default explanation of what deleting removes.
+	Detail      string
 	SkipConfirm bool
 	DeleteCmd   tea.Cmd
 }
@@ -16,6 +18,9 @@ type confirmDeleteMsg struct {
 
 type dismissModalMsg struct{}
 
+// restoreMsg restores the most recently deleted content.
+type restoreMsg struct{}
+
 type pendingDeleteState struct {
 	tabID   tabID
 	request deleteRequestMsg
@@ -1,34 +1,60 @@
 package app
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/drakenstar/stash-cli/stash"
+	"github.com/drakenstar/stash-cli/ui"
 	"github.com/stretchr/testify/require"
 )
 
-type deleteTestService struct{}
+type deleteTestService struct {
+	savedFilterTestService
+	scrapeTestService
+	editTestService
+	performerEditTestService
+}
 
 func (deleteTestService) Scenes(stash.FindFilter, stash.SceneFilter) tea.Cmd { return nil }
 func (deleteTestService) DeleteScene(string) tea.Cmd {
 	return func() tea.Msg { return sceneDeletedMsg{id: "scene-1"} }
 }
-func (deleteTestService) TagScene(stash.Scene, []string) tea.Cmd                  { return nil }
-func (deleteTestService) ResolveTags([]string) tea.Cmd                            { return nil }
-func (deleteTestService) ResolveStudios([]string) tea.Cmd                         { return nil }
-func (deleteTestService) ResolvePerformers([]string) tea.Cmd                      { return nil }
-func (deleteTestService) Galleries(stash.FindFilter, stash.GalleryFilter) tea.Cmd { return nil }
+func (deleteTestService) TagScene(stash.Scene, []string) tea.Cmd                    { return nil }
+func (deleteTestService) TagScenes([]string, []string) tea.Cmd                      { return nil }
+func (deleteTestService) RetagScenes([]string, []string) tea.Cmd                    { return nil }
+func (deleteTestService) UpdateScenes(stash.BulkSceneUpdate) tea.Cmd                { return nil }
+func (deleteTestService) DeleteScenes([]string) tea.Cmd                             { return nil }
+func (deleteTestService) RetagScene(stash.Scene, []string) tea.Cmd                  { return nil }
+func (deleteTestService) RecordPlay(stash.Scene) tea.Cmd                            { return nil }
+func (deleteTestService) OCounterScene(stash.Scene, oCounterChange) tea.Cmd         { return nil }
+func (deleteTestService) UpdateScene(stash.Scene, stash.Scene) tea.Cmd              { return nil }
+func (deleteTestService) StudioScene(stash.Scene, string) tea.Cmd                   { return nil }
+func (deleteTestService) SaveSceneActivity(stash.Scene, *float64, *float64) tea.Cmd { return nil }
+func (deleteTestService) ResolveTags([]string) tea.Cmd                              { return nil }
+func (deleteTestService) ResolveStudios([]string) tea.Cmd                           { return nil }
+func (deleteTestService) ResolvePerformers([]string) tea.Cmd                        { return nil }
+func (deleteTestService) StartTask(taskRequest) tea.Cmd                             { return nil }
+func (deleteTestService) Galleries(stash.FindFilter, stash.GalleryFilter) tea.Cmd   { return nil }
 func (deleteTestService) DeleteGallery(string) tea.Cmd {
 	return func() tea.Msg { return galleryDeletedMsg{id: "gallery-1"} }
 }
-func (deleteTestService) TagGallery(stash.Gallery, []string) tea.Cmd { return nil }
+func (deleteTestService) TagGallery(stash.Gallery, []string) tea.Cmd      { return nil }
+func (deleteTestService) TagGalleries([]string, []string) tea.Cmd         { return nil }
+func (deleteTestService) RetagGalleries([]string, []string) tea.Cmd       { return nil }
+func (deleteTestService) UpdateGalleries(stash.BulkGalleryUpdate) tea.Cmd { return nil }
+func (deleteTestService) DeleteGalleries([]string) tea.Cmd                { return nil }
+func (deleteTestService) RetagGallery(stash.Gallery, []string) tea.Cmd    { return nil }
 
 type deleteTestLookup struct{}
 
 func (deleteTestLookup) GetStudio(string) (stash.Studio, error)       { return stash.Studio{}, nil }
 func (deleteTestLookup) GetTag(string) (stash.Tag, error)             { return stash.Tag{}, nil }
 func (deleteTestLookup) GetPerformer(string) (stash.Performer, error) { return stash.Performer{}, nil }
+func (deleteTestLookup) GetMovie(string) (stash.Movie, error)         { return stash.Movie{}, nil }
 
 func TestScenesModelDeleteRequest(t *testing.T) {
 	m := NewScenesModel(deleteTestService{}, deleteTestLookup{})
@@ -111,3 +137,31 @@ func TestDeleteModalClosesAfterGalleryRefresh(t *testing.T) {
 
 	require.Nil(t, updated.pendingDelete)
 }
+
+func TestRestoreCommand(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	path := filepath.Join(root, "scene.mp4")
+	require.NoError(t, os.WriteFile(path, nil, 0o644))
+	s, err := stash.NewLocalStash(root, "")
+	require.NoError(t, err)
+	require.NoError(t, s.Scan(context.Background(), nil))
+	_, err = s.DeleteScene(context.Background(), "scene.mp4")
+	require.NoError(t, err)
+
+	m := New(s, nil)
+	_, cmd := m.Update(ui.CommandExecMsg{Command: "restore"})
+	msg := cmd()
+	require.Equal(t, restoreMsg{}, msg)
+	_, cmd = m.Update(msg)
+	require.Equal(t, restoredMsg{path}, cmd())
+	require.FileExists(t, path)
+}
+
+func TestRestoreCommandUnsupported(t *testing.T) {
+	m := New(stash.NewMemoryStash(), nil)
+	_, cmd := m.Update(restoreMsg{})
+	msg := cmd()
+	require.IsType(t, ErrorMsg{}, msg)
+	require.ErrorContains(t, msg.(ErrorMsg), "can't be restored")
+}
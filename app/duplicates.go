@@ -0,0 +1,485 @@
+package app
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"sync/atomic"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/drakenstar/stash-cli/command"
+	"github.com/drakenstar/stash-cli/stash"
+	"github.com/drakenstar/stash-cli/ui"
+)
+
+type DuplicateService interface {
+	DuplicateScenes(int, float64) tea.Cmd
+	MergeScenes([]string, string) tea.Cmd
+	DeleteScenes([]string) tea.Cmd
+}
+
+// DuplicatesModel lists groups of scenes with matching perceptual hashes one group at a time, so that the best file of
+// each group can be kept and the others merged into it or deleted.
+type DuplicatesModel struct {
+	DuplicateService
+
+	groups []duplicateGroup
+	group  int
+	index  int
+
+	// distance is the maximum phash distance between files of a group, and durationDiff the maximum difference in
+	// seconds between their durations.  A negative durationDiff matches files of any duration.
+	distance     int
+	durationDiff float64
+
+	loaded bool
+	screen Size
+
+	listRequestID uint64
+}
+
+// duplicateGroup is a set of duplicate scenes along with the index of the scene to keep.
+type duplicateGroup struct {
+	scenes []stash.Scene
+	keep   int
+}
+
+func NewDuplicatesModel(duplicateService DuplicateService) *DuplicatesModel {
+	return &DuplicatesModel{
+		DuplicateService: duplicateService,
+		durationDiff:     -1,
+	}
+}
+
+// SetSize loads duplicates the first time the tab is laid out.  Finding duplicates is expensive, so they are not
+// reloaded when the size changes.
+func (m *DuplicatesModel) SetSize(s Size) tea.Cmd {
+	m.screen = s
+	if m.loaded {
+		return nil
+	}
+	return m.updateCmd()
+}
+
+// manualRefresh keeps duplicates from being found again every time a scan completes.
+func (m *DuplicatesModel) manualRefresh() {}
+
+func (m *DuplicatesModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *DuplicatesModel) Title() string {
+	return fmt.Sprintf("%c Duplicates (%s)", '\U000f018f', humanNumber(len(m.groups)))
+}
+
+// Current returns the selected scene of the current group.
+func (m *DuplicatesModel) Current() stash.Scene {
+	return m.groups[m.group].scenes[m.index]
+}
+
+// PreviewPath returns the path of the screenshot of the selected scene, so that duplicates can be compared by eye.
+func (m *DuplicatesModel) PreviewPath() string {
+	if len(m.groups) == 0 {
+		return ""
+	}
+	return "/scene/" + m.Current().ID + "/screenshot"
+}
+
+var DuplicatesModelDefaultKeymap = map[string]string{
+	"up":    "skip -1",
+	"down":  "skip 1",
+	"left":  "group -1",
+	"right": "group 1",
+	"z":     "group -1",
+	"x":     "group 1",
+	"k":     "keep",
+	"m":     "merge",
+	"D":     "delete",
+	"enter": "open",
+	"o":     "open",
+	"`":     "open-url",
+}
+
+var DuplicatesModelCommandConfig command.Config = command.Config{
+	"delete":   binder[DuplicatesModelDeleteMsg](),
+	"filter":   binder[DuplicatesModelFilterMsg](),
+	"group":    binder[DuplicatesModelGroupMsg](),
+	"keep":     binder[DuplicatesModelKeepMsg](),
+	"merge":    binder[DuplicatesModelMergeMsg](),
+	"open":     binder[DuplicatesModelOpenMsg](),
+	"open-url": binder[DuplicatesModelOpenURLMsg](),
+	"refresh":  binder[DuplicatesModelRefresh](),
+	"skip":     binder[DuplicatesModelSkipMsg](),
+}
+
+func (m DuplicatesModel) CommandConfig() command.Config {
+	return DuplicatesModelCommandConfig
+}
+
+func (m DuplicatesModel) Search(query string) tea.Msg {
+	return DuplicatesModelFindMsg{Query: query}
+}
+
+// DuplicatesModelFilterMsg sets how closely scenes must match to be considered duplicates.  Distance is the maximum
+// phash distance, where 0 is an exact match, and Duration the maximum difference in seconds between file durations.  A
+// negative Duration matches files of any duration.
+type DuplicatesModelFilterMsg struct {
+	Distance *int
+	Duration *float64
+}
+
+// DuplicatesModelFindMsg moves to the next group containing a scene with a title or path matching Query.
+type DuplicatesModelFindMsg struct {
+	Query string
+}
+
+// DuplicatesModelGroupMsg moves between duplicate groups.
+type DuplicatesModelGroupMsg struct {
+	Count int `command:",positional"`
+}
+
+// DuplicatesModelSkipMsg moves between the scenes of the current group.
+type DuplicatesModelSkipMsg struct {
+	Count int `command:",positional"`
+}
+
+// DuplicatesModelKeepMsg sets the selected scene as the one to keep from the current group.
+type DuplicatesModelKeepMsg struct{}
+
+// DuplicatesModelMergeMsg merges the other scenes of the current group into the scene being kept.
+type DuplicatesModelMergeMsg struct{}
+
+// DuplicatesModelDeleteMsg deletes the other scenes of the current group, leaving only the scene being kept.
+type DuplicatesModelDeleteMsg struct {
+	Confirm bool
+}
+
+type DuplicatesModelOpenMsg struct{}
+
+type DuplicatesModelOpenURLMsg struct{}
+
+type DuplicatesModelRefresh struct{}
+
+type duplicatesLoadedMsg struct {
+	requestID uint64
+	groups    [][]stash.Scene
+}
+
+func (m *DuplicatesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case DuplicatesModelFilterMsg:
+		if msg.Distance != nil {
+			if *msg.Distance < 0 {
+				return m, NewErrorCmd(fmt.Errorf("distance must not be negative"))
+			}
+			m.distance = *msg.Distance
+		}
+		if msg.Duration != nil {
+			m.durationDiff = *msg.Duration
+		}
+		return m, m.updateCmd()
+
+	case DuplicatesModelFindMsg:
+		query := strings.ToLower(msg.Query)
+		for i := 1; i <= len(m.groups); i++ {
+			g := (m.group + i) % len(m.groups)
+			for j, scene := range m.groups[g].scenes {
+				if strings.Contains(strings.ToLower(sceneTitle(scene)), query) ||
+					strings.Contains(strings.ToLower(scene.FilePath()), query) {
+					m.group, m.index = g, j
+					return m, nil
+				}
+			}
+		}
+		return m, NewErrorCmd(fmt.Errorf("no duplicates matching '%s'", msg.Query))
+
+	case DuplicatesModelGroupMsg:
+		if len(m.groups) > 0 {
+			m.group = wrapIndex(m.group+msg.Count, len(m.groups))
+			m.index = 0
+		}
+
+	case DuplicatesModelSkipMsg:
+		if len(m.groups) > 0 {
+			m.index = wrapIndex(m.index+msg.Count, len(m.groups[m.group].scenes))
+		}
+
+	case DuplicatesModelKeepMsg:
+		if len(m.groups) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no duplicates"))
+		}
+		m.groups[m.group].keep = m.index
+
+	case DuplicatesModelMergeMsg:
+		if len(m.groups) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no duplicates"))
+		}
+		keep, others := m.groups[m.group].split()
+		return m, m.DuplicateService.MergeScenes(sceneIDs(others), keep.ID)
+
+	case DuplicatesModelDeleteMsg:
+		if len(m.groups) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no duplicates"))
+		}
+		_, others := m.groups[m.group].split()
+		titles := make([]string, len(others))
+		for i, scene := range others {
+			titles[i] = scene.FilePath()
+		}
+		return m, func() tea.Msg {
+			return deleteRequestMsg{
+				Entity:      fmt.Sprintf("%d duplicate scenes", len(others)),
+				Title:       markedTitles(titles),
+				SkipConfirm: msg.Confirm,
+				DeleteCmd:   m.DuplicateService.DeleteScenes(sceneIDs(others)),
+			}
+		}
+
+	case DuplicatesModelOpenMsg:
+		if len(m.groups) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no duplicates"))
+		}
+		scene := m.Current()
+		return m, func() tea.Msg { return OpenMsg{scene} }
+
+	case DuplicatesModelOpenURLMsg:
+		if len(m.groups) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no duplicates"))
+		}
+		src := path.Join("scenes", m.Current().ID)
+		return m, func() tea.Msg { return OpenMsg{src} }
+
+	case DuplicatesModelRefresh:
+		return m, m.updateCmd()
+
+	case tea.KeyMsg:
+		if cmd, ok := DuplicatesModelDefaultKeymap[msg.String()]; ok {
+			return m, func() tea.Msg { return ui.CommandExecMsg{Command: cmd} }
+		}
+
+	case duplicatesLoadedMsg:
+		if msg.requestID != m.listRequestID {
+			return m, nil
+		}
+		m.loaded = true
+		m.groups = make([]duplicateGroup, 0, len(msg.groups))
+		for _, scenes := range msg.groups {
+			m.groups = append(m.groups, duplicateGroup{scenes: scenes, keep: bestDuplicate(scenes)})
+		}
+		m.group, m.index = 0, 0
+
+	case scenesMergedMsg:
+		m.removeScenes(msg.source)
+
+	case scenesDeletedMsg:
+		m.removeScenes(msg.ids)
+	}
+
+	return m, nil
+}
+
+// removeScenes removes scenes that no longer exist from their groups.  Groups left with a single scene are no longer
+// duplicates and are removed entirely.
+func (m *DuplicatesModel) removeScenes(ids []string) {
+	groups := m.groups[:0]
+	for _, g := range m.groups {
+		keepID := g.scenes[g.keep].ID
+		var scenes []stash.Scene
+		for _, scene := range g.scenes {
+			if !containsString(ids, scene.ID) {
+				scenes = append(scenes, scene)
+			}
+		}
+		if len(scenes) < 2 {
+			continue
+		}
+		keep := 0
+		for i, scene := range scenes {
+			if scene.ID == keepID {
+				keep = i
+			}
+		}
+		groups = append(groups, duplicateGroup{scenes: scenes, keep: keep})
+	}
+	m.groups = groups
+	m.group = min(m.group, max(len(m.groups)-1, 0))
+	m.index = 0
+}
+
+// split returns the scene being kept from a group and the others.
+func (g duplicateGroup) split() (stash.Scene, []stash.Scene) {
+	var others []stash.Scene
+	for i, scene := range g.scenes {
+		if i != g.keep {
+			others = append(others, scene)
+		}
+	}
+	return g.scenes[g.keep], others
+}
+
+// bestDuplicate returns the index of the scene with the best primary file, preferring higher resolutions, then higher
+// bitrates and then larger files.
+func bestDuplicate(scenes []stash.Scene) int {
+	best := 0
+	for i := 1; i < len(scenes); i++ {
+		if betterFile(primaryFile(scenes[i]), primaryFile(scenes[best])) {
+			best = i
+		}
+	}
+	return best
+}
+
+func betterFile(a, b stash.VideoFile) bool {
+	if a.Width*a.Height != b.Width*b.Height {
+		return a.Width*a.Height > b.Width*b.Height
+	}
+	if a.BitRate != b.BitRate {
+		return a.BitRate > b.BitRate
+	}
+	return a.Size > b.Size
+}
+
+func primaryFile(s stash.Scene) stash.VideoFile {
+	if len(s.Files) == 0 {
+		return stash.VideoFile{}
+	}
+	return s.Files[0]
+}
+
+func sceneIDs(scenes []stash.Scene) []string {
+	ids := make([]string, len(scenes))
+	for i, scene := range scenes {
+		ids[i] = scene.ID
+	}
+	return ids
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// wrapIndex returns i wrapped around to within [0, n).
+func wrapIndex(i, n int) int {
+	return ((i % n) + n) % n
+}
+
+func (m DuplicatesModel) View() string {
+	var rows []ui.Row
+	if len(m.groups) > 0 {
+		g := m.groups[m.group]
+		for i, scene := range g.scenes {
+			file := primaryFile(scene)
+			keep := ""
+			if i == g.keep {
+				keep = check
+			}
+			rows = append(rows, ui.Row{
+				Values: []string{
+					keep,
+					sceneTitle(scene),
+					timestamp(file.Duration),
+					humanBytes(file.Size),
+					resolution(file),
+					codecs(file),
+					bitRate(file.BitRate),
+					file.Path,
+				},
+			})
+			if m.index == i {
+				rows[i].Background = &ColorRowSelected
+			}
+		}
+	}
+
+	leftStatus := []string{"no duplicates"}
+	if len(m.groups) > 0 {
+		leftStatus = []string{fmt.Sprintf("group %d of %d", m.group+1, len(m.groups))}
+	}
+
+	rightStatus := []string{fmt.Sprintf("distance %d", m.distance)}
+	if m.durationDiff >= 0 {
+		rightStatus = append(rightStatus, fmt.Sprintf("duration ±%gs", m.durationDiff))
+	}
+
+	return lipgloss.JoinVertical(0,
+		statusBar.Render(m.screen.Width, leftStatus, rightStatus),
+		duplicatesTable.Render(m.screen.Width, rows),
+	)
+}
+
+func (m *DuplicatesModel) updateCmd() tea.Cmd {
+	requestID := atomic.AddUint64(&m.listRequestID, 1)
+	cmd := m.DuplicateService.DuplicateScenes(m.distance, m.durationDiff)
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		return wrapDuplicatesLoadedMsg(cmd(), requestID)
+	}
+}
+
+func wrapDuplicatesLoadedMsg(msg tea.Msg, requestID uint64) tea.Msg {
+	switch msg := msg.(type) {
+	case duplicateScenesMsg:
+		return duplicatesLoadedMsg{requestID: requestID, groups: msg.groups}
+	case loadingMsg:
+		if payload, ok := msg.payload.(duplicateScenesMsg); ok {
+			msg.payload = duplicatesLoadedMsg{requestID: requestID, groups: payload.groups}
+		}
+		return msg
+	default:
+		return msg
+	}
+}
+
+var (
+	duplicatesTable = &ui.Table{
+		AltBackground: ColorBlack,
+		Cols: []ui.Column{
+			{
+				Name: "Keep",
+			},
+			{
+				Name:       "Title",
+				Foreground: &ColorOffWhite,
+				Bold:       true,
+				Weight:     1,
+			},
+			{
+				Name:       "Duration",
+				Foreground: &ColorGrey,
+				Align:      lipgloss.Right,
+			},
+			{
+				Name:       "Size",
+				Foreground: &ColorBlue,
+				Align:      lipgloss.Right,
+			},
+			{
+				Name:       "Resolution",
+				Foreground: &ColorYellow,
+				Align:      lipgloss.Right,
+			},
+			{
+				Name:       "Codec",
+				Foreground: &ColorPurple,
+			},
+			{
+				Name:       "Bitrate",
+				Foreground: &ColorSalmon,
+				Align:      lipgloss.Right,
+			},
+			{
+				Name:       "Path",
+				Foreground: &ColorGrey,
+				Flex:       true,
+			},
+		},
+	}
+)
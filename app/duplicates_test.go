@@ -0,0 +1,127 @@
+package app
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/drakenstar/stash-cli/stash"
+	"github.com/stretchr/testify/require"
+)
+
+type duplicateTestService struct {
+	distance     int
+	durationDiff float64
+	groups       [][]stash.Scene
+	merged       []string
+	into         string
+	deleted      []string
+	loads        int
+}
+
+func (s *duplicateTestService) DuplicateScenes(distance int, durationDiff float64) tea.Cmd {
+	s.distance, s.durationDiff = distance, durationDiff
+	s.loads++
+	return func() tea.Msg { return duplicateScenesMsg{groups: s.groups} }
+}
+
+func (s *duplicateTestService) MergeScenes(source []string, destination string) tea.Cmd {
+	s.merged, s.into = source, destination
+	return func() tea.Msg { return scenesMergedMsg{scene: stash.Scene{ID: destination}, source: source} }
+}
+
+func (s *duplicateTestService) DeleteScenes(ids []string) tea.Cmd {
+	return func() tea.Msg {
+		s.deleted = ids
+		return scenesDeletedMsg{ids: ids}
+	}
+}
+
+func newDuplicateTestModel() (*DuplicatesModel, *duplicateTestService) {
+	srv := &duplicateTestService{groups: [][]stash.Scene{
+		{
+			{ID: "1", Files: []stash.VideoFile{{Path: "/a.mp4", Width: 1280, Height: 720, Size: 900}}},
+			{ID: "2", Files: []stash.VideoFile{{Path: "/b.mp4", Width: 1920, Height: 1080, BitRate: 4000, Size: 500}}},
+			{ID: "3", Files: []stash.VideoFile{{Path: "/c.mp4", Width: 1920, Height: 1080, BitRate: 8000, Size: 400}}},
+		},
+		{
+			{ID: "4", Files: []stash.VideoFile{{Path: "/d.mp4", Size: 100}}},
+			{ID: "5", Files: []stash.VideoFile{{Path: "/e.mp4", Size: 200}}},
+		},
+	}}
+	m := NewDuplicatesModel(srv)
+	cmd := m.SetSize(Size{Width: 120, Height: 20})
+	m.Update(cmd())
+	return m, srv
+}
+
+func TestDuplicatesModelNotRefreshedOnScan(t *testing.T) {
+	dupes, srv := newDuplicateTestModel()
+	m := New(&stash.LocalStash{}, nil)
+	m.TabOpen(func(tabID) TabModel { return dupes })
+
+	_, cmd := m.Update(scanCompletedMsg{})
+	if cmd != nil {
+		cmd()
+	}
+	require.Equal(t, 1, srv.loads)
+}
+
+func TestDuplicatesModelLoad(t *testing.T) {
+	m, srv := newDuplicateTestModel()
+	require.Equal(t, -1.0, srv.durationDiff)
+	require.Len(t, m.groups, 2)
+	require.Equal(t, 2, m.groups[0].keep)
+	require.Equal(t, 1, m.groups[1].keep)
+
+	// Resizing does not search for duplicates again.
+	require.Nil(t, m.SetSize(Size{Width: 100, Height: 20}))
+
+	_, cmd := m.Update(DuplicatesModelFilterMsg{Distance: ptr(4), Duration: ptr(2.5)})
+	m.Update(cmd())
+	require.Equal(t, 4, srv.distance)
+	require.Equal(t, 2.5, srv.durationDiff)
+
+	_, cmd = m.Update(DuplicatesModelFilterMsg{Distance: ptr(-1)})
+	require.IsType(t, ErrorMsg{}, cmd())
+
+	m.Update(DuplicatesModelFindMsg{Query: "e.mp4"})
+	require.Equal(t, "5", m.Current().ID)
+	m.Update(DuplicatesModelGroupMsg{Count: 1})
+	require.Equal(t, "1", m.Current().ID)
+}
+
+func TestDuplicatesModelMerge(t *testing.T) {
+	m, srv := newDuplicateTestModel()
+	m.Update(DuplicatesModelKeepMsg{})
+	require.Equal(t, 0, m.groups[0].keep)
+
+	_, cmd := m.Update(DuplicatesModelMergeMsg{})
+	m.Update(cmd())
+	require.Equal(t, []string{"2", "3"}, srv.merged)
+	require.Equal(t, "1", srv.into)
+
+	// The group is no longer a duplicate once merged.
+	require.Len(t, m.groups, 1)
+	require.Equal(t, "4", m.Current().ID)
+}
+
+func TestDuplicatesModelDelete(t *testing.T) {
+	m, srv := newDuplicateTestModel()
+	m.Update(DuplicatesModelSkipMsg{Count: -1})
+	require.Equal(t, "3", m.Current().ID)
+
+	_, cmd := m.Update(DuplicatesModelDeleteMsg{})
+	request := cmd().(deleteRequestMsg)
+	require.Equal(t, "2 duplicate scenes", request.Entity)
+	require.Equal(t, "/a.mp4\n/b.mp4", request.Title)
+
+	m.Update(request.DeleteCmd())
+	require.Equal(t, []string{"1", "2"}, srv.deleted)
+	require.Len(t, m.groups, 1)
+}
+
+func TestSceneFilterStatusPHashDistance(t *testing.T) {
+	require.Equal(t, []string{"PHash distance is abc within 4"}, sceneFilterStatus(stash.SceneFilter{
+		PHashDistance: &stash.PHashDistanceCriterion{Value: "abc", Modifier: stash.CriterionModifierEquals, Distance: ptr(4)},
+	}, deleteTestLookup{}))
+}
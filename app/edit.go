@@ -0,0 +1,342 @@
+package app
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/drakenstar/stash-cli/stash"
+	"github.com/kballard/go-shellquote"
+	"gopkg.in/yaml.v3"
+)
+
+// EditService applies the changes made to a scene or gallery in an editor.
+type EditService interface {
+	EditScene(stash.Scene, []byte) tea.Cmd
+	EditGallery(stash.Gallery, []byte) tea.Cmd
+}
+
+// EditMsg opens the metadata of the current item as YAML in $EDITOR, and saves any changes made once the editor exits.
+type EditMsg struct{}
+
+// sceneEditedMsg is received with the document of scene once it has been changed in an editor.
+type sceneEditedMsg struct {
+	scene    stash.Scene
+	document []byte
+}
+
+// galleryEditedMsg is received with the document of gallery once it has been changed in an editor.
+type galleryEditedMsg struct {
+	gallery  stash.Gallery
+	document []byte
+}
+
+// sceneDocument is the editable metadata of a scene.  Studios, performers and tags are given by name.
+type sceneDocument struct {
+	Title      string   `yaml:"title"`
+	Code       string   `yaml:"code"`
+	Date       string   `yaml:"date"`
+	Director   string   `yaml:"director"`
+	Rating     int      `yaml:"rating"`
+	Organised  bool     `yaml:"organised"`
+	Studio     string   `yaml:"studio"`
+	URLs       []string `yaml:"urls"`
+	Performers []string `yaml:"performers"`
+	Tags       []string `yaml:"tags"`
+	Details    string   `yaml:"details"`
+}
+
+// galleryDocument is the editable metadata of a gallery.  Studios, performers and tags are given by name.
+type galleryDocument struct {
+	Title      string   `yaml:"title"`
+	Date       string   `yaml:"date"`
+	Rating     int      `yaml:"rating"`
+	Organised  bool     `yaml:"organised"`
+	Studio     string   `yaml:"studio"`
+	URL        string   `yaml:"url"`
+	Performers []string `yaml:"performers"`
+	Tags       []string `yaml:"tags"`
+	Details    string   `yaml:"details"`
+}
+
+// editDocumentHeader is written above a document to explain how it is saved.
+const editDocumentHeader = `# Save and exit to apply changes, or exit without saving to discard them.
+# Studios, performers and tags are given by name or ID.  Tags that do not exist are created.
+`
+
+func newSceneDocument(scene stash.Scene) sceneDocument {
+	return sceneDocument{
+		Title:      scene.Title,
+		Code:       scene.Code,
+		Date:       scene.Date,
+		Director:   scene.Director,
+		Rating:     scene.Rating,
+		Organised:  scene.Organized,
+		Studio:     scene.Studio.Name,
+		URLs:       scene.URLs,
+		Performers: documentPerformers(scene.Performers),
+		Tags:       documentTags(scene.Tags),
+		Details:    scene.Details,
+	}
+}
+
+func newGalleryDocument(gallery stash.Gallery) galleryDocument {
+	return galleryDocument{
+		Title:      gallery.Title,
+		Date:       gallery.Date,
+		Rating:     gallery.Rating,
+		Organised:  gallery.Organized,
+		Studio:     gallery.Studio.Name,
+		URL:        gallery.URL,
+		Performers: documentPerformers(gallery.Performers),
+		Tags:       documentTags(gallery.Tags),
+		Details:    gallery.Details,
+	}
+}
+
+func documentPerformers(performers []stash.Performer) []string {
+	names := make([]string, len(performers))
+	for i, p := range performers {
+		names[i] = performerName(p)
+	}
+	return names
+}
+
+func documentTags(tags []stash.Tag) []string {
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = tag.Name
+	}
+	return names
+}
+
+// editDocument returns a command that writes doc as YAML to a temporary file and opens it in an editor, suspending the
+// program until the editor exits.  done is called with the file as saved, unless it was left unchanged.
+func editDocument(doc any, done func([]byte) tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		body, err := yaml.Marshal(doc)
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		original := append([]byte(editDocumentHeader), body...)
+
+		f, err := os.CreateTemp("", "stash-cli-*.yaml")
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		path := f.Name()
+		_, err = f.Write(original)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(path)
+			return ErrorMsg{err}
+		}
+
+		cmd, err := editorCommand(path)
+		if err != nil {
+			os.Remove(path)
+			return ErrorMsg{err}
+		}
+		// The message of ExecProcess is returned directly so that the temporary file exists before the editor runs.
+		return tea.ExecProcess(cmd, func(err error) tea.Msg {
+			defer os.Remove(path)
+			if err != nil {
+				return ErrorMsg{fmt.Errorf("editor failed: %w", err)}
+			}
+			edited, err := os.ReadFile(path)
+			if err != nil {
+				return ErrorMsg{err}
+			}
+			if bytes.Equal(edited, original) {
+				return nil
+			}
+			return done(edited)
+		})()
+	}
+}
+
+// editorCommand returns the command to edit path with, taken from $VISUAL or $EDITOR and falling back to vi.
+func editorCommand(path string) (*exec.Cmd, error) {
+	editor := cmp.Or(os.Getenv("VISUAL"), os.Getenv("EDITOR"), "vi")
+	parts, err := shellquote.Split(editor)
+	if err != nil {
+		return nil, fmt.Errorf("invalid editor '%s': %w", editor, err)
+	}
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("no editor set")
+	}
+	return exec.Command(parts[0], append(parts[1:], path)...), nil
+}
+
+// validateDocument checks the fields of a document that stash only accepts in a certain form.
+func validateDocument(date string, rating int) error {
+	if date != "" {
+		if _, err := time.Parse(time.DateOnly, date); err != nil {
+			return fmt.Errorf("date must be given as YYYY-MM-DD")
+		}
+	}
+	if rating < 0 || rating > 100 {
+		return fmt.Errorf("rating must be between 0 and 100")
+	}
+	return nil
+}
+
+// editedText returns the edited value of a text field.  Surrounding whitespace, such as the trailing newline of a YAML
+// block, is ignored so that it is not taken as a change.
+func editedText(current, edited string) string {
+	edited = strings.TrimSpace(edited)
+	if strings.TrimSpace(current) == edited {
+		return current
+	}
+	return edited
+}
+
+// EditScene applies an edited scene document to scene.
+func (s *cmdService) EditScene(scene stash.Scene, document []byte) tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		var doc sceneDocument
+		if err := yaml.Unmarshal(document, &doc); err != nil {
+			return ErrorMsg{fmt.Errorf("invalid scene document: %w", err)}
+		}
+		doc.Date = strings.TrimSpace(doc.Date)
+		if err := validateDocument(doc.Date, doc.Rating); err != nil {
+			return ErrorMsg{err}
+		}
+
+		updated := scene
+		updated.Title = editedText(scene.Title, doc.Title)
+		updated.Code = editedText(scene.Code, doc.Code)
+		updated.Date = doc.Date
+		updated.Director = editedText(scene.Director, doc.Director)
+		updated.Rating = doc.Rating
+		updated.Organized = doc.Organised
+		updated.URLs = splitDocumentList(doc.URLs)
+		updated.Details = editedText(scene.Details, doc.Details)
+		err := s.resolveDocumentEntities(context.Background(), doc.Studio, doc.Tags, doc.Performers, &updated.Studio, &updated.Tags, &updated.Performers)
+		if err != nil {
+			return ErrorMsg{err}
+		}
+
+		result, err := s.Stash.SceneUpdate(context.Background(), stash.NewSceneUpdate(scene, updated))
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		return sceneUpdatedMsg{scene: result}
+	})
+}
+
+// EditGallery applies an edited gallery document to gallery.
+func (s *cmdService) EditGallery(gallery stash.Gallery, document []byte) tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		var doc galleryDocument
+		if err := yaml.Unmarshal(document, &doc); err != nil {
+			return ErrorMsg{fmt.Errorf("invalid gallery document: %w", err)}
+		}
+		doc.Date = strings.TrimSpace(doc.Date)
+		if err := validateDocument(doc.Date, doc.Rating); err != nil {
+			return ErrorMsg{err}
+		}
+
+		updated := gallery
+		updated.Title = editedText(gallery.Title, doc.Title)
+		updated.Date = doc.Date
+		updated.Rating = doc.Rating
+		updated.Organized = doc.Organised
+		updated.URL = strings.TrimSpace(doc.URL)
+		updated.Details = editedText(gallery.Details, doc.Details)
+		err := s.resolveDocumentEntities(context.Background(), doc.Studio, doc.Tags, doc.Performers, &updated.Studio, &updated.Tags, &updated.Performers)
+		if err != nil {
+			return ErrorMsg{err}
+		}
+
+		result, err := s.Stash.GalleryUpdate(context.Background(), stash.NewGalleryUpdate(gallery, updated))
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		return galleryTaggedMsg{gallery: result}
+	})
+}
+
+// resolveDocumentEntities replaces the studio, tags and performers of an item with those named in a document.  Names
+// already on the item are kept as they are, and otherwise are looked up by name or ID.  Tags that do not exist are
+// created, while studios and performers must already exist.
+func (s *cmdService) resolveDocumentEntities(ctx context.Context, studioName string, tagNames, performerNames []string, studio *stash.Studio, tags *[]stash.Tag, performers *[]stash.Performer) error {
+	studioName = strings.TrimSpace(studioName)
+	switch {
+	case studioName == "":
+		*studio = stash.Studio{}
+	case strings.EqualFold(studioName, studio.Name) || studioName == studio.ID:
+	default:
+		ids, err := resolveEntityInputs([]string{studioName}, s.StudioFindByName)
+		if err != nil {
+			return fmt.Errorf("studio resolution failed: %w", err)
+		}
+		*studio = stash.Studio{ID: ids[0], Name: studioName}
+	}
+
+	resolvedTags := make([]stash.Tag, 0, len(tagNames))
+	for _, name := range splitDocumentList(tagNames) {
+		if i := findEntity(*tags, name, func(t stash.Tag) string { return t.Name }); i >= 0 {
+			resolvedTags = append(resolvedTags, (*tags)[i])
+			continue
+		}
+		created, err := s.resolveOrCreateTags(ctx, []string{name})
+		if err != nil {
+			return fmt.Errorf("tag resolution failed: %w", err)
+		}
+		resolvedTags = append(resolvedTags, created...)
+	}
+	*tags = resolvedTags
+
+	resolvedPerformers := make([]stash.Performer, 0, len(performerNames))
+	for _, name := range splitDocumentList(performerNames) {
+		if i := findEntity(*performers, name, performerName); i >= 0 {
+			resolvedPerformers = append(resolvedPerformers, (*performers)[i])
+			continue
+		}
+		ids, err := resolveEntityInputs([]string{name}, s.PerformerFindByName)
+		if err != nil {
+			return fmt.Errorf("performer resolution failed: %w", err)
+		}
+		resolvedPerformers = append(resolvedPerformers, stash.Performer{ID: ids[0], Name: name})
+	}
+	*performers = resolvedPerformers
+	return nil
+}
+
+// findEntity returns the index of the entity in list with the given name or ID, or -1 if there is none.
+func findEntity[T interface{ EntityID() string }](list []T, name string, nameOf func(T) string) int {
+	for i, entity := range list {
+		if entity.EntityID() == name || strings.EqualFold(nameOf(entity), name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// splitDocumentList trims each value of a document list, dropping empty values.
+func splitDocumentList(values []string) []string {
+	var list []string
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			list = append(list, value)
+		}
+	}
+	return list
+}
+
+func (s *cmdServiceWithID) EditScene(scene stash.Scene, document []byte) tea.Cmd {
+	return s.withID(s.s.EditScene(scene, document))
+}
+
+func (s *cmdServiceWithID) EditGallery(gallery stash.Gallery, document []byte) tea.Cmd {
+	return s.withID(s.s.EditGallery(gallery, document))
+}
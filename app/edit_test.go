@@ -0,0 +1,137 @@
+package app
+
+import (
+	"encoding/json"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/drakenstar/stash-cli/command"
+	"github.com/drakenstar/stash-cli/stash"
+	"github.com/hasura/go-graphql-client"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+type editTestService struct{}
+
+func (editTestService) EditScene(stash.Scene, []byte) tea.Cmd     { return nil }
+func (editTestService) EditGallery(stash.Gallery, []byte) tea.Cmd { return nil }
+
+func TestEditCommand(t *testing.T) {
+	msg, err := ScenesModelCommandConfig.Resolve(command.Parser(`edit`))
+	require.NoError(t, err)
+	require.Equal(t, EditMsg{}, msg)
+
+	msg, err = GalleriesModelCommandConfig.Resolve(command.Parser(`edit`))
+	require.NoError(t, err)
+	require.Equal(t, EditMsg{}, msg)
+}
+
+func TestSceneDocument(t *testing.T) {
+	out, err := yaml.Marshal(newSceneDocument(stash.Scene{
+		Title:      "A title",
+		Details:    "First line.\nSecond line.",
+		Performers: []stash.Performer{{ID: "7", Name: "Jane", Disambiguation: "II"}},
+	}))
+	require.NoError(t, err)
+	require.Equal(t, `title: A title
+code: ""
+date: ""
+director: ""
+rating: 0
+organised: false
+studio: ""
+urls: []
+performers:
+    - Jane (II)
+tags: []
+details: |-
+    First line.
+    Second line.
+`, string(out))
+}
+
+func TestEditorCommand(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", `code --wait "--new window"`)
+	cmd, err := editorCommand("/tmp/scene.yaml")
+	require.NoError(t, err)
+	require.Equal(t, []string{"code", "--wait", "--new window", "/tmp/scene.yaml"}, cmd.Args)
+
+	t.Setenv("EDITOR", "")
+	cmd, err = editorCommand("/tmp/scene.yaml")
+	require.NoError(t, err)
+	require.Equal(t, []string{"vi", "/tmp/scene.yaml"}, cmd.Args)
+}
+
+// newEditTestService returns a service where the studio and performer named in edits are already cached.
+func newEditTestService(backend stash.Stash) *cmdService {
+	svc := &cmdService{Stash: backend, cache: newCacheLookup()}
+	svc.cache.CacheStudio(stash.Studio{ID: "40", Name: "Studio"})
+	svc.cache.CachePerformers([]stash.Performer{{ID: "30", Name: "Anna"}})
+	return svc
+}
+
+func TestEditScene(t *testing.T) {
+	backend := &scrapeTestStash{}
+	svc := newEditTestService(backend)
+	scene := stash.Scene{
+		ID:         "1",
+		Title:      "Old",
+		Details:    "Some details.",
+		Tags:       []stash.Tag{{ID: "1", Name: "Outdoors"}},
+		Performers: []stash.Performer{{ID: "7", Name: "Jane", Disambiguation: "II"}},
+	}
+
+	msg := svc.EditScene(scene, []byte(`
+title: New
+date: 2024-01-02
+rating: 80
+studio: Studio
+performers: [Jane (II), Anna]
+tags: [outdoors, Sunset]
+details: |
+  Some details.
+`))()
+	require.IsType(t, sceneUpdatedMsg{}, msg)
+	require.Equal(t, "New", *backend.update.Title)
+	require.Equal(t, "2024-01-02", *backend.update.Date)
+	require.Equal(t, 80, *backend.update.Rating)
+	require.Nil(t, backend.update.Details)
+	require.Equal(t, graphql.ID("40"), *backend.update.StudioID)
+	require.Equal(t, []graphql.ID{"7", "30"}, *backend.update.PerformerIDs)
+	require.Equal(t, []graphql.ID{"1", "20"}, *backend.update.TagIDs)
+	require.Equal(t, []string{"tag Sunset"}, backend.created)
+
+	// Emptying a list removes everything in it, rather than leaving it unchanged.
+	scene.URLs = []string{"https://example.com/1"}
+	msg = svc.EditScene(scene, []byte("title: Old\nurls: []\nperformers: []\ntags: [Outdoors]\ndetails: Some details.\n"))()
+	require.IsType(t, sceneUpdatedMsg{}, msg)
+	body, err := json.Marshal(backend.update)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"id":"1","urls":[],"performer_ids":[]}`, string(body))
+
+	require.IsType(t, ErrorMsg{}, svc.EditScene(scene, []byte(`date: 02/01/2024`))())
+	require.IsType(t, ErrorMsg{}, svc.EditScene(scene, []byte(`rating: 101`))())
+	require.IsType(t, ErrorMsg{}, svc.EditScene(scene, []byte(`title: [`))())
+}
+
+func TestEditGallery(t *testing.T) {
+	backend := &scrapeTestStash{}
+	svc := newEditTestService(backend)
+	gallery := stash.Gallery{ID: "2", Title: "Old", Studio: stash.Studio{ID: "5", Name: "Studio"}}
+
+	msg := svc.EditGallery(gallery, []byte("title: Old\nstudio: \"\"\nurl: https://example.com/2\n"))()
+	require.IsType(t, galleryTaggedMsg{}, msg)
+	require.Nil(t, backend.gUpdate.Title)
+	require.Equal(t, "https://example.com/2", *backend.gUpdate.URL)
+	require.Equal(t, graphql.ID(""), *backend.gUpdate.StudioID)
+
+	gallery.URL = "https://example.com/2"
+	gallery.Performers = []stash.Performer{{ID: "30", Name: "Anna"}}
+	msg = svc.EditGallery(gallery, []byte("title: Old\nstudio: Studio\nurl: \"\"\nperformers: []\n"))()
+	require.IsType(t, galleryTaggedMsg{}, msg)
+	body, err := json.Marshal(backend.gUpdate)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"id":"2","url":"","performer_ids":[]}`, string(body))
+}
@@ -1,8 +1,10 @@
 package app
 
 import (
+	"cmp"
 	"fmt"
 	"path"
+	"reflect"
 	"strings"
 	"sync/atomic"
 
@@ -26,9 +28,17 @@ type GalleryService interface {
 	Galleries(stash.FindFilter, stash.GalleryFilter) tea.Cmd
 	DeleteGallery(string) tea.Cmd
 	TagGallery(stash.Gallery, []string) tea.Cmd
+	RetagGallery(stash.Gallery, []string) tea.Cmd
+	TagGalleries([]string, []string) tea.Cmd
+	RetagGalleries([]string, []string) tea.Cmd
+	UpdateGalleries(stash.BulkGalleryUpdate) tea.Cmd
+	DeleteGalleries([]string) tea.Cmd
 	ResolveTags([]string) tea.Cmd
 	ResolveStudios([]string) tea.Cmd
 	ResolvePerformers([]string) tea.Cmd
+	SavedFilterService
+	ScrapeService
+	EditService
 }
 
 type GalleriesModel struct {
@@ -37,6 +47,7 @@ type GalleriesModel struct {
 
 	pageState pageState
 	galleries []stash.Gallery
+	marks     markSet[stash.Gallery]
 
 	query         string
 	sort          string
@@ -44,6 +55,8 @@ type GalleriesModel struct {
 	galleryFilter stash.GalleryFilter
 
 	history []galleryFilterState
+	// defaultState is the state of the default filter for galleries, which is used on reset once loaded.
+	defaultState *galleryFilterState
 
 	screen Size
 
@@ -76,16 +89,56 @@ func (m *GalleriesModel) Current() stash.Gallery {
 	return m.galleries[m.pageState.index]
 }
 
+// PreviewPath returns the path of the cover of the current gallery.
+func (m *GalleriesModel) PreviewPath() string {
+	if len(m.galleries) == 0 {
+		return ""
+	}
+	return "/gallery/" + m.Current().ID + "/cover"
+}
+
 func (m *GalleriesModel) reset() tea.Cmd {
-	m.query = ""
-	m.sort = stash.SortPath
-	m.sortDirection = stash.SortDirectionAsc
-	m.galleryFilter = stash.GalleryFilter{}
+	state := galleryFilterState{
+		sort:          stash.SortPath,
+		sortDirection: stash.SortDirectionAsc,
+	}
+	if m.defaultState != nil {
+		state = *m.defaultState
+	}
+	m.query = state.query
+	m.sort = state.sort
+	m.sortDirection = state.sortDirection
+	m.galleryFilter = state.galleryFilter
 	m.pageState.Reset()
 
 	return m.updateCmd()
 }
 
+// isReset returns true if the model has not been filtered since it was reset.
+func (m *GalleriesModel) isReset() bool {
+	return len(m.history) == 0 &&
+		m.query == "" &&
+		m.sort == stash.SortPath &&
+		m.sortDirection == stash.SortDirectionAsc &&
+		reflect.ValueOf(m.galleryFilter).IsZero()
+}
+
+// savedGalleryFilterState returns the state of a saved gallery filter.  The default sort is used if it has none.
+func savedGalleryFilterState(saved stash.SavedFilter) (galleryFilterState, error) {
+	filter, err := saved.GalleryFilter()
+	if err != nil {
+		return galleryFilterState{}, err
+	}
+	find := savedFindFilter(saved)
+	state := galleryFilterState{
+		query:         find.Query,
+		sort:          cmp.Or(find.Sort, stash.SortPath),
+		sortDirection: cmp.Or(find.Direction, stash.SortDirectionAsc),
+		galleryFilter: filter,
+	}
+	return state, nil
+}
+
 func (m *GalleriesModel) SetSize(s Size) tea.Cmd {
 	m.screen = s
 	m.pageState.SetPerPage(s.Height - 1) // account for status line
@@ -93,7 +146,7 @@ func (m *GalleriesModel) SetSize(s Size) tea.Cmd {
 }
 
 func (m *GalleriesModel) Init() tea.Cmd {
-	return nil
+	return m.GalleryService.DefaultFilter(stash.FilterModeGalleries)
 }
 
 func (m *GalleriesModel) Title() string {
@@ -154,6 +207,7 @@ var GalleriesModelDefaultKeymap = map[string]string{
 	"D":     "delete",
 	"enter": "open skip",
 	" ":     "open skip", // space
+	"i":     "images",
 	"z":     "skip -1",
 	"x":     "skip 1",
 	"o":     "open",
@@ -161,6 +215,10 @@ var GalleriesModelDefaultKeymap = map[string]string{
 	"u":     "undo", // state pop?  Maybe some sort of generic state management command
 	"f":     "filter favourite=1",
 	"p":     "filter performer=current",
+	"O":     "organise",
+	"v":     "mark",
+	"V":     "mark all",
+	"esc":   "mark clear",
 	"`":     "open-url source=stash",
 }
 
@@ -173,14 +231,22 @@ var GalleriesModelDefaultCommandAlias = map[string]string{
 
 var GalleriesModelCommandConfig command.Config = command.Config{
 	"delete":   binder[GalleriesModelDeleteMsg](),
-	"filter":   binder[GalleriesModelFilterMsg](),
+	"edit":     binder[EditMsg](),
+	"filter":   savedFilterCommand(binder[GalleriesModelFilterMsg]()),
+	"images":   binder[GalleriesModelImagesMsg](),
+	"mark":     binder[GalleriesModelMarkMsg](),
 	"open":     binder[GalleriesModelOpenMsg](),
 	"open-url": binder[GalleriesModelOpenURLMsg](),
+	"organise": binder[GalleriesModelOrganiseMsg](),
+	"rate":     binder[GalleriesModelRateMsg](),
 	"refresh":  binder[GalleriesModelRefresh](),
 	"reset":    binder[GalleriesModelResetMsg](),
 	"sort":     binder[GalleriesModelSortMsg](),
 	"skip":     binder[GalleriesModelSkipMsg](),
+	"retag":    binder[GalleriesModelRetagMsg](),
+	"scrape":   binder[ScrapeMsg](),
 	"tag":      binder[GalleriesModelTagMsg](),
+	"untag":    binder[GalleriesModelUntagMsg](),
 	"undo":     binder[GalleriesModelUndoMsg](),
 }
 
@@ -233,18 +299,47 @@ type GalleriesModelOpenMsg struct {
 	Skip bool `command:",positional"`
 }
 
+// GalleriesModelImagesMsg opens a new images tab listing the images of the current gallery.
+type GalleriesModelImagesMsg struct{}
+
 type GalleriesModelOpenURLMsg struct {
 	Source string
 }
 
+// GalleriesModelMarkMsg toggles the mark on the current gallery.  While any galleries are marked the tag, untag, retag,
+// rate, organise and delete commands act on all marked galleries rather than the current one.  A Target of "all" marks
+// every gallery on the page, and "clear" removes all marks.
+type GalleriesModelMarkMsg struct {
+	Target string `command:",positional"`
+}
+
+// GalleriesModelRateMsg sets the rating of the current or marked galleries, out of 100.
+type GalleriesModelRateMsg struct {
+	Rating int `command:",positional"`
+}
+
+// GalleriesModelOrganiseMsg toggles whether the current or marked galleries are organised.
+type GalleriesModelOrganiseMsg struct{}
+
 type GalleriesModelDeleteMsg struct {
 	Confirm bool
 }
 
+// GalleriesModelTagMsg adds tags to the current gallery.  Tags prefixed with '-' are removed instead.
 type GalleriesModelTagMsg struct {
 	Tags []string `command:",positional"`
 }
 
+// GalleriesModelUntagMsg removes tags from the current gallery.
+type GalleriesModelUntagMsg struct {
+	Tags []string `command:",positional"`
+}
+
+// GalleriesModelRetagMsg replaces all tags of the current gallery.
+type GalleriesModelRetagMsg struct {
+	Tags []string `command:",positional"`
+}
+
 type GalleriesModelRefresh struct{}
 
 type GalleriesModelResetMsg struct{}
@@ -363,6 +458,13 @@ func (m *GalleriesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cur := m.Current()
 		return m, func() tea.Msg { return OpenMsg{cur} }
 
+	case GalleriesModelImagesMsg:
+		if len(m.galleries) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no gallery selected"))
+		}
+		gallery := m.Current()
+		return m, func() tea.Msg { return openGalleryImagesTabMsg(gallery) }
+
 	case GalleriesModelOpenURLMsg:
 		cur := m.Current()
 		var src string
@@ -372,7 +474,68 @@ func (m *GalleriesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, func() tea.Msg { return OpenMsg{src} }
 
+	case GalleriesModelMarkMsg:
+		switch msg.Target {
+		case "":
+			if len(m.galleries) == 0 {
+				return m, NewErrorCmd(fmt.Errorf("no gallery selected"))
+			}
+			m.marks.Toggle(m.Current())
+		case "all":
+			for _, gallery := range m.galleries {
+				m.marks.Mark(gallery)
+			}
+		case "clear":
+			m.marks.Clear()
+		default:
+			return m, NewErrorCmd(fmt.Errorf("unknown mark target '%s'", msg.Target))
+		}
+
+	case GalleriesModelRateMsg:
+		galleries := m.targets()
+		if len(galleries) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no gallery selected"))
+		}
+		if msg.Rating < 1 || msg.Rating > 100 {
+			return m, NewErrorCmd(fmt.Errorf("rating must be between 1 and 100"))
+		}
+		update := stash.NewBulkGalleryUpdate(galleryIDs(galleries))
+		update.Rating = &msg.Rating
+		return m, m.GalleryService.UpdateGalleries(update)
+
+	case GalleriesModelOrganiseMsg:
+		galleries := m.targets()
+		if len(galleries) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no gallery selected"))
+		}
+		// Galleries are all organised, unless they already all are in which case they are all unorganised.
+		organised := false
+		for _, gallery := range galleries {
+			if !gallery.Organized {
+				organised = true
+				break
+			}
+		}
+		update := stash.NewBulkGalleryUpdate(galleryIDs(galleries))
+		update.Organized = &organised
+		return m, m.GalleryService.UpdateGalleries(update)
+
 	case GalleriesModelDeleteMsg:
+		if m.marks.Len() > 0 {
+			galleries := m.marks.Items()
+			titles := make([]string, len(galleries))
+			for i, gallery := range galleries {
+				titles[i] = galleryTitle(gallery)
+			}
+			return m, func() tea.Msg {
+				return deleteRequestMsg{
+					Entity:      fmt.Sprintf("%d galleries", len(galleries)),
+					Title:       markedTitles(titles),
+					SkipConfirm: msg.Confirm,
+					DeleteCmd:   m.GalleryService.DeleteGalleries(m.marks.IDs()),
+				}
+			}
+		}
 		if len(m.galleries) == 0 {
 			return m, NewErrorCmd(fmt.Errorf("no gallery selected"))
 		}
@@ -387,6 +550,39 @@ func (m *GalleriesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+	case EditMsg:
+		if len(m.galleries) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no gallery selected"))
+		}
+		gallery := m.Current()
+		return m, editDocument(newGalleryDocument(gallery), func(document []byte) tea.Msg {
+			return galleryEditedMsg{gallery: gallery, document: document}
+		})
+
+	case galleryEditedMsg:
+		return m, m.GalleryService.EditGallery(msg.gallery, msg.document)
+
+	case ScrapeMsg:
+		if len(m.galleries) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no gallery selected"))
+		}
+		return m, m.GalleryService.ScrapeGallery(m.Current(), msg.Source)
+
+	case galleryScrapedMsg:
+		changes := galleryScrapeChanges(msg.gallery, msg.scraped)
+		if len(changes) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("scraped metadata matches the gallery"))
+		}
+		return m, func() tea.Msg {
+			return scrapeReviewMsg{
+				title:   msg.gallery.Title,
+				changes: changes,
+				apply: func(accepted []scrapeChange) tea.Cmd {
+					return m.GalleryService.ApplyGalleryScrape(msg.gallery, accepted)
+				},
+			}
+		}
+
 	case GalleriesModelTagMsg:
 		if len(m.galleries) == 0 {
 			return m, NewErrorCmd(fmt.Errorf("no gallery selected"))
@@ -394,14 +590,92 @@ func (m *GalleriesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if len(msg.Tags) == 0 {
 			return m, NewErrorCmd(fmt.Errorf("no tags specified"))
 		}
+		if m.marks.Len() > 0 {
+			return m, m.GalleryService.TagGalleries(m.marks.IDs(), msg.Tags)
+		}
 		return m, m.GalleryService.TagGallery(m.Current(), msg.Tags)
 
+	case GalleriesModelUntagMsg:
+		if len(m.galleries) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no gallery selected"))
+		}
+		if len(msg.Tags) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no tags specified"))
+		}
+		if m.marks.Len() > 0 {
+			return m, m.GalleryService.TagGalleries(m.marks.IDs(), untagNames(msg.Tags))
+		}
+		return m, m.GalleryService.TagGallery(m.Current(), untagNames(msg.Tags))
+
+	case GalleriesModelRetagMsg:
+		if len(m.galleries) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no gallery selected"))
+		}
+		if len(msg.Tags) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no tags specified"))
+		}
+		if m.marks.Len() > 0 {
+			return m, m.GalleryService.RetagGalleries(m.marks.IDs(), msg.Tags)
+		}
+		return m, m.GalleryService.RetagGallery(m.Current(), msg.Tags)
+
 	case GalleriesModelRefresh:
 		return m, m.updateCmd()
 
 	case GalleriesModelResetMsg:
 		return m, m.reset()
 
+	case defaultFilterMsg:
+		state, err := savedGalleryFilterState(msg.filter)
+		if err != nil {
+			return m, NewErrorCmd(fmt.Errorf("default filter: %w", err))
+		}
+		// Tabs that have been filtered since they opened, such as those showing a performer, are left as they are.
+		apply := m.isReset()
+		m.defaultState = &state
+		if apply {
+			return m, m.reset()
+		}
+
+	case FilterLoadMsg:
+		return m, m.GalleryService.LoadFilter(stash.FilterModeGalleries, msg.Name)
+
+	case savedFilterMsg:
+		state, err := savedGalleryFilterState(msg.filter)
+		if err != nil {
+			return m, NewErrorCmd(fmt.Errorf("filter %s: %w", msg.filter.Name, err))
+		}
+		return m.PushState(func(gm *GalleriesModel) {
+			gm.query = state.query
+			gm.sort = state.sort
+			gm.sortDirection = state.sortDirection
+			gm.galleryFilter = state.galleryFilter
+		})
+
+	case FilterSaveMsg:
+		if msg.Name == "" {
+			return m, NewErrorCmd(fmt.Errorf("no filter name specified"))
+		}
+		objectFilter, err := stash.ObjectFilter(m.galleryFilter)
+		if err != nil {
+			return m, NewErrorCmd(err)
+		}
+		return m, m.GalleryService.SaveFilter(stash.SaveFilter{
+			Mode: stash.FilterModeGalleries,
+			Name: msg.Name,
+			FindFilter: &stash.FindFilter{
+				Query:     m.query,
+				Page:      1,
+				PerPage:   m.pageState.PerPage,
+				Sort:      m.sort,
+				Direction: m.sortDirection,
+			},
+			ObjectFilter: objectFilter,
+		})
+
+	case FilterDeleteMsg:
+		return m, m.GalleryService.DeleteFilter(stash.FilterModeGalleries, msg.Name)
+
 	case GalleriesModelSortMsg:
 		switch msg.Field {
 		case "random":
@@ -447,15 +721,53 @@ func (m *GalleriesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.pageState.DeleteCurrent()
 		return m, m.updateCmd()
 
+	case galleriesDeletedMsg:
+		m.marks.Clear()
+		m.pageState.Delete(len(msg.ids))
+		return m, m.updateCmd()
+
+	case galleriesUpdatedMsg:
+		for _, updated := range msg.galleries {
+			m.marks.Update(updated)
+			for i, gallery := range m.galleries {
+				if gallery.ID == updated.ID {
+					m.galleries[i] = updated
+				}
+			}
+		}
+
 	case galleryTaggedMsg:
-		if len(m.galleries) > 0 {
-			m.galleries[m.pageState.index] = msg.gallery
+		// Galleries are matched by ID, since a scrape may be applied after the current gallery has changed.
+		m.marks.Update(msg.gallery)
+		for i, gallery := range m.galleries {
+			if gallery.ID == msg.gallery.ID {
+				m.galleries[i] = msg.gallery
+			}
 		}
 	}
 
 	return m, nil
 }
 
+// targets returns the marked galleries, or the current gallery if none are marked.
+func (m *GalleriesModel) targets() []stash.Gallery {
+	if m.marks.Len() > 0 {
+		return m.marks.Items()
+	}
+	if len(m.galleries) == 0 {
+		return nil
+	}
+	return []stash.Gallery{m.Current()}
+}
+
+func galleryIDs(galleries []stash.Gallery) []string {
+	ids := make([]string, len(galleries))
+	for i, gallery := range galleries {
+		ids[i] = gallery.ID
+	}
+	return ids
+}
+
 func (m *GalleriesModel) filterNeedsAsyncResolution(msg GalleriesModelFilterMsg) bool {
 	return needsEntityResolution(msg.Tag) ||
 		needsSingleEntityResolution(msg.Studio) ||
@@ -647,7 +959,9 @@ func (m GalleriesModel) View() string {
 				performerList(gallery.Performers),
 				tagList(gallery.Tags),
 				details(gallery.Details),
-			}})
+			},
+			Marked: m.marks.Has(gallery.ID),
+		})
 		if m.pageState.index == i {
 			rows[i].Background = &ColorRowSelected
 		}
@@ -657,6 +971,9 @@ func (m GalleriesModel) View() string {
 		m.pageState.String(),
 		sort(m.sort, m.sortDirection),
 	}
+	if m.marks.Len() > 0 {
+		leftStatus = append(leftStatus, fmt.Sprintf("%d marked", m.marks.Len()))
+	}
 
 	rightStatus := galleryFilterStatus(m.galleryFilter, m.StashLookup)
 	if m.query != "" {
@@ -672,6 +989,18 @@ func (m GalleriesModel) View() string {
 	)
 }
 
+// openGalleriesTabMsg returns a ModelTabOpenMsg that opens a new galleries tab with the given filter applied.
+func openGalleriesTabMsg(filter stash.GalleryFilter) ModelTabOpenMsg {
+	return ModelTabOpenMsg{
+		Name: "galleries",
+		Configure: func(t TabModel) {
+			if gm, ok := t.(*GalleriesModel); ok {
+				gm.galleryFilter = filter
+			}
+		},
+	}
+}
+
 func (m *GalleriesModel) updateCmd() tea.Cmd {
 	if m.pageState.PerPage == 0 {
 		return nil
@@ -708,7 +1037,8 @@ func wrapGalleriesLoadedMsg(msg tea.Msg, requestID uint64) tea.Msg {
 
 var (
 	galleriesTable = &ui.Table{
-		AltBackground: ColorBlack,
+		AltBackground:    ColorBlack,
+		MarkedBackground: ColorRowMarked,
 		Cols: []ui.Column{
 			{
 				Name: "Organised",
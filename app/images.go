@@ -0,0 +1,545 @@
+package app
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"sync/atomic"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/drakenstar/stash-cli/command"
+	"github.com/drakenstar/stash-cli/stash"
+	"github.com/drakenstar/stash-cli/ui"
+)
+
+type imageFilterState struct {
+	query         string
+	sort          string
+	sortDirection string
+	imageFilter   stash.ImageFilter
+
+	pageState pageState
+}
+
+type ImageService interface {
+	Images(stash.FindFilter, stash.ImageFilter) tea.Cmd
+	DeleteImage(string) tea.Cmd
+	TagImage(stash.Image, []string) tea.Cmd
+	RateImage(stash.Image, int) tea.Cmd
+	OCounterImage(stash.Image, oCounterChange) tea.Cmd
+	ResolveTags([]string) tea.Cmd
+}
+
+// ImagesModel lists individual images, typically those of a single gallery when opened from the galleries tab.
+type ImagesModel struct {
+	ImageService
+	StashLookup
+
+	pageState pageState
+	images    []stash.Image
+
+	// gallery is the title of the gallery this tab was opened from, if any.  It is shown as the tab title.
+	gallery string
+
+	query         string
+	sort          string
+	sortDirection string
+	imageFilter   stash.ImageFilter
+
+	history []imageFilterState
+
+	screen Size
+
+	pendingFilterRequestID uint64
+	pendingFilter          *pendingImageFilter
+	listRequestID          uint64
+}
+
+type pendingImageFilter struct {
+	requestID uint64
+	msg       ImagesModelFilterMsg
+}
+
+func NewImagesModel(imageService ImageService, lookup StashLookup) *ImagesModel {
+	m := &ImagesModel{
+		ImageService: imageService,
+		StashLookup:  lookup,
+	}
+	m.pageState.PerPage = 40
+	m.reset()
+	return m
+}
+
+// reset clears all filtering other than the gallery the tab is scoped to.
+func (m *ImagesModel) reset() tea.Cmd {
+	m.query = ""
+	m.sort = stash.SortPath
+	m.sortDirection = stash.SortDirectionAsc
+	m.imageFilter = stash.ImageFilter{Galleries: m.imageFilter.Galleries}
+	m.pageState.Reset()
+
+	return m.updateCmd()
+}
+
+func (m *ImagesModel) SetSize(s Size) tea.Cmd {
+	m.screen = s
+	m.pageState.SetPerPage(s.Height - 1) // account for status line
+	return m.updateCmd()
+}
+
+func (m *ImagesModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *ImagesModel) Title() string {
+	t := "Images"
+	if m.query != "" {
+		t = fmt.Sprintf("\"%s\"", m.query)
+	} else if m.gallery != "" {
+		t = m.gallery
+	}
+	return fmt.Sprintf("%c %s (%s)", '\U000f02e9', t, humanNumber(m.pageState.total))
+}
+
+func (m *ImagesModel) Current() stash.Image {
+	return m.images[m.pageState.index]
+}
+
+// PreviewPath returns the path of the thumbnail of the current image.
+func (m *ImagesModel) PreviewPath() string {
+	if len(m.images) == 0 {
+		return ""
+	}
+	return "/image/" + m.Current().ID + "/thumbnail"
+}
+
+func (m *ImagesModel) PushState(mutate func(*ImagesModel)) (*ImagesModel, tea.Cmd) {
+	m.history = append(m.history, imageFilterState{
+		query:         m.query,
+		sort:          m.sort,
+		sortDirection: m.sortDirection,
+		imageFilter:   m.imageFilter,
+		pageState:     m.pageState,
+	})
+	mutate(m)
+	m.pageState.Reset()
+	return m, m.updateCmd()
+}
+
+// Pop sets the current state to the previous state from the history stack.  If the history stack is empty this is a
+// noop.
+func (m *ImagesModel) Pop() (*ImagesModel, tea.Cmd) {
+	if len(m.history) == 0 {
+		return m, nil
+	}
+
+	state := m.history[len(m.history)-1]
+	m.history = m.history[0 : len(m.history)-1]
+
+	m.pageState = state.pageState
+	m.query = state.query
+	m.sort = state.sort
+	m.sortDirection = state.sortDirection
+	m.imageFilter = state.imageFilter
+	m.images = []stash.Image{}
+
+	return m, m.updateCmd()
+}
+
+var ImagesModelDefaultKeymap = map[string]string{
+	"up":    "skip -1",
+	"down":  "skip 1",
+	"D":     "delete",
+	"enter": "open skip",
+	" ":     "open skip", // space
+	"z":     "skip -1",
+	"x":     "skip 1",
+	"o":     "open",
+	"r":     "sort random",
+	"u":     "undo",
+	"+":     "o+",
+	"-":     "o-",
+	"`":     "open-url",
+}
+
+var ImagesModelCommandConfig command.Config = command.Config{
+	"delete":   binder[ImagesModelDeleteMsg](),
+	"filter":   binder[ImagesModelFilterMsg](),
+	"o":        {SubCommands: command.Config{"reset": static(ImagesModelOCounterMsg{Change: oCounterReset})}},
+	"o+":       static(ImagesModelOCounterMsg{Change: oCounterIncrement}),
+	"o-":       static(ImagesModelOCounterMsg{Change: oCounterDecrement}),
+	"open":     binder[ImagesModelOpenMsg](),
+	"open-url": binder[ImagesModelOpenURLMsg](),
+	"rate":     binder[ImagesModelRateMsg](),
+	"refresh":  binder[ImagesModelRefresh](),
+	"reset":    binder[ImagesModelResetMsg](),
+	"sort":     binder[ImagesModelSortMsg](),
+	"skip":     binder[ImagesModelSkipMsg](),
+	"tag":      binder[ImagesModelTagMsg](),
+	"undo":     binder[ImagesModelUndoMsg](),
+}
+
+var imageSortFields = sortFields{
+	"path":    stash.SortPath,
+	"title":   stash.SortTitle,
+	"rating":  "rating",
+	"o":       stash.SortOCounter,
+	"date":    stash.SortDate,
+	"created": stash.SortCreatedAt,
+	"updated": stash.SortUpdatedAt,
+}
+
+func (m ImagesModel) CommandConfig() command.Config {
+	return ImagesModelCommandConfig
+}
+
+func (m ImagesModel) Search(query string) tea.Msg {
+	return ImagesModelFilterMsg{
+		Query: &query,
+	}
+}
+
+// ImagesModelFilterMsg controls the filtering of images.  Rating matches an exact rating out of 100, and O an exact
+// o-counter.
+type ImagesModelFilterMsg struct {
+	Query     *string
+	Organised *bool
+	Rating    *int
+	O         *int
+	Tag       []string
+}
+
+type imageTagsResolvedMsg struct {
+	requestID uint64
+	ids       []string
+}
+
+type ImagesModelOpenMsg struct {
+	Skip bool `command:",positional"`
+}
+
+type ImagesModelOpenURLMsg struct{}
+
+type ImagesModelDeleteMsg struct {
+	Confirm bool
+}
+
+// ImagesModelOCounterMsg increments, decrements or resets the o-counter of the current image.
+type ImagesModelOCounterMsg struct {
+	Change oCounterChange
+}
+
+// ImagesModelRateMsg sets the rating of the current image, out of 100.
+type ImagesModelRateMsg struct {
+	Rating int `command:",positional"`
+}
+
+type ImagesModelTagMsg struct {
+	Tags []string `command:",positional"`
+}
+
+type ImagesModelRefresh struct{}
+
+type ImagesModelResetMsg struct{}
+
+type ImagesModelSkipMsg struct {
+	Count int `command:",positional"`
+}
+
+type ImagesModelSortMsg struct {
+	Field string `command:",positional"`
+}
+
+type ImagesModelUndoMsg struct{}
+
+func (m *ImagesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case ImagesModelFilterMsg:
+		if needsEntityResolution(msg.Tag) {
+			requestID := atomic.AddUint64(&m.pendingFilterRequestID, 1)
+			m.pendingFilter = &pendingImageFilter{requestID: requestID, msg: msg}
+			return m, m.resolveImageTagsCmd(requestID, msg.Tag)
+		}
+		return m.applyFilter(msg, msg.Tag)
+
+	case imageTagsResolvedMsg:
+		if m.pendingFilter == nil || m.pendingFilter.requestID != msg.requestID {
+			return m, nil
+		}
+		pending := m.pendingFilter
+		m.pendingFilter = nil
+		return m.applyFilter(pending.msg, msg.ids)
+
+	case ImagesModelOpenMsg:
+		if msg.Skip && m.pageState.Next() {
+			return m, m.updateCmd()
+		}
+		if len(m.images) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no image selected"))
+		}
+		cur := m.Current()
+		return m, func() tea.Msg { return OpenMsg{cur} }
+
+	case ImagesModelOpenURLMsg:
+		if len(m.images) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no image selected"))
+		}
+		src := path.Join("images", m.Current().ID)
+		return m, func() tea.Msg { return OpenMsg{src} }
+
+	case ImagesModelDeleteMsg:
+		if len(m.images) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no image selected"))
+		}
+		image := m.Current()
+		return m, func() tea.Msg {
+			return deleteRequestMsg{
+				Entity:      "image",
+				Title:       imageName(image),
+				Path:        image.FilePath(),
+				SkipConfirm: msg.Confirm,
+				DeleteCmd:   m.ImageService.DeleteImage(image.ID),
+			}
+		}
+
+	case ImagesModelRateMsg:
+		if len(m.images) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no image selected"))
+		}
+		if msg.Rating < 1 || msg.Rating > 100 {
+			return m, NewErrorCmd(fmt.Errorf("rating must be between 1 and 100"))
+		}
+		return m, m.ImageService.RateImage(m.Current(), msg.Rating)
+
+	case ImagesModelOCounterMsg:
+		if len(m.images) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no image selected"))
+		}
+		return m, m.ImageService.OCounterImage(m.Current(), msg.Change)
+
+	case ImagesModelTagMsg:
+		if len(m.images) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no image selected"))
+		}
+		if len(msg.Tags) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no tags specified"))
+		}
+		return m, m.ImageService.TagImage(m.Current(), msg.Tags)
+
+	case ImagesModelRefresh:
+		return m, m.updateCmd()
+
+	case ImagesModelResetMsg:
+		return m, m.reset()
+
+	case ImagesModelSortMsg:
+		sort, direction, err := imageSortFields.parse(msg.Field)
+		if err != nil {
+			return m, NewErrorCmd(err)
+		}
+		return m.PushState(func(im *ImagesModel) {
+			im.sort = sort
+			im.sortDirection = direction
+		})
+
+	case ImagesModelSkipMsg:
+		if m.pageState.Skip(msg.Count) {
+			return m, m.updateCmd()
+		}
+
+	case ImagesModelUndoMsg:
+		return m.Pop()
+
+	case tea.KeyMsg:
+		if cmd, ok := ImagesModelDefaultKeymap[msg.String()]; ok {
+			return m, func() tea.Msg { return ui.CommandExecMsg{Command: cmd} }
+		}
+
+	case imagesListLoadedMsg:
+		if msg.requestID != m.listRequestID {
+			return m, nil
+		}
+		m.images, m.pageState.total = msg.images, msg.total
+
+	case imageDeletedMsg:
+		m.pageState.DeleteCurrent()
+		return m, m.updateCmd()
+
+	case imageUpdatedMsg:
+		for i, image := range m.images {
+			if image.ID == msg.image.ID {
+				m.images[i] = msg.image
+			}
+		}
+	}
+
+	return m, nil
+}
+
+func (m *ImagesModel) resolveImageTagsCmd(requestID uint64, rawTags []string) tea.Cmd {
+	tags := append([]string(nil), rawTags...)
+	return func() tea.Msg {
+		resolved := m.ImageService.ResolveTags(tags)()
+		switch msg := resolved.(type) {
+		case resolvedTagIDsMsg:
+			return imageTagsResolvedMsg{requestID: requestID, ids: msg.ids}
+		case loadingMsg:
+			if payload, ok := msg.payload.(resolvedTagIDsMsg); ok {
+				msg.payload = imageTagsResolvedMsg{requestID: requestID, ids: payload.ids}
+			}
+			return msg
+		default:
+			return resolved
+		}
+	}
+}
+
+func (m *ImagesModel) applyFilter(msg ImagesModelFilterMsg, tagIDs []string) (*ImagesModel, tea.Cmd) {
+	return m.PushState(func(im *ImagesModel) {
+		if msg.Query != nil {
+			im.query = *msg.Query
+		}
+		if msg.Organised != nil {
+			im.imageFilter.Organized = msg.Organised
+		}
+		if msg.Rating != nil {
+			im.imageFilter.Rating100 = &stash.IntCriterion{
+				Modifier: stash.CriterionModifierEquals,
+				Value:    *msg.Rating,
+			}
+		}
+		if msg.O != nil {
+			im.imageFilter.OCounter = &stash.IntCriterion{
+				Modifier: stash.CriterionModifierEquals,
+				Value:    *msg.O,
+			}
+		}
+		if len(tagIDs) > 0 {
+			im.imageFilter.Tags = &stash.HierarchicalMultiCriterion{
+				Value:    tagIDs,
+				Modifier: stash.CriterionModifierIncludes,
+			}
+		}
+	})
+}
+
+func (m ImagesModel) View() string {
+	var rows []ui.Row
+	for i, image := range m.images {
+		rows = append(rows, ui.Row{
+			Values: []string{
+				imageName(image),
+				imageDimensions(image),
+				rating(image.Rating),
+				strconv.Itoa(image.OCounter),
+				tagList(image.Tags),
+			},
+		})
+		if m.pageState.index == i {
+			rows[i].Background = &ColorRowSelected
+		}
+	}
+
+	leftStatus := []string{
+		m.pageState.String(),
+		sort(m.sort, m.sortDirection),
+	}
+
+	rightStatus := imageFilterStatus(m.imageFilter, m.StashLookup)
+	if m.query != "" {
+		rightStatus = append(rightStatus, "\""+m.query+"\"")
+	}
+	if len(m.history) > 0 {
+		rightStatus = append(rightStatus, fmt.Sprintf("[%d]", len(m.history)))
+	}
+
+	return lipgloss.JoinVertical(0,
+		statusBar.Render(m.screen.Width, leftStatus, rightStatus),
+		imagesTable.Render(m.screen.Width, rows),
+	)
+}
+
+// openGalleryImagesTabMsg returns a ModelTabOpenMsg that opens a new images tab scoped to the given gallery.
+func openGalleryImagesTabMsg(gallery stash.Gallery) ModelTabOpenMsg {
+	title := galleryTitle(gallery)
+	return ModelTabOpenMsg{
+		Name: "images",
+		Configure: func(t TabModel) {
+			if im, ok := t.(*ImagesModel); ok {
+				im.gallery = title
+				im.imageFilter.Galleries = &stash.MultiCriterion{
+					Value:    []string{gallery.ID},
+					Modifier: stash.CriterionModifierIncludes,
+				}
+			}
+		},
+	}
+}
+
+func (m *ImagesModel) updateCmd() tea.Cmd {
+	if m.pageState.PerPage == 0 {
+		return nil
+	}
+	requestID := atomic.AddUint64(&m.listRequestID, 1)
+	cmd := m.ImageService.Images(stash.FindFilter{
+		Query:     m.query,
+		Page:      m.pageState.page + 1,
+		PerPage:   m.pageState.PerPage,
+		Sort:      m.sort,
+		Direction: m.sortDirection,
+	}, m.imageFilter)
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		return wrapImagesLoadedMsg(cmd(), requestID)
+	}
+}
+
+func wrapImagesLoadedMsg(msg tea.Msg, requestID uint64) tea.Msg {
+	switch msg := msg.(type) {
+	case imagesMsg:
+		return imagesListLoadedMsg{requestID: requestID, images: msg.images, total: msg.total}
+	case loadingMsg:
+		if payload, ok := msg.payload.(imagesMsg); ok {
+			msg.payload = imagesListLoadedMsg{requestID: requestID, images: payload.images, total: payload.total}
+		}
+		return msg
+	default:
+		return msg
+	}
+}
+
+var (
+	imagesTable = &ui.Table{
+		AltBackground: ColorBlack,
+		Cols: []ui.Column{
+			{
+				Name:       "File",
+				Foreground: &ColorYellow,
+				Bold:       true,
+				Weight:     2,
+			},
+			{
+				Name:       "Dimensions",
+				Foreground: &ColorGrey,
+			},
+			{
+				Name:       "Rating",
+				Foreground: &ColorYellow,
+			},
+			{
+				Name:       "O",
+				Foreground: &ColorBlue,
+				Align:      lipgloss.Right,
+			},
+			{
+				Name:       "Tags",
+				Foreground: &ColorPurple,
+				Flex:       true,
+				Weight:     1,
+			},
+		},
+	}
+)
@@ -0,0 +1,87 @@
+package app
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/drakenstar/stash-cli/stash"
+	"github.com/stretchr/testify/require"
+)
+
+type imageTestService struct {
+	filters []stash.ImageFilter
+	rated   int
+	tagged  []string
+	changes []oCounterChange
+}
+
+func (s *imageTestService) Images(_ stash.FindFilter, imf stash.ImageFilter) tea.Cmd {
+	s.filters = append(s.filters, imf)
+	return nil
+}
+
+func (s *imageTestService) DeleteImage(string) tea.Cmd { return nil }
+
+func (s *imageTestService) TagImage(_ stash.Image, names []string) tea.Cmd {
+	s.tagged = names
+	return nil
+}
+
+func (s *imageTestService) RateImage(_ stash.Image, rating int) tea.Cmd {
+	s.rated = rating
+	return nil
+}
+
+func (s *imageTestService) OCounterImage(image stash.Image, change oCounterChange) tea.Cmd {
+	s.changes = append(s.changes, change)
+	updated := image
+	updated.OCounter++
+	return func() tea.Msg { return imageUpdatedMsg{image: updated} }
+}
+
+func (s *imageTestService) ResolveTags([]string) tea.Cmd { return nil }
+
+func TestGalleriesModelImagesOpensScopedTab(t *testing.T) {
+	galleries := NewGalleriesModel(deleteTestService{}, tagResolveTestLookup{})
+	galleries.galleries = []stash.Gallery{{ID: "5", Title: "Holiday"}}
+
+	_, cmd := galleries.Update(GalleriesModelImagesMsg{})
+	open := cmd().(ModelTabOpenMsg)
+	require.Equal(t, "images", open.Name)
+
+	srv := &imageTestService{}
+	images := NewImagesModel(srv, tagResolveTestLookup{})
+	open.Configure(images)
+	require.Equal(t, []string{"5"}, images.imageFilter.Galleries.Value)
+	require.Contains(t, images.Title(), "Holiday")
+
+	// Resetting filters keeps the tab scoped to its gallery.
+	images.Update(ImagesModelFilterMsg{Rating: ptr(80)})
+	images.Update(ImagesModelResetMsg{})
+	require.Nil(t, images.imageFilter.Rating100)
+	require.Equal(t, []string{"5"}, images.imageFilter.Galleries.Value)
+}
+
+func TestImagesModelUpdates(t *testing.T) {
+	srv := &imageTestService{}
+	m := NewImagesModel(srv, tagResolveTestLookup{})
+	m.images = []stash.Image{{ID: "1", Files: []stash.ImageFile{{Path: "/a/001.jpg"}}}}
+
+	_, cmd := m.Update(ImagesModelRateMsg{Rating: 101})
+	require.Error(t, cmd().(ErrorMsg).error)
+	m.Update(ImagesModelRateMsg{Rating: 80})
+	require.Equal(t, 80, srv.rated)
+
+	m.Update(ImagesModelTagMsg{Tags: []string{"Foo"}})
+	require.Equal(t, []string{"Foo"}, srv.tagged)
+
+	m.Update(imageUpdatedMsg{image: stash.Image{ID: "1", Rating: 80}})
+	require.Equal(t, 80, m.Current().Rating)
+
+	_, cmd = m.Update(ImagesModelOpenMsg{})
+	require.IsType(t, stash.Image{}, cmd().(OpenMsg).target)
+
+	_, cmd = m.Update(ImagesModelOCounterMsg{Change: oCounterIncrement})
+	m.Update(cmd())
+	require.Equal(t, 1, m.Current().OCounter)
+}
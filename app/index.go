@@ -0,0 +1,85 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/drakenstar/stash-cli/command"
+	"github.com/drakenstar/stash-cli/stash"
+)
+
+// indexer is implemented by stashes that index content themselves, such as a local folder, and which index it again
+// when refreshed.
+type indexer interface {
+	Scan(ctx context.Context, progress func(stash.ScanProgress)) error
+}
+
+// indexProgressInterval limits how often indexing progress is shown, as a large folder reports the progress of
+// thousands of directories a second.
+const indexProgressInterval = 100 * time.Millisecond
+
+// refreshMsg refreshes content.  Content is indexed again first for stashes that index it themselves.
+type refreshMsg struct{}
+
+// indexProgressMsg is received as content is indexed.
+type indexProgressMsg struct {
+	progress stash.ScanProgress
+}
+
+// indexedMsg is received when content has been indexed, at which point open tabs may be showing stale content.
+type indexedMsg struct {
+	err error
+}
+
+// Index indexes content in the background for stashes that index it themselves, sending progress to the application
+// with send.  Content is indexed at first, and then again each time it is refreshed, until ctx is cancelled.  Index
+// returns immediately for other stashes.
+func (m *Model) Index(ctx context.Context, send func(tea.Msg)) error {
+	s := m.cmdService
+	if s.indexer == nil {
+		return nil
+	}
+	for {
+		var shown time.Time
+		err := s.indexer.Scan(ctx, func(progress stash.ScanProgress) {
+			if now := time.Now(); now.Sub(shown) >= indexProgressInterval {
+				shown = now
+				send(indexProgressMsg{progress})
+			}
+		})
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		send(indexedMsg{err})
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.rescan:
+		}
+	}
+}
+
+// refresh indexes content again, or refreshes the active tab for stashes that don't index content themselves.
+func (m *Model) refresh() tea.Cmd {
+	if m.cmdService.indexer == nil {
+		msg, err := m.tabs[m.active].model.CommandConfig().Resolve(command.Parser("refresh"))
+		if err != nil {
+			return NewErrorCmd(err)
+		}
+		return func() tea.Msg { return msg }
+	}
+	// A rescan that is already waiting will pick up any change this one would.
+	select {
+	case m.cmdService.rescan <- struct{}{}:
+	default:
+	}
+	m.footer.Status = "indexing"
+	return nil
+}
+
+func indexStatus(progress stash.ScanProgress) string {
+	return fmt.Sprintf("indexing: %d folders scanned, %d changed", progress.Dirs, progress.Changed)
+}
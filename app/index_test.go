@@ -0,0 +1,72 @@
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/drakenstar/stash-cli/stash"
+	"github.com/drakenstar/stash-cli/ui"
+	"github.com/stretchr/testify/require"
+)
+
+// nextIndexed returns the indexedMsg sent to msgs, skipping progress.
+func nextIndexed(t *testing.T, msgs <-chan tea.Msg) indexedMsg {
+	t.Helper()
+	for msg := range msgs {
+		if indexed, ok := msg.(indexedMsg); ok {
+			return indexed
+		}
+		require.IsType(t, indexProgressMsg{}, msg)
+	}
+	t.Fatal("indexing stopped")
+	return indexedMsg{}
+}
+
+func TestIndex(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "beach.mp4"), nil, 0o644))
+	s, err := stash.NewLocalStash(root, "")
+	require.NoError(t, err)
+	m := New(s, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	msgs := make(chan tea.Msg, 16)
+	done := make(chan error)
+	go func() { done <- m.Index(ctx, func(msg tea.Msg) { msgs <- msg }) }()
+
+	require.NoError(t, nextIndexed(t, msgs).err)
+	scenes, _, err := s.Scenes(ctx, stash.FindFilter{PerPage: -1}, stash.SceneFilter{})
+	require.NoError(t, err)
+	require.Len(t, scenes, 1)
+
+	// Refreshing indexes content again, showing progress in the footer until it's done.
+	require.NoError(t, os.WriteFile(filepath.Join(root, "city.mov"), nil, 0o644))
+	// The folder is only read again once it's modified, which is made certain rather than left to the clock.
+	modified := time.Now().Add(time.Hour)
+	require.NoError(t, os.Chtimes(root, modified, modified))
+	model, cmd := m.Update(ui.CommandExecMsg{Command: "refresh"})
+	model, _ = model.Update(cmd())
+	require.Equal(t, "indexing", model.(Model).footer.Status)
+	indexed := nextIndexed(t, msgs)
+	require.NoError(t, indexed.err)
+	model, _ = model.Update(indexed)
+	require.Empty(t, model.(Model).footer.Status)
+	scenes, _, err = s.Scenes(ctx, stash.FindFilter{PerPage: -1}, stash.SceneFilter{})
+	require.NoError(t, err)
+	require.Len(t, scenes, 2)
+
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestRefreshWithoutIndex(t *testing.T) {
+	m := New(stash.NewMemoryStash(), nil)
+	require.NoError(t, m.Index(context.Background(), func(tea.Msg) { t.Fatal("nothing is indexed") }))
+
+	_, cmd := m.Update(refreshMsg{})
+	require.Equal(t, ScenesModelRefresh{}, cmd())
+}
@@ -0,0 +1,440 @@
+package app
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/drakenstar/stash-cli/command"
+	"github.com/drakenstar/stash-cli/stash"
+	"github.com/drakenstar/stash-cli/ui"
+)
+
+// Tasks that can be started with the task command.
+const (
+	taskScan     = "scan"
+	taskGenerate = "generate"
+	taskAutoTag  = "auto-tag"
+	taskIdentify = "identify"
+	taskClean    = "clean"
+)
+
+var tasks = []string{taskScan, taskGenerate, taskAutoTag, taskIdentify, taskClean}
+
+// TaskMsg starts a stash task.  Paths are local paths to scope the task to, and are mapped to the paths used by stash.
+// Source lists the stash-box endpoints or scraper IDs to identify scenes with, Overwrite regenerates existing content
+// and DryRun only logs what a clean would remove.  Confirm starts a task over the whole library without asking first.
+type TaskMsg struct {
+	Task      string
+	Paths     []string `command:",positional"`
+	Source    []string
+	Overwrite bool
+	DryRun    bool `command:"dry-run"`
+	Confirm   bool
+}
+
+// taskRequestMsg asks for a task to be started, which is confirmed first unless SkipConfirm is set.
+type taskRequestMsg struct {
+	Task        string
+	Detail      string
+	SkipConfirm bool
+	StartCmd    tea.Cmd
+}
+
+type confirmTaskMsg struct {
+	Request taskRequestMsg
+}
+
+// taskDetails explain what each task does when run over the whole library.
+var taskDetails = map[string]string{
+	taskScan:     "This will scan every library path for new and changed files.",
+	taskGenerate: "This will generate missing content for every scene.",
+	taskAutoTag:  "This will tag all content with the performers, studios and tags matching its path.",
+	taskIdentify: "This will identify every scene with the sources given, which may change its details.",
+	taskClean:    "This will remove everything from Stash whose files no longer exist.  Run with dry-run to only log what would be removed.",
+}
+
+// taskCommand returns the task command, which has a sub-command for each task.
+func taskCommand() command.Command {
+	subCommands := command.Config{}
+	for _, task := range tasks {
+		subCommands[task] = command.Command{
+			Resolve: func(i command.Iterator) (any, error) {
+				msg := TaskMsg{Task: task}
+				err := command.Bind(i, &msg)
+				return msg, err
+			},
+		}
+	}
+	return command.Command{
+		Resolve: func(command.Iterator) (any, error) {
+			return nil, fmt.Errorf("expected one of %s", strings.Join(tasks, ", "))
+		},
+		SubCommands: subCommands,
+	}
+}
+
+// taskRequest describes a task to start.  Paths are paths as stash knows them, while LocalPaths are mapped before use.
+// SceneIDs scope the generate and identify tasks.  A task with no scope runs over the whole library.
+type taskRequest struct {
+	Task       string
+	Paths      []string
+	LocalPaths []string
+	SceneIDs   []string
+	Sources    []string
+	Overwrite  bool
+	DryRun     bool
+}
+
+// unscoped returns true if the task would run over the whole library.
+func (r taskRequest) unscoped() bool {
+	return len(r.Paths) == 0 && len(r.LocalPaths) == 0 && len(r.SceneIDs) == 0
+}
+
+// newTaskRequest returns a request for the task of msg.  The task is scoped to any paths given, or otherwise to scenes.
+// Tasks that select files by path are scoped to the directories of scenes, and others to the scenes themselves.
+func newTaskRequest(msg TaskMsg, scenes []stash.Scene) (taskRequest, error) {
+	req := taskRequest{
+		Task:       msg.Task,
+		LocalPaths: msg.Paths,
+		Sources:    msg.Source,
+		Overwrite:  msg.Overwrite,
+		DryRun:     msg.DryRun,
+	}
+	if msg.Task == taskIdentify && len(msg.Source) == 0 {
+		return req, fmt.Errorf("identify requires at least one source")
+	}
+	if msg.Task == taskGenerate && len(msg.Paths) > 0 {
+		return req, fmt.Errorf("generate cannot be scoped to paths")
+	}
+	if len(msg.Paths) > 0 {
+		return req, nil
+	}
+
+	switch msg.Task {
+	case taskGenerate, taskIdentify:
+		req.SceneIDs = sceneIDs(scenes)
+	default:
+		for _, scene := range scenes {
+			dir := path.Dir(scene.FilePath())
+			if !containsString(req.Paths, dir) {
+				req.Paths = append(req.Paths, dir)
+			}
+		}
+	}
+	return req, nil
+}
+
+type JobService interface {
+	Jobs([]string) tea.Cmd
+	StopJob(string) tea.Cmd
+	StartTask(taskRequest) tea.Cmd
+}
+
+// jobsPollInterval is how often jobs are refreshed while any are active, and jobsIdlePollInterval how often otherwise.
+const (
+	jobsPollInterval     = time.Second
+	jobsIdlePollInterval = 5 * time.Second
+)
+
+// JobsModel lists the jobs in the stash job queue, polling for their progress.  Jobs that finish remain listed for as
+// long as stash can find them.
+type JobsModel struct {
+	JobService
+
+	jobs  []stash.Job
+	index int
+
+	screen Size
+
+	pollRequestID uint64
+}
+
+func NewJobsModel(jobService JobService) *JobsModel {
+	return &JobsModel{
+		JobService: jobService,
+	}
+}
+
+func (m *JobsModel) SetSize(s Size) tea.Cmd {
+	m.screen = s
+	return m.pollCmd(0)
+}
+
+func (m *JobsModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *JobsModel) Title() string {
+	active := 0
+	for _, job := range m.jobs {
+		if !job.Done() {
+			active++
+		}
+	}
+	return fmt.Sprintf("%c Jobs (%d)", '\U000f0b3d', active)
+}
+
+func (m *JobsModel) Current() stash.Job {
+	return m.jobs[m.index]
+}
+
+var JobsModelDefaultKeymap = map[string]string{
+	"up":   "skip -1",
+	"down": "skip 1",
+	"z":    "skip -1",
+	"x":    "skip 1",
+	"C":    "stop",
+}
+
+var JobsModelCommandConfig command.Config = command.Config{
+	"refresh": binder[JobsModelRefresh](),
+	"skip":    binder[JobsModelSkipMsg](),
+	"stop":    binder[JobsModelStopMsg](),
+	"task":    taskCommand(),
+}
+
+func (m JobsModel) CommandConfig() command.Config {
+	return JobsModelCommandConfig
+}
+
+// Search selects the next job with a description matching query.
+func (m JobsModel) Search(query string) tea.Msg {
+	return JobsModelFindMsg{Query: query}
+}
+
+type JobsModelFindMsg struct {
+	Query string
+}
+
+type JobsModelRefresh struct{}
+
+type JobsModelSkipMsg struct {
+	Count int `command:",positional"`
+}
+
+// JobsModelStopMsg stops the current job.
+type JobsModelStopMsg struct{}
+
+func (m *JobsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case JobsModelFindMsg:
+		query := strings.ToLower(msg.Query)
+		for i := 1; i <= len(m.jobs); i++ {
+			j := (m.index + i) % len(m.jobs)
+			if strings.Contains(strings.ToLower(m.jobs[j].Description), query) {
+				m.index = j
+				return m, nil
+			}
+		}
+		return m, NewErrorCmd(fmt.Errorf("no jobs matching '%s'", msg.Query))
+
+	case JobsModelRefresh:
+		return m, m.pollCmd(0)
+
+	case JobsModelSkipMsg:
+		if len(m.jobs) > 0 {
+			m.index = wrapIndex(m.index+msg.Count, len(m.jobs))
+		}
+
+	case JobsModelStopMsg:
+		if len(m.jobs) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no job selected"))
+		}
+		if m.Current().Done() {
+			return m, NewErrorCmd(fmt.Errorf("job has already finished"))
+		}
+		return m, m.JobService.StopJob(m.Current().ID)
+
+	case TaskMsg:
+		req, err := newTaskRequest(msg, nil)
+		if err != nil {
+			return m, NewErrorCmd(err)
+		}
+		// Tasks started here run over the whole library unless paths are given, so they're confirmed first.  A dry run
+		// changes nothing, so it isn't.
+		return m, func() tea.Msg {
+			return taskRequestMsg{
+				Task:        req.Task,
+				Detail:      taskDetails[req.Task],
+				SkipConfirm: msg.Confirm || !req.unscoped() || req.DryRun,
+				StartCmd:    m.JobService.StartTask(req),
+			}
+		}
+
+	case taskStartedMsg:
+		// The job is listed straight away, since it may finish before the next poll.
+		m.jobs = append(m.jobs, stash.Job{ID: msg.jobID, Status: stash.JobStatusReady, Description: msg.task})
+		return m, m.pollCmd(0)
+
+	case jobUpdatedMsg:
+		job := msg.update.Job
+		for i := range m.jobs {
+			if m.jobs[i].ID == job.ID {
+				m.jobs[i] = job
+				return m, nil
+			}
+		}
+		m.jobs = append(m.jobs, job)
+
+	case jobStoppedMsg:
+		for i, job := range m.jobs {
+			if job.ID == msg.id && !job.Done() {
+				m.jobs[i].Status = stash.JobStatusStopping
+			}
+		}
+
+	case tea.KeyMsg:
+		if cmd, ok := JobsModelDefaultKeymap[msg.String()]; ok {
+			return m, func() tea.Msg { return ui.CommandExecMsg{Command: cmd} }
+		}
+
+	case jobsLoadedMsg:
+		if msg.requestID != m.pollRequestID {
+			return m, nil
+		}
+		// Finished jobs aren't polled again, so those that have left the queue are kept as they were last seen.
+		jobs := msg.jobs
+		for _, job := range m.jobs {
+			if job.Done() && !slices.ContainsFunc(jobs, func(j stash.Job) bool { return j.ID == job.ID }) {
+				jobs = append(jobs, job)
+			}
+		}
+		m.jobs = jobs
+		m.index = min(m.index, max(len(m.jobs)-1, 0))
+		return m, m.pollCmd(m.pollInterval())
+	}
+
+	return m, nil
+}
+
+// pollInterval returns how long to wait before polling jobs again.
+func (m *JobsModel) pollInterval() time.Duration {
+	for _, job := range m.jobs {
+		if !job.Done() {
+			return jobsPollInterval
+		}
+	}
+	return jobsIdlePollInterval
+}
+
+// pollCmd loads jobs after delay.  Each load polls again once handled, so starting a new poll replaces any that is
+// already in progress.  Jobs that have finished can't change, so they aren't looked for once they leave the queue.
+func (m *JobsModel) pollCmd(delay time.Duration) tea.Cmd {
+	requestID := atomic.AddUint64(&m.pollRequestID, 1)
+	var ids []string
+	for _, job := range m.jobs {
+		if !job.Done() {
+			ids = append(ids, job.ID)
+		}
+	}
+	cmd := m.JobService.Jobs(ids)
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		time.Sleep(delay)
+		return wrapJobsLoadedMsg(cmd(), requestID)
+	}
+}
+
+func wrapJobsLoadedMsg(msg tea.Msg, requestID uint64) tea.Msg {
+	switch msg := msg.(type) {
+	case jobsMsg:
+		return jobsLoadedMsg{requestID: requestID, jobs: msg.jobs}
+	case loadingMsg:
+		if payload, ok := msg.payload.(jobsMsg); ok {
+			msg.payload = jobsLoadedMsg{requestID: requestID, jobs: payload.jobs}
+		}
+		return msg
+	default:
+		return msg
+	}
+}
+
+func (m JobsModel) View() string {
+	var rows []ui.Row
+	for i, job := range m.jobs {
+		rows = append(rows, ui.Row{
+			Values: []string{
+				jobStatus(job.Status),
+				job.Description,
+				jobProgress(job),
+				strings.Join(job.SubTasks, ", "),
+				job.AddTime.Local().Format(time.TimeOnly),
+			},
+		})
+		if m.index == i {
+			rows[i].Background = &ColorRowSelected
+		}
+	}
+
+	leftStatus := []string{fmt.Sprintf("%d jobs", len(m.jobs))}
+	var rightStatus []string
+	if len(m.jobs) > 0 && m.Current().StartTime != nil {
+		end := time.Now()
+		if m.Current().EndTime != nil {
+			end = *m.Current().EndTime
+		}
+		rightStatus = append(rightStatus, fmt.Sprintf("ran for %s", end.Sub(*m.Current().StartTime).Round(time.Second)))
+	}
+
+	return lipgloss.JoinVertical(0,
+		statusBar.Render(m.screen.Width, leftStatus, rightStatus),
+		jobsTable.Render(m.screen.Width, rows),
+	)
+}
+
+func jobStatus(status stash.JobStatus) string {
+	return strings.ToLower(string(status))
+}
+
+// jobProgress renders the progress of a running job as a percentage.
+func jobProgress(job stash.Job) string {
+	if job.Status == stash.JobStatusFinished {
+		return "100%"
+	}
+	if job.Progress == nil || job.Done() {
+		return ""
+	}
+	return fmt.Sprintf("%d%%", int(math.Floor(*job.Progress*100)))
+}
+
+var (
+	jobsTable = &ui.Table{
+		AltBackground: ColorBlack,
+		Cols: []ui.Column{
+			{
+				Name:       "Status",
+				Foreground: &ColorYellow,
+			},
+			{
+				Name:       "Description",
+				Foreground: &ColorOffWhite,
+				Bold:       true,
+				Weight:     1,
+			},
+			{
+				Name:       "Progress",
+				Foreground: &ColorBlue,
+				Align:      lipgloss.Right,
+			},
+			{
+				Name:       "Task",
+				Foreground: &ColorGrey,
+				Flex:       true,
+			},
+			{
+				Name:       "Added",
+				Foreground: &ColorPurple,
+				Align:      lipgloss.Right,
+			},
+		},
+	}
+)
@@ -0,0 +1,215 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/drakenstar/stash-cli/command"
+	"github.com/drakenstar/stash-cli/stash"
+	"github.com/stretchr/testify/require"
+)
+
+type jobTestService struct {
+	polled  [][]string
+	jobs    []stash.Job
+	stopped []string
+	tasks   []taskRequest
+}
+
+func (s *jobTestService) Jobs(ids []string) tea.Cmd {
+	s.polled = append(s.polled, ids)
+	return func() tea.Msg { return jobsMsg{jobs: s.jobs} }
+}
+
+func (s *jobTestService) StopJob(id string) tea.Cmd {
+	s.stopped = append(s.stopped, id)
+	return func() tea.Msg { return jobStoppedMsg{id: id} }
+}
+
+func (s *jobTestService) StartTask(req taskRequest) tea.Cmd {
+	s.tasks = append(s.tasks, req)
+	return nil
+}
+
+type taskTestService struct {
+	deleteTestService
+	tasks []taskRequest
+}
+
+func (s *taskTestService) StartTask(req taskRequest) tea.Cmd {
+	s.tasks = append(s.tasks, req)
+	return nil
+}
+
+type taskTestStash struct {
+	stash.Stash
+	scan     stash.ScanMetadata
+	generate stash.GenerateMetadata
+	found    map[string]stash.Job
+}
+
+func (s *taskTestStash) MetadataScan(_ context.Context, input stash.ScanMetadata) (string, error) {
+	s.scan = input
+	return "1", nil
+}
+
+func (s *taskTestStash) MetadataGenerate(_ context.Context, input stash.GenerateMetadata) (string, error) {
+	s.generate = input
+	return "2", nil
+}
+
+func (s *taskTestStash) JobQueue(context.Context) ([]stash.Job, error) {
+	return []stash.Job{{ID: "2", Status: stash.JobStatusRunning}}, nil
+}
+
+func (s *taskTestStash) FindJob(_ context.Context, id string) (stash.Job, bool, error) {
+	job, ok := s.found[id]
+	return job, ok, nil
+}
+
+func TestTaskCommand(t *testing.T) {
+	msg, err := ScenesModelCommandConfig.Resolve(command.Parser(`task clean "/mnt/new files" dry-run`))
+	require.NoError(t, err)
+	require.Equal(t, TaskMsg{Task: taskClean, Paths: []string{"/mnt/new files"}, DryRun: true}, msg)
+
+	msg, err = JobsModelCommandConfig.Resolve(command.Parser(`task scan confirm`))
+	require.NoError(t, err)
+	require.Equal(t, TaskMsg{Task: taskScan, Confirm: true}, msg)
+
+	_, err = JobsModelCommandConfig.Resolve(command.Parser(`task`))
+	require.Error(t, err)
+}
+
+func TestScenesModelTask(t *testing.T) {
+	srv := &taskTestService{}
+	m := NewScenesModel(srv, deleteTestLookup{})
+	m.scenes = []stash.Scene{
+		{ID: "1", Files: []stash.VideoFile{{Path: "/library/a/1.mp4"}}},
+		{ID: "2", Files: []stash.VideoFile{{Path: "/library/a/2.mp4"}}},
+		{ID: "3", Files: []stash.VideoFile{{Path: "/library/b/3.mp4"}}},
+	}
+
+	// Without marks tasks are scoped to the current scene.
+	m.Update(TaskMsg{Task: taskScan})
+	require.Equal(t, []string{"/library/a"}, srv.tasks[0].Paths)
+
+	m.Update(ScenesModelMarkMsg{Target: "all"})
+	m.Update(TaskMsg{Task: taskAutoTag})
+	require.Equal(t, []string{"/library/a", "/library/b"}, srv.tasks[1].Paths)
+	m.Update(TaskMsg{Task: taskGenerate})
+	require.Equal(t, []string{"1", "2", "3"}, srv.tasks[2].SceneIDs)
+	require.Empty(t, srv.tasks[2].Paths)
+
+	// Paths given take precedence over the selection.
+	m.Update(TaskMsg{Task: taskScan, Paths: []string{"/mnt/new"}})
+	require.Equal(t, []string{"/mnt/new"}, srv.tasks[3].LocalPaths)
+	require.Empty(t, srv.tasks[3].Paths)
+
+	_, cmd := m.Update(TaskMsg{Task: taskIdentify})
+	require.IsType(t, ErrorMsg{}, cmd())
+	_, cmd = m.Update(TaskMsg{Task: taskGenerate, Paths: []string{"/mnt/new"}})
+	require.IsType(t, ErrorMsg{}, cmd())
+	require.Len(t, srv.tasks, 4)
+}
+
+func TestCmdServiceStartTask(t *testing.T) {
+	backend := &taskTestStash{}
+	svc := &cmdService{Stash: backend, unmapPath: func(p string) string { return "/library" + p[len("/mnt"):] }}
+
+	msg := svc.StartTask(taskRequest{Task: taskScan, Paths: []string{"/library/a"}, LocalPaths: []string{"/mnt/b"}})()
+	require.Equal(t, taskStartedMsg{task: taskScan, jobID: "1"}, msg)
+	require.Equal(t, []string{"/library/a", "/library/b"}, backend.scan.Paths)
+
+	svc.StartTask(taskRequest{Task: taskGenerate, SceneIDs: []string{"1"}})()
+	require.True(t, backend.generate.Covers)
+	require.Len(t, backend.generate.SceneIDs, 1)
+
+	// Jobs that have left the queue are found individually.
+	backend.found = map[string]stash.Job{"1": {ID: "1", Status: stash.JobStatusFinished}}
+	msg = svc.Jobs([]string{"1", "2", "3"})()
+	require.Equal(t, jobsMsg{jobs: []stash.Job{
+		{ID: "2", Status: stash.JobStatusRunning},
+		{ID: "1", Status: stash.JobStatusFinished},
+	}}, msg)
+}
+
+func TestJobsModel(t *testing.T) {
+	srv := &jobTestService{jobs: []stash.Job{
+		{ID: "1", Status: stash.JobStatusRunning, Description: "Scanning...", Progress: ptr(0.256)},
+		{ID: "2", Status: stash.JobStatusFinished, Description: "Generating..."},
+	}}
+	m := NewJobsModel(srv)
+	_, cmd := m.Update(taskStartedMsg{task: taskScan, jobID: "1"})
+	require.Equal(t, "1", m.Current().ID)
+	m.Update(cmd())
+	require.Equal(t, []string{"1"}, srv.polled[0])
+	require.Len(t, m.jobs, 2)
+	// Finished jobs aren't looked for again, and are kept once they leave the queue.
+	require.Equal(t, []string{"1"}, srv.polled[1])
+	m.Update(jobsLoadedMsg{requestID: m.pollRequestID, jobs: srv.jobs[:1]})
+	require.Equal(t, []string{"1", "2"}, []string{m.jobs[0].ID, m.jobs[1].ID})
+	require.Equal(t, "25%", jobProgress(m.Current()))
+	require.Equal(t, jobsPollInterval, m.pollInterval())
+
+	_, cmd = m.Update(JobsModelStopMsg{})
+	m.Update(cmd())
+	require.Equal(t, []string{"1"}, srv.stopped)
+	require.Equal(t, stash.JobStatusStopping, m.Current().Status)
+
+	m.Update(JobsModelFindMsg{Query: "generating"})
+	require.Equal(t, "2", m.Current().ID)
+	_, cmd = m.Update(JobsModelStopMsg{})
+	require.IsType(t, ErrorMsg{}, cmd())
+
+	// Tasks started from the jobs tab run over the whole library, so they're confirmed unless they change nothing or
+	// are scoped to paths.
+	_, cmd = m.Update(TaskMsg{Task: taskClean, DryRun: true})
+	require.True(t, cmd().(taskRequestMsg).SkipConfirm)
+	require.Equal(t, taskRequest{Task: taskClean, DryRun: true}, srv.tasks[0])
+	_, cmd = m.Update(TaskMsg{Task: taskClean})
+	request := cmd().(taskRequestMsg)
+	require.False(t, request.SkipConfirm)
+	require.Equal(t, taskClean, request.Task)
+	require.Equal(t, taskDetails[taskClean], request.Detail)
+	_, cmd = m.Update(TaskMsg{Task: taskScan, Confirm: true})
+	require.True(t, cmd().(taskRequestMsg).SkipConfirm)
+	_, cmd = m.Update(TaskMsg{Task: taskAutoTag, Paths: []string{"/mnt/new"}})
+	require.True(t, cmd().(taskRequestMsg).SkipConfirm)
+}
+
+func TestModelConfirmsTask(t *testing.T) {
+	var model tea.Model = New(&stash.LocalStash{}, nil)
+	started := taskStartedMsg{task: taskClean, jobID: "1"}
+	request := taskRequestMsg{Task: taskClean, Detail: taskDetails[taskClean], StartCmd: func() tea.Msg { return started }}
+
+	model, cmd := model.Update(request)
+	require.Nil(t, cmd)
+	require.NotNil(t, model.(Model).confirmation)
+	require.Contains(t, model.(Model).confirmation.Message, "Run clean over the whole library?")
+
+	model, cmd = model.Update(confirmTaskMsg{Request: request})
+	require.Nil(t, model.(Model).confirmation)
+	require.Equal(t, started, cmd())
+
+	request.SkipConfirm = true
+	model, cmd = model.Update(request)
+	require.Nil(t, model.(Model).confirmation)
+	require.Equal(t, started, cmd())
+}
+
+func TestModelShowsStartedJob(t *testing.T) {
+	var model tea.Model = New(&stash.LocalStash{}, nil)
+
+	model, _ = model.Update(taskStartedMsg{task: taskScan, jobID: "1"})
+	m := model.(Model)
+	require.Len(t, m.tabs, 2)
+	require.IsType(t, &JobsModel{}, m.tabs[m.active].model)
+
+	m.TabSet(0)
+	model, _ = m.Update(taskStartedMsg{task: taskScan, jobID: "2"})
+	m = model.(Model)
+	require.Len(t, m.tabs, 2)
+	require.Equal(t, 1, m.active)
+	require.Len(t, m.tabs[1].model.(*JobsModel).jobs, 2)
+}
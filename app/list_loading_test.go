@@ -9,6 +9,10 @@ import (
 )
 
 type sceneListTestService struct {
+	savedFilterTestService
+	scrapeTestService
+	editTestService
+	performerEditTestService
 	responses [][]stash.Scene
 }
 
@@ -18,13 +22,28 @@ func (s *sceneListTestService) Scenes(stash.FindFilter, stash.SceneFilter) tea.C
 	return func() tea.Msg { return scenesMsg{scenes: scenes, total: 100} }
 }
 
-func (s *sceneListTestService) DeleteScene(string) tea.Cmd             { return nil }
-func (s *sceneListTestService) TagScene(stash.Scene, []string) tea.Cmd { return nil }
-func (s *sceneListTestService) ResolveTags([]string) tea.Cmd           { return nil }
-func (s *sceneListTestService) ResolveStudios([]string) tea.Cmd        { return nil }
-func (s *sceneListTestService) ResolvePerformers([]string) tea.Cmd     { return nil }
+func (s *sceneListTestService) DeleteScene(string) tea.Cmd                                { return nil }
+func (s *sceneListTestService) TagScene(stash.Scene, []string) tea.Cmd                    { return nil }
+func (s *sceneListTestService) TagScenes([]string, []string) tea.Cmd                      { return nil }
+func (s *sceneListTestService) RetagScenes([]string, []string) tea.Cmd                    { return nil }
+func (s *sceneListTestService) UpdateScenes(stash.BulkSceneUpdate) tea.Cmd                { return nil }
+func (s *sceneListTestService) DeleteScenes([]string) tea.Cmd                             { return nil }
+func (s *sceneListTestService) RetagScene(stash.Scene, []string) tea.Cmd                  { return nil }
+func (s *sceneListTestService) RecordPlay(stash.Scene) tea.Cmd                            { return nil }
+func (s *sceneListTestService) OCounterScene(stash.Scene, oCounterChange) tea.Cmd         { return nil }
+func (s *sceneListTestService) UpdateScene(stash.Scene, stash.Scene) tea.Cmd              { return nil }
+func (s *sceneListTestService) StudioScene(stash.Scene, string) tea.Cmd                   { return nil }
+func (s *sceneListTestService) SaveSceneActivity(stash.Scene, *float64, *float64) tea.Cmd { return nil }
+func (s *sceneListTestService) ResolveTags([]string) tea.Cmd                              { return nil }
+func (s *sceneListTestService) ResolveStudios([]string) tea.Cmd                           { return nil }
+func (s *sceneListTestService) ResolvePerformers([]string) tea.Cmd                        { return nil }
+func (s *sceneListTestService) StartTask(taskRequest) tea.Cmd                             { return nil }
 
 type galleryListTestService struct {
+	savedFilterTestService
+	scrapeTestService
+	editTestService
+	performerEditTestService
 	responses [][]stash.Gallery
 }
 
@@ -34,11 +53,16 @@ func (s *galleryListTestService) Galleries(stash.FindFilter, stash.GalleryFilter
 	return func() tea.Msg { return galleriesMsg{galleries: galleries, total: 100} }
 }
 
-func (s *galleryListTestService) DeleteGallery(string) tea.Cmd               { return nil }
-func (s *galleryListTestService) TagGallery(stash.Gallery, []string) tea.Cmd { return nil }
-func (s *galleryListTestService) ResolveTags([]string) tea.Cmd               { return nil }
-func (s *galleryListTestService) ResolveStudios([]string) tea.Cmd            { return nil }
-func (s *galleryListTestService) ResolvePerformers([]string) tea.Cmd         { return nil }
+func (s *galleryListTestService) DeleteGallery(string) tea.Cmd                    { return nil }
+func (s *galleryListTestService) TagGallery(stash.Gallery, []string) tea.Cmd      { return nil }
+func (s *galleryListTestService) TagGalleries([]string, []string) tea.Cmd         { return nil }
+func (s *galleryListTestService) RetagGalleries([]string, []string) tea.Cmd       { return nil }
+func (s *galleryListTestService) UpdateGalleries(stash.BulkGalleryUpdate) tea.Cmd { return nil }
+func (s *galleryListTestService) DeleteGalleries([]string) tea.Cmd                { return nil }
+func (s *galleryListTestService) RetagGallery(stash.Gallery, []string) tea.Cmd    { return nil }
+func (s *galleryListTestService) ResolveTags([]string) tea.Cmd                    { return nil }
+func (s *galleryListTestService) ResolveStudios([]string) tea.Cmd                 { return nil }
+func (s *galleryListTestService) ResolvePerformers([]string) tea.Cmd              { return nil }
 
 func TestScenesModelIgnoresStaleListLoads(t *testing.T) {
 	srv := &sceneListTestService{responses: [][]stash.Scene{
@@ -0,0 +1,414 @@
+package app
+
+import (
+	"fmt"
+	"path"
+	"sync/atomic"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/drakenstar/stash-cli/command"
+	"github.com/drakenstar/stash-cli/stash"
+	"github.com/drakenstar/stash-cli/ui"
+)
+
+type markerFilterState struct {
+	query         string
+	sort          string
+	sortDirection string
+	markerFilter  stash.SceneMarkerFilter
+
+	pageState pageState
+}
+
+type MarkerService interface {
+	SceneMarkers(stash.FindFilter, stash.SceneMarkerFilter) tea.Cmd
+	ResolveTags([]string) tea.Cmd
+}
+
+// MarkersModel lists scene markers.  Opening a marker opens its scene starting at the marker's position.
+type MarkersModel struct {
+	MarkerService
+	StashLookup
+
+	pageState pageState
+	markers   []stash.SceneMarker
+
+	query         string
+	sort          string
+	sortDirection string
+	markerFilter  stash.SceneMarkerFilter
+
+	history []markerFilterState
+
+	screen Size
+
+	pendingFilterRequestID uint64
+	pendingFilter          *pendingMarkerFilter
+	listRequestID          uint64
+}
+
+type pendingMarkerFilter struct {
+	requestID uint64
+	msg       MarkersModelFilterMsg
+}
+
+func NewMarkersModel(markerService MarkerService, lookup StashLookup) *MarkersModel {
+	m := &MarkersModel{
+		MarkerService: markerService,
+		StashLookup:   lookup,
+	}
+	m.pageState.PerPage = 40
+	m.reset()
+	return m
+}
+
+func (m *MarkersModel) reset() tea.Cmd {
+	m.query = ""
+	m.sort = stash.SortUpdatedAt
+	m.sortDirection = stash.SortDirectionDesc
+	m.markerFilter = stash.SceneMarkerFilter{}
+	m.pageState.Reset()
+
+	return m.updateCmd()
+}
+
+func (m *MarkersModel) SetSize(s Size) tea.Cmd {
+	m.screen = s
+	m.pageState.SetPerPage(s.Height - 1) // account for status line
+	return m.updateCmd()
+}
+
+func (m *MarkersModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *MarkersModel) Title() string {
+	t := "Markers"
+	if m.query != "" {
+		t = fmt.Sprintf("\"%s\"", m.query)
+	}
+	return fmt.Sprintf("%c %s (%s)", '\U000f0c54', t, humanNumber(m.pageState.total))
+}
+
+func (m *MarkersModel) Current() stash.SceneMarker {
+	return m.markers[m.pageState.index]
+}
+
+// PreviewPath returns the path of the screenshot of the current marker.
+func (m *MarkersModel) PreviewPath() string {
+	if len(m.markers) == 0 {
+		return ""
+	}
+	marker := m.Current()
+	return "/scene/" + marker.Scene.ID + "/scene_marker/" + marker.ID + "/screenshot"
+}
+
+func (m *MarkersModel) PushState(mutate func(*MarkersModel)) (*MarkersModel, tea.Cmd) {
+	m.history = append(m.history, markerFilterState{
+		query:         m.query,
+		sort:          m.sort,
+		sortDirection: m.sortDirection,
+		markerFilter:  m.markerFilter,
+		pageState:     m.pageState,
+	})
+	mutate(m)
+	m.pageState.Reset()
+	return m, m.updateCmd()
+}
+
+// Pop sets the current state to the previous state from the history stack.  If the history stack is empty this is a
+// noop.
+func (m *MarkersModel) Pop() (*MarkersModel, tea.Cmd) {
+	if len(m.history) == 0 {
+		return m, nil
+	}
+
+	state := m.history[len(m.history)-1]
+	m.history = m.history[0 : len(m.history)-1]
+
+	m.pageState = state.pageState
+	m.query = state.query
+	m.sort = state.sort
+	m.sortDirection = state.sortDirection
+	m.markerFilter = state.markerFilter
+	m.markers = []stash.SceneMarker{}
+
+	return m, m.updateCmd()
+}
+
+var MarkersModelDefaultKeymap = map[string]string{
+	"up":    "skip -1",
+	"down":  "skip 1",
+	"enter": "open",
+	"z":     "skip -1",
+	"x":     "skip 1",
+	"o":     "open",
+	"r":     "sort random",
+	"u":     "undo",
+	"`":     "open-url",
+}
+
+var MarkersModelCommandConfig command.Config = command.Config{
+	"filter":   binder[MarkersModelFilterMsg](),
+	"open":     binder[MarkersModelOpenMsg](),
+	"open-url": binder[MarkersModelOpenURLMsg](),
+	"refresh":  binder[MarkersModelRefresh](),
+	"reset":    binder[MarkersModelResetMsg](),
+	"sort":     binder[MarkersModelSortMsg](),
+	"skip":     binder[MarkersModelSkipMsg](),
+	"undo":     binder[MarkersModelUndoMsg](),
+}
+
+var markerSortFields = sortFields{
+	"title":   stash.SortTitle,
+	"seconds": stash.SortSeconds,
+	"created": stash.SortCreatedAt,
+	"updated": stash.SortUpdatedAt,
+}
+
+func (m MarkersModel) CommandConfig() command.Config {
+	return MarkersModelCommandConfig
+}
+
+func (m MarkersModel) Search(query string) tea.Msg {
+	return MarkersModelFilterMsg{
+		Query: &query,
+	}
+}
+
+// MarkersModelFilterMsg controls the filtering of markers.  Tag matches markers having any of the given tags as either
+// their primary tag or one of their other tags.
+type MarkersModelFilterMsg struct {
+	Query *string
+	Tag   []string
+}
+
+type markerTagsResolvedMsg struct {
+	requestID uint64
+	ids       []string
+}
+
+// MarkersModelOpenMsg opens the scene of the current marker, starting playback at the marker.
+type MarkersModelOpenMsg struct{}
+
+type MarkersModelOpenURLMsg struct{}
+
+type MarkersModelRefresh struct{}
+
+type MarkersModelResetMsg struct{}
+
+type MarkersModelSkipMsg struct {
+	Count int `command:",positional"`
+}
+
+type MarkersModelSortMsg struct {
+	Field string `command:",positional"`
+}
+
+type MarkersModelUndoMsg struct{}
+
+func (m *MarkersModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case MarkersModelFilterMsg:
+		if needsEntityResolution(msg.Tag) {
+			requestID := atomic.AddUint64(&m.pendingFilterRequestID, 1)
+			m.pendingFilter = &pendingMarkerFilter{requestID: requestID, msg: msg}
+			return m, m.resolveMarkerTagsCmd(requestID, msg.Tag)
+		}
+		return m.applyFilter(msg, msg.Tag)
+
+	case markerTagsResolvedMsg:
+		if m.pendingFilter == nil || m.pendingFilter.requestID != msg.requestID {
+			return m, nil
+		}
+		pending := m.pendingFilter
+		m.pendingFilter = nil
+		return m.applyFilter(pending.msg, msg.ids)
+
+	case MarkersModelOpenMsg:
+		if len(m.markers) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no marker selected"))
+		}
+		marker := m.Current()
+		return m, func() tea.Msg { return OpenMsg{marker} }
+
+	case MarkersModelOpenURLMsg:
+		if len(m.markers) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no marker selected"))
+		}
+		src := path.Join("scenes", m.Current().Scene.ID)
+		return m, func() tea.Msg { return OpenMsg{src} }
+
+	case MarkersModelRefresh:
+		return m, m.updateCmd()
+
+	case MarkersModelResetMsg:
+		return m, m.reset()
+
+	case MarkersModelSortMsg:
+		sort, direction, err := markerSortFields.parse(msg.Field)
+		if err != nil {
+			return m, NewErrorCmd(err)
+		}
+		return m.PushState(func(mm *MarkersModel) {
+			mm.sort = sort
+			mm.sortDirection = direction
+		})
+
+	case MarkersModelSkipMsg:
+		if m.pageState.Skip(msg.Count) {
+			return m, m.updateCmd()
+		}
+
+	case MarkersModelUndoMsg:
+		return m.Pop()
+
+	case tea.KeyMsg:
+		if cmd, ok := MarkersModelDefaultKeymap[msg.String()]; ok {
+			return m, func() tea.Msg { return ui.CommandExecMsg{Command: cmd} }
+		}
+
+	case markersListLoadedMsg:
+		if msg.requestID != m.listRequestID {
+			return m, nil
+		}
+		m.markers, m.pageState.total = msg.markers, msg.total
+	}
+
+	return m, nil
+}
+
+func (m *MarkersModel) resolveMarkerTagsCmd(requestID uint64, rawTags []string) tea.Cmd {
+	tags := append([]string(nil), rawTags...)
+	return func() tea.Msg {
+		resolved := m.MarkerService.ResolveTags(tags)()
+		switch msg := resolved.(type) {
+		case resolvedTagIDsMsg:
+			return markerTagsResolvedMsg{requestID: requestID, ids: msg.ids}
+		case loadingMsg:
+			if payload, ok := msg.payload.(resolvedTagIDsMsg); ok {
+				msg.payload = markerTagsResolvedMsg{requestID: requestID, ids: payload.ids}
+			}
+			return msg
+		default:
+			return resolved
+		}
+	}
+}
+
+func (m *MarkersModel) applyFilter(msg MarkersModelFilterMsg, tagIDs []string) (*MarkersModel, tea.Cmd) {
+	return m.PushState(func(mm *MarkersModel) {
+		if msg.Query != nil {
+			mm.query = *msg.Query
+		}
+		if len(tagIDs) > 0 {
+			mm.markerFilter.Tags = &stash.HierarchicalMultiCriterion{
+				Value:    tagIDs,
+				Modifier: stash.CriterionModifierIncludes,
+			}
+		}
+	})
+}
+
+func (m MarkersModel) View() string {
+	var rows []ui.Row
+	for i, marker := range m.markers {
+		rows = append(rows, ui.Row{
+			Values: []string{
+				marker.Title,
+				marker.PrimaryTag.Name,
+				sceneTitle(marker.Scene),
+				timestamp(marker.Seconds),
+				tagList(marker.Tags),
+			},
+		})
+		if m.pageState.index == i {
+			rows[i].Background = &ColorRowSelected
+		}
+	}
+
+	leftStatus := []string{
+		m.pageState.String(),
+		sort(m.sort, m.sortDirection),
+	}
+
+	rightStatus := markerFilterStatus(m.markerFilter, m.StashLookup)
+	if m.query != "" {
+		rightStatus = append(rightStatus, "\""+m.query+"\"")
+	}
+	if len(m.history) > 0 {
+		rightStatus = append(rightStatus, fmt.Sprintf("[%d]", len(m.history)))
+	}
+
+	return lipgloss.JoinVertical(0,
+		statusBar.Render(m.screen.Width, leftStatus, rightStatus),
+		markersTable.Render(m.screen.Width, rows),
+	)
+}
+
+func (m *MarkersModel) updateCmd() tea.Cmd {
+	if m.pageState.PerPage == 0 {
+		return nil
+	}
+	requestID := atomic.AddUint64(&m.listRequestID, 1)
+	cmd := m.MarkerService.SceneMarkers(stash.FindFilter{
+		Query:     m.query,
+		Page:      m.pageState.page + 1,
+		PerPage:   m.pageState.PerPage,
+		Sort:      m.sort,
+		Direction: m.sortDirection,
+	}, m.markerFilter)
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		return wrapMarkersLoadedMsg(cmd(), requestID)
+	}
+}
+
+func wrapMarkersLoadedMsg(msg tea.Msg, requestID uint64) tea.Msg {
+	switch msg := msg.(type) {
+	case markersMsg:
+		return markersListLoadedMsg{requestID: requestID, markers: msg.markers, total: msg.total}
+	case loadingMsg:
+		if payload, ok := msg.payload.(markersMsg); ok {
+			msg.payload = markersListLoadedMsg{requestID: requestID, markers: payload.markers, total: payload.total}
+		}
+		return msg
+	default:
+		return msg
+	}
+}
+
+var (
+	markersTable = &ui.Table{
+		AltBackground: ColorBlack,
+		Cols: []ui.Column{
+			{
+				Name:       "Title",
+				Foreground: &ColorYellow,
+				Bold:       true,
+				Weight:     1,
+			},
+			{
+				Name:       "Primary Tag",
+				Foreground: &ColorPurple,
+			},
+			{
+				Name:   "Scene",
+				Weight: 2,
+			},
+			{
+				Name:       "Time",
+				Foreground: &ColorBlue,
+				Align:      lipgloss.Right,
+			},
+			{
+				Name:       "Tags",
+				Foreground: &ColorPurple,
+				Flex:       true,
+			},
+		},
+	}
+)
@@ -0,0 +1,52 @@
+package app
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/drakenstar/stash-cli/stash"
+	"github.com/stretchr/testify/require"
+)
+
+type markerTestService struct {
+	filters []stash.SceneMarkerFilter
+}
+
+func (s *markerTestService) SceneMarkers(_ stash.FindFilter, mf stash.SceneMarkerFilter) tea.Cmd {
+	s.filters = append(s.filters, mf)
+	return nil
+}
+
+func (s *markerTestService) ResolveTags([]string) tea.Cmd {
+	return func() tea.Msg { return resolvedTagIDsMsg{ids: []string{"7"}} }
+}
+
+func TestMarkersModelFilterResolvesTags(t *testing.T) {
+	m := NewMarkersModel(&markerTestService{}, tagResolveTestLookup{})
+
+	_, cmd := m.Update(MarkersModelFilterMsg{Tag: []string{"Foo"}})
+	require.NotNil(t, cmd)
+	require.Nil(t, m.markerFilter.Tags)
+
+	_, _ = m.Update(cmd())
+	require.Equal(t, []string{"7"}, m.markerFilter.Tags.Value)
+	require.Len(t, m.history, 1)
+}
+
+func TestMarkersModelOpenSendsMarker(t *testing.T) {
+	m := NewMarkersModel(&markerTestService{}, tagResolveTestLookup{})
+	marker := stash.SceneMarker{ID: "1", Title: "Intro", Seconds: 90.5, Scene: stash.Scene{ID: "42"}}
+	m.markers = []stash.SceneMarker{marker}
+
+	_, cmd := m.Update(MarkersModelOpenMsg{})
+	require.Equal(t, OpenMsg{marker}, cmd())
+
+	_, cmd = m.Update(MarkersModelOpenURLMsg{})
+	require.Equal(t, OpenMsg{"scenes/42"}, cmd())
+}
+
+func TestTimestamp(t *testing.T) {
+	require.Equal(t, "0:05", timestamp(5))
+	require.Equal(t, "1:30", timestamp(90.5))
+	require.Equal(t, "1:02:03", timestamp(3723))
+}
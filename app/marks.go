@@ -0,0 +1,89 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+)
+
+// markSet tracks the entities marked in a list tab so that commands can act on all of them at once.  Marks are kept by
+// ID in the order they were made, and survive paging and filtering of the tab.
+type markSet[T interface{ EntityID() string }] struct {
+	ids   []string
+	items map[string]T
+}
+
+// Toggle marks the item if it is not already marked, otherwise it is unmarked.
+func (s *markSet[T]) Toggle(item T) {
+	if s.Has(item.EntityID()) {
+		s.remove(item.EntityID())
+		return
+	}
+	s.Mark(item)
+}
+
+// Mark adds the item to the set if it is not already marked.
+func (s *markSet[T]) Mark(item T) {
+	id := item.EntityID()
+	if s.Has(id) {
+		return
+	}
+	if s.items == nil {
+		s.items = make(map[string]T)
+	}
+	s.ids = append(s.ids, id)
+	s.items[id] = item
+}
+
+// Update replaces a marked item with a newer copy of it.  Items that are not marked are ignored.
+func (s *markSet[T]) Update(item T) {
+	if s.Has(item.EntityID()) {
+		s.items[item.EntityID()] = item
+	}
+}
+
+func (s *markSet[T]) Clear() {
+	s.ids = nil
+	s.items = nil
+}
+
+func (s markSet[T]) Has(id string) bool {
+	_, ok := s.items[id]
+	return ok
+}
+
+func (s markSet[T]) Len() int {
+	return len(s.ids)
+}
+
+// IDs returns the IDs of all marked items in the order they were marked.
+func (s markSet[T]) IDs() []string {
+	return append([]string(nil), s.ids...)
+}
+
+// Items returns all marked items in the order they were marked.
+func (s markSet[T]) Items() []T {
+	items := make([]T, len(s.ids))
+	for i, id := range s.ids {
+		items[i] = s.items[id]
+	}
+	return items
+}
+
+func (s *markSet[T]) remove(id string) {
+	delete(s.items, id)
+	for i := range s.ids {
+		if s.ids[i] == id {
+			s.ids = append(s.ids[:i], s.ids[i+1:]...)
+			return
+		}
+	}
+}
+
+// markedTitles lists the titles of marked items for display in a confirmation, abbreviating long lists.
+func markedTitles(titles []string) string {
+	const limit = 5
+	if len(titles) <= limit {
+		return strings.Join(titles, "\n")
+	}
+	return fmt.Sprintf("%s\n…and %d more", strings.Join(titles[:limit], "\n"), len(titles)-limit)
+}
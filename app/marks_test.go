@@ -0,0 +1,132 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/drakenstar/stash-cli/command"
+	"github.com/drakenstar/stash-cli/stash"
+	"github.com/hasura/go-graphql-client"
+	"github.com/stretchr/testify/require"
+)
+
+type bulkTestService struct {
+	deleteTestService
+	tagged    [][]string
+	updates   []stash.BulkSceneUpdate
+	gUpdates  []stash.BulkGalleryUpdate
+	deleted   []string
+	singleTag bool
+}
+
+func (s *bulkTestService) TagScene(stash.Scene, []string) tea.Cmd {
+	s.singleTag = true
+	return nil
+}
+
+func (s *bulkTestService) TagScenes(ids []string, names []string) tea.Cmd {
+	s.tagged = append(s.tagged, ids, names)
+	return nil
+}
+
+func (s *bulkTestService) UpdateScenes(update stash.BulkSceneUpdate) tea.Cmd {
+	s.updates = append(s.updates, update)
+	return func() tea.Msg {
+		return scenesUpdatedMsg{scenes: []stash.Scene{{ID: "1", Title: "One", Organized: true}, {ID: "3", Title: "Three", Organized: true}}}
+	}
+}
+
+func (s *bulkTestService) DeleteScenes(ids []string) tea.Cmd {
+	s.deleted = ids
+	return func() tea.Msg { return scenesDeletedMsg{ids: ids} }
+}
+
+func (s *bulkTestService) UpdateGalleries(update stash.BulkGalleryUpdate) tea.Cmd {
+	s.gUpdates = append(s.gUpdates, update)
+	return nil
+}
+
+func TestScenesModelMarkedCommands(t *testing.T) {
+	srv := &bulkTestService{}
+	m := NewScenesModel(srv, deleteTestLookup{})
+	m.scenes = []stash.Scene{{ID: "1", Title: "One"}, {ID: "2", Title: "Two"}, {ID: "3", Title: "Three"}}
+	m.pageState.total = 3
+
+	for _, input := range []string{"mark", "skip 2", "mark", "skip -1", "mark", "mark"} {
+		msg, err := ScenesModelCommandConfig.Resolve(command.Parser(input))
+		require.NoError(t, err)
+		m.Update(msg)
+	}
+	require.Equal(t, []string{"1", "3"}, m.marks.IDs())
+	require.Contains(t, m.View(), "2 marked")
+
+	m.Update(ScenesModelTagMsg{Tags: []string{"Foo"}})
+	m.Update(ScenesModelUntagMsg{Tags: []string{"Bar"}})
+	require.Equal(t, [][]string{{"1", "3"}, {"Foo"}, {"1", "3"}, {"-Bar"}}, srv.tagged)
+	require.False(t, srv.singleTag)
+
+	m.Update(ScenesModelRateMsg{Rating: 60})
+	_, cmd := m.Update(ScenesModelOrganiseMsg{})
+	m.Update(cmd())
+	require.Equal(t, []graphql.ID{"1", "3"}, srv.updates[0].IDs)
+	require.Equal(t, 60, *srv.updates[0].Rating)
+	require.True(t, *srv.updates[1].Organized)
+	require.True(t, m.scenes[2].Organized)
+
+	// Once every marked scene is organised, organising again unorganises them.
+	m.Update(ScenesModelOrganiseMsg{})
+	require.False(t, *srv.updates[2].Organized)
+
+	_, cmd = m.Update(ScenesModelDeleteMsg{})
+	request := cmd().(deleteRequestMsg)
+	require.Equal(t, "2 scenes", request.Entity)
+	require.Equal(t, "One\nThree", request.Title)
+	require.Equal(t, []string{"1", "3"}, srv.deleted)
+
+	m.Update(request.DeleteCmd())
+	require.Zero(t, m.marks.Len())
+	require.Equal(t, 1, m.pageState.total)
+
+	m.Update(ScenesModelTagMsg{Tags: []string{"Foo"}})
+	require.True(t, srv.singleTag)
+}
+
+func TestGalleriesModelMarkedCommands(t *testing.T) {
+	srv := &bulkTestService{}
+	m := NewGalleriesModel(srv, deleteTestLookup{})
+	m.galleries = []stash.Gallery{{ID: "1", Organized: true}, {ID: "2"}}
+
+	// Without marks rate and organise act on the current gallery.
+	m.Update(GalleriesModelRateMsg{Rating: 20})
+	m.Update(GalleriesModelOrganiseMsg{})
+	require.Equal(t, []graphql.ID{"1"}, srv.gUpdates[0].IDs)
+	require.False(t, *srv.gUpdates[1].Organized)
+
+	m.Update(GalleriesModelMarkMsg{Target: "all"})
+	m.Update(GalleriesModelOrganiseMsg{})
+	require.Equal(t, []graphql.ID{"1", "2"}, srv.gUpdates[2].IDs)
+	require.True(t, *srv.gUpdates[2].Organized)
+
+	m.Update(GalleriesModelMarkMsg{Target: "clear"})
+	require.Zero(t, m.marks.Len())
+
+	_, cmd := m.Update(GalleriesModelMarkMsg{Target: "some"})
+	require.IsType(t, ErrorMsg{}, cmd())
+}
+
+func TestBulkTagChanges(t *testing.T) {
+	backend := &tagCreateTestStash{}
+	svc := &cmdService{Stash: backend, cache: newCacheLookup()}
+
+	changes, err := svc.bulkTagChanges(context.Background(), []string{"new", "-existing"}, false)
+	require.NoError(t, err)
+	require.Equal(t, []stash.BulkUpdateIDs{
+		{IDs: []graphql.ID{"2"}, Mode: stash.BulkUpdateIDModeAdd},
+		{IDs: []graphql.ID{"1"}, Mode: stash.BulkUpdateIDModeRemove},
+	}, changes)
+
+	changes, err = svc.bulkTagChanges(context.Background(), nil, true)
+	require.NoError(t, err)
+	require.Equal(t, []stash.BulkUpdateIDs{{IDs: []graphql.ID{}, Mode: stash.BulkUpdateIDModeSet}}, changes)
+}
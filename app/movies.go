@@ -0,0 +1,481 @@
+package app
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"sync/atomic"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/drakenstar/stash-cli/command"
+	"github.com/drakenstar/stash-cli/stash"
+	"github.com/drakenstar/stash-cli/ui"
+	"github.com/hasura/go-graphql-client"
+)
+
+type movieFilterState struct {
+	query         string
+	sort          string
+	sortDirection string
+	movieFilter   stash.MovieFilter
+
+	pageState pageState
+}
+
+type MovieService interface {
+	Movies(stash.FindFilter, stash.MovieFilter) tea.Cmd
+	CreateMovie(string) tea.Cmd
+	UpdateMovie(stash.MovieUpdate) tea.Cmd
+	ResolveStudios([]string) tea.Cmd
+}
+
+type MoviesModel struct {
+	MovieService
+	StashLookup
+
+	pageState pageState
+	movies    []stash.MovieDetail
+
+	query         string
+	sort          string
+	sortDirection string
+	movieFilter   stash.MovieFilter
+
+	history []movieFilterState
+
+	screen Size
+
+	pendingFilterRequestID uint64
+	pendingFilter          *pendingMovieFilter
+	listRequestID          uint64
+}
+
+type pendingMovieFilter struct {
+	requestID uint64
+	msg       MoviesModelFilterMsg
+}
+
+func NewMoviesModel(movieService MovieService, lookup StashLookup) *MoviesModel {
+	m := &MoviesModel{
+		MovieService: movieService,
+		StashLookup:  lookup,
+	}
+	m.pageState.PerPage = 40
+	m.reset()
+	return m
+}
+
+func (m *MoviesModel) reset() tea.Cmd {
+	m.query = ""
+	m.sort = stash.SortName
+	m.sortDirection = stash.SortDirectionAsc
+	m.movieFilter = stash.MovieFilter{}
+	m.pageState.Reset()
+
+	return m.updateCmd()
+}
+
+func (m *MoviesModel) SetSize(s Size) tea.Cmd {
+	m.screen = s
+	m.pageState.SetPerPage(s.Height - 1) // account for status line
+	return m.updateCmd()
+}
+
+func (m *MoviesModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *MoviesModel) Title() string {
+	t := "Movies"
+	if m.query != "" {
+		t = fmt.Sprintf("\"%s\"", m.query)
+	}
+	return fmt.Sprintf("%c %s (%s)", '\U000f0231', t, humanNumber(m.pageState.total))
+}
+
+func (m *MoviesModel) Current() stash.MovieDetail {
+	return m.movies[m.pageState.index]
+}
+
+func (m *MoviesModel) PushState(mutate func(*MoviesModel)) (*MoviesModel, tea.Cmd) {
+	m.history = append(m.history, movieFilterState{
+		query:         m.query,
+		sort:          m.sort,
+		sortDirection: m.sortDirection,
+		movieFilter:   m.movieFilter,
+		pageState:     m.pageState,
+	})
+	mutate(m)
+	m.pageState.Reset()
+	return m, m.updateCmd()
+}
+
+// Pop sets the current state to the previous state from the history stack.  If the history stack is empty this is a
+// noop.
+func (m *MoviesModel) Pop() (*MoviesModel, tea.Cmd) {
+	if len(m.history) == 0 {
+		return m, nil
+	}
+
+	state := m.history[len(m.history)-1]
+	m.history = m.history[0 : len(m.history)-1]
+
+	m.pageState = state.pageState
+	m.query = state.query
+	m.sort = state.sort
+	m.sortDirection = state.sortDirection
+	m.movieFilter = state.movieFilter
+	m.movies = []stash.MovieDetail{}
+
+	return m, m.updateCmd()
+}
+
+var MoviesModelDefaultKeymap = map[string]string{
+	"up":    "skip -1",
+	"down":  "skip 1",
+	"enter": "scenes",
+	"z":     "skip -1",
+	"x":     "skip 1",
+	"o":     "scenes",
+	"r":     "sort random",
+	"u":     "undo",
+	"`":     "open-url",
+}
+
+var MoviesModelCommandConfig command.Config = command.Config{
+	"create":   binder[MoviesModelCreateMsg](),
+	"filter":   binder[MoviesModelFilterMsg](),
+	"open-url": binder[MoviesModelOpenURLMsg](),
+	"refresh":  binder[MoviesModelRefresh](),
+	"rename":   binder[MoviesModelRenameMsg](),
+	"reset":    binder[MoviesModelResetMsg](),
+	"scenes":   binder[MoviesModelScenesMsg](),
+	"sort":     binder[MoviesModelSortMsg](),
+	"skip":     binder[MoviesModelSkipMsg](),
+	"undo":     binder[MoviesModelUndoMsg](),
+}
+
+var movieSortFields = sortFields{
+	"name":     stash.SortName,
+	"date":     stash.SortDate,
+	"duration": stash.SortDuration,
+	"scenes":   stash.SortSceneCount,
+	"created":  stash.SortCreatedAt,
+	"updated":  stash.SortUpdatedAt,
+}
+
+func (m MoviesModel) CommandConfig() command.Config {
+	return MoviesModelCommandConfig
+}
+
+func (m MoviesModel) Search(query string) tea.Msg {
+	return MoviesModelFilterMsg{
+		Query: &query,
+	}
+}
+
+// MoviesModelFilterMsg controls the filtering of movies.  Studio includes movies of any of the given studios and
+// their sub-studios.
+type MoviesModelFilterMsg struct {
+	Query  *string
+	Rating *int
+	Studio []string
+}
+
+type movieStudiosResolvedMsg struct {
+	requestID uint64
+	ids       []string
+}
+
+type MoviesModelCreateMsg struct {
+	Name string `command:",positional"`
+}
+
+type MoviesModelRenameMsg struct {
+	Name string `command:",positional"`
+}
+
+type MoviesModelOpenURLMsg struct{}
+
+// MoviesModelScenesMsg opens a new scenes tab listing the scenes of the current movie in the order they appear in it.
+type MoviesModelScenesMsg struct{}
+
+type MoviesModelRefresh struct{}
+
+type MoviesModelResetMsg struct{}
+
+type MoviesModelSkipMsg struct {
+	Count int `command:",positional"`
+}
+
+type MoviesModelSortMsg struct {
+	Field string `command:",positional"`
+}
+
+type MoviesModelUndoMsg struct{}
+
+func (m *MoviesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case MoviesModelFilterMsg:
+		if needsEntityResolution(msg.Studio) {
+			requestID := atomic.AddUint64(&m.pendingFilterRequestID, 1)
+			m.pendingFilter = &pendingMovieFilter{requestID: requestID, msg: msg}
+			return m, m.resolveMovieStudiosCmd(requestID, msg.Studio)
+		}
+		return m.applyFilter(msg, msg.Studio)
+
+	case movieStudiosResolvedMsg:
+		if m.pendingFilter == nil || m.pendingFilter.requestID != msg.requestID {
+			return m, nil
+		}
+		pending := m.pendingFilter
+		m.pendingFilter = nil
+		return m.applyFilter(pending.msg, msg.ids)
+
+	case MoviesModelCreateMsg:
+		if msg.Name == "" {
+			return m, NewErrorCmd(fmt.Errorf("no movie name specified"))
+		}
+		return m, m.MovieService.CreateMovie(msg.Name)
+
+	case MoviesModelRenameMsg:
+		if len(m.movies) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no movie selected"))
+		}
+		if msg.Name == "" {
+			return m, NewErrorCmd(fmt.Errorf("no movie name specified"))
+		}
+		return m, m.MovieService.UpdateMovie(stash.MovieUpdate{
+			ID:   graphql.ID(m.Current().ID),
+			Name: &msg.Name,
+		})
+
+	case MoviesModelOpenURLMsg:
+		if len(m.movies) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no movie selected"))
+		}
+		src := path.Join("movies", m.Current().ID)
+		return m, func() tea.Msg { return OpenMsg{src} }
+
+	case MoviesModelScenesMsg:
+		if len(m.movies) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no movie selected"))
+		}
+		movie := m.Current().Movie()
+		return m, func() tea.Msg { return openMovieScenesTabMsg(movie) }
+
+	case MoviesModelRefresh:
+		return m, m.updateCmd()
+
+	case MoviesModelResetMsg:
+		return m, m.reset()
+
+	case MoviesModelSortMsg:
+		sort, direction, err := movieSortFields.parse(msg.Field)
+		if err != nil {
+			return m, NewErrorCmd(err)
+		}
+		return m.PushState(func(mm *MoviesModel) {
+			mm.sort = sort
+			mm.sortDirection = direction
+		})
+
+	case MoviesModelSkipMsg:
+		if m.pageState.Skip(msg.Count) {
+			return m, m.updateCmd()
+		}
+
+	case MoviesModelUndoMsg:
+		return m.Pop()
+
+	case tea.KeyMsg:
+		if cmd, ok := MoviesModelDefaultKeymap[msg.String()]; ok {
+			return m, func() tea.Msg { return ui.CommandExecMsg{Command: cmd} }
+		}
+
+	case moviesListLoadedMsg:
+		if msg.requestID != m.listRequestID {
+			return m, nil
+		}
+		m.movies, m.pageState.total = msg.movies, msg.total
+
+	case movieCreatedMsg:
+		return m, m.updateCmd()
+
+	case movieUpdatedMsg:
+		for i := range m.movies {
+			if m.movies[i].ID == msg.movie.ID {
+				m.movies[i] = msg.movie
+			}
+		}
+	}
+
+	return m, nil
+}
+
+func (m *MoviesModel) resolveMovieStudiosCmd(requestID uint64, rawStudios []string) tea.Cmd {
+	studios := append([]string(nil), rawStudios...)
+	return func() tea.Msg {
+		resolved := m.MovieService.ResolveStudios(studios)()
+		switch msg := resolved.(type) {
+		case resolvedStudioIDsMsg:
+			return movieStudiosResolvedMsg{requestID: requestID, ids: msg.ids}
+		case loadingMsg:
+			if payload, ok := msg.payload.(resolvedStudioIDsMsg); ok {
+				msg.payload = movieStudiosResolvedMsg{requestID: requestID, ids: payload.ids}
+			}
+			return msg
+		default:
+			return resolved
+		}
+	}
+}
+
+func (m *MoviesModel) applyFilter(msg MoviesModelFilterMsg, studioIDs []string) (*MoviesModel, tea.Cmd) {
+	return m.PushState(func(mm *MoviesModel) {
+		if msg.Query != nil {
+			mm.query = *msg.Query
+		}
+		if msg.Rating != nil {
+			mm.movieFilter.Rating100 = &stash.IntCriterion{
+				Modifier: stash.CriterionModifierEquals,
+				Value:    *msg.Rating,
+			}
+		}
+		if len(studioIDs) > 0 {
+			mm.movieFilter.Studios = &stash.HierarchicalMultiCriterion{
+				Value:    studioIDs,
+				Modifier: stash.CriterionModifierIncludes,
+				Depth:    -1,
+			}
+		}
+	})
+}
+
+func (m MoviesModel) View() string {
+	var rows []ui.Row
+	for i, movie := range m.movies {
+		var duration string
+		if movie.Duration > 0 {
+			duration = timestamp(float64(movie.Duration))
+		}
+		rows = append(rows, ui.Row{
+			Values: []string{
+				movie.Name,
+				movie.Studio.Name,
+				movie.Date,
+				duration,
+				strconv.Itoa(movie.SceneCount),
+			},
+		})
+		if m.pageState.index == i {
+			rows[i].Background = &ColorRowSelected
+		}
+	}
+
+	leftStatus := []string{
+		m.pageState.String(),
+		sort(m.sort, m.sortDirection),
+	}
+
+	rightStatus := movieFilterStatus(m.movieFilter, m.StashLookup)
+	if m.query != "" {
+		rightStatus = append(rightStatus, "\""+m.query+"\"")
+	}
+	if len(m.history) > 0 {
+		rightStatus = append(rightStatus, fmt.Sprintf("[%d]", len(m.history)))
+	}
+
+	return lipgloss.JoinVertical(0,
+		statusBar.Render(m.screen.Width, leftStatus, rightStatus),
+		moviesTable.Render(m.screen.Width, rows),
+	)
+}
+
+// openMovieScenesTabMsg returns a ModelTabOpenMsg that opens a new scenes tab listing the scenes of a movie, sorted by
+// their position within it.
+func openMovieScenesTabMsg(movie stash.Movie) ModelTabOpenMsg {
+	return ModelTabOpenMsg{
+		Name: "scenes",
+		Configure: func(t TabModel) {
+			if sm, ok := t.(*ScenesModel); ok {
+				sm.sceneFilter = stash.SceneFilter{
+					Movies: &stash.MultiCriterion{
+						Value:    []string{movie.ID},
+						Modifier: stash.CriterionModifierIncludes,
+					},
+				}
+				sm.sort = stash.SortMovieSceneIndex
+				sm.sortDirection = stash.SortDirectionAsc
+			}
+		},
+	}
+}
+
+func (m *MoviesModel) updateCmd() tea.Cmd {
+	if m.pageState.PerPage == 0 {
+		return nil
+	}
+	requestID := atomic.AddUint64(&m.listRequestID, 1)
+	cmd := m.MovieService.Movies(stash.FindFilter{
+		Query:     m.query,
+		Page:      m.pageState.page + 1,
+		PerPage:   m.pageState.PerPage,
+		Sort:      m.sort,
+		Direction: m.sortDirection,
+	}, m.movieFilter)
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		return wrapMoviesLoadedMsg(cmd(), requestID)
+	}
+}
+
+func wrapMoviesLoadedMsg(msg tea.Msg, requestID uint64) tea.Msg {
+	switch msg := msg.(type) {
+	case moviesMsg:
+		return moviesListLoadedMsg{requestID: requestID, movies: msg.movies, total: msg.total}
+	case loadingMsg:
+		if payload, ok := msg.payload.(moviesMsg); ok {
+			msg.payload = moviesListLoadedMsg{requestID: requestID, movies: payload.movies, total: payload.total}
+		}
+		return msg
+	default:
+		return msg
+	}
+}
+
+var (
+	moviesTable = &ui.Table{
+		AltBackground: ColorBlack,
+		Cols: []ui.Column{
+			{
+				Name:       "Name",
+				Foreground: &ColorYellow,
+				Bold:       true,
+				Weight:     2,
+			},
+			{
+				Name:       "Studio",
+				Foreground: &ColorPurple,
+				Weight:     1,
+			},
+			{
+				Name:       "Date",
+				Foreground: &ColorGrey,
+			},
+			{
+				Name:  "Duration",
+				Align: lipgloss.Right,
+			},
+			{
+				Name:       "Scenes",
+				Foreground: &ColorBlue,
+				Align:      lipgloss.Right,
+				Flex:       true,
+			},
+		},
+	}
+)
@@ -0,0 +1,79 @@
+package app
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/drakenstar/stash-cli/stash"
+	"github.com/stretchr/testify/require"
+)
+
+type movieTestService struct {
+	updates []stash.MovieUpdate
+}
+
+func (s *movieTestService) Movies(stash.FindFilter, stash.MovieFilter) tea.Cmd { return nil }
+func (s *movieTestService) CreateMovie(string) tea.Cmd                         { return nil }
+
+func (s *movieTestService) UpdateMovie(update stash.MovieUpdate) tea.Cmd {
+	s.updates = append(s.updates, update)
+	return nil
+}
+
+func (s *movieTestService) ResolveStudios([]string) tea.Cmd {
+	return func() tea.Msg { return resolvedStudioIDsMsg{ids: []string{"3"}} }
+}
+
+func TestMoviesModelScenesOpensInSceneOrder(t *testing.T) {
+	m := NewMoviesModel(&movieTestService{}, tagResolveTestLookup{})
+	m.movies = []stash.MovieDetail{{ID: "9", Name: "Feature"}}
+
+	_, cmd := m.Update(MoviesModelScenesMsg{})
+	open := cmd().(ModelTabOpenMsg)
+	scenes := NewScenesModel(deleteTestService{}, tagResolveTestLookup{})
+	open.Configure(scenes)
+	require.Equal(t, []string{"9"}, scenes.sceneFilter.Movies.Value)
+	require.Equal(t, stash.SortMovieSceneIndex, scenes.sort)
+	require.Equal(t, stash.SortDirectionAsc, scenes.sortDirection)
+}
+
+func TestMoviesModelFilterAndRename(t *testing.T) {
+	srv := &movieTestService{}
+	m := NewMoviesModel(srv, tagResolveTestLookup{})
+	m.movies = []stash.MovieDetail{{ID: "9", Name: "Feature"}}
+
+	_, cmd := m.Update(MoviesModelFilterMsg{Studio: []string{"Studio"}})
+	m.Update(cmd())
+	require.Equal(t, []string{"3"}, m.movieFilter.Studios.Value)
+
+	m.movies = []stash.MovieDetail{{ID: "9", Name: "Feature"}}
+	m.Update(MoviesModelRenameMsg{Name: "Feature II"})
+	require.Equal(t, "Feature II", *srv.updates[0].Name)
+
+	m.Update(movieUpdatedMsg{movie: stash.MovieDetail{ID: "9", Name: "Feature II"}})
+	require.Equal(t, "Feature II", m.Current().Name)
+}
+
+func TestScenesModelMovieRequiresMovie(t *testing.T) {
+	m := NewScenesModel(deleteTestService{}, tagResolveTestLookup{})
+	m.scenes = []stash.Scene{{ID: "1"}}
+
+	_, cmd := m.Update(ScenesModelMovieMsg{})
+	require.IsType(t, ErrorMsg{}, cmd())
+
+	m.scenes[0].Movies = []stash.SceneMovie{{Movie: stash.Movie{ID: "9"}, SceneIndex: 2}}
+	_, cmd = m.Update(ScenesModelMovieMsg{})
+	require.Equal(t, "scenes", cmd().(ModelTabOpenMsg).Name)
+}
+
+func TestCacheLookupMovies(t *testing.T) {
+	c := newCacheLookup()
+	c.CacheScenes([]stash.Scene{{Movies: []stash.SceneMovie{{Movie: stash.Movie{ID: "9", Name: "Feature"}}}}})
+
+	movie, err := c.GetMovie("9")
+	require.NoError(t, err)
+	require.Equal(t, "Feature", movie.Name)
+	require.Equal(t, []string{"Movies in Feature"}, sceneFilterStatus(stash.SceneFilter{
+		Movies: &stash.MultiCriterion{Value: []string{"9"}, Modifier: stash.CriterionModifierIncludes},
+	}, c))
+}
@@ -105,12 +105,17 @@ func (p *pageState) SetPerPage(perPage int) {
 
 // DeleteCurrent updates page state after the current item is removed so that the next fetch targets a valid position.
 func (p *pageState) DeleteCurrent() {
+	p.Delete(1)
+}
+
+// Delete updates page state after count items are removed, keeping the current position where it is still valid.
+func (p *pageState) Delete(count int) {
 	if p.total == 0 {
 		return
 	}
 
 	position := p.Position()
-	p.total--
+	p.total = max(p.total-count, 0)
 	p.opened = false
 
 	if p.total <= 0 {
@@ -90,6 +90,21 @@ func TestPaginator(t *testing.T) {
 		require.False(t, p.opened)
 	})
 
+	t.Run("delete many clamps to the last item", func(t *testing.T) {
+		p := pageState{
+			total:   12,
+			PerPage: 5,
+			page:    2,
+			index:   1,
+		}
+
+		p.Delete(4)
+
+		require.Equal(t, 8, p.total)
+		require.Equal(t, 1, p.page)
+		require.Equal(t, 2, p.index)
+	})
+
 	t.Run("set per page preserves absolute position", func(t *testing.T) {
 		p := pageState{
 			PerPage: 5,
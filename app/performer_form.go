@@ -0,0 +1,285 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/drakenstar/stash-cli/command"
+	"github.com/drakenstar/stash-cli/stash"
+	"github.com/drakenstar/stash-cli/ui"
+	"github.com/hasura/go-graphql-client"
+)
+
+// PerformerEditService loads performers for editing and saves the changes made to them.
+type PerformerEditService interface {
+	LoadPerformer(string) tea.Cmd
+	SavePerformer(stash.Performer, stash.Performer) tea.Cmd
+	FavouritePerformers([]string, bool) tea.Cmd
+}
+
+// PerformerNewMsg opens a form to create a performer, with the name filled in if given.
+type PerformerNewMsg struct {
+	Name string `command:",positional"`
+}
+
+// PerformerEditMsg opens a form to edit the performer given by name or ID.  Without a performer the current performer
+// is edited, or the performer of the current scene if it has only one.
+type PerformerEditMsg struct {
+	Performer string `command:",positional"`
+}
+
+// PerformerFavouriteMsg toggles whether the current performer is a favourite, or the performers of the current scene.
+type PerformerFavouriteMsg struct{}
+
+// performerCommand returns the performer command, which creates and edits performers.
+func performerCommand() command.Command {
+	return command.Command{
+		Resolve: func(command.Iterator) (any, error) {
+			return nil, fmt.Errorf("expected one of new, edit, favourite")
+		},
+		SubCommands: command.Config{
+			"edit":      binder[PerformerEditMsg](),
+			"favourite": binder[PerformerFavouriteMsg](),
+			"new":       binder[PerformerNewMsg](),
+		},
+	}
+}
+
+// isPerformerEditCommand returns true if the tokens are a performer edit command, which takes the name of a performer.
+func isPerformerEditCommand(tokens []commandToken) bool {
+	return len(tokens) >= 2 && tokens[0].raw == "performer" && tokens[1].raw == "edit"
+}
+
+// performerLoadedMsg is received with a performer to edit.
+type performerLoadedMsg struct {
+	performer stash.Performer
+}
+
+// performersSavedMsg is received once performers have been created or changed.
+type performersSavedMsg struct {
+	performers []stash.Performer
+	created    bool
+}
+
+// performerFormMsg opens a form to edit performer, which is a new performer if it has no ID.  Save is called with the
+// performer as edited.
+type performerFormMsg struct {
+	performer stash.Performer
+	save      func(stash.Performer) tea.Cmd
+}
+
+// performerFormSubmitMsg is sent with the values of a submitted performer form.
+type performerFormSubmitMsg struct {
+	form   performerFormMsg
+	values []string
+}
+
+// performerFormGenders are the genders that can be chosen in a performer form.
+var performerFormGenders = []string{"", "female", "male", "transgender_female", "transgender_male", "intersex", "non_binary"}
+
+// The fields of a performer form, in order.
+const (
+	performerFormName = iota
+	performerFormDisambiguation
+	performerFormAliases
+	performerFormGender
+	performerFormBirthdate
+	performerFormCountry
+	performerFormURLs
+	performerFormTags
+	performerFormFavourite
+)
+
+// newPerformerForm returns a form for msg.  Lists such as aliases and tags are edited as comma separated values.
+func newPerformerForm(msg performerFormMsg) ui.Form {
+	p := msg.performer
+	title := "New Performer"
+	if p.ID != "" {
+		title = "Edit " + performerName(p)
+	}
+	favourite := "no"
+	if p.Favorite {
+		favourite = "yes"
+	}
+	tags := make([]string, len(p.Tags))
+	for i, tag := range p.Tags {
+		tags[i] = tag.Name
+	}
+	fields := []ui.FormField{
+		performerFormName:           {Label: "Name", Value: p.Name},
+		performerFormDisambiguation: {Label: "Disambiguation", Value: p.Disambiguation},
+		performerFormAliases:        {Label: "Aliases", Value: strings.Join(p.Aliases, ", ")},
+		performerFormGender:         {Label: "Gender", Value: strings.ToLower(p.Gender.Enum()), Options: performerFormGenders},
+		performerFormBirthdate:      {Label: "Birthdate", Value: p.Birthdate},
+		performerFormCountry:        {Label: "Country", Value: string(p.Country)},
+		performerFormURLs:           {Label: "URLs", Value: strings.Join(p.URLs, ", ")},
+		performerFormTags:           {Label: "Tags", Value: strings.Join(tags, ", ")},
+		performerFormFavourite:      {Label: "Favourite", Value: favourite, Options: []string{"no", "yes"}},
+	}
+	return ui.NewForm(title, fields, func(values []string) tea.Cmd {
+		return func() tea.Msg { return performerFormSubmitMsg{form: msg, values: values} }
+	}, func() tea.Msg { return dismissModalMsg{} })
+}
+
+// parsePerformerForm returns the performer of a form with the values submitted.  Tags are returned by name only, and
+// are resolved when the performer is saved.
+func parsePerformerForm(p stash.Performer, values []string) (stash.Performer, error) {
+	p.Name = strings.TrimSpace(values[performerFormName])
+	if p.Name == "" {
+		return p, fmt.Errorf("a performer must have a name")
+	}
+	p.Disambiguation = strings.TrimSpace(values[performerFormDisambiguation])
+	p.Aliases = splitFormList(values[performerFormAliases])
+	p.URLs = splitFormList(values[performerFormURLs])
+
+	gender, err := stash.ParseGender(values[performerFormGender])
+	if err != nil {
+		return p, fmt.Errorf("unknown gender '%s'", values[performerFormGender])
+	}
+	p.Gender = gender
+
+	p.Birthdate = strings.TrimSpace(values[performerFormBirthdate])
+	if p.Birthdate != "" {
+		if _, err := time.Parse(time.DateOnly, p.Birthdate); err != nil {
+			return p, fmt.Errorf("birthdate must be given as YYYY-MM-DD")
+		}
+	}
+
+	country := strings.ToUpper(strings.TrimSpace(values[performerFormCountry]))
+	if country != "" && (len(country) != 2 || strings.IndexFunc(country, func(r rune) bool { return r < 'A' || r > 'Z' }) >= 0) {
+		return p, fmt.Errorf("country must be a two letter code such as AU")
+	}
+	p.Country = stash.Country(country)
+
+	p.Tags = nil
+	for _, name := range splitFormList(values[performerFormTags]) {
+		p.Tags = append(p.Tags, stash.Tag{Name: name})
+	}
+	p.Favorite = values[performerFormFavourite] == "yes"
+	return p, nil
+}
+
+// splitFormList splits a comma separated form value, dropping empty values.
+func splitFormList(value string) []string {
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// LoadPerformer loads the performer given by name or ID for editing.
+func (s *cmdService) LoadPerformer(performer string) tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		ids, err := resolveEntityInputs([]string{performer}, s.PerformerFindByName)
+		if err != nil {
+			return ErrorMsg{fmt.Errorf("performer resolution failed: %w", err)}
+		}
+		if len(ids) == 0 {
+			return ErrorMsg{fmt.Errorf("no performer specified")}
+		}
+		p, err := s.Stash.PerformerGet(context.Background(), ids[0])
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		return performerLoadedMsg{performer: p}
+	})
+}
+
+// SavePerformer creates updated if it has no ID, or otherwise saves the differences between old and updated.  Tags
+// are given by name, and any that do not exist are created.
+func (s *cmdService) SavePerformer(old, updated stash.Performer) tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		ctx := context.Background()
+		names := make([]string, len(updated.Tags))
+		for i, tag := range updated.Tags {
+			names[i] = tag.Name
+		}
+		tags, err := s.resolveOrCreateTags(ctx, names)
+		if err != nil {
+			return ErrorMsg{fmt.Errorf("tag resolution failed: %w", err)}
+		}
+		updated.Tags = tags
+
+		if updated.ID == "" {
+			p, err := s.Stash.PerformerCreate(ctx, stash.NewPerformerCreate(updated))
+			if err != nil {
+				return ErrorMsg{err}
+			}
+			return performersSavedMsg{performers: []stash.Performer{p}, created: true}
+		}
+		p, err := s.Stash.PerformerUpdate(ctx, stash.NewPerformerUpdate(old, updated))
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		return performersSavedMsg{performers: []stash.Performer{p}}
+	})
+}
+
+// FavouritePerformers sets whether the performers with ids are favourites.
+func (s *cmdService) FavouritePerformers(ids []string, favourite bool) tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		performers := make([]stash.Performer, 0, len(ids))
+		for _, id := range ids {
+			p, err := s.Stash.PerformerUpdate(context.Background(), stash.PerformerUpdate{
+				ID:       graphql.ID(id),
+				Favorite: &favourite,
+			})
+			if err != nil {
+				return ErrorMsg{err}
+			}
+			performers = append(performers, p)
+		}
+		return performersSavedMsg{performers: performers}
+	})
+}
+
+func (s *cmdServiceWithID) LoadPerformer(performer string) tea.Cmd {
+	return s.withID(s.s.LoadPerformer(performer))
+}
+
+func (s *cmdServiceWithID) SavePerformer(old, updated stash.Performer) tea.Cmd {
+	return s.withID(s.s.SavePerformer(old, updated))
+}
+
+func (s *cmdServiceWithID) FavouritePerformers(ids []string, favourite bool) tea.Cmd {
+	return s.withID(s.s.FavouritePerformers(ids, favourite))
+}
+
+// editPerformerFormCmd returns a command that opens a form for p, saving it with service.
+func editPerformerFormCmd(service PerformerEditService, p stash.Performer) tea.Cmd {
+	return func() tea.Msg {
+		return performerFormMsg{
+			performer: p,
+			save: func(updated stash.Performer) tea.Cmd {
+				return service.SavePerformer(p, updated)
+			},
+		}
+	}
+}
+
+// favouriteToggle returns whether performers should be made favourites, which is unless they all already are.
+func favouriteToggle(performers []stash.Performer) bool {
+	for _, p := range performers {
+		if !p.Favorite {
+			return true
+		}
+	}
+	return false
+}
+
+// updatePerformers replaces each performer in list that was saved, keeping the order of list.
+func updatePerformers(list []stash.Performer, saved []stash.Performer) {
+	for i := range list {
+		for _, p := range saved {
+			if list[i].ID == p.ID {
+				list[i] = p
+			}
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/drakenstar/stash-cli/command"
+	"github.com/drakenstar/stash-cli/stash"
+	"github.com/stretchr/testify/require"
+)
+
+type performerEditTestService struct{}
+
+func (performerEditTestService) LoadPerformer(string) tea.Cmd                           { return nil }
+func (performerEditTestService) SavePerformer(stash.Performer, stash.Performer) tea.Cmd { return nil }
+func (performerEditTestService) FavouritePerformers([]string, bool) tea.Cmd             { return nil }
+
+type performerFavouriteRecordingService struct {
+	deleteTestService
+	ids       []string
+	favourite bool
+}
+
+func (s *performerFavouriteRecordingService) FavouritePerformers(ids []string, favourite bool) tea.Cmd {
+	s.ids, s.favourite = ids, favourite
+	return nil
+}
+
+func TestPerformerCommand(t *testing.T) {
+	msg, err := ScenesModelCommandConfig.Resolve(command.Parser(`performer edit "Jane Doe"`))
+	require.NoError(t, err)
+	require.Equal(t, PerformerEditMsg{Performer: "Jane Doe"}, msg)
+
+	msg, err = PerformersModelCommandConfig.Resolve(command.Parser(`performer new Jane`))
+	require.NoError(t, err)
+	require.Equal(t, PerformerNewMsg{Name: "Jane"}, msg)
+
+	_, err = PerformersModelCommandConfig.Resolve(command.Parser(`performer`))
+	require.Error(t, err)
+}
+
+func TestParsePerformerForm(t *testing.T) {
+	old := stash.Performer{ID: "1", Name: "Jane", SceneCount: 3}
+	values := newPerformerForm(performerFormMsg{performer: old}).Values()
+	values[performerFormName] = " Jane Doe "
+	values[performerFormAliases] = "JD, , Janey"
+	values[performerFormGender] = "female"
+	values[performerFormBirthdate] = "1990-02-03"
+	values[performerFormCountry] = "au"
+	values[performerFormURLs] = "https://example.com/jane"
+	values[performerFormTags] = "Outdoors, Beach"
+	values[performerFormFavourite] = "yes"
+
+	p, err := parsePerformerForm(old, values)
+	require.NoError(t, err)
+	require.Equal(t, stash.Performer{
+		ID:         "1",
+		Name:       "Jane Doe",
+		Aliases:    []string{"JD", "Janey"},
+		Gender:     stash.GenderFemale,
+		Birthdate:  "1990-02-03",
+		Country:    "AU",
+		URLs:       []string{"https://example.com/jane"},
+		Tags:       []stash.Tag{{Name: "Outdoors"}, {Name: "Beach"}},
+		Favorite:   true,
+		SceneCount: 3,
+	}, p)
+
+	values[performerFormBirthdate] = "03/02/1990"
+	_, err = parsePerformerForm(old, values)
+	require.Error(t, err)
+
+	values[performerFormBirthdate] = ""
+	values[performerFormCountry] = "Australia"
+	_, err = parsePerformerForm(old, values)
+	require.Error(t, err)
+}
+
+func TestModelPerformerForm(t *testing.T) {
+	var saved *stash.Performer
+	m := *New(&stash.LocalStash{}, nil)
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = updated.(Model)
+
+	updated, _ = m.Update(performerFormMsg{
+		performer: stash.Performer{Name: "Jane"},
+		save: func(p stash.Performer) tea.Cmd {
+			saved = &p
+			return nil
+		},
+	})
+	m = updated.(Model)
+	require.NotNil(t, m.form)
+	require.Contains(t, m.View(), "New Performer")
+
+	// An invalid value keeps the form open.
+	updated, _ = m.Update(performerFormSubmitMsg{form: performerFormMsg{}, values: make([]string, performerFormFavourite+1)})
+	m = updated.(Model)
+	require.NotNil(t, m.form)
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	m = updated.(Model)
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+	require.Nil(t, m.form)
+	require.Equal(t, "Jane", saved.Name)
+}
+
+func TestScenesModelPerformerFavourite(t *testing.T) {
+	srv := &performerFavouriteRecordingService{}
+	m := NewScenesModel(srv, deleteTestLookup{})
+	m.scenes = []stash.Scene{{ID: "1", Performers: []stash.Performer{
+		{ID: "7", Name: "Jane", Favorite: true},
+		{ID: "8", Name: "Anna"},
+	}}}
+
+	m.Update(PerformerFavouriteMsg{})
+	require.Equal(t, []string{"7", "8"}, srv.ids)
+	require.True(t, srv.favourite)
+
+	m.Update(performersSavedMsg{performers: []stash.Performer{{ID: "8", Name: "Anna", Favorite: true}}})
+	require.True(t, m.scenes[0].Performers[1].Favorite)
+
+	m.Update(PerformerFavouriteMsg{})
+	require.False(t, srv.favourite)
+
+	_, cmd := m.Update(PerformerEditMsg{})
+	require.IsType(t, ErrorMsg{}, cmd())
+}
+
+type performerSaveTestStash struct {
+	stash.Stash
+}
+
+func (performerSaveTestStash) PerformerCreate(_ context.Context, input stash.PerformerCreate) (stash.Performer, error) {
+	return stash.Performer{ID: "9", Name: input.Name}, nil
+}
+
+func (performerSaveTestStash) PerformerUpdate(_ context.Context, input stash.PerformerUpdate) (stash.Performer, error) {
+	return stash.Performer{ID: string(input.ID), Name: *input.Name}, nil
+}
+
+func TestSavePerformerCachesName(t *testing.T) {
+	lookup := newCacheLookup()
+	svc := &cmdService{Stash: &cachingStash{performerSaveTestStash{}, lookup}, cache: lookup}
+
+	msg := svc.SavePerformer(stash.Performer{}, stash.Performer{Name: "Jane Doe"})()
+	require.Equal(t, performersSavedMsg{performers: []stash.Performer{{ID: "9", Name: "Jane Doe"}}, created: true}, msg)
+	require.Equal(t, []stash.Performer{{ID: "9", Name: "Jane Doe"}}, lookup.PerformersByPrefix("jane", 5))
+
+	svc.SavePerformer(stash.Performer{ID: "9", Name: "Jane Doe"}, stash.Performer{ID: "9", Name: "Janet"})()
+	_, err := lookup.GetPerformerByName("Jane Doe")
+	require.Error(t, err)
+	performer, err := lookup.GetPerformerByName("Janet")
+	require.NoError(t, err)
+	require.Equal(t, "9", performer.ID)
+}
@@ -0,0 +1,501 @@
+package app
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/drakenstar/stash-cli/command"
+	"github.com/drakenstar/stash-cli/stash"
+	"github.com/drakenstar/stash-cli/ui"
+)
+
+type performerFilterState struct {
+	query           string
+	sort            string
+	sortDirection   string
+	performerFilter stash.PerformerFilter
+
+	pageState pageState
+}
+
+type PerformerService interface {
+	Performers(stash.FindFilter, stash.PerformerFilter) tea.Cmd
+	ResolveTags([]string) tea.Cmd
+	PerformerEditService
+}
+
+type PerformersModel struct {
+	PerformerService
+	StashLookup
+
+	pageState  pageState
+	performers []stash.Performer
+
+	query           string
+	sort            string
+	sortDirection   string
+	performerFilter stash.PerformerFilter
+
+	history []performerFilterState
+
+	screen Size
+
+	pendingFilterRequestID uint64
+	pendingFilter          *pendingPerformerFilter
+	listRequestID          uint64
+}
+
+type pendingPerformerFilter struct {
+	requestID uint64
+	msg       PerformersModelFilterMsg
+}
+
+func NewPerformersModel(performerService PerformerService, lookup StashLookup) *PerformersModel {
+	m := &PerformersModel{
+		PerformerService: performerService,
+		StashLookup:      lookup,
+	}
+	m.pageState.PerPage = 40
+	m.reset()
+	return m
+}
+
+func (m *PerformersModel) reset() tea.Cmd {
+	m.query = ""
+	m.sort = stash.SortName
+	m.sortDirection = stash.SortDirectionAsc
+	m.performerFilter = stash.PerformerFilter{}
+	m.pageState.Reset()
+
+	return m.updateCmd()
+}
+
+func (m *PerformersModel) SetSize(s Size) tea.Cmd {
+	m.screen = s
+	m.pageState.SetPerPage(s.Height - 1) // account for status line
+	return m.updateCmd()
+}
+
+func (m *PerformersModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *PerformersModel) Title() string {
+	t := "Performers"
+	if m.query != "" {
+		t = fmt.Sprintf("\"%s\"", m.query)
+	}
+	return fmt.Sprintf("%c %s (%s)", '\U000f15c9', t, humanNumber(m.pageState.total))
+}
+
+func (m *PerformersModel) Current() stash.Performer {
+	return m.performers[m.pageState.index]
+}
+
+func (m *PerformersModel) PushState(mutate func(*PerformersModel)) (*PerformersModel, tea.Cmd) {
+	m.history = append(m.history, performerFilterState{
+		query:           m.query,
+		sort:            m.sort,
+		sortDirection:   m.sortDirection,
+		performerFilter: m.performerFilter,
+		pageState:       m.pageState,
+	})
+	mutate(m)
+	m.pageState.Reset()
+	return m, m.updateCmd()
+}
+
+// Pop sets the current state to the previous state from the history stack.  If the history stack is empty this is a
+// noop.
+func (m *PerformersModel) Pop() (*PerformersModel, tea.Cmd) {
+	if len(m.history) == 0 {
+		return m, nil
+	}
+
+	state := m.history[len(m.history)-1]
+	m.history = m.history[0 : len(m.history)-1]
+
+	m.pageState = state.pageState
+	m.query = state.query
+	m.sort = state.sort
+	m.sortDirection = state.sortDirection
+	m.performerFilter = state.performerFilter
+	m.performers = []stash.Performer{}
+
+	return m, m.updateCmd()
+}
+
+var PerformersModelDefaultKeymap = map[string]string{
+	"up":    "skip -1",
+	"down":  "skip 1",
+	"enter": "scenes",
+	"z":     "skip -1",
+	"x":     "skip 1",
+	"o":     "scenes",
+	"r":     "sort random",
+	"u":     "undo",
+	"f":     "filter favourite=1",
+	"`":     "open-url",
+}
+
+var PerformersModelCommandConfig command.Config = command.Config{
+	"filter":    binder[PerformersModelFilterMsg](),
+	"open-url":  binder[PerformersModelOpenURLMsg](),
+	"performer": performerCommand(),
+	"refresh":   binder[PerformersModelRefresh](),
+	"reset":     binder[PerformersModelResetMsg](),
+	"scenes":    binder[PerformersModelScenesMsg](),
+	"sort":      binder[PerformersModelSortMsg](),
+	"skip":      binder[PerformersModelSkipMsg](),
+	"undo":      binder[PerformersModelUndoMsg](),
+}
+
+var performerSortFields = sortFields{
+	"name":      stash.SortName,
+	"scenes":    stash.SortScenesCount,
+	"birthdate": "birthdate",
+	"created":   stash.SortCreatedAt,
+	"updated":   stash.SortUpdatedAt,
+}
+
+func (m PerformersModel) CommandConfig() command.Config {
+	return PerformersModelCommandConfig
+}
+
+func (m PerformersModel) Search(query string) tea.Msg {
+	return PerformersModelFilterMsg{
+		Query: &query,
+	}
+}
+
+// PerformersModelFilterMsg controls the filtering of performers.  Searching by name or alias is done through Query,
+// which stash matches against both.  Scenes filters to performers with more than the given number of scenes.
+type PerformersModelFilterMsg struct {
+	Query     *string
+	Favourite *bool
+	Gender    *string
+	Country   *string
+	Scenes    *int
+	Tag       []string
+}
+
+type performerTagsResolvedMsg struct {
+	requestID uint64
+	ids       []string
+}
+
+type PerformersModelOpenURLMsg struct{}
+
+// PerformersModelScenesMsg opens a new scenes tab filtered to the current performer.
+type PerformersModelScenesMsg struct{}
+
+type PerformersModelRefresh struct{}
+
+type PerformersModelResetMsg struct{}
+
+type PerformersModelSkipMsg struct {
+	Count int `command:",positional"`
+}
+
+type PerformersModelSortMsg struct {
+	Field string `command:",positional"`
+}
+
+type PerformersModelUndoMsg struct{}
+
+func (m *PerformersModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case PerformersModelFilterMsg:
+		if needsEntityResolution(msg.Tag) {
+			requestID := atomic.AddUint64(&m.pendingFilterRequestID, 1)
+			m.pendingFilter = &pendingPerformerFilter{requestID: requestID, msg: msg}
+			return m, m.resolvePerformerTagsCmd(requestID, msg.Tag)
+		}
+		return m.applyFilter(msg, msg.Tag)
+
+	case performerTagsResolvedMsg:
+		if m.pendingFilter == nil || m.pendingFilter.requestID != msg.requestID {
+			return m, nil
+		}
+		pending := m.pendingFilter
+		m.pendingFilter = nil
+		return m.applyFilter(pending.msg, msg.ids)
+
+	case PerformersModelOpenURLMsg:
+		if len(m.performers) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no performer selected"))
+		}
+		src := path.Join("performers", m.Current().ID)
+		return m, func() tea.Msg { return OpenMsg{src} }
+
+	case PerformersModelScenesMsg:
+		if len(m.performers) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no performer selected"))
+		}
+		filter := stash.SceneFilter{
+			Performers: &stash.MultiCriterion{
+				Value:    []string{m.Current().ID},
+				Modifier: stash.CriterionModifierIncludes,
+			},
+		}
+		return m, func() tea.Msg { return openScenesTabMsg(filter) }
+
+	case PerformersModelRefresh:
+		return m, m.updateCmd()
+
+	case PerformerNewMsg:
+		return m, editPerformerFormCmd(m.PerformerService, stash.Performer{Name: msg.Name})
+
+	case PerformerEditMsg:
+		if msg.Performer != "" {
+			return m, m.PerformerService.LoadPerformer(msg.Performer)
+		}
+		if len(m.performers) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no performer selected"))
+		}
+		return m, editPerformerFormCmd(m.PerformerService, m.Current())
+
+	case performerLoadedMsg:
+		return m, editPerformerFormCmd(m.PerformerService, msg.performer)
+
+	case PerformerFavouriteMsg:
+		if len(m.performers) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no performer selected"))
+		}
+		return m, m.PerformerService.FavouritePerformers([]string{m.Current().ID}, !m.Current().Favorite)
+
+	case performersSavedMsg:
+		if msg.created {
+			return m, m.updateCmd()
+		}
+		updatePerformers(m.performers, msg.performers)
+
+	case PerformersModelResetMsg:
+		return m, m.reset()
+
+	case PerformersModelSortMsg:
+		sort, direction, err := performerSortFields.parse(msg.Field)
+		if err != nil {
+			return m, NewErrorCmd(err)
+		}
+		return m.PushState(func(pm *PerformersModel) {
+			pm.sort = sort
+			pm.sortDirection = direction
+		})
+
+	case PerformersModelSkipMsg:
+		if m.pageState.Skip(msg.Count) {
+			return m, m.updateCmd()
+		}
+
+	case PerformersModelUndoMsg:
+		return m.Pop()
+
+	case tea.KeyMsg:
+		if cmd, ok := PerformersModelDefaultKeymap[msg.String()]; ok {
+			return m, func() tea.Msg { return ui.CommandExecMsg{Command: cmd} }
+		}
+
+	case performersListLoadedMsg:
+		if msg.requestID != m.listRequestID {
+			return m, nil
+		}
+		m.performers, m.pageState.total = msg.performers, msg.total
+	}
+
+	return m, nil
+}
+
+func (m *PerformersModel) resolvePerformerTagsCmd(requestID uint64, rawTags []string) tea.Cmd {
+	tags := append([]string(nil), rawTags...)
+	return func() tea.Msg {
+		resolved := m.PerformerService.ResolveTags(tags)()
+		switch msg := resolved.(type) {
+		case resolvedTagIDsMsg:
+			return performerTagsResolvedMsg{requestID: requestID, ids: msg.ids}
+		case loadingMsg:
+			if payload, ok := msg.payload.(resolvedTagIDsMsg); ok {
+				msg.payload = performerTagsResolvedMsg{requestID: requestID, ids: payload.ids}
+			}
+			return msg
+		default:
+			return resolved
+		}
+	}
+}
+
+func (m *PerformersModel) applyFilter(msg PerformersModelFilterMsg, tagIDs []string) (*PerformersModel, tea.Cmd) {
+	var gender *stash.GenderCriterion
+	if msg.Gender != nil {
+		g, err := stash.ParseGender(*msg.Gender)
+		if err != nil {
+			return m, NewErrorCmd(fmt.Errorf("unknown gender '%s'", *msg.Gender))
+		}
+		gender = &stash.GenderCriterion{
+			Value:    g,
+			Modifier: stash.CriterionModifierEquals,
+		}
+	}
+
+	return m.PushState(func(pm *PerformersModel) {
+		if msg.Query != nil {
+			pm.query = *msg.Query
+		}
+		if msg.Favourite != nil {
+			pm.performerFilter.Favourite = msg.Favourite
+		}
+		if gender != nil {
+			pm.performerFilter.Gender = gender
+		}
+		if msg.Country != nil {
+			pm.performerFilter.Country = &stash.StringCriterion{
+				Value:    strings.ToUpper(*msg.Country),
+				Modifier: stash.CriterionModifierEquals,
+			}
+		}
+		if msg.Scenes != nil {
+			pm.performerFilter.SceneCount = &stash.IntCriterion{
+				Value:    *msg.Scenes,
+				Modifier: stash.CriterionModifierGreaterThan,
+			}
+		}
+		if len(tagIDs) > 0 {
+			pm.performerFilter.Tags = &stash.HierarchicalMultiCriterion{
+				Value:    tagIDs,
+				Modifier: stash.CriterionModifierIncludes,
+			}
+		}
+	})
+}
+
+func (m PerformersModel) View() string {
+	var rows []ui.Row
+	for i, p := range m.performers {
+		rows = append(rows, ui.Row{
+			Values: []string{
+				favourite(p.Favorite),
+				performerName(p),
+				strings.Join(p.Aliases, ", "),
+				p.Gender.String(),
+				p.Country.String(),
+				p.Birthdate,
+				strconv.Itoa(p.SceneCount),
+				tagList(p.Tags),
+			},
+		})
+		if m.pageState.index == i {
+			rows[i].Background = &ColorRowSelected
+		}
+	}
+
+	leftStatus := []string{
+		m.pageState.String(),
+		sort(m.sort, m.sortDirection),
+	}
+
+	rightStatus := performerFilterStatus(m.performerFilter, m.StashLookup)
+	if m.query != "" {
+		rightStatus = append(rightStatus, "\""+m.query+"\"")
+	}
+	if len(m.history) > 0 {
+		rightStatus = append(rightStatus, fmt.Sprintf("[%d]", len(m.history)))
+	}
+
+	return lipgloss.JoinVertical(0,
+		statusBar.Render(m.screen.Width, leftStatus, rightStatus),
+		performersTable.Render(m.screen.Width, rows),
+	)
+}
+
+// openPerformersTabMsg returns a ModelTabOpenMsg that opens a new performers tab with the given filter applied.
+func openPerformersTabMsg(filter stash.PerformerFilter) ModelTabOpenMsg {
+	return ModelTabOpenMsg{
+		Name: "performers",
+		Configure: func(t TabModel) {
+			if pm, ok := t.(*PerformersModel); ok {
+				pm.performerFilter = filter
+			}
+		},
+	}
+}
+
+func (m *PerformersModel) updateCmd() tea.Cmd {
+	if m.pageState.PerPage == 0 {
+		return nil
+	}
+	requestID := atomic.AddUint64(&m.listRequestID, 1)
+	cmd := m.PerformerService.Performers(stash.FindFilter{
+		Query:     m.query,
+		Page:      m.pageState.page + 1,
+		PerPage:   m.pageState.PerPage,
+		Sort:      m.sort,
+		Direction: m.sortDirection,
+	}, m.performerFilter)
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		return wrapPerformersLoadedMsg(cmd(), requestID)
+	}
+}
+
+func wrapPerformersLoadedMsg(msg tea.Msg, requestID uint64) tea.Msg {
+	switch msg := msg.(type) {
+	case performersMsg:
+		return performersListLoadedMsg{requestID: requestID, performers: msg.performers, total: msg.total}
+	case loadingMsg:
+		if payload, ok := msg.payload.(performersMsg); ok {
+			msg.payload = performersListLoadedMsg{requestID: requestID, performers: payload.performers, total: payload.total}
+		}
+		return msg
+	default:
+		return msg
+	}
+}
+
+var (
+	performersTable = &ui.Table{
+		AltBackground: ColorBlack,
+		Cols: []ui.Column{
+			{
+				Name: "Favourite",
+			},
+			{
+				Name:       "Name",
+				Foreground: &ColorYellow,
+				Bold:       true,
+				Weight:     1,
+			},
+			{
+				Name:       "Aliases",
+				Foreground: &ColorGrey,
+				Weight:     1,
+			},
+			{
+				Name: "Gender",
+			},
+			{
+				Name: "Country",
+			},
+			{
+				Name:       "Birthdate",
+				Foreground: &ColorGrey,
+			},
+			{
+				Name:       "Scenes",
+				Foreground: &ColorBlue,
+				Align:      lipgloss.Right,
+			},
+			{
+				Name:       "Tags",
+				Foreground: &ColorPurple,
+				Flex:       true,
+			},
+		},
+	}
+)
@@ -0,0 +1,89 @@
+package app
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/drakenstar/stash-cli/stash"
+	"github.com/stretchr/testify/require"
+)
+
+type performerTestService struct {
+	performerEditTestService
+	filters []stash.PerformerFilter
+}
+
+func (s *performerTestService) Performers(_ stash.FindFilter, pf stash.PerformerFilter) tea.Cmd {
+	s.filters = append(s.filters, pf)
+	return nil
+}
+
+func (s *performerTestService) ResolveTags([]string) tea.Cmd {
+	return func() tea.Msg { return resolvedTagIDsMsg{ids: []string{"7"}} }
+}
+
+func TestPerformersModelFilter(t *testing.T) {
+	m := NewPerformersModel(&performerTestService{}, tagResolveTestLookup{})
+
+	_, _ = m.Update(PerformersModelFilterMsg{
+		Favourite: ptr(true),
+		Gender:    ptr("female"),
+		Country:   ptr("au"),
+		Scenes:    ptr(10),
+	})
+
+	require.Equal(t, ptr(true), m.performerFilter.Favourite)
+	require.Equal(t, &stash.GenderCriterion{
+		Value:    stash.GenderFemale,
+		Modifier: stash.CriterionModifierEquals,
+	}, m.performerFilter.Gender)
+	require.Equal(t, "AU", m.performerFilter.Country.Value)
+	require.Equal(t, &stash.IntCriterion{
+		Value:    10,
+		Modifier: stash.CriterionModifierGreaterThan,
+	}, m.performerFilter.SceneCount)
+	require.Len(t, m.history, 1)
+}
+
+func TestPerformersModelFilterResolvesTags(t *testing.T) {
+	m := NewPerformersModel(&performerTestService{}, tagResolveTestLookup{})
+
+	_, cmd := m.Update(PerformersModelFilterMsg{Tag: []string{"Foo"}})
+	require.NotNil(t, cmd)
+	require.Nil(t, m.performerFilter.Tags)
+
+	_, _ = m.Update(cmd())
+	require.Equal(t, []string{"7"}, m.performerFilter.Tags.Value)
+}
+
+func TestPerformersModelScenesOpensFilteredTab(t *testing.T) {
+	m := New(&stash.LocalStash{}, nil)
+	performers := NewPerformersModel(&performerTestService{}, tagResolveTestLookup{})
+	performers.performers = []stash.Performer{{ID: "42", Name: "Example"}}
+
+	_, cmd := performers.Update(PerformersModelScenesMsg{})
+	require.NotNil(t, cmd)
+	open, ok := cmd().(ModelTabOpenMsg)
+	require.True(t, ok)
+
+	updated, _ := m.Update(open)
+	model := updated.(Model)
+	require.Len(t, model.tabs, 2)
+	require.Equal(t, 1, model.active)
+	scenes := model.tabs[1].model.(*ScenesModel)
+	require.Equal(t, []string{"42"}, scenes.sceneFilter.Performers.Value)
+}
+
+func TestSortFieldsParse(t *testing.T) {
+	sort, direction, err := performerSortFields.parse("-scenes")
+	require.NoError(t, err)
+	require.Equal(t, stash.SortScenesCount, sort)
+	require.Equal(t, stash.SortDirectionDesc, direction)
+
+	_, _, err = performerSortFields.parse("unknown")
+	require.Error(t, err)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
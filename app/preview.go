@@ -0,0 +1,197 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/drakenstar/stash-cli/ui"
+)
+
+// ImageFetcher fetches the image at a path of the stash instance, such as /scene/1/screenshot.
+type ImageFetcher func(ctx context.Context, path string) ([]byte, error)
+
+// ImageCache loads the images shown in the preview pane.
+type ImageCache interface {
+	Load(ctx context.Context, path string) (image.Image, error)
+}
+
+// FileImageCache fetches images and keeps a copy of each in Dir, so that an image is only fetched once.  Instance
+// identifies the stash instance images are fetched from, since paths are the same across instances.
+type FileImageCache struct {
+	Dir      string
+	Instance string
+	Fetch    ImageFetcher
+}
+
+func NewFileImageCache(dir, instance string, fetch ImageFetcher) FileImageCache {
+	return FileImageCache{Dir: dir, Instance: instance, Fetch: fetch}
+}
+
+func (c FileImageCache) Load(ctx context.Context, path string) (image.Image, error) {
+	file := c.file(path)
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		data, err = c.Fetch(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		// An image that can't be cached is still shown, and will be fetched again next time.
+		_ = c.store(file, data)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode preview: %w", err)
+	}
+	return img, nil
+}
+
+func (c FileImageCache) file(path string) string {
+	sum := sha256.Sum256([]byte(c.Instance + path))
+	return filepath.Join(c.Dir, hex.EncodeToString(sum[:]))
+}
+
+// store writes data to file through a temporary file, so that a partially written image is never read back.
+func (c FileImageCache) store(file string, data []byte) error {
+	if err := os.MkdirAll(c.Dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(c.Dir, ".preview-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
+
+// previewable is implemented by tabs that can show an image of their current item in the preview pane.
+type previewable interface {
+	// PreviewPath returns the path of an image of the current item, or an empty string if there is none.
+	PreviewPath() string
+}
+
+type previewToggleMsg struct{}
+
+// previewLoadedMsg returns the image loaded for path.
+type previewLoadedMsg struct {
+	path string
+	img  image.Image
+	err  error
+}
+
+// previewPane shows an image of the current item of the active tab beside it.  The pane is shared by copies of the
+// Model, as the image it renders is kept between views.
+type previewPane struct {
+	enabled  bool
+	cache    ImageCache
+	renderer ui.ImageRenderer
+
+	// path is the path of the image shown, or being loaded if img and err are both nil.
+	path string
+	img  image.Image
+	err  error
+
+	// rendered is the image last rendered, which is reused while the image and size of the pane are unchanged.
+	rendered     string
+	renderedSize Size
+	// drawn is set once an image is drawn, until it is cleared from the screen.
+	drawn bool
+}
+
+// previewWidth returns the width of the preview pane on a screen of width.
+func previewWidth(width int) int {
+	return width * 2 / 5
+}
+
+// follow loads the image of the current item of tab if it has changed.
+func (p *previewPane) follow(tab TabModel) tea.Cmd {
+	if !p.enabled {
+		return nil
+	}
+	var path string
+	if t, ok := tab.(previewable); ok {
+		path = t.PreviewPath()
+	}
+	if path == p.path {
+		return nil
+	}
+	p.path, p.img, p.err = path, nil, nil
+	p.rendered = ""
+	if path == "" {
+		return nil
+	}
+	cache := p.cache
+	return func() tea.Msg {
+		img, err := cache.Load(context.Background(), path)
+		return previewLoadedMsg{path: path, img: img, err: err}
+	}
+}
+
+func (p *previewPane) loaded(msg previewLoadedMsg) {
+	// Images of items that are no longer current are discarded.
+	if msg.path != p.path {
+		return
+	}
+	p.img, p.err = msg.img, msg.err
+	p.rendered = ""
+}
+
+// toggle shows or hides the pane.  A hidden pane forgets its image, so that it is loaded again when shown.
+func (p *previewPane) toggle() {
+	p.enabled = !p.enabled
+	p.path, p.img, p.err = "", nil, nil
+	p.rendered = ""
+}
+
+// View renders the pane as height lines of width cells.
+func (p *previewPane) View(width, height int) string {
+	style := lipgloss.NewStyle().Width(width).Height(height).MaxWidth(width).MaxHeight(height).Foreground(ColorGrey)
+	switch {
+	case p.err != nil:
+		return style.Foreground(ColorSalmon).Render(p.err.Error())
+	case p.path == "":
+		return style.Render("No preview")
+	case p.img == nil:
+		return style.Render("Loading preview...")
+	}
+
+	size := Size{Width: width, Height: height}
+	if p.rendered == "" || p.renderedSize != size {
+		p.rendered = p.renderer.Render(p.img, width, height)
+		p.renderedSize = size
+	}
+	p.drawn = true
+	return p.rendered
+}
+
+// clear returns a sequence that removes an image left on screen once the pane is hidden or no longer shows it, or an
+// empty string if there is none.
+func (p *previewPane) clear() string {
+	if !p.drawn || (p.enabled && p.img != nil) {
+		return ""
+	}
+	p.drawn = false
+	return p.renderer.Clear()
+}
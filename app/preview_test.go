@@ -0,0 +1,91 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/drakenstar/stash-cli/stash"
+	"github.com/drakenstar/stash-cli/ui"
+	"github.com/stretchr/testify/require"
+)
+
+func testPNG(t *testing.T) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	img.Set(0, 0, color.RGBA{0xff, 0, 0, 0xff})
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestFileImageCache(t *testing.T) {
+	data := testPNG(t)
+	var fetched []string
+	cache := NewFileImageCache(t.TempDir(), "http://stash", func(_ context.Context, path string) ([]byte, error) {
+		fetched = append(fetched, path)
+		return data, nil
+	})
+
+	img, err := cache.Load(context.Background(), "/scene/1/screenshot")
+	require.NoError(t, err)
+	require.Equal(t, image.Pt(4, 2), img.Bounds().Size())
+
+	// Images are only fetched the first time they are loaded.
+	_, err = cache.Load(context.Background(), "/scene/1/screenshot")
+	require.NoError(t, err)
+	require.Equal(t, []string{"/scene/1/screenshot"}, fetched)
+
+	// The same path of another instance is a different image.
+	other := cache
+	other.Instance = "http://other"
+	_, err = other.Load(context.Background(), "/scene/1/screenshot")
+	require.NoError(t, err)
+	require.Len(t, fetched, 2)
+}
+
+type previewTestCache struct {
+	img image.Image
+}
+
+func (c previewTestCache) Load(context.Context, string) (image.Image, error) {
+	return c.img, nil
+}
+
+func TestModelPreview(t *testing.T) {
+	m := New(&stash.LocalStash{}, nil)
+	m.screen = Size{Width: 100, Height: 30}
+
+	// Previews are unavailable until configured.
+	_, cmd := m.Update(previewToggleMsg{})
+	require.IsType(t, ErrorMsg{}, cmd())
+
+	img, err := png.Decode(bytes.NewReader(testPNG(t)))
+	require.NoError(t, err)
+	m.SetPreview(previewTestCache{img}, ui.ImageRenderer{Graphics: ui.GraphicsBlocks}, false)
+	scenes := m.tabs[0].model.(*ScenesModel)
+	scenes.scenes = []stash.Scene{{ID: "1", Title: "One"}, {ID: "2", Title: "Two"}}
+
+	// The image of the current scene is loaded when the pane is shown, and again when the current scene changes.
+	_, cmd = m.Update(previewToggleMsg{})
+	require.True(t, m.preview.enabled)
+	require.Equal(t, 100-previewWidth(100)-1, m.tabSize().Width)
+	require.Equal(t, "/scene/1/screenshot", m.preview.path)
+	m.Update(previewLoadedMsg{path: "/scene/1/screenshot", img: img})
+	require.Equal(t, img, m.preview.img)
+	require.Contains(t, m.View(), "▀")
+
+	scenes.pageState.index = 1
+	_, cmd = m.Update(nil)
+	require.NotNil(t, cmd)
+	require.Equal(t, "/scene/2/screenshot", m.preview.path)
+	require.Nil(t, m.preview.img)
+	require.Contains(t, m.View(), "Loading preview...")
+
+	m.Update(previewToggleMsg{})
+	require.False(t, m.preview.enabled)
+	require.Equal(t, 100, m.tabSize().Width)
+}
@@ -8,6 +8,7 @@ import (
 	"strconv"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/drakenstar/stash-cli/stash"
@@ -25,6 +26,7 @@ var (
 	ColorSalmon      = lipgloss.Color("#FF9C8A")
 	ColorBlue        = lipgloss.Color("#A2D2FF")
 	ColorRowSelected = lipgloss.Color("#28664A")
+	ColorRowMarked   = lipgloss.Color("#4B3A6B")
 	ColorRed         = lipgloss.Color("#FF0000")
 
 	ColorStatusBar  = lipgloss.Color("#2B2A60")
@@ -100,6 +102,67 @@ func performer(p stash.Performer) string {
 	return name
 }
 
+func performerName(p stash.Performer) string {
+	if p.Disambiguation != "" {
+		return fmt.Sprintf("%s (%s)", p.Name, p.Disambiguation)
+	}
+	return p.Name
+}
+
+func favourite(f bool) string {
+	if f {
+		return lipgloss.NewStyle().Foreground(ColorRed).Render("\U000f02d1")
+	}
+	return ""
+}
+
+// treeName renders a name indented to its depth in a tree, with a marker for entries that have children showing
+// whether they are expanded.
+func treeName(name string, depth int, hasChildren, expanded bool) string {
+	marker := "  "
+	if hasChildren {
+		marker = "\u25b8 "
+		if expanded {
+			marker = "\u25be "
+		}
+	}
+	return strings.Repeat("  ", depth) + marker + name
+}
+
+// tagUsage summarises how much content a tag is used on.
+func tagUsage(t stash.TagDetail) string {
+	return fmt.Sprintf("%d scenes, %d galleries, %d performers", t.SceneCount, t.GalleryCount, t.PerformerCount)
+}
+
+// timestamp renders a position in seconds as h:mm:ss, or m:ss when under an hour.
+func timestamp(seconds float64) string {
+	total := int(seconds)
+	h, m, sec := total/3600, (total%3600)/60, total%60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, sec)
+	}
+	return fmt.Sprintf("%d:%02d", m, sec)
+}
+
+// oCounter renders an o-counter, which is left blank when zero.
+func oCounter(count int) string {
+	if count <= 0 {
+		return ""
+	}
+	return strconv.Itoa(count)
+}
+
+// plays renders the play count of a scene along with the date it was last played.
+func plays(s stash.Scene) string {
+	if s.PlayCount <= 0 {
+		return ""
+	}
+	if s.LastPlayedAt == nil {
+		return fmt.Sprintf("%d\U000f040a", s.PlayCount)
+	}
+	return fmt.Sprintf("%d\U000f040a %s", s.PlayCount, s.LastPlayedAt.Format(time.DateOnly))
+}
+
 func tagList(tags []stash.Tag) string {
 	var tagStrings []string
 	for _, t := range tags {
@@ -144,6 +207,36 @@ func humanBytes(bytes int64) string {
 	}
 }
 
+// resolution renders the dimensions of a video file as WxH.
+func resolution(f stash.VideoFile) string {
+	if f.Width == 0 || f.Height == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d×%d", f.Width, f.Height)
+}
+
+// codecs renders the video and audio codecs of a file.
+func codecs(f stash.VideoFile) string {
+	if f.AudioCodec == "" {
+		return f.VideoCodec
+	}
+	return f.VideoCodec + "/" + f.AudioCodec
+}
+
+// bitRate renders a bit rate in bits per second using the largest fitting decimal unit.
+func bitRate(bps int64) string {
+	switch {
+	case bps <= 0:
+		return ""
+	case bps >= 1_000_000:
+		return fmt.Sprintf("%.1fMb/s", float64(bps)/1_000_000)
+	case bps >= 1_000:
+		return fmt.Sprintf("%.0fkb/s", float64(bps)/1_000)
+	default:
+		return fmt.Sprintf("%db/s", bps)
+	}
+}
+
 func galleryTitle(g stash.Gallery) string {
 	if g.Title != "" {
 		return g.Title
@@ -156,6 +249,20 @@ func galleryTitle(g stash.Gallery) string {
 	return filepath.Base(g.FilePath())
 }
 
+func imageName(i stash.Image) string {
+	if i.Title != "" {
+		return i.Title
+	}
+	return filepath.Base(i.FilePath())
+}
+
+func imageDimensions(i stash.Image) string {
+	if len(i.Files) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d×%d", i.Files[0].Width, i.Files[0].Height)
+}
+
 func gallerySize(g stash.Gallery) string {
 	return strconv.Itoa(g.ImageCount)
 }
@@ -182,6 +289,7 @@ type StashLookup interface {
 	GetStudio(id string) (stash.Studio, error)
 	GetTag(id string) (stash.Tag, error)
 	GetPerformer(id string) (stash.Performer, error)
+	GetMovie(id string) (stash.Movie, error)
 }
 
 // sceneFilterStatus takes a stash.SceneFilter and returns a slice of strings, each string representing an enabled
@@ -224,7 +332,11 @@ func sceneFilterStatus(filter stash.SceneFilter, srv StashLookup) []string {
 
 	})
 	status.multiCriterion("Movies", filter.Movies, func(id string) string {
-		return id // TODO: movie cache
+		movie, err := srv.GetMovie(id)
+		if err != nil {
+			return "error movie"
+		}
+		return movie.Name
 	})
 	status.heirarchicalMultiCriterion("Tags", filter.Tags, func(id string) string {
 		tag, err := srv.GetTag(id)
@@ -325,6 +437,185 @@ func galleryFilterStatus(filter stash.GalleryFilter, srv StashLookup) []string {
 	return status
 }
 
+func performerFilterStatus(filter stash.PerformerFilter, srv StashLookup) []string {
+	var status criterionRenderer
+
+	status.stringCriterion("Name", filter.Name)
+	status.stringCriterion("Disambiguation", filter.Disambiguation)
+	status.stringCriterion("Details", filter.Details)
+	status.boolCriterion(filter.Favourite, "Favourite", "Non-favourite")
+	status.intCriterion("Birth year", filter.BirthYear)
+	status.intCriterion("Age", filter.Age)
+	status.stringCriterion("Country", filter.Country)
+	status.stringCriterion("Aliases", filter.Aliases)
+	status.genderCriterion("Gender", filter.Gender)
+	if filter.IsMissing != nil {
+		status = append(status, "Is missing "+*filter.IsMissing)
+	}
+	status.heirarchicalMultiCriterion("Tags", filter.Tags, func(id string) string {
+		tag, err := srv.GetTag(id)
+		if err != nil {
+			return "error tag"
+		}
+		return tag.Name
+	})
+	status.intCriterion("Tag #", filter.TagCount)
+	status.intCriterion("Scene #", filter.SceneCount)
+	status.intCriterion("Image #", filter.ImageCount)
+	status.intCriterion("Gallery #", filter.GalleryCount)
+	status.intCriterion("O-counter", filter.OCounter)
+	status.intCriterion("Rating", filter.Rating100)
+	status.stringCriterion("URL", filter.URL)
+	status.heirarchicalMultiCriterion("Studios", filter.Studios, func(id string) string {
+		studio, err := srv.GetStudio(id)
+		if err != nil {
+			return "error studio"
+		}
+		return studio.Name
+	})
+	status.multiCriterion("Performers", filter.Performers, func(id string) string {
+		performer, err := srv.GetPerformer(id)
+		if err != nil {
+			return "error performer"
+		}
+		return performer.Name
+	})
+	status.timestampCriterion("Created", filter.CreatedAt)
+	status.timestampCriterion("Updated", filter.UpdatedAt)
+
+	return status
+}
+
+func studioFilterStatus(filter stash.StudioFilter, srv StashLookup) []string {
+	var status criterionRenderer
+
+	status.stringCriterion("Name", filter.Name)
+	status.stringCriterion("Details", filter.Details)
+	status.multiCriterion("Parents", filter.Parents, func(id string) string {
+		studio, err := srv.GetStudio(id)
+		if err != nil {
+			return "error studio"
+		}
+		return studio.Name
+	})
+	if filter.IsMissing != nil {
+		status = append(status, "Is missing "+*filter.IsMissing)
+	}
+	status.intCriterion("Rating", filter.Rating100)
+	status.intCriterion("Scene #", filter.SceneCount)
+	status.intCriterion("Image #", filter.ImageCount)
+	status.intCriterion("Gallery #", filter.GalleryCount)
+	status.stringCriterion("URL", filter.URL)
+	status.stringCriterion("Aliases", filter.Aliases)
+	status.boolCriterion(filter.IgnoreAutoTag, "Ignore auto tag", "Auto tag")
+	status.timestampCriterion("Created", filter.CreatedAt)
+	status.timestampCriterion("Updated", filter.UpdatedAt)
+
+	return status
+}
+
+func tagFilterStatus(filter stash.TagFilter, srv StashLookup) []string {
+	var status criterionRenderer
+
+	status.stringCriterion("Name", filter.Name)
+	status.stringCriterion("Aliases", filter.Aliases)
+	status.stringCriterion("Description", filter.Description)
+	if filter.IsMissing != nil {
+		status = append(status, "Is missing "+*filter.IsMissing)
+	}
+	status.intCriterion("Scene #", filter.SceneCount)
+	status.intCriterion("Image #", filter.ImageCount)
+	status.intCriterion("Gallery #", filter.GalleryCount)
+	status.intCriterion("Performer #", filter.PerformerCount)
+	status.intCriterion("Marker #", filter.MarkerCount)
+	tagName := func(id string) string {
+		tag, err := srv.GetTag(id)
+		if err != nil {
+			return "error tag"
+		}
+		return tag.Name
+	}
+	status.heirarchicalMultiCriterion("Parents", filter.Parents, tagName)
+	status.heirarchicalMultiCriterion("Children", filter.Children, tagName)
+	status.intCriterion("Parent #", filter.ParentCount)
+	status.intCriterion("Child #", filter.ChildCount)
+	status.timestampCriterion("Created", filter.CreatedAt)
+	status.timestampCriterion("Updated", filter.UpdatedAt)
+
+	return status
+}
+
+func imageFilterStatus(filter stash.ImageFilter, srv StashLookup) []string {
+	var status criterionRenderer
+
+	status.intCriterion("Rating", filter.Rating100)
+	status.boolCriterion(filter.Organized, "Organised", "Unorganised")
+	status.intCriterion("O-counter", filter.OCounter)
+	status.heirarchicalMultiCriterion("Tags", filter.Tags, func(id string) string {
+		tag, err := srv.GetTag(id)
+		if err != nil {
+			return "error tag"
+		}
+		return tag.Name
+	})
+	status.timestampCriterion("Created", filter.CreatedAt)
+	status.timestampCriterion("Updated", filter.UpdatedAt)
+
+	return status
+}
+
+func movieFilterStatus(filter stash.MovieFilter, srv StashLookup) []string {
+	var status criterionRenderer
+
+	status.stringCriterion("Name", filter.Name)
+	status.stringCriterion("Director", filter.Director)
+	status.intCriterion("Duration", filter.Duration)
+	status.intCriterion("Rating", filter.Rating100)
+	status.heirarchicalMultiCriterion("Studios", filter.Studios, func(id string) string {
+		studio, err := srv.GetStudio(id)
+		if err != nil {
+			return "error studio"
+		}
+		return studio.Name
+	})
+	status.dateCriterion("Date", filter.Date)
+	status.timestampCriterion("Created", filter.CreatedAt)
+	status.timestampCriterion("Updated", filter.UpdatedAt)
+
+	return status
+}
+
+func markerFilterStatus(filter stash.SceneMarkerFilter, srv StashLookup) []string {
+	var status criterionRenderer
+
+	tagName := func(id string) string {
+		tag, err := srv.GetTag(id)
+		if err != nil {
+			return "error tag"
+		}
+		return tag.Name
+	}
+	if filter.TagID != nil {
+		status = append(status, "Tag "+tagName(*filter.TagID))
+	}
+	status.heirarchicalMultiCriterion("Tags", filter.Tags, tagName)
+	status.heirarchicalMultiCriterion("Scene tags", filter.SceneTags, tagName)
+	status.multiCriterion("Performers", filter.Performers, func(id string) string {
+		performer, err := srv.GetPerformer(id)
+		if err != nil {
+			return "error performer"
+		}
+		return performer.Name
+	})
+	status.timestampCriterion("Created", filter.CreatedAt)
+	status.timestampCriterion("Updated", filter.UpdatedAt)
+	status.dateCriterion("Scene date", filter.SceneDate)
+	status.timestampCriterion("Scene created", filter.SceneCreatedAt)
+	status.timestampCriterion("Scene updated", filter.SceneUpdatedAt)
+
+	return status
+}
+
 var criterionModifierTemplates []*template.Template
 
 func init() {
@@ -419,11 +710,19 @@ func (r *criterionRenderer) stringCriterion(fieldLabel string, c *stash.StringCr
 	}))
 }
 
+// pHashDistanceCriterion renders a phash criterion along with the distance it matches within, if one is set.
 func (r *criterionRenderer) pHashDistanceCriterion(fieldLabel string, c *stash.PHashDistanceCriterion) {
 	if c == nil {
 		return
 	}
-	panic("not implemented")
+	s := renderCriterion(c.Modifier, criterionData{
+		FieldLabel: fieldLabel,
+		Value:      c.Value,
+	})
+	if c.Distance != nil {
+		s += fmt.Sprintf(" within %d", *c.Distance)
+	}
+	*r = append(*r, s)
 }
 
 var resolutionLabels = []string{
@@ -453,6 +752,16 @@ func (r *criterionRenderer) resolutionCriterion(fieldLabel string, c *stash.Reso
 	}))
 }
 
+func (r *criterionRenderer) genderCriterion(fieldLabel string, c *stash.GenderCriterion) {
+	if c == nil {
+		return
+	}
+	*r = append(*r, renderCriterion(c.Modifier, criterionData{
+		FieldLabel: fieldLabel,
+		Value:      c.Value.String(),
+	}))
+}
+
 func (r *criterionRenderer) dateCriterion(fieldLabel string, c *stash.DateCriterion) {
 	if c == nil {
 		return
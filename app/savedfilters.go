@@ -0,0 +1,73 @@
+package app
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/drakenstar/stash-cli/command"
+	"github.com/drakenstar/stash-cli/stash"
+)
+
+// SavedFilterService loads and saves the filters stash stores for each mode, which are shared with the web UI.
+type SavedFilterService interface {
+	DefaultFilter(stash.FilterMode) tea.Cmd
+	LoadFilter(stash.FilterMode, string) tea.Cmd
+	SaveFilter(stash.SaveFilter) tea.Cmd
+	DeleteFilter(stash.FilterMode, string) tea.Cmd
+}
+
+// FilterSaveMsg saves the current filter and sort of a tab under Name, replacing any filter already saved with that
+// name.
+type FilterSaveMsg struct {
+	Name string `command:",positional"`
+}
+
+// FilterLoadMsg replaces the filter and sort of a tab with the saved filter Name.
+type FilterLoadMsg struct {
+	Name string `command:",positional"`
+}
+
+// FilterDeleteMsg deletes the saved filter Name.
+type FilterDeleteMsg struct {
+	Name string `command:",positional"`
+}
+
+// savedFilterCommand adds the save, load and delete sub-commands to filter, a command that filters a tab.
+func savedFilterCommand(filter command.Command) command.Command {
+	filter.SubCommands = command.Config{
+		"delete": binder[FilterDeleteMsg](),
+		"load":   binder[FilterLoadMsg](),
+		"save":   binder[FilterSaveMsg](),
+	}
+	return filter
+}
+
+// isSavedFilterCommand returns true if the tokens are a filter sub-command that takes the name of a saved filter.
+func isSavedFilterCommand(tokens []commandToken) bool {
+	if len(tokens) < 2 || tokens[0].raw != "filter" {
+		return false
+	}
+	switch tokens[1].raw {
+	case "delete", "load", "save":
+		return true
+	}
+	return false
+}
+
+// savedFilterMode returns the mode of the saved filters that can be used in a tab.
+func savedFilterMode(model TabModel) (stash.FilterMode, bool) {
+	switch model.(type) {
+	case *ScenesModel:
+		return stash.FilterModeScenes, true
+	case *GalleriesModel:
+		return stash.FilterModeGalleries, true
+	default:
+		return "", false
+	}
+}
+
+// savedFindFilter returns the find filter of a saved filter, or an empty filter if it has none.
+func savedFindFilter(saved stash.SavedFilter) stash.SavedFindFilter {
+	if saved.FindFilter == nil {
+		return stash.SavedFindFilter{}
+	}
+	return *saved.FindFilter
+}
@@ -0,0 +1,107 @@
+package app
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/drakenstar/stash-cli/command"
+	"github.com/drakenstar/stash-cli/stash"
+	"github.com/stretchr/testify/require"
+)
+
+type savedFilterTestService struct{}
+
+func (savedFilterTestService) DefaultFilter(stash.FilterMode) tea.Cmd        { return nil }
+func (savedFilterTestService) LoadFilter(stash.FilterMode, string) tea.Cmd   { return nil }
+func (savedFilterTestService) SaveFilter(stash.SaveFilter) tea.Cmd           { return nil }
+func (savedFilterTestService) DeleteFilter(stash.FilterMode, string) tea.Cmd { return nil }
+
+type savedFilterRecordingService struct {
+	deleteTestService
+	loaded  []string
+	saved   []stash.SaveFilter
+	deleted []string
+}
+
+func (s *savedFilterRecordingService) LoadFilter(_ stash.FilterMode, name string) tea.Cmd {
+	s.loaded = append(s.loaded, name)
+	return nil
+}
+
+func (s *savedFilterRecordingService) SaveFilter(input stash.SaveFilter) tea.Cmd {
+	s.saved = append(s.saved, input)
+	return nil
+}
+
+func (s *savedFilterRecordingService) DeleteFilter(_ stash.FilterMode, name string) tea.Cmd {
+	s.deleted = append(s.deleted, name)
+	return nil
+}
+
+func TestSavedFilterCommand(t *testing.T) {
+	msg, err := ScenesModelCommandConfig.Resolve(command.Parser(`filter save "Tagged outdoors"`))
+	require.NoError(t, err)
+	require.Equal(t, FilterSaveMsg{Name: "Tagged outdoors"}, msg)
+
+	msg, err = ScenesModelCommandConfig.Resolve(command.Parser(`filter load Recent`))
+	require.NoError(t, err)
+	require.Equal(t, FilterLoadMsg{Name: "Recent"}, msg)
+
+	// Filter arguments are still bound by the filter command itself.
+	msg, err = GalleriesModelCommandConfig.Resolve(command.Parser(`filter organised=1`))
+	require.NoError(t, err)
+	require.IsType(t, GalleriesModelFilterMsg{}, msg)
+}
+
+func TestScenesModelSavedFilters(t *testing.T) {
+	srv := &savedFilterRecordingService{}
+	m := NewScenesModel(srv, deleteTestLookup{})
+
+	m.Update(defaultFilterMsg{stash.SavedFilter{
+		FindFilter:   &stash.SavedFindFilter{Sort: stash.SortTitle, Direction: stash.SortDirectionAsc},
+		ObjectFilter: []byte(`{"organized": {"modifier": "EQUALS", "value": "false"}}`),
+	}})
+	require.Equal(t, stash.SortTitle, m.sort)
+	require.False(t, *m.sceneFilter.Organized)
+	require.Empty(t, m.history)
+
+	m.Update(savedFilterMsg{stash.SavedFilter{
+		Name:         "Tagged",
+		ObjectFilter: []byte(`{"tags": {"modifier": "INCLUDES", "value": {"items": [{"id": "1", "label": "Outdoors"}], "depth": 0}}}`),
+	}})
+	require.Len(t, m.history, 1)
+	require.Equal(t, stash.SortDate, m.sort)
+	require.Equal(t, []string{"1"}, m.sceneFilter.Tags.Value)
+
+	m.Update(FilterSaveMsg{Name: "Outdoors"})
+	require.Len(t, srv.saved, 1)
+	require.Equal(t, stash.FilterModeScenes, srv.saved[0].Mode)
+	require.Equal(t, "Outdoors", srv.saved[0].Name)
+	require.Contains(t, srv.saved[0].ObjectFilter, "tags")
+
+	// Reset returns to the default filter rather than an unfiltered list.
+	m.Update(ScenesModelResetMsg{})
+	require.Equal(t, stash.SortTitle, m.sort)
+	require.Nil(t, m.sceneFilter.Tags)
+	require.NotNil(t, m.sceneFilter.Organized)
+
+	m.Update(FilterLoadMsg{Name: "Recent"})
+	m.Update(FilterDeleteMsg{Name: "Recent"})
+	require.Equal(t, []string{"Recent"}, srv.loaded)
+	require.Equal(t, []string{"Recent"}, srv.deleted)
+}
+
+func TestGalleriesModelDefaultFilterKeepsFilteredTab(t *testing.T) {
+	m := NewGalleriesModel(deleteTestService{}, deleteTestLookup{})
+	m.galleryFilter.Performers = &stash.MultiCriterion{Value: []string{"1"}}
+
+	m.Update(defaultFilterMsg{stash.SavedFilter{
+		FindFilter: &stash.SavedFindFilter{Sort: stash.SortTitle},
+	}})
+	require.Equal(t, stash.SortPath, m.sort)
+	require.NotNil(t, m.galleryFilter.Performers)
+
+	m.Update(GalleriesModelResetMsg{})
+	require.Equal(t, stash.SortTitle, m.sort)
+	require.Nil(t, m.galleryFilter.Performers)
+}
@@ -1,11 +1,14 @@
 package app
 
 import (
+	"cmp"
 	"fmt"
 	"math"
 	"path"
+	"reflect"
 	"strings"
 	"sync/atomic"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -27,9 +30,24 @@ type SceneService interface {
 	Scenes(stash.FindFilter, stash.SceneFilter) tea.Cmd
 	DeleteScene(string) tea.Cmd
 	TagScene(stash.Scene, []string) tea.Cmd
+	RetagScene(stash.Scene, []string) tea.Cmd
+	TagScenes([]string, []string) tea.Cmd
+	RetagScenes([]string, []string) tea.Cmd
+	UpdateScenes(stash.BulkSceneUpdate) tea.Cmd
+	DeleteScenes([]string) tea.Cmd
+	UpdateScene(stash.Scene, stash.Scene) tea.Cmd
+	StudioScene(stash.Scene, string) tea.Cmd
+	OCounterScene(stash.Scene, oCounterChange) tea.Cmd
+	RecordPlay(stash.Scene) tea.Cmd
+	SaveSceneActivity(stash.Scene, *float64, *float64) tea.Cmd
 	ResolveTags([]string) tea.Cmd
 	ResolveStudios([]string) tea.Cmd
 	ResolvePerformers([]string) tea.Cmd
+	StartTask(taskRequest) tea.Cmd
+	SavedFilterService
+	ScrapeService
+	PerformerEditService
+	EditService
 }
 
 type ScenesModel struct {
@@ -38,6 +56,7 @@ type ScenesModel struct {
 
 	pageState pageState
 	scenes    []stash.Scene
+	marks     markSet[stash.Scene]
 
 	query         string
 	sort          string
@@ -45,6 +64,8 @@ type ScenesModel struct {
 	sceneFilter   stash.SceneFilter
 
 	history []sceneFilterState
+	// defaultState is the state of the default filter for scenes, which is used on reset once loaded.
+	defaultState *sceneFilterState
 
 	screen Size
 
@@ -74,15 +95,47 @@ func NewScenesModel(sceneService SceneService, lookup StashLookup) *ScenesModel
 }
 
 func (m *ScenesModel) reset() tea.Cmd {
-	m.query = ""
-	m.sort = stash.SortDate
-	m.sortDirection = stash.SortDirectionDesc
-	m.sceneFilter = stash.SceneFilter{}
+	state := sceneFilterState{
+		sort:          stash.SortDate,
+		sortDirection: stash.SortDirectionDesc,
+	}
+	if m.defaultState != nil {
+		state = *m.defaultState
+	}
+	m.query = state.query
+	m.sort = state.sort
+	m.sortDirection = state.sortDirection
+	m.sceneFilter = state.sceneFilter
 	m.pageState.Reset()
 
 	return m.updateCmd()
 }
 
+// isReset returns true if the model has not been filtered since it was reset.
+func (m *ScenesModel) isReset() bool {
+	return len(m.history) == 0 &&
+		m.query == "" &&
+		m.sort == stash.SortDate &&
+		m.sortDirection == stash.SortDirectionDesc &&
+		reflect.ValueOf(m.sceneFilter).IsZero()
+}
+
+// savedSceneFilterState returns the state of a saved scene filter.  The default sort is used if it has none.
+func savedSceneFilterState(saved stash.SavedFilter) (sceneFilterState, error) {
+	filter, err := saved.SceneFilter()
+	if err != nil {
+		return sceneFilterState{}, err
+	}
+	find := savedFindFilter(saved)
+	state := sceneFilterState{
+		query:         find.Query,
+		sort:          cmp.Or(find.Sort, stash.SortDate),
+		sortDirection: cmp.Or(find.Direction, stash.SortDirectionDesc),
+		sceneFilter:   filter,
+	}
+	return state, nil
+}
+
 // SetSize takes a size input that indicates the size of the tab being rendered.
 func (m *ScenesModel) SetSize(s Size) tea.Cmd {
 	m.screen = s
@@ -91,7 +144,7 @@ func (m *ScenesModel) SetSize(s Size) tea.Cmd {
 }
 
 func (m *ScenesModel) Init() tea.Cmd {
-	return nil
+	return m.SceneService.DefaultFilter(stash.FilterModeScenes)
 }
 
 func (m *ScenesModel) Title() string {
@@ -105,6 +158,13 @@ func (m *ScenesModel) Title() string {
 			performers = append(performers, perf.Name)
 		}
 		t = strings.Join(performers, ", ")
+	} else if m.sceneFilter.Movies != nil {
+		var movies []string
+		for _, id := range m.sceneFilter.Movies.Value {
+			movie, _ := m.StashLookup.GetMovie(id)
+			movies = append(movies, movie.Name)
+		}
+		t = strings.Join(movies, ", ")
 	}
 
 	return fmt.Sprintf("%c %s (%s)", '\U000f0fce', t, humanNumber(m.pageState.total))
@@ -114,6 +174,14 @@ func (m *ScenesModel) Current() stash.Scene {
 	return m.scenes[m.pageState.index]
 }
 
+// PreviewPath returns the path of the screenshot of the current scene.
+func (m *ScenesModel) PreviewPath() string {
+	if len(m.scenes) == 0 {
+		return ""
+	}
+	return "/scene/" + m.Current().ID + "/screenshot"
+}
+
 func (m *ScenesModel) PushState(mutate func(*ScenesModel)) (*ScenesModel, tea.Cmd) {
 	m.history = append(m.history, sceneFilterState{
 		query:         m.query,
@@ -163,6 +231,13 @@ var ScenesModelDefaultKeymap = map[string]string{
 	"u":     "undo", // state pop?  Maybe some sort of generic state management command
 	"f":     "filter favourite=1",
 	"p":     "filter performer=current",
+	"m":     "movie",
+	"O":     "organise",
+	"+":     "o+",
+	"-":     "o-",
+	"v":     "mark",
+	"V":     "mark all",
+	"esc":   "mark clear",
 	"`":     "open-url source=stash",
 }
 
@@ -174,16 +249,46 @@ var ScenesModelDefaultCommandAlias = map[string]string{
 }
 
 var ScenesModelCommandConfig command.Config = command.Config{
-	"delete":   binder[ScenesModelDeleteMsg](),
-	"filter":   binder[ScenesModelFilterMsg](),
-	"open":     binder[ScenesModelOpenMsg](),
-	"open-url": binder[ScenesModelOpenURLMsg](),
-	"refresh":  binder[ScenesModelRefresh](),
-	"reset":    binder[ScenesModelResetMsg](),
-	"sort":     binder[ScenesModelSortMsg](),
-	"skip":     binder[ScenesModelSkipMsg](),
-	"tag":      binder[ScenesModelTagMsg](),
-	"undo":     binder[ScenesModelUndoMsg](),
+	"activity":  binder[ScenesModelActivityMsg](),
+	"date":      binder[ScenesModelDateMsg](),
+	"delete":    binder[ScenesModelDeleteMsg](),
+	"edit":      binder[EditMsg](),
+	"filter":    savedFilterCommand(binder[ScenesModelFilterMsg]()),
+	"mark":      binder[ScenesModelMarkMsg](),
+	"movie":     binder[ScenesModelMovieMsg](),
+	"o":         {SubCommands: command.Config{"reset": static(ScenesModelOCounterMsg{Change: oCounterReset})}},
+	"o+":        static(ScenesModelOCounterMsg{Change: oCounterIncrement}),
+	"o-":        static(ScenesModelOCounterMsg{Change: oCounterDecrement}),
+	"open":      binder[ScenesModelOpenMsg](),
+	"open-url":  binder[ScenesModelOpenURLMsg](),
+	"organise":  binder[ScenesModelOrganiseMsg](),
+	"performer": performerCommand(),
+	"rate":      binder[ScenesModelRateMsg](),
+	"refresh":   binder[ScenesModelRefresh](),
+	"reset":     binder[ScenesModelResetMsg](),
+	"sort":      binder[ScenesModelSortMsg](),
+	"skip":      binder[ScenesModelSkipMsg](),
+	"studio":    binder[ScenesModelStudioMsg](),
+	"retag":     binder[ScenesModelRetagMsg](),
+	"scrape":    binder[ScrapeMsg](),
+	"task":      taskCommand(),
+	"tag":       binder[ScenesModelTagMsg](),
+	"untag":     binder[ScenesModelUntagMsg](),
+	"title":     binder[ScenesModelTitleMsg](),
+	"undo":      binder[ScenesModelUndoMsg](),
+}
+
+var sceneSortFields = sortFields{
+	"date":    stash.SortDate,
+	"title":   stash.SortTitle,
+	"rating":  "rating",
+	"o":       stash.SortOCounter,
+	"created": stash.SortCreatedAt,
+	"updated": stash.SortUpdatedAt,
+	"plays":   stash.SortPlayCount,
+	"played":  stash.SortLastPlayedAt,
+	"path":    stash.SortPath,
+	"size":    stash.SortFileSize,
 }
 
 func (m ScenesModel) CommandConfig() command.Config {
@@ -198,12 +303,13 @@ func (m ScenesModel) Search(query string) tea.Msg {
 
 // ScenesModelFilterMsg controls the filtering of various fields on the model. Currently this has a bit of a limitation
 // in that although pointers can be used to determine if the user intended to set a field or not, there is no way
-// currently to reset a filter.
+// currently to reset a filter.  O matches an exact o-counter.
 type ScenesModelFilterMsg struct {
 	Query        *string
 	Favourite    *bool
 	Organised    *bool
 	Rating       *int
+	O            *int
 	Date         *dateFilterValue
 	Created      *dateFilterValue `command:"created"`
 	Updated      *dateFilterValue `command:"updated"`
@@ -241,10 +347,38 @@ type resolvedSceneFilterIDs struct {
 	performerTagIDs []string
 }
 
+// ScenesModelOpenMsg opens the current scene and records a play of it.  With Skip the next scene is opened instead.
+// With Stream, or the Label of a stream such as a transcode, the scene is opened from a stream rather than its file
+// path.
 type ScenesModelOpenMsg struct {
-	Skip bool `command:",positional"`
+	Skip   bool
+	Stream bool
+	Label  string `command:",positional"`
+}
+
+// ScenesModelActivityMsg records playback activity for the current scene.  Resume is the position in seconds to resume
+// playback from, and Duration is the number of seconds played to add to the total play duration.
+type ScenesModelActivityMsg struct {
+	Resume   *float64
+	Duration *float64
+}
+
+// ScenesModelMarkMsg toggles the mark on the current scene.  While any scenes are marked the tag, untag, retag, rate,
+// organise and delete commands act on all marked scenes rather than the current one.  A Target of "all" marks every
+// scene on the page, and "clear" removes all marks.
+type ScenesModelMarkMsg struct {
+	Target string `command:",positional"`
+}
+
+// ScenesModelOCounterMsg increments, decrements or resets the o-counter of the current scene.
+type ScenesModelOCounterMsg struct {
+	Change oCounterChange
 }
 
+// ScenesModelMovieMsg opens a new scenes tab listing the scenes of the movie the current scene belongs to, in the order
+// they appear in it.
+type ScenesModelMovieMsg struct{}
+
 type ScenesModelOpenURLMsg struct {
 	Source string
 }
@@ -253,10 +387,45 @@ type ScenesModelDeleteMsg struct {
 	Confirm bool
 }
 
+// ScenesModelTagMsg adds tags to the current scene.  Tags prefixed with '-' are removed instead.
 type ScenesModelTagMsg struct {
 	Tags []string `command:",positional"`
 }
 
+// ScenesModelUntagMsg removes tags from the current scene.
+type ScenesModelUntagMsg struct {
+	Tags []string `command:",positional"`
+}
+
+// ScenesModelRetagMsg replaces all tags of the current scene.
+type ScenesModelRetagMsg struct {
+	Tags []string `command:",positional"`
+}
+
+// ScenesModelRateMsg sets the rating of the current scene, out of 100.
+type ScenesModelRateMsg struct {
+	Rating int `command:",positional"`
+}
+
+// ScenesModelOrganiseMsg toggles whether the current scene is organised.
+type ScenesModelOrganiseMsg struct{}
+
+// ScenesModelTitleMsg sets the title of the current scene.
+type ScenesModelTitleMsg struct {
+	Title string `command:",positional"`
+}
+
+// ScenesModelDateMsg sets the date of the current scene.  Dates are given as YYYY-MM-DD, or relative to today such as
+// -1d.
+type ScenesModelDateMsg struct {
+	Date string `command:",positional"`
+}
+
+// ScenesModelStudioMsg sets the studio of the current scene, given by name or ID.
+type ScenesModelStudioMsg struct {
+	Studio string `command:",positional"`
+}
+
 type ScenesModelRefresh struct{}
 
 type ScenesModelResetMsg struct{}
@@ -363,7 +532,34 @@ func (m *ScenesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, m.updateCmd()
 		}
 		cur := m.Current()
-		return m, func() tea.Msg { return OpenMsg{cur} }
+		open := func() tea.Msg { return OpenMsg{cur} }
+		if msg.Stream || msg.Label != "" {
+			open = func() tea.Msg { return openStreamMsg{scene: cur, label: msg.Label} }
+		}
+		return m, tea.Batch(
+			open,
+			m.SceneService.RecordPlay(cur),
+		)
+
+	case ScenesModelActivityMsg:
+		if len(m.scenes) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no scene selected"))
+		}
+		if msg.Resume == nil && msg.Duration == nil {
+			return m, NewErrorCmd(fmt.Errorf("no activity specified"))
+		}
+		return m, m.SceneService.SaveSceneActivity(m.Current(), msg.Resume, msg.Duration)
+
+	case ScenesModelMovieMsg:
+		if len(m.scenes) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no scene selected"))
+		}
+		scene := m.Current()
+		if len(scene.Movies) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("scene is not part of a movie"))
+		}
+		movie := scene.Movies[0].Movie
+		return m, func() tea.Msg { return openMovieScenesTabMsg(movie) }
 
 	case ScenesModelOpenURLMsg:
 		cur := m.Current()
@@ -374,7 +570,39 @@ func (m *ScenesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, func() tea.Msg { return OpenMsg{src} }
 
+	case ScenesModelMarkMsg:
+		switch msg.Target {
+		case "":
+			if len(m.scenes) == 0 {
+				return m, NewErrorCmd(fmt.Errorf("no scene selected"))
+			}
+			m.marks.Toggle(m.Current())
+		case "all":
+			for _, scene := range m.scenes {
+				m.marks.Mark(scene)
+			}
+		case "clear":
+			m.marks.Clear()
+		default:
+			return m, NewErrorCmd(fmt.Errorf("unknown mark target '%s'", msg.Target))
+		}
+
 	case ScenesModelDeleteMsg:
+		if m.marks.Len() > 0 {
+			scenes := m.marks.Items()
+			titles := make([]string, len(scenes))
+			for i, scene := range scenes {
+				titles[i] = sceneTitle(scene)
+			}
+			return m, func() tea.Msg {
+				return deleteRequestMsg{
+					Entity:      fmt.Sprintf("%d scenes", len(scenes)),
+					Title:       markedTitles(titles),
+					SkipConfirm: msg.Confirm,
+					DeleteCmd:   m.SceneService.DeleteScenes(m.marks.IDs()),
+				}
+			}
+		}
 		if len(m.scenes) == 0 {
 			return m, NewErrorCmd(fmt.Errorf("no scene selected"))
 		}
@@ -389,6 +617,103 @@ func (m *ScenesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+	case TaskMsg:
+		// Tasks are scoped to the marked scenes, or the current scene, unless paths are given.
+		var scenes []stash.Scene
+		if m.marks.Len() > 0 {
+			scenes = m.marks.Items()
+		} else if len(m.scenes) > 0 {
+			scenes = []stash.Scene{m.Current()}
+		}
+		if len(scenes) == 0 && len(msg.Paths) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no scene selected"))
+		}
+		req, err := newTaskRequest(msg, scenes)
+		if err != nil {
+			return m, NewErrorCmd(err)
+		}
+		return m, m.SceneService.StartTask(req)
+
+	case PerformerNewMsg:
+		return m, editPerformerFormCmd(m.SceneService, stash.Performer{Name: msg.Name})
+
+	case PerformerEditMsg:
+		if msg.Performer != "" {
+			return m, m.SceneService.LoadPerformer(msg.Performer)
+		}
+		if len(m.scenes) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no scene selected"))
+		}
+		switch performers := m.Current().Performers; len(performers) {
+		case 0:
+			return m, NewErrorCmd(fmt.Errorf("scene has no performers"))
+		case 1:
+			return m, m.SceneService.LoadPerformer(performers[0].ID)
+		default:
+			return m, NewErrorCmd(fmt.Errorf("scene has %d performers, give the one to edit", len(performers)))
+		}
+
+	case performerLoadedMsg:
+		return m, editPerformerFormCmd(m.SceneService, msg.performer)
+
+	case PerformerFavouriteMsg:
+		if len(m.scenes) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no scene selected"))
+		}
+		performers := m.Current().Performers
+		if len(performers) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("scene has no performers"))
+		}
+		ids := make([]string, len(performers))
+		for i, p := range performers {
+			ids[i] = p.ID
+		}
+		return m, m.SceneService.FavouritePerformers(ids, favouriteToggle(performers))
+
+	case performersSavedMsg:
+		for _, scene := range m.scenes {
+			updatePerformers(scene.Performers, msg.performers)
+		}
+
+	case EditMsg:
+		if len(m.scenes) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no scene selected"))
+		}
+		scene := m.Current()
+		return m, editDocument(newSceneDocument(scene), func(document []byte) tea.Msg {
+			return sceneEditedMsg{scene: scene, document: document}
+		})
+
+	case sceneEditedMsg:
+		return m, m.SceneService.EditScene(msg.scene, msg.document)
+
+	case ScrapeMsg:
+		if len(m.scenes) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no scene selected"))
+		}
+		return m, m.SceneService.ScrapeScene(m.Current(), msg.Source)
+
+	case sceneScrapedMsg:
+		changes := sceneScrapeChanges(msg.scene, msg.scraped)
+		if len(changes) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("scraped metadata matches the scene"))
+		}
+		return m, func() tea.Msg {
+			return scrapeReviewMsg{
+				title:   msg.scene.Title,
+				changes: changes,
+				apply: func(accepted []scrapeChange) tea.Cmd {
+					return m.SceneService.ApplySceneScrape(msg.scene, accepted)
+				},
+			}
+		}
+
+	case ScenesModelOCounterMsg:
+		if len(m.scenes) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no scene selected"))
+		}
+		return m, m.SceneService.OCounterScene(m.Current(), msg.Change)
+
 	case ScenesModelTagMsg:
 		if len(m.scenes) == 0 {
 			return m, NewErrorCmd(fmt.Errorf("no scene selected"))
@@ -396,14 +721,150 @@ func (m *ScenesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if len(msg.Tags) == 0 {
 			return m, NewErrorCmd(fmt.Errorf("no tags specified"))
 		}
+		if m.marks.Len() > 0 {
+			return m, m.SceneService.TagScenes(m.marks.IDs(), msg.Tags)
+		}
 		return m, m.SceneService.TagScene(m.Current(), msg.Tags)
 
+	case ScenesModelUntagMsg:
+		if len(m.scenes) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no scene selected"))
+		}
+		if len(msg.Tags) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no tags specified"))
+		}
+		if m.marks.Len() > 0 {
+			return m, m.SceneService.TagScenes(m.marks.IDs(), untagNames(msg.Tags))
+		}
+		return m, m.SceneService.TagScene(m.Current(), untagNames(msg.Tags))
+
+	case ScenesModelRetagMsg:
+		if len(m.scenes) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no scene selected"))
+		}
+		if len(msg.Tags) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no tags specified"))
+		}
+		if m.marks.Len() > 0 {
+			return m, m.SceneService.RetagScenes(m.marks.IDs(), msg.Tags)
+		}
+		return m, m.SceneService.RetagScene(m.Current(), msg.Tags)
+
+	case ScenesModelRateMsg:
+		if len(m.scenes) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no scene selected"))
+		}
+		if msg.Rating < 1 || msg.Rating > 100 {
+			return m, NewErrorCmd(fmt.Errorf("rating must be between 1 and 100"))
+		}
+		if m.marks.Len() > 0 {
+			update := stash.NewBulkSceneUpdate(m.marks.IDs())
+			update.Rating = &msg.Rating
+			return m, m.SceneService.UpdateScenes(update)
+		}
+		return m, m.updateCurrent(func(s *stash.Scene) { s.Rating = msg.Rating })
+
+	case ScenesModelOrganiseMsg:
+		// Marked scenes are all organised, unless they already all are in which case they are all unorganised.
+		if m.marks.Len() > 0 {
+			organised := false
+			for _, scene := range m.marks.Items() {
+				if !scene.Organized {
+					organised = true
+					break
+				}
+			}
+			update := stash.NewBulkSceneUpdate(m.marks.IDs())
+			update.Organized = &organised
+			return m, m.SceneService.UpdateScenes(update)
+		}
+		if len(m.scenes) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no scene selected"))
+		}
+		return m, m.updateCurrent(func(s *stash.Scene) { s.Organized = !s.Organized })
+
+	case ScenesModelTitleMsg:
+		if len(m.scenes) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no scene selected"))
+		}
+		return m, m.updateCurrent(func(s *stash.Scene) { s.Title = msg.Title })
+
+	case ScenesModelDateMsg:
+		if len(m.scenes) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no scene selected"))
+		}
+		date, err := parseDateValue(msg.Date)
+		if err != nil {
+			return m, NewErrorCmd(err)
+		}
+		return m, m.updateCurrent(func(s *stash.Scene) { s.Date = date.Format(time.DateOnly) })
+
+	case ScenesModelStudioMsg:
+		if len(m.scenes) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no scene selected"))
+		}
+		if msg.Studio == "" {
+			return m, NewErrorCmd(fmt.Errorf("no studio specified"))
+		}
+		return m, m.SceneService.StudioScene(m.Current(), msg.Studio)
+
 	case ScenesModelRefresh:
 		return m, m.updateCmd()
 
 	case ScenesModelResetMsg:
 		return m, m.reset()
 
+	case defaultFilterMsg:
+		state, err := savedSceneFilterState(msg.filter)
+		if err != nil {
+			return m, NewErrorCmd(fmt.Errorf("default filter: %w", err))
+		}
+		// Tabs that have been filtered since they opened, such as those showing a performer, are left as they are.
+		apply := m.isReset()
+		m.defaultState = &state
+		if apply {
+			return m, m.reset()
+		}
+
+	case FilterLoadMsg:
+		return m, m.SceneService.LoadFilter(stash.FilterModeScenes, msg.Name)
+
+	case savedFilterMsg:
+		state, err := savedSceneFilterState(msg.filter)
+		if err != nil {
+			return m, NewErrorCmd(fmt.Errorf("filter %s: %w", msg.filter.Name, err))
+		}
+		return m.PushState(func(sm *ScenesModel) {
+			sm.query = state.query
+			sm.sort = state.sort
+			sm.sortDirection = state.sortDirection
+			sm.sceneFilter = state.sceneFilter
+		})
+
+	case FilterSaveMsg:
+		if msg.Name == "" {
+			return m, NewErrorCmd(fmt.Errorf("no filter name specified"))
+		}
+		objectFilter, err := stash.ObjectFilter(m.sceneFilter)
+		if err != nil {
+			return m, NewErrorCmd(err)
+		}
+		return m, m.SceneService.SaveFilter(stash.SaveFilter{
+			Mode: stash.FilterModeScenes,
+			Name: msg.Name,
+			FindFilter: &stash.FindFilter{
+				Query:     m.query,
+				Page:      1,
+				PerPage:   m.pageState.PerPage,
+				Sort:      m.sort,
+				Direction: m.sortDirection,
+			},
+			ObjectFilter: objectFilter,
+		})
+
+	case FilterDeleteMsg:
+		return m, m.SceneService.DeleteFilter(stash.FilterModeScenes, msg.Name)
+
 	case ScenesModelSortMsg:
 		switch msg.Field {
 		case "random":
@@ -422,6 +883,15 @@ func (m *ScenesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				sm.sort = "date"
 				sm.sortDirection = stash.SortDirectionDesc
 			})
+		default:
+			sort, direction, err := sceneSortFields.parse(msg.Field)
+			if err != nil {
+				return m, NewErrorCmd(err)
+			}
+			return m.PushState(func(sm *ScenesModel) {
+				sm.sort = sort
+				sm.sortDirection = direction
+			})
 		}
 
 	case ScenesModelSkipMsg:
@@ -449,15 +919,65 @@ func (m *ScenesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.pageState.DeleteCurrent()
 		return m, m.updateCmd()
 
+	case scenesDeletedMsg:
+		m.marks.Clear()
+		m.pageState.Delete(len(msg.ids))
+		return m, m.updateCmd()
+
+	case scenesUpdatedMsg:
+		for _, updated := range msg.scenes {
+			m.marks.Update(updated)
+			for i, scene := range m.scenes {
+				if scene.ID == updated.ID {
+					m.scenes[i] = updated
+				}
+			}
+		}
+
 	case sceneTaggedMsg:
+		m.marks.Update(msg.scene)
 		if len(m.scenes) > 0 {
 			m.scenes[m.pageState.index] = msg.scene
 		}
+
+	case sceneUpdatedMsg:
+		m.marks.Update(msg.scene)
+		for i, scene := range m.scenes {
+			if scene.ID == msg.scene.ID {
+				m.scenes[i] = msg.scene
+			}
+		}
+
+	case scenePlayedMsg:
+		for i := range m.scenes {
+			if m.scenes[i].ID == msg.id {
+				m.scenes[i].PlayCount = msg.count
+				m.scenes[i].LastPlayedAt = &msg.at
+			}
+		}
+
+	case sceneActivitySavedMsg:
+		if msg.resumeTime == nil {
+			return m, nil
+		}
+		for i := range m.scenes {
+			if m.scenes[i].ID == msg.id {
+				m.scenes[i].ResumeTime = *msg.resumeTime
+			}
+		}
 	}
 
 	return m, nil
 }
 
+// updateCurrent applies mutate to a copy of the current scene and saves any changes made.
+func (m *ScenesModel) updateCurrent(mutate func(*stash.Scene)) tea.Cmd {
+	old := m.Current()
+	updated := old
+	mutate(&updated)
+	return m.SceneService.UpdateScene(old, updated)
+}
+
 func (m *ScenesModel) filterNeedsAsyncResolution(msg ScenesModelFilterMsg) bool {
 	return needsEntityResolution(msg.Tag) ||
 		needsEntityResolution(msg.Studio) ||
@@ -583,6 +1103,12 @@ func (m *ScenesModel) applyFilter(msg ScenesModelFilterMsg, resolved resolvedSce
 				Value:    *msg.Rating,
 			}
 		}
+		if msg.O != nil {
+			sm.sceneFilter.OCounter = &stash.IntCriterion{
+				Modifier: stash.CriterionModifierEquals,
+				Value:    *msg.O,
+			}
+		}
 		if msg.Date != nil {
 			sm.sceneFilter.Date = msg.Date.DateCriterion()
 		}
@@ -644,13 +1170,16 @@ func (m ScenesModel) View() string {
 				organised(scene.Organized),
 				scene.Date,
 				rating(scene.Rating),
+				oCounter(scene.OCounter),
 				sceneTitle(scene),
 				sceneSize(scene),
+				plays(scene),
 				scene.Studio.Name,
 				performerList(scene.Performers),
 				tagList(scene.Tags),
 				details(scene.Details),
 			},
+			Marked: m.marks.Has(scene.ID),
 		})
 		if m.pageState.index == i {
 			rows[i].Background = &ColorRowSelected
@@ -661,6 +1190,9 @@ func (m ScenesModel) View() string {
 		m.pageState.String(),
 		sort(m.sort, m.sortDirection),
 	}
+	if m.marks.Len() > 0 {
+		leftStatus = append(leftStatus, fmt.Sprintf("%d marked", m.marks.Len()))
+	}
 
 	rightStatus := sceneFilterStatus(m.sceneFilter, m.StashLookup)
 	if m.query != "" {
@@ -676,6 +1208,18 @@ func (m ScenesModel) View() string {
 	)
 }
 
+// openScenesTabMsg returns a ModelTabOpenMsg that opens a new scenes tab with the given filter applied.
+func openScenesTabMsg(filter stash.SceneFilter) ModelTabOpenMsg {
+	return ModelTabOpenMsg{
+		Name: "scenes",
+		Configure: func(t TabModel) {
+			if sm, ok := t.(*ScenesModel); ok {
+				sm.sceneFilter = filter
+			}
+		},
+	}
+}
+
 // updateCmd sets initial loading state then returns a tea.Cmd to execute loading of scenes.
 func (m *ScenesModel) updateCmd() tea.Cmd {
 	if m.pageState.PerPage == 0 {
@@ -713,7 +1257,8 @@ func wrapScenesLoadedMsg(msg tea.Msg, requestID uint64) tea.Msg {
 
 var (
 	sceneTable = &ui.Table{
-		AltBackground: ColorBlack,
+		AltBackground:    ColorBlack,
+		MarkedBackground: ColorRowMarked,
 		Cols: []ui.Column{
 			{
 				Name: "Organised",
@@ -725,6 +1270,11 @@ var (
 			{
 				Name: "Rating",
 			},
+			{
+				Name:       "O",
+				Foreground: &ColorBlue,
+				Align:      lipgloss.Right,
+			},
 			{
 				Name:       "Title",
 				Foreground: &ColorOffWhite,
@@ -736,6 +1286,10 @@ var (
 				Foreground: &ColorBlue,
 				Align:      lipgloss.Right,
 			},
+			{
+				Name:       "Played",
+				Foreground: &ColorGrey,
+			},
 			{
 				Name:       "Studio",
 				Foreground: &ColorSalmon,
@@ -0,0 +1,135 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/drakenstar/stash-cli/command"
+	"github.com/drakenstar/stash-cli/stash"
+	"github.com/stretchr/testify/require"
+)
+
+type scenePlayTestService struct {
+	deleteTestService
+	played   []string
+	activity []*float64
+	changes  []oCounterChange
+	updates  []stash.SceneUpdate
+	studio   string
+}
+
+func (s *scenePlayTestService) UpdateScene(old, updated stash.Scene) tea.Cmd {
+	s.updates = append(s.updates, stash.NewSceneUpdate(old, updated))
+	return func() tea.Msg { return sceneUpdatedMsg{scene: updated} }
+}
+
+func (s *scenePlayTestService) StudioScene(_ stash.Scene, studio string) tea.Cmd {
+	s.studio = studio
+	return nil
+}
+
+func (s *scenePlayTestService) OCounterScene(scene stash.Scene, change oCounterChange) tea.Cmd {
+	s.changes = append(s.changes, change)
+	updated := scene
+	updated.OCounter = 0
+	return func() tea.Msg { return sceneUpdatedMsg{scene: updated} }
+}
+
+func (s *scenePlayTestService) RecordPlay(scene stash.Scene) tea.Cmd {
+	s.played = append(s.played, scene.ID)
+	return func() tea.Msg {
+		return scenePlayedMsg{id: scene.ID, count: scene.PlayCount + 1, at: time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)}
+	}
+}
+
+func (s *scenePlayTestService) SaveSceneActivity(scene stash.Scene, resumeTime, playDuration *float64) tea.Cmd {
+	s.activity = append(s.activity, resumeTime, playDuration)
+	return func() tea.Msg { return sceneActivitySavedMsg{id: scene.ID, resumeTime: resumeTime} }
+}
+
+func TestScenesModelOpenRecordsPlay(t *testing.T) {
+	srv := &scenePlayTestService{}
+	m := NewScenesModel(srv, deleteTestLookup{})
+	m.scenes = []stash.Scene{{ID: "1", PlayCount: 2}}
+
+	_, cmd := m.Update(ScenesModelOpenMsg{})
+	batch := cmd().(tea.BatchMsg)
+	require.Len(t, batch, 2)
+	require.Equal(t, OpenMsg{m.scenes[0]}, batch[0]())
+	require.Equal(t, []string{"1"}, srv.played)
+
+	m.Update(batch[1]())
+	require.Equal(t, 3, m.Current().PlayCount)
+	require.Equal(t, "3\U000f040a 2024-07-01", plays(m.Current()))
+
+	_, cmd = m.Update(ScenesModelActivityMsg{Resume: ptr(90.0)})
+	m.Update(cmd())
+	require.Equal(t, 90.0, m.Current().ResumeTime)
+	require.Nil(t, srv.activity[1])
+}
+
+func TestScenesModelOCounter(t *testing.T) {
+	srv := &scenePlayTestService{}
+	m := NewScenesModel(srv, deleteTestLookup{})
+	m.scenes = []stash.Scene{{ID: "1", OCounter: 2}}
+
+	for _, input := range []string{"o+", "o-", "o reset"} {
+		msg, err := ScenesModelCommandConfig.Resolve(command.Parser(input))
+		require.NoError(t, err)
+		_, cmd := m.Update(msg)
+		m.Update(cmd())
+	}
+	require.Equal(t, []oCounterChange{oCounterIncrement, oCounterDecrement, oCounterReset}, srv.changes)
+	require.Equal(t, 0, m.Current().OCounter)
+
+	m.Update(ScenesModelFilterMsg{O: ptr(0)})
+	require.Equal(t, &stash.IntCriterion{Value: 0, Modifier: stash.CriterionModifierEquals}, m.sceneFilter.OCounter)
+}
+
+func TestScenesModelEditMetadata(t *testing.T) {
+	srv := &scenePlayTestService{}
+	m := NewScenesModel(srv, deleteTestLookup{})
+	m.scenes = []stash.Scene{{ID: "1", Title: "Scene"}}
+
+	for _, input := range []string{`rate 80`, `organise`, `title "New Title"`, `date 2024-02-03`, `studio "Studio A"`} {
+		msg, err := ScenesModelCommandConfig.Resolve(command.Parser(input))
+		require.NoError(t, err)
+		_, cmd := m.Update(msg)
+		if cmd != nil {
+			m.Update(cmd())
+		}
+	}
+
+	require.Len(t, srv.updates, 4)
+	require.Equal(t, 80, *srv.updates[0].Rating)
+	require.True(t, *srv.updates[1].Organized)
+	require.Equal(t, "New Title", *srv.updates[2].Title)
+	require.Equal(t, "2024-02-03", *srv.updates[3].Date)
+	require.Nil(t, srv.updates[3].Title)
+	require.Equal(t, "Studio A", srv.studio)
+	require.Equal(t, "New Title", m.Current().Title)
+	require.True(t, m.Current().Organized)
+
+	_, cmd := m.Update(ScenesModelRateMsg{Rating: 0})
+	require.IsType(t, ErrorMsg{}, cmd())
+	_, cmd = m.Update(ScenesModelDateMsg{Date: "yesterday"})
+	require.IsType(t, ErrorMsg{}, cmd())
+}
+
+func TestScenesModelSortPlays(t *testing.T) {
+	m := NewScenesModel(deleteTestService{}, deleteTestLookup{})
+
+	m.Update(ScenesModelSortMsg{Field: "-played"})
+	require.Equal(t, stash.SortLastPlayedAt, m.sort)
+	require.Equal(t, stash.SortDirectionDesc, m.sortDirection)
+
+	_, cmd := m.Update(ScenesModelSortMsg{Field: "unknown"})
+	require.IsType(t, ErrorMsg{}, cmd())
+}
+
+func TestCmdServiceRecordPlayDisabled(t *testing.T) {
+	s := &cmdService{noRecordPlay: true}
+	require.Nil(t, s.RecordPlay(stash.Scene{ID: "1"}))
+	require.Nil(t, s.SaveSceneActivity(stash.Scene{ID: "1"}, ptr(1.0), nil))
+}
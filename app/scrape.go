@@ -0,0 +1,482 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/drakenstar/stash-cli/stash"
+	"github.com/drakenstar/stash-cli/ui"
+	"github.com/hasura/go-graphql-client"
+)
+
+// ScrapeService scrapes metadata for scenes and galleries, and applies the scraped changes that are accepted.
+type ScrapeService interface {
+	ScrapeScene(stash.Scene, string) tea.Cmd
+	ScrapeGallery(stash.Gallery, string) tea.Cmd
+	ApplySceneScrape(stash.Scene, []scrapeChange) tea.Cmd
+	ApplyGalleryScrape(stash.Gallery, []scrapeChange) tea.Cmd
+}
+
+// ScrapeMsg scrapes metadata for the current item and shows the changes it would make for review.  Source is a URL to
+// scrape, or the ID or name of a scraper.  Without a source the URL of the item is scraped.
+type ScrapeMsg struct {
+	Source string `command:",positional"`
+}
+
+// Fields of a scene or gallery that scraping can change.
+const (
+	scrapeFieldTitle     = "Title"
+	scrapeFieldCode      = "Code"
+	scrapeFieldDetails   = "Details"
+	scrapeFieldDirector  = "Director"
+	scrapeFieldDate      = "Date"
+	scrapeFieldURL       = "URL"
+	scrapeFieldStudio    = "Studio"
+	scrapeFieldTag       = "Tag"
+	scrapeFieldPerformer = "Performer"
+)
+
+// scrapeChange is a single change that scraped metadata makes to a scene or gallery.  Value is the new value of a
+// field, or the name of a studio, tag or performer to set or add.  ID is the ID of the matching entity in stash, and is
+// empty if it does not exist yet and would be created.
+type scrapeChange struct {
+	Field string
+	Text  string
+	Value string
+	ID    string
+
+	// URL and Disambiguation are used when creating a studio or performer.
+	URL            string
+	Disambiguation string
+}
+
+// isNew returns true if the change adds a studio, tag or performer that does not yet exist.
+func (c scrapeChange) isNew() bool {
+	switch c.Field {
+	case scrapeFieldStudio, scrapeFieldTag, scrapeFieldPerformer:
+		return c.ID == ""
+	}
+	return false
+}
+
+// scrapeReviewMsg shows the changes found by a scrape for review.  Apply is called with the changes that are
+// accepted.
+type scrapeReviewMsg struct {
+	title   string
+	changes []scrapeChange
+	apply   func([]scrapeChange) tea.Cmd
+}
+
+// scrapeAcceptedMsg is sent once the changes of a scrape have been reviewed.
+type scrapeAcceptedMsg struct {
+	changes []scrapeChange
+	apply   func([]scrapeChange) tea.Cmd
+}
+
+// scrapeConfirmation returns a confirmation listing the changes of a scrape.  Changes that would create a studio, tag or
+// performer start unchecked, so that nothing is created unless asked for.
+func scrapeConfirmation(msg scrapeReviewMsg) ui.Confirmation {
+	items := make([]ui.ConfirmationItem, len(msg.changes))
+	for i, change := range msg.changes {
+		items[i] = ui.ConfirmationItem{Text: change.Text, Checked: !change.isNew()}
+	}
+	return ui.Confirmation{
+		Title:   "Scraped " + msg.title,
+		Message: "Choose the changes to apply.",
+		Items:   items,
+		Options: []ui.ConfirmationOption{
+			{
+				Text: "Cancel",
+				Cmd:  func() tea.Msg { return dismissModalMsg{} },
+			},
+			{
+				Text: "Apply",
+				CheckedCmd: func(checked []int) tea.Cmd {
+					accepted := make([]scrapeChange, len(checked))
+					for i, j := range checked {
+						accepted[i] = msg.changes[j]
+					}
+					return func() tea.Msg {
+						return scrapeAcceptedMsg{changes: accepted, apply: msg.apply}
+					}
+				},
+			},
+		},
+	}
+}
+
+type sceneScrapedMsg struct {
+	scene   stash.Scene
+	scraped stash.ScrapedScene
+}
+
+type galleryScrapedMsg struct {
+	gallery stash.Gallery
+	scraped stash.ScrapedGallery
+}
+
+// sceneScrapeChanges returns the changes scraped would make to scene.  Fields are only changed where a value was
+// scraped, and URLs, tags and performers are only ever added.
+func sceneScrapeChanges(scene stash.Scene, scraped stash.ScrapedScene) []scrapeChange {
+	var changes []scrapeChange
+	changes = appendFieldChange(changes, scrapeFieldTitle, scene.Title, scraped.Title)
+	changes = appendFieldChange(changes, scrapeFieldCode, scene.Code, scraped.Code)
+	changes = appendFieldChange(changes, scrapeFieldDate, scene.Date, scraped.Date)
+	changes = appendFieldChange(changes, scrapeFieldDirector, scene.Director, scraped.Director)
+	changes = appendFieldChange(changes, scrapeFieldDetails, scene.Details, scraped.Details)
+	for _, url := range scraped.URLs {
+		if url != "" && !slices.Contains(scene.URLs, url) {
+			changes = append(changes, scrapeChange{Field: scrapeFieldURL, Text: "URL: + " + url, Value: url})
+		}
+	}
+	changes = appendStudioChange(changes, scene.Studio, scraped.Studio)
+	changes = appendTagChanges(changes, scene.Tags, scraped.Tags)
+	return appendPerformerChanges(changes, scene.Performers, scraped.Performers)
+}
+
+// galleryScrapeChanges returns the changes scraped would make to gallery.  Fields are only changed where a value was
+// scraped, and tags and performers are only ever added.
+func galleryScrapeChanges(gallery stash.Gallery, scraped stash.ScrapedGallery) []scrapeChange {
+	var changes []scrapeChange
+	changes = appendFieldChange(changes, scrapeFieldTitle, gallery.Title, scraped.Title)
+	changes = appendFieldChange(changes, scrapeFieldDate, gallery.Date, scraped.Date)
+	changes = appendFieldChange(changes, scrapeFieldURL, gallery.URL, scraped.URL)
+	changes = appendFieldChange(changes, scrapeFieldDetails, gallery.Details, scraped.Details)
+	changes = appendStudioChange(changes, gallery.Studio, scraped.Studio)
+	changes = appendTagChanges(changes, gallery.Tags, scraped.Tags)
+	return appendPerformerChanges(changes, gallery.Performers, scraped.Performers)
+}
+
+// scrapeTextLimit is the length that values are shortened to when listed for review.
+const scrapeTextLimit = 60
+
+func appendFieldChange(changes []scrapeChange, field, current, scraped string) []scrapeChange {
+	if scraped == "" || scraped == current {
+		return changes
+	}
+	text := fmt.Sprintf("%s: %s", field, shortenScraped(scraped))
+	if current != "" {
+		text = fmt.Sprintf("%s: %s → %s", field, shortenScraped(current), shortenScraped(scraped))
+	}
+	return append(changes, scrapeChange{Field: field, Text: text, Value: scraped})
+}
+
+// shortenScraped returns the first line of s, shortened to scrapeTextLimit.
+func shortenScraped(s string) string {
+	line, _, multiline := strings.Cut(s, "\n")
+	runes := []rune(line)
+	if len(runes) > scrapeTextLimit {
+		return string(runes[:scrapeTextLimit-1]) + "…"
+	}
+	if multiline {
+		return line + "…"
+	}
+	return line
+}
+
+func appendStudioChange(changes []scrapeChange, current stash.Studio, scraped *stash.ScrapedStudio) []scrapeChange {
+	if scraped == nil || scraped.Name == "" {
+		return changes
+	}
+	if scraped.StoredID != "" && scraped.StoredID == current.ID {
+		return changes
+	}
+	if scraped.StoredID == "" && strings.EqualFold(scraped.Name, current.Name) {
+		return changes
+	}
+	text := fmt.Sprintf("%s: %s", scrapeFieldStudio, scrapedName(scraped.Name, scraped.StoredID))
+	if current.ID != "" {
+		text = fmt.Sprintf("%s: %s → %s", scrapeFieldStudio, current.Name, scrapedName(scraped.Name, scraped.StoredID))
+	}
+	return append(changes, scrapeChange{
+		Field: scrapeFieldStudio,
+		Text:  text,
+		Value: scraped.Name,
+		ID:    scraped.StoredID,
+		URL:   scraped.URL,
+	})
+}
+
+func appendTagChanges(changes []scrapeChange, current []stash.Tag, scraped []stash.ScrapedTag) []scrapeChange {
+	for _, tag := range scraped {
+		if tag.Name == "" || slices.ContainsFunc(current, func(t stash.Tag) bool {
+			return t.ID == tag.StoredID || strings.EqualFold(t.Name, tag.Name)
+		}) {
+			continue
+		}
+		changes = append(changes, scrapeChange{
+			Field: scrapeFieldTag,
+			Text:  fmt.Sprintf("%s: + %s", scrapeFieldTag, scrapedName(tag.Name, tag.StoredID)),
+			Value: tag.Name,
+			ID:    tag.StoredID,
+		})
+	}
+	return changes
+}
+
+func appendPerformerChanges(changes []scrapeChange, current []stash.Performer, scraped []stash.ScrapedPerformer) []scrapeChange {
+	for _, performer := range scraped {
+		if performer.Name == "" || slices.ContainsFunc(current, func(p stash.Performer) bool {
+			return p.ID == performer.StoredID || strings.EqualFold(p.Name, performer.Name)
+		}) {
+			continue
+		}
+		name := performer.Name
+		if performer.Disambiguation != "" {
+			name = fmt.Sprintf("%s (%s)", name, performer.Disambiguation)
+		}
+		changes = append(changes, scrapeChange{
+			Field:          scrapeFieldPerformer,
+			Text:           fmt.Sprintf("%s: + %s", scrapeFieldPerformer, scrapedName(name, performer.StoredID)),
+			Value:          performer.Name,
+			ID:             performer.StoredID,
+			URL:            performer.URL,
+			Disambiguation: performer.Disambiguation,
+		})
+	}
+	return changes
+}
+
+// scrapedName marks the names of scraped entities that do not exist in stash.
+func scrapedName(name, storedID string) string {
+	if storedID == "" {
+		return name + " (new)"
+	}
+	return name
+}
+
+// ScrapeScene scrapes metadata for scene from source, which is a URL or the ID or name of a scraper.  Without a source
+// the first URL of the scene is scraped.
+func (s *cmdService) ScrapeScene(scene stash.Scene, source string) tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		ctx := context.Background()
+		if source == "" {
+			if len(scene.URLs) == 0 {
+				return ErrorMsg{fmt.Errorf("scene has no URL to scrape, give a scraper or URL")}
+			}
+			source = scene.URLs[0]
+		}
+
+		if isScrapeURL(source) {
+			scraped, ok, err := s.Stash.ScrapeSceneURL(ctx, source)
+			if err != nil {
+				return ErrorMsg{err}
+			}
+			if !ok {
+				return ErrorMsg{fmt.Errorf("nothing scraped from %s", source)}
+			}
+			return sceneScrapedMsg{scene: scene, scraped: scraped}
+		}
+
+		scraper, err := s.findScraper(ctx, stash.ScrapeContentTypeScene, source)
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		id := graphql.ID(scene.ID)
+		scenes, err := s.Stash.ScrapeSingleScene(ctx, stash.ScraperSource{ScraperID: &scraper.ID}, stash.ScrapeSingleScene{SceneID: &id})
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		if len(scenes) == 0 {
+			return ErrorMsg{fmt.Errorf("nothing scraped by %s", scraper.Name)}
+		}
+		return sceneScrapedMsg{scene: scene, scraped: scenes[0]}
+	})
+}
+
+// ScrapeGallery scrapes metadata for gallery from source, which is a URL or the ID or name of a scraper.  Without a
+// source the URL of the gallery is scraped.
+func (s *cmdService) ScrapeGallery(gallery stash.Gallery, source string) tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		ctx := context.Background()
+		if source == "" {
+			if gallery.URL == "" {
+				return ErrorMsg{fmt.Errorf("gallery has no URL to scrape, give a scraper or URL")}
+			}
+			source = gallery.URL
+		}
+
+		if isScrapeURL(source) {
+			scraped, ok, err := s.Stash.ScrapeGalleryURL(ctx, source)
+			if err != nil {
+				return ErrorMsg{err}
+			}
+			if !ok {
+				return ErrorMsg{fmt.Errorf("nothing scraped from %s", source)}
+			}
+			return galleryScrapedMsg{gallery: gallery, scraped: scraped}
+		}
+
+		scraper, err := s.findScraper(ctx, stash.ScrapeContentTypeGallery, source)
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		id := graphql.ID(gallery.ID)
+		galleries, err := s.Stash.ScrapeSingleGallery(ctx, stash.ScraperSource{ScraperID: &scraper.ID}, stash.ScrapeSingleGallery{GalleryID: &id})
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		if len(galleries) == 0 {
+			return ErrorMsg{fmt.Errorf("nothing scraped by %s", scraper.Name)}
+		}
+		return galleryScrapedMsg{gallery: gallery, scraped: galleries[0]}
+	})
+}
+
+func isScrapeURL(source string) bool {
+	return strings.Contains(source, "://")
+}
+
+// findScraper returns the scraper of content with the ID or name given, ignoring case.
+func (s *cmdService) findScraper(ctx context.Context, content stash.ScrapeContentType, name string) (stash.Scraper, error) {
+	scrapers, err := s.Stash.ListScrapers(ctx, []stash.ScrapeContentType{content})
+	if err != nil {
+		return stash.Scraper{}, err
+	}
+	for _, scraper := range scrapers {
+		if scraper.ID == name || strings.EqualFold(scraper.Name, name) {
+			return scraper, nil
+		}
+	}
+	return stash.Scraper{}, fmt.Errorf("no %s scraper '%s'", strings.ToLower(string(content)), name)
+}
+
+// ApplySceneScrape applies accepted scrape changes to scene, creating any studio, tags and performers that do not yet
+// exist.
+func (s *cmdService) ApplySceneScrape(scene stash.Scene, changes []scrapeChange) tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		ctx := context.Background()
+		updated := scene
+		updated.URLs = slices.Clone(scene.URLs)
+		updated.Tags = slices.Clone(scene.Tags)
+		updated.Performers = slices.Clone(scene.Performers)
+		for _, change := range changes {
+			var err error
+			switch change.Field {
+			case scrapeFieldTitle:
+				updated.Title = change.Value
+			case scrapeFieldCode:
+				updated.Code = change.Value
+			case scrapeFieldDetails:
+				updated.Details = change.Value
+			case scrapeFieldDirector:
+				updated.Director = change.Value
+			case scrapeFieldDate:
+				updated.Date = change.Value
+			case scrapeFieldURL:
+				updated.URLs = append(updated.URLs, change.Value)
+			default:
+				err = s.applyScrapedEntity(ctx, change, &updated.Studio, &updated.Tags, &updated.Performers)
+			}
+			if err != nil {
+				return ErrorMsg{err}
+			}
+		}
+		result, err := s.Stash.SceneUpdate(ctx, stash.NewSceneUpdate(scene, updated))
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		return sceneUpdatedMsg{scene: result}
+	})
+}
+
+// ApplyGalleryScrape applies accepted scrape changes to gallery, creating any studio, tags and performers that do not
+// yet exist.
+func (s *cmdService) ApplyGalleryScrape(gallery stash.Gallery, changes []scrapeChange) tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		ctx := context.Background()
+		updated := gallery
+		updated.Tags = slices.Clone(gallery.Tags)
+		updated.Performers = slices.Clone(gallery.Performers)
+		for _, change := range changes {
+			var err error
+			switch change.Field {
+			case scrapeFieldTitle:
+				updated.Title = change.Value
+			case scrapeFieldDetails:
+				updated.Details = change.Value
+			case scrapeFieldDate:
+				updated.Date = change.Value
+			case scrapeFieldURL:
+				updated.URL = change.Value
+			default:
+				err = s.applyScrapedEntity(ctx, change, &updated.Studio, &updated.Tags, &updated.Performers)
+			}
+			if err != nil {
+				return ErrorMsg{err}
+			}
+		}
+		result, err := s.Stash.GalleryUpdate(ctx, stash.NewGalleryUpdate(gallery, updated))
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		return galleryTaggedMsg{gallery: result}
+	})
+}
+
+// applyScrapedEntity sets the studio or adds the tag or performer of a scrape change, creating it first if it does not
+// exist in stash.
+func (s *cmdService) applyScrapedEntity(ctx context.Context, change scrapeChange, studio *stash.Studio, tags *[]stash.Tag, performers *[]stash.Performer) error {
+	switch change.Field {
+	case scrapeFieldStudio:
+		if change.ID != "" {
+			*studio = stash.Studio{ID: change.ID, Name: change.Value}
+			return nil
+		}
+		input := stash.StudioCreate{Name: change.Value}
+		if change.URL != "" {
+			input.URL = &change.URL
+		}
+		created, err := s.Stash.StudioCreate(ctx, input)
+		if err != nil {
+			return fmt.Errorf("creating studio %s: %w", change.Value, err)
+		}
+		*studio = created
+
+	case scrapeFieldTag:
+		if change.ID != "" {
+			*tags = append(*tags, stash.Tag{ID: change.ID, Name: change.Value})
+			return nil
+		}
+		created, err := s.resolveOrCreateTags(ctx, []string{change.Value})
+		if err != nil {
+			return fmt.Errorf("creating tag %s: %w", change.Value, err)
+		}
+		*tags = append(*tags, created...)
+
+	case scrapeFieldPerformer:
+		if change.ID != "" {
+			*performers = append(*performers, stash.Performer{ID: change.ID, Name: change.Value})
+			return nil
+		}
+		input := stash.PerformerCreate{Name: change.Value, Disambiguation: change.Disambiguation}
+		if change.URL != "" {
+			input.URL = &change.URL
+		}
+		created, err := s.Stash.PerformerCreate(ctx, input)
+		if err != nil {
+			return fmt.Errorf("creating performer %s: %w", change.Value, err)
+		}
+		*performers = append(*performers, created)
+	}
+	return nil
+}
+
+func (s *cmdServiceWithID) ScrapeScene(scene stash.Scene, source string) tea.Cmd {
+	return s.withID(s.s.ScrapeScene(scene, source))
+}
+
+func (s *cmdServiceWithID) ScrapeGallery(gallery stash.Gallery, source string) tea.Cmd {
+	return s.withID(s.s.ScrapeGallery(gallery, source))
+}
+
+func (s *cmdServiceWithID) ApplySceneScrape(scene stash.Scene, changes []scrapeChange) tea.Cmd {
+	return s.withID(s.s.ApplySceneScrape(scene, changes))
+}
+
+func (s *cmdServiceWithID) ApplyGalleryScrape(gallery stash.Gallery, changes []scrapeChange) tea.Cmd {
+	return s.withID(s.s.ApplyGalleryScrape(gallery, changes))
+}
@@ -0,0 +1,196 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/drakenstar/stash-cli/command"
+	"github.com/drakenstar/stash-cli/stash"
+	"github.com/hasura/go-graphql-client"
+	"github.com/stretchr/testify/require"
+)
+
+type scrapeTestService struct{}
+
+func (scrapeTestService) ScrapeScene(stash.Scene, string) tea.Cmd                  { return nil }
+func (scrapeTestService) ScrapeGallery(stash.Gallery, string) tea.Cmd              { return nil }
+func (scrapeTestService) ApplySceneScrape(stash.Scene, []scrapeChange) tea.Cmd     { return nil }
+func (scrapeTestService) ApplyGalleryScrape(stash.Gallery, []scrapeChange) tea.Cmd { return nil }
+
+func TestScrapeCommand(t *testing.T) {
+	msg, err := ScenesModelCommandConfig.Resolve(command.Parser(`scrape https://example.com/scene/1`))
+	require.NoError(t, err)
+	require.Equal(t, ScrapeMsg{Source: "https://example.com/scene/1"}, msg)
+
+	msg, err = GalleriesModelCommandConfig.Resolve(command.Parser(`scrape`))
+	require.NoError(t, err)
+	require.Equal(t, ScrapeMsg{}, msg)
+}
+
+func TestSceneScrapeChanges(t *testing.T) {
+	scene := stash.Scene{
+		Title:      "Old",
+		Date:       "2024-01-02",
+		URLs:       []string{"https://example.com/1"},
+		Studio:     stash.Studio{ID: "5", Name: "Studio"},
+		Tags:       []stash.Tag{{ID: "1", Name: "Outdoors"}},
+		Performers: []stash.Performer{{ID: "7", Name: "Jane"}},
+	}
+	scraped := stash.ScrapedScene{
+		Title:  "New",
+		Date:   "2024-01-02",
+		URLs:   []string{"https://example.com/1", "https://example.com/2"},
+		Studio: &stash.ScrapedStudio{StoredID: "5", Name: "Studio"},
+		Tags: []stash.ScrapedTag{
+			{StoredID: "1", Name: "Outdoors"},
+			{Name: "outdoors"},
+			{StoredID: "2", Name: "Beach"},
+			{Name: "Sunset"},
+		},
+		Performers: []stash.ScrapedPerformer{
+			{Name: "Jane"},
+			{Name: "Anna", Disambiguation: "II"},
+		},
+	}
+
+	changes := sceneScrapeChanges(scene, scraped)
+	var texts []string
+	for _, change := range changes {
+		texts = append(texts, change.Text)
+	}
+	require.Equal(t, []string{
+		"Title: Old → New",
+		"URL: + https://example.com/2",
+		"Tag: + Beach",
+		"Tag: + Sunset (new)",
+		"Performer: + Anna (II) (new)",
+	}, texts)
+	require.False(t, changes[2].isNew())
+	require.True(t, changes[3].isNew())
+
+	require.Empty(t, sceneScrapeChanges(scene, stash.ScrapedScene{Title: "Old"}))
+}
+
+type scrapeTestStash struct {
+	stash.Stash
+	scrapers []stash.Scraper
+	source   stash.ScraperSource
+	update   stash.SceneUpdate
+	gUpdate  stash.GalleryUpdate
+	created  []string
+}
+
+func (s *scrapeTestStash) ListScrapers(context.Context, []stash.ScrapeContentType) ([]stash.Scraper, error) {
+	return s.scrapers, nil
+}
+
+func (s *scrapeTestStash) ScrapeSingleScene(_ context.Context, source stash.ScraperSource, _ stash.ScrapeSingleScene) ([]stash.ScrapedScene, error) {
+	s.source = source
+	return []stash.ScrapedScene{{Title: "Scraped"}}, nil
+}
+
+func (s *scrapeTestStash) ScrapeSceneURL(context.Context, string) (stash.ScrapedScene, bool, error) {
+	return stash.ScrapedScene{}, false, nil
+}
+
+func (s *scrapeTestStash) SceneUpdate(_ context.Context, input stash.SceneUpdate) (stash.Scene, error) {
+	s.update = input
+	return stash.Scene{ID: string(input.ID)}, nil
+}
+
+func (s *scrapeTestStash) GalleryUpdate(_ context.Context, input stash.GalleryUpdate) (stash.Gallery, error) {
+	s.gUpdate = input
+	return stash.Gallery{ID: string(input.ID)}, nil
+}
+
+func (s *scrapeTestStash) TagFindByName(context.Context, string) (stash.Tag, error) {
+	return stash.Tag{}, stash.ErrTagNotFound
+}
+
+func (s *scrapeTestStash) TagCreate(_ context.Context, input stash.TagCreate) (stash.Tag, error) {
+	s.created = append(s.created, "tag "+input.Name)
+	return stash.Tag{ID: "20", Name: input.Name}, nil
+}
+
+func (s *scrapeTestStash) PerformerCreate(_ context.Context, input stash.PerformerCreate) (stash.Performer, error) {
+	s.created = append(s.created, "performer "+input.Name)
+	return stash.Performer{ID: "30", Name: input.Name}, nil
+}
+
+func (s *scrapeTestStash) StudioCreate(_ context.Context, input stash.StudioCreate) (stash.Studio, error) {
+	s.created = append(s.created, "studio "+input.Name)
+	return stash.Studio{ID: "40", Name: input.Name}, nil
+}
+
+func TestScrapeScene(t *testing.T) {
+	backend := &scrapeTestStash{scrapers: []stash.Scraper{{ID: "example", Name: "Example Site"}}}
+	svc := &cmdService{Stash: backend, cache: newCacheLookup()}
+	scene := stash.Scene{ID: "1"}
+
+	msg := svc.ScrapeScene(scene, "example site")()
+	require.Equal(t, sceneScrapedMsg{scene: scene, scraped: stash.ScrapedScene{Title: "Scraped"}}, msg)
+	require.Equal(t, "example", *backend.source.ScraperID)
+
+	require.IsType(t, ErrorMsg{}, svc.ScrapeScene(scene, "missing")())
+	require.IsType(t, ErrorMsg{}, svc.ScrapeScene(scene, "")())
+	require.IsType(t, ErrorMsg{}, svc.ScrapeScene(scene, "https://example.com/1")())
+}
+
+func TestApplySceneScrape(t *testing.T) {
+	backend := &scrapeTestStash{}
+	svc := &cmdService{Stash: backend, cache: newCacheLookup()}
+	scene := stash.Scene{ID: "1", Title: "Old", Tags: []stash.Tag{{ID: "1", Name: "Outdoors"}}}
+
+	msg := svc.ApplySceneScrape(scene, []scrapeChange{
+		{Field: scrapeFieldTitle, Value: "New"},
+		{Field: scrapeFieldStudio, Value: "Studio"},
+		{Field: scrapeFieldTag, Value: "Beach", ID: "2"},
+		{Field: scrapeFieldTag, Value: "Sunset"},
+		{Field: scrapeFieldPerformer, Value: "Anna"},
+	})()
+	require.IsType(t, sceneUpdatedMsg{}, msg)
+	require.Equal(t, "New", *backend.update.Title)
+	require.Equal(t, graphql.ID("40"), *backend.update.StudioID)
+	require.Equal(t, []graphql.ID{"1", "2", "20"}, *backend.update.TagIDs)
+	require.Equal(t, []graphql.ID{"30"}, *backend.update.PerformerIDs)
+	require.Equal(t, []string{"studio Studio", "tag Sunset", "performer Anna"}, backend.created)
+	// The scene given is left unchanged.
+	require.Len(t, scene.Tags, 1)
+}
+
+func TestModelScrapeReview(t *testing.T) {
+	var applied []scrapeChange
+	m := *New(&stash.LocalStash{}, nil)
+	changes := []scrapeChange{
+		{Field: scrapeFieldTitle, Text: "Title: New", Value: "New"},
+		{Field: scrapeFieldTag, Text: "Tag: + Sunset (new)", Value: "Sunset"},
+		{Field: scrapeFieldDate, Text: "Date: 2024-01-02", Value: "2024-01-02"},
+	}
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = updated.(Model)
+	updated, _ = m.Update(scrapeReviewMsg{
+		title:   "Scene",
+		changes: changes,
+		apply: func(accepted []scrapeChange) tea.Cmd {
+			applied = accepted
+			return nil
+		},
+	})
+	m = updated.(Model)
+	require.NotNil(t, m.confirmation)
+	require.Contains(t, m.View(), "[ ] Tag: + Sunset (new)")
+
+	// Uncheck the title, then apply.
+	for _, key := range []tea.KeyMsg{{Type: tea.KeySpace}, {Type: tea.KeyRight}, {Type: tea.KeyEnter}} {
+		var cmd tea.Cmd
+		updated, cmd = m.Update(key)
+		m = updated.(Model)
+		if cmd != nil {
+			updated, _ = m.Update(cmd())
+			m = updated.(Model)
+		}
+	}
+	require.Nil(t, m.confirmation)
+	require.Equal(t, []scrapeChange{changes[2]}, applied)
+}
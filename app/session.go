@@ -93,9 +93,16 @@ type Session struct {
 }
 
 type TabSession struct {
-	Type      string            `json:"type"`
-	Scenes    *ScenesSession    `json:"scenes,omitempty"`
-	Galleries *GalleriesSession `json:"galleries,omitempty"`
+	Type       string             `json:"type"`
+	Scenes     *ScenesSession     `json:"scenes,omitempty"`
+	Galleries  *GalleriesSession  `json:"galleries,omitempty"`
+	Performers *PerformersSession `json:"performers,omitempty"`
+	Studios    *StudiosSession    `json:"studios,omitempty"`
+	Tags       *TagsSession       `json:"tags,omitempty"`
+	Markers    *MarkersSession    `json:"markers,omitempty"`
+	Images     *ImagesSession     `json:"images,omitempty"`
+	Movies     *MoviesSession     `json:"movies,omitempty"`
+	Duplicates *DuplicatesSession `json:"duplicates,omitempty"`
 }
 
 type ScenesSession struct {
@@ -132,6 +139,116 @@ type GalleriesFilterStateSession struct {
 	Page          PageSession         `json:"page"`
 }
 
+type PerformersSession struct {
+	Query         string                         `json:"query,omitempty"`
+	Sort          string                         `json:"sort,omitempty"`
+	SortDirection string                         `json:"sortDirection,omitempty"`
+	Filter        stash.PerformerFilter          `json:"filter"`
+	Page          PageSession                    `json:"page"`
+	History       []PerformersFilterStateSession `json:"history,omitempty"`
+}
+
+type PerformersFilterStateSession struct {
+	Query         string                `json:"query,omitempty"`
+	Sort          string                `json:"sort,omitempty"`
+	SortDirection string                `json:"sortDirection,omitempty"`
+	Filter        stash.PerformerFilter `json:"filter"`
+	Page          PageSession           `json:"page"`
+}
+
+type StudiosSession struct {
+	Query         string                      `json:"query,omitempty"`
+	Sort          string                      `json:"sort,omitempty"`
+	SortDirection string                      `json:"sortDirection,omitempty"`
+	Filter        stash.StudioFilter          `json:"filter"`
+	Page          PageSession                 `json:"page"`
+	History       []StudiosFilterStateSession `json:"history,omitempty"`
+}
+
+type StudiosFilterStateSession struct {
+	Query         string             `json:"query,omitempty"`
+	Sort          string             `json:"sort,omitempty"`
+	SortDirection string             `json:"sortDirection,omitempty"`
+	Filter        stash.StudioFilter `json:"filter"`
+	Page          PageSession        `json:"page"`
+}
+
+type TagsSession struct {
+	Query         string                   `json:"query,omitempty"`
+	Sort          string                   `json:"sort,omitempty"`
+	SortDirection string                   `json:"sortDirection,omitempty"`
+	Filter        stash.TagFilter          `json:"filter"`
+	Page          PageSession              `json:"page"`
+	History       []TagsFilterStateSession `json:"history,omitempty"`
+}
+
+type TagsFilterStateSession struct {
+	Query         string          `json:"query,omitempty"`
+	Sort          string          `json:"sort,omitempty"`
+	SortDirection string          `json:"sortDirection,omitempty"`
+	Filter        stash.TagFilter `json:"filter"`
+	Page          PageSession     `json:"page"`
+}
+
+type MarkersSession struct {
+	Query         string                      `json:"query,omitempty"`
+	Sort          string                      `json:"sort,omitempty"`
+	SortDirection string                      `json:"sortDirection,omitempty"`
+	Filter        stash.SceneMarkerFilter     `json:"filter"`
+	Page          PageSession                 `json:"page"`
+	History       []MarkersFilterStateSession `json:"history,omitempty"`
+}
+
+type MarkersFilterStateSession struct {
+	Query         string                  `json:"query,omitempty"`
+	Sort          string                  `json:"sort,omitempty"`
+	SortDirection string                  `json:"sortDirection,omitempty"`
+	Filter        stash.SceneMarkerFilter `json:"filter"`
+	Page          PageSession             `json:"page"`
+}
+
+type ImagesSession struct {
+	Gallery       string                     `json:"gallery,omitempty"`
+	Query         string                     `json:"query,omitempty"`
+	Sort          string                     `json:"sort,omitempty"`
+	SortDirection string                     `json:"sortDirection,omitempty"`
+	Filter        stash.ImageFilter          `json:"filter"`
+	Page          PageSession                `json:"page"`
+	History       []ImagesFilterStateSession `json:"history,omitempty"`
+}
+
+type ImagesFilterStateSession struct {
+	Query         string            `json:"query,omitempty"`
+	Sort          string            `json:"sort,omitempty"`
+	SortDirection string            `json:"sortDirection,omitempty"`
+	Filter        stash.ImageFilter `json:"filter"`
+	Page          PageSession       `json:"page"`
+}
+
+type MoviesSession struct {
+	Query         string                     `json:"query,omitempty"`
+	Sort          string                     `json:"sort,omitempty"`
+	SortDirection string                     `json:"sortDirection,omitempty"`
+	Filter        stash.MovieFilter          `json:"filter"`
+	Page          PageSession                `json:"page"`
+	History       []MoviesFilterStateSession `json:"history,omitempty"`
+}
+
+type MoviesFilterStateSession struct {
+	Query         string            `json:"query,omitempty"`
+	Sort          string            `json:"sort,omitempty"`
+	SortDirection string            `json:"sortDirection,omitempty"`
+	Filter        stash.MovieFilter `json:"filter"`
+	Page          PageSession       `json:"page"`
+}
+
+// DuplicatesSession stores how closely scenes must match to be shown as duplicates.  Groups are found again when the
+// session is restored.
+type DuplicatesSession struct {
+	Distance     int     `json:"distance"`
+	DurationDiff float64 `json:"durationDiff"`
+}
+
 type PageSession struct {
 	Position int  `json:"position"`
 	Opened   bool `json:"opened"`
@@ -158,6 +275,29 @@ func (m Model) SaveSession() Session {
 		case *GalleriesModel:
 			saved := model.saveSession()
 			session.Tabs = append(session.Tabs, TabSession{Type: "galleries", Galleries: &saved})
+		case *PerformersModel:
+			saved := model.saveSession()
+			session.Tabs = append(session.Tabs, TabSession{Type: "performers", Performers: &saved})
+		case *StudiosModel:
+			saved := model.saveSession()
+			session.Tabs = append(session.Tabs, TabSession{Type: "studios", Studios: &saved})
+		case *TagsModel:
+			saved := model.saveSession()
+			session.Tabs = append(session.Tabs, TabSession{Type: "tags", Tags: &saved})
+		case *MarkersModel:
+			saved := model.saveSession()
+			session.Tabs = append(session.Tabs, TabSession{Type: "markers", Markers: &saved})
+		case *ImagesModel:
+			saved := model.saveSession()
+			session.Tabs = append(session.Tabs, TabSession{Type: "images", Images: &saved})
+		case *MoviesModel:
+			saved := model.saveSession()
+			session.Tabs = append(session.Tabs, TabSession{Type: "movies", Movies: &saved})
+		case *DuplicatesModel:
+			saved := model.saveSession()
+			session.Tabs = append(session.Tabs, TabSession{Type: "duplicates", Duplicates: &saved})
+		case *JobsModel:
+			session.Tabs = append(session.Tabs, TabSession{Type: "jobs"})
 		}
 	}
 	if session.ActiveTab >= len(session.Tabs) {
@@ -192,6 +332,34 @@ func (m *Model) RestoreSession(session Session) bool {
 			if saved.Galleries != nil {
 				typed.restoreSession(*saved.Galleries)
 			}
+		case *PerformersModel:
+			if saved.Performers != nil {
+				typed.restoreSession(*saved.Performers)
+			}
+		case *StudiosModel:
+			if saved.Studios != nil {
+				typed.restoreSession(*saved.Studios)
+			}
+		case *TagsModel:
+			if saved.Tags != nil {
+				typed.restoreSession(*saved.Tags)
+			}
+		case *MarkersModel:
+			if saved.Markers != nil {
+				typed.restoreSession(*saved.Markers)
+			}
+		case *ImagesModel:
+			if saved.Images != nil {
+				typed.restoreSession(*saved.Images)
+			}
+		case *MoviesModel:
+			if saved.Movies != nil {
+				typed.restoreSession(*saved.Movies)
+			}
+		case *DuplicatesModel:
+			if saved.Duplicates != nil {
+				typed.restoreSession(*saved.Duplicates)
+			}
 		}
 		t := tab{id: id, model: model}
 		m.tabs = append(m.tabs, t)
@@ -208,6 +376,7 @@ func (m *Model) RestoreSession(session Session) bool {
 func (m *Model) resetSession() tea.Cmd {
 	m.mode = ModeNormal
 	m.confirmation = nil
+	m.form = nil
 	m.pendingDelete = nil
 	m.err = nil
 	m.tagsLoading = false
@@ -222,7 +391,7 @@ func (m *Model) resetSession() tea.Cmd {
 	}
 	return tea.Batch(
 		m.tabs[m.active].model.Init(),
-		m.tabs[m.active].model.SetSize(Size{Width: m.screen.Width, Height: m.screen.Height - 5}),
+		m.tabs[m.active].model.SetSize(m.tabSize()),
 	)
 }
 
@@ -339,6 +508,144 @@ func (m *GalleriesModel) restoreSession(session GalleriesSession) {
 	}
 }
 
+func (m *PerformersModel) saveSession() PerformersSession {
+	history := make([]PerformersFilterStateSession, 0, len(m.history))
+	for _, state := range m.history {
+		history = append(history, PerformersFilterStateSession{
+			Query:         state.query,
+			Sort:          state.sort,
+			SortDirection: state.sortDirection,
+			Filter:        state.performerFilter,
+			Page:          savePageSession(state.pageState),
+		})
+	}
+	return PerformersSession{
+		Query:         m.query,
+		Sort:          m.sort,
+		SortDirection: m.sortDirection,
+		Filter:        m.performerFilter,
+		Page:          savePageSession(m.pageState),
+		History:       history,
+	}
+}
+
+func (m *PerformersModel) restoreSession(session PerformersSession) {
+	m.query = session.Query
+	m.sort = session.Sort
+	if m.sort == "" {
+		m.sort = stash.SortName
+	}
+	m.sortDirection = session.SortDirection
+	if m.sortDirection == "" {
+		m.sortDirection = stash.SortDirectionAsc
+	}
+	m.performerFilter = session.Filter
+	m.pageState = restorePageSession(session.Page, m.pageState.PerPage)
+	m.performers = nil
+	m.history = make([]performerFilterState, 0, len(session.History))
+	for _, state := range session.History {
+		m.history = append(m.history, performerFilterState{
+			query:           state.Query,
+			sort:            state.Sort,
+			sortDirection:   state.SortDirection,
+			performerFilter: state.Filter,
+			pageState:       restorePageSession(state.Page, m.pageState.PerPage),
+		})
+	}
+}
+
+func (m *StudiosModel) saveSession() StudiosSession {
+	history := make([]StudiosFilterStateSession, 0, len(m.history))
+	for _, state := range m.history {
+		history = append(history, StudiosFilterStateSession{
+			Query:         state.query,
+			Sort:          state.sort,
+			SortDirection: state.sortDirection,
+			Filter:        state.studioFilter,
+			Page:          savePageSession(state.pageState),
+		})
+	}
+	return StudiosSession{
+		Query:         m.query,
+		Sort:          m.sort,
+		SortDirection: m.sortDirection,
+		Filter:        m.studioFilter,
+		Page:          savePageSession(m.pageState),
+		History:       history,
+	}
+}
+
+func (m *StudiosModel) restoreSession(session StudiosSession) {
+	m.query = session.Query
+	m.sort = session.Sort
+	if m.sort == "" {
+		m.sort = stash.SortName
+	}
+	m.sortDirection = session.SortDirection
+	if m.sortDirection == "" {
+		m.sortDirection = stash.SortDirectionAsc
+	}
+	m.studioFilter = session.Filter
+	m.pageState = restorePageSession(session.Page, m.pageState.PerPage)
+	m.rows = nil
+	m.history = make([]studioFilterState, 0, len(session.History))
+	for _, state := range session.History {
+		m.history = append(m.history, studioFilterState{
+			query:         state.Query,
+			sort:          state.Sort,
+			sortDirection: state.SortDirection,
+			studioFilter:  state.Filter,
+			pageState:     restorePageSession(state.Page, m.pageState.PerPage),
+		})
+	}
+}
+
+func (m *TagsModel) saveSession() TagsSession {
+	history := make([]TagsFilterStateSession, 0, len(m.history))
+	for _, state := range m.history {
+		history = append(history, TagsFilterStateSession{
+			Query:         state.query,
+			Sort:          state.sort,
+			SortDirection: state.sortDirection,
+			Filter:        state.tagFilter,
+			Page:          savePageSession(state.pageState),
+		})
+	}
+	return TagsSession{
+		Query:         m.query,
+		Sort:          m.sort,
+		SortDirection: m.sortDirection,
+		Filter:        m.tagFilter,
+		Page:          savePageSession(m.pageState),
+		History:       history,
+	}
+}
+
+func (m *TagsModel) restoreSession(session TagsSession) {
+	m.query = session.Query
+	m.sort = session.Sort
+	if m.sort == "" {
+		m.sort = stash.SortName
+	}
+	m.sortDirection = session.SortDirection
+	if m.sortDirection == "" {
+		m.sortDirection = stash.SortDirectionAsc
+	}
+	m.tagFilter = session.Filter
+	m.pageState = restorePageSession(session.Page, m.pageState.PerPage)
+	m.rows = nil
+	m.history = make([]tagFilterState, 0, len(session.History))
+	for _, state := range session.History {
+		m.history = append(m.history, tagFilterState{
+			query:         state.Query,
+			sort:          state.Sort,
+			sortDirection: state.SortDirection,
+			tagFilter:     state.Filter,
+			pageState:     restorePageSession(state.Page, m.pageState.PerPage),
+		})
+	}
+}
+
 func savePageSession(page pageState) PageSession {
 	return PageSession{Position: page.Position(), Opened: page.opened}
 }
@@ -362,3 +669,157 @@ func clampInt(v, low, high int) int {
 	}
 	return v
 }
+
+func (m *MarkersModel) saveSession() MarkersSession {
+	history := make([]MarkersFilterStateSession, 0, len(m.history))
+	for _, state := range m.history {
+		history = append(history, MarkersFilterStateSession{
+			Query:         state.query,
+			Sort:          state.sort,
+			SortDirection: state.sortDirection,
+			Filter:        state.markerFilter,
+			Page:          savePageSession(state.pageState),
+		})
+	}
+	return MarkersSession{
+		Query:         m.query,
+		Sort:          m.sort,
+		SortDirection: m.sortDirection,
+		Filter:        m.markerFilter,
+		Page:          savePageSession(m.pageState),
+		History:       history,
+	}
+}
+
+func (m *MarkersModel) restoreSession(session MarkersSession) {
+	m.query = session.Query
+	m.sort = session.Sort
+	if m.sort == "" {
+		m.sort = stash.SortUpdatedAt
+	}
+	m.sortDirection = session.SortDirection
+	if m.sortDirection == "" {
+		m.sortDirection = stash.SortDirectionDesc
+	}
+	m.markerFilter = session.Filter
+	m.pageState = restorePageSession(session.Page, m.pageState.PerPage)
+	m.markers = nil
+	m.history = make([]markerFilterState, 0, len(session.History))
+	for _, state := range session.History {
+		m.history = append(m.history, markerFilterState{
+			query:         state.Query,
+			sort:          state.Sort,
+			sortDirection: state.SortDirection,
+			markerFilter:  state.Filter,
+			pageState:     restorePageSession(state.Page, m.pageState.PerPage),
+		})
+	}
+}
+
+func (m *ImagesModel) saveSession() ImagesSession {
+	history := make([]ImagesFilterStateSession, 0, len(m.history))
+	for _, state := range m.history {
+		history = append(history, ImagesFilterStateSession{
+			Query:         state.query,
+			Sort:          state.sort,
+			SortDirection: state.sortDirection,
+			Filter:        state.imageFilter,
+			Page:          savePageSession(state.pageState),
+		})
+	}
+	return ImagesSession{
+		Gallery:       m.gallery,
+		Query:         m.query,
+		Sort:          m.sort,
+		SortDirection: m.sortDirection,
+		Filter:        m.imageFilter,
+		Page:          savePageSession(m.pageState),
+		History:       history,
+	}
+}
+
+func (m *ImagesModel) restoreSession(session ImagesSession) {
+	m.gallery = session.Gallery
+	m.query = session.Query
+	m.sort = session.Sort
+	if m.sort == "" {
+		m.sort = stash.SortPath
+	}
+	m.sortDirection = session.SortDirection
+	if m.sortDirection == "" {
+		m.sortDirection = stash.SortDirectionAsc
+	}
+	m.imageFilter = session.Filter
+	m.pageState = restorePageSession(session.Page, m.pageState.PerPage)
+	m.images = nil
+	m.history = make([]imageFilterState, 0, len(session.History))
+	for _, state := range session.History {
+		m.history = append(m.history, imageFilterState{
+			query:         state.Query,
+			sort:          state.Sort,
+			sortDirection: state.SortDirection,
+			imageFilter:   state.Filter,
+			pageState:     restorePageSession(state.Page, m.pageState.PerPage),
+		})
+	}
+}
+
+func (m *MoviesModel) saveSession() MoviesSession {
+	history := make([]MoviesFilterStateSession, 0, len(m.history))
+	for _, state := range m.history {
+		history = append(history, MoviesFilterStateSession{
+			Query:         state.query,
+			Sort:          state.sort,
+			SortDirection: state.sortDirection,
+			Filter:        state.movieFilter,
+			Page:          savePageSession(state.pageState),
+		})
+	}
+	return MoviesSession{
+		Query:         m.query,
+		Sort:          m.sort,
+		SortDirection: m.sortDirection,
+		Filter:        m.movieFilter,
+		Page:          savePageSession(m.pageState),
+		History:       history,
+	}
+}
+
+func (m *MoviesModel) restoreSession(session MoviesSession) {
+	m.query = session.Query
+	m.sort = session.Sort
+	if m.sort == "" {
+		m.sort = stash.SortName
+	}
+	m.sortDirection = session.SortDirection
+	if m.sortDirection == "" {
+		m.sortDirection = stash.SortDirectionAsc
+	}
+	m.movieFilter = session.Filter
+	m.pageState = restorePageSession(session.Page, m.pageState.PerPage)
+	m.movies = nil
+	m.history = make([]movieFilterState, 0, len(session.History))
+	for _, state := range session.History {
+		m.history = append(m.history, movieFilterState{
+			query:         state.Query,
+			sort:          state.Sort,
+			sortDirection: state.SortDirection,
+			movieFilter:   state.Filter,
+			pageState:     restorePageSession(state.Page, m.pageState.PerPage),
+		})
+	}
+}
+
+func (m *DuplicatesModel) saveSession() DuplicatesSession {
+	return DuplicatesSession{
+		Distance:     m.distance,
+		DurationDiff: m.durationDiff,
+	}
+}
+
+func (m *DuplicatesModel) restoreSession(session DuplicatesSession) {
+	m.distance = session.Distance
+	m.durationDiff = session.DurationDiff
+	m.groups = nil
+	m.loaded = false
+}
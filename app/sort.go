@@ -0,0 +1,32 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/drakenstar/stash-cli/stash"
+)
+
+// sortFields maps the sort field names a user can enter to the sort value understood by stash.
+type sortFields map[string]string
+
+// parse takes a sort field as input by a user and returns a stash sort value and direction.  A field prefixed with
+// '-' sorts in descending order, otherwise ascending is used.  The special field "random" returns a newly seeded
+// random sort.
+func (f sortFields) parse(field string) (string, string, error) {
+	if field == "random" {
+		return stash.RandomSort(), stash.SortDirectionAsc, nil
+	}
+
+	direction := stash.SortDirectionAsc
+	if strings.HasPrefix(field, "-") {
+		direction = stash.SortDirectionDesc
+		field = field[1:]
+	}
+
+	sort, ok := f[field]
+	if !ok {
+		return "", "", fmt.Errorf("unsupported sort field '%s'", field)
+	}
+	return sort, direction, nil
+}
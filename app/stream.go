@@ -0,0 +1,64 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/drakenstar/stash-cli/config"
+	"github.com/drakenstar/stash-cli/stash"
+)
+
+// directStreamLabel is the label stash gives the untranscoded stream of a scene.
+const directStreamLabel = "Direct stream"
+
+// openStreamMsg opens scene from one of its streams rather than its file path, starting playback at start seconds.
+// label picks the stream, and is the configured stream if empty.
+type openStreamMsg struct {
+	scene stash.Scene
+	start float64
+	label string
+}
+
+// OpenStream opens scene from the stream with label, starting playback at start seconds.
+func (s *cmdService) OpenStream(scene stash.Scene, start float64, label string) tea.Cmd {
+	return s.withLoadingCount(func() tea.Msg {
+		streams, err := s.Stash.SceneStreams(context.Background(), scene.ID)
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		stream, err := findStream(streams, label)
+		if err != nil {
+			return ErrorMsg{err}
+		}
+		return OpenMsg{config.Stream{URL: stream.URL, Start: start}}
+	})
+}
+
+// findStream returns the stream with label, or the direct stream if label is empty.  Labels are matched ignoring case,
+// and otherwise the first stream with a label containing label is used, so that "480p" finds "MP4 Standard (480p)".
+func findStream(streams []stash.SceneStreamEndpoint, label string) (stash.SceneStreamEndpoint, error) {
+	if len(streams) == 0 {
+		return stash.SceneStreamEndpoint{}, fmt.Errorf("scene has no streams")
+	}
+	if label == "" {
+		label = directStreamLabel
+	}
+	for _, stream := range streams {
+		if strings.EqualFold(stream.Label, label) {
+			return stream, nil
+		}
+	}
+	for _, stream := range streams {
+		if strings.Contains(strings.ToLower(stream.Label), strings.ToLower(label)) {
+			return stream, nil
+		}
+	}
+
+	labels := make([]string, len(streams))
+	for i, stream := range streams {
+		labels[i] = stream.Label
+	}
+	return stash.SceneStreamEndpoint{}, fmt.Errorf("no stream '%s', expected one of %s", label, strings.Join(labels, ", "))
+}
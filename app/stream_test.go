@@ -0,0 +1,78 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drakenstar/stash-cli/command"
+	"github.com/drakenstar/stash-cli/config"
+	"github.com/drakenstar/stash-cli/stash"
+	"github.com/stretchr/testify/require"
+)
+
+var testStreams = []stash.SceneStreamEndpoint{
+	{URL: "http://stash/scene/1/stream", Label: "Direct stream"},
+	{URL: "http://stash/scene/1/stream.mp4", Label: "MP4"},
+	{URL: "http://stash/scene/1/stream.mp4?resolution=STANDARD", Label: "MP4 Standard (480p)"},
+}
+
+func TestOpenCommand(t *testing.T) {
+	tests := map[string]ScenesModelOpenMsg{
+		`open`:              {},
+		`open skip`:         {Skip: true},
+		`open stream`:       {Stream: true},
+		`open skip 480p`:    {Skip: true, Label: "480p"},
+		`open "MP4 (480p)"`: {Label: "MP4 (480p)"},
+	}
+	for input, expected := range tests {
+		msg, err := ScenesModelCommandConfig.Resolve(command.Parser(input))
+		require.NoError(t, err, input)
+		require.Equal(t, expected, msg, input)
+	}
+}
+
+func TestFindStream(t *testing.T) {
+	stream, err := findStream(testStreams, "")
+	require.NoError(t, err)
+	require.Equal(t, "Direct stream", stream.Label)
+
+	stream, err = findStream(testStreams, "mp4")
+	require.NoError(t, err)
+	require.Equal(t, "MP4", stream.Label)
+
+	stream, err = findStream(testStreams, "480p")
+	require.NoError(t, err)
+	require.Equal(t, "MP4 Standard (480p)", stream.Label)
+
+	_, err = findStream(testStreams, "webm")
+	require.EqualError(t, err, "no stream 'webm', expected one of Direct stream, MP4, MP4 Standard (480p)")
+}
+
+type streamTestStash struct {
+	stash.Stash
+}
+
+func (streamTestStash) SceneStreams(context.Context, string) ([]stash.SceneStreamEndpoint, error) {
+	return testStreams, nil
+}
+
+func TestModelOpenStream(t *testing.T) {
+	var opened any
+	m := New(streamTestStash{}, func(content any) error {
+		opened = content
+		return nil
+	})
+	scene := stash.Scene{ID: "1"}
+	marker := stash.SceneMarker{Seconds: 30, Scene: scene}
+
+	// Without streaming configured, only an explicit stream opens one.
+	_, cmd := m.Update(OpenMsg{marker})
+	require.Nil(t, cmd())
+	require.Equal(t, marker, opened)
+	_, cmd = m.Update(openStreamMsg{scene: scene, label: "480p"})
+	require.Equal(t, OpenMsg{config.Stream{URL: "http://stash/scene/1/stream.mp4?resolution=STANDARD"}}, cmd())
+
+	m.SetStream(true, "MP4")
+	_, cmd = m.Update(OpenMsg{marker})
+	require.Equal(t, OpenMsg{config.Stream{URL: "http://stash/scene/1/stream.mp4", Start: 30}}, cmd())
+}
@@ -0,0 +1,469 @@
+package app
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/drakenstar/stash-cli/command"
+	"github.com/drakenstar/stash-cli/stash"
+	"github.com/drakenstar/stash-cli/ui"
+)
+
+type studioFilterState struct {
+	query         string
+	sort          string
+	sortDirection string
+	studioFilter  stash.StudioFilter
+
+	pageState pageState
+}
+
+type StudioService interface {
+	Studios(stash.FindFilter, stash.StudioFilter) tea.Cmd
+}
+
+// StudiosModel lists studios as a tree.  Without a search query only top level studios are fetched, and child
+// studios are loaded as they are expanded.  A search query lists all matching studios regardless of their parent.
+type StudiosModel struct {
+	StudioService
+	StashLookup
+
+	treeList[stash.StudioDetail]
+
+	query         string
+	sort          string
+	sortDirection string
+	studioFilter  stash.StudioFilter
+
+	history []studioFilterState
+
+	screen Size
+
+	listRequestID uint64
+}
+
+func NewStudiosModel(studioService StudioService, lookup StashLookup) *StudiosModel {
+	m := &StudiosModel{
+		StudioService: studioService,
+		StashLookup:   lookup,
+	}
+	m.pageState.PerPage = 40
+	m.reset()
+	return m
+}
+
+func (m *StudiosModel) reset() tea.Cmd {
+	m.query = ""
+	m.sort = stash.SortName
+	m.sortDirection = stash.SortDirectionAsc
+	m.studioFilter = stash.StudioFilter{}
+	m.pageState.Reset()
+
+	return m.updateCmd()
+}
+
+func (m *StudiosModel) SetSize(s Size) tea.Cmd {
+	m.screen = s
+	m.pageState.SetPerPage(s.Height - 1) // account for status line
+	return m.updateCmd()
+}
+
+func (m *StudiosModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *StudiosModel) Title() string {
+	t := "Studios"
+	if m.query != "" {
+		t = fmt.Sprintf("\"%s\"", m.query)
+	}
+	return fmt.Sprintf("%c %s (%s)", '\U000f0381', t, humanNumber(m.pageState.total))
+}
+
+func (m *StudiosModel) Current() stash.StudioDetail {
+	return m.rows[m.cursor].item
+}
+
+func (m *StudiosModel) PushState(mutate func(*StudiosModel)) (*StudiosModel, tea.Cmd) {
+	m.history = append(m.history, studioFilterState{
+		query:         m.query,
+		sort:          m.sort,
+		sortDirection: m.sortDirection,
+		studioFilter:  m.studioFilter,
+		pageState:     m.pageState,
+	})
+	mutate(m)
+	m.pageState.Reset()
+	return m, m.updateCmd()
+}
+
+// Pop sets the current state to the previous state from the history stack.  If the history stack is empty this is a
+// noop.
+func (m *StudiosModel) Pop() (*StudiosModel, tea.Cmd) {
+	if len(m.history) == 0 {
+		return m, nil
+	}
+
+	state := m.history[len(m.history)-1]
+	m.history = m.history[0 : len(m.history)-1]
+
+	m.pageState = state.pageState
+	m.query = state.query
+	m.sort = state.sort
+	m.sortDirection = state.sortDirection
+	m.studioFilter = state.studioFilter
+	m.rows = []treeRow[stash.StudioDetail]{}
+
+	return m, m.updateCmd()
+}
+
+var StudiosModelDefaultKeymap = map[string]string{
+	"up":    "skip -1",
+	"down":  "skip 1",
+	"right": "expand",
+	"left":  "collapse",
+	"enter": "scenes",
+	"z":     "skip -1",
+	"x":     "skip 1",
+	"o":     "scenes",
+	"G":     "galleries",
+	"u":     "undo",
+	"`":     "open-url",
+}
+
+var StudiosModelCommandConfig command.Config = command.Config{
+	"collapse":  binder[StudiosModelCollapseMsg](),
+	"expand":    binder[StudiosModelExpandMsg](),
+	"filter":    binder[StudiosModelFilterMsg](),
+	"galleries": binder[StudiosModelGalleriesMsg](),
+	"open-url":  binder[StudiosModelOpenURLMsg](),
+	"refresh":   binder[StudiosModelRefresh](),
+	"reset":     binder[StudiosModelResetMsg](),
+	"scenes":    binder[StudiosModelScenesMsg](),
+	"sort":      binder[StudiosModelSortMsg](),
+	"skip":      binder[StudiosModelSkipMsg](),
+	"undo":      binder[StudiosModelUndoMsg](),
+}
+
+var studioSortFields = sortFields{
+	"name":      stash.SortName,
+	"scenes":    stash.SortScenesCount,
+	"galleries": stash.SortGalleriesCount,
+	"created":   stash.SortCreatedAt,
+	"updated":   stash.SortUpdatedAt,
+}
+
+func (m StudiosModel) CommandConfig() command.Config {
+	return StudiosModelCommandConfig
+}
+
+func (m StudiosModel) Search(query string) tea.Msg {
+	return StudiosModelFilterMsg{
+		Query: &query,
+	}
+}
+
+// StudiosModelFilterMsg controls the filtering of studios.  Scenes and Galleries filter to studios with more than the
+// given number of scenes or galleries.
+type StudiosModelFilterMsg struct {
+	Query     *string
+	Scenes    *int
+	Galleries *int
+}
+
+// StudiosModelExpandMsg loads and shows the child studios of the current studio.
+type StudiosModelExpandMsg struct{}
+
+// StudiosModelCollapseMsg hides the child studios of the current studio.  If the current studio is not expanded then
+// its parent is collapsed instead.
+type StudiosModelCollapseMsg struct{}
+
+// StudiosModelScenesMsg opens a new scenes tab filtered to the current studio and all of its sub-studios.
+type StudiosModelScenesMsg struct{}
+
+// StudiosModelGalleriesMsg opens a new galleries tab filtered to the current studio and all of its sub-studios.
+type StudiosModelGalleriesMsg struct{}
+
+type StudiosModelOpenURLMsg struct{}
+
+type StudiosModelRefresh struct{}
+
+type StudiosModelResetMsg struct{}
+
+type StudiosModelSkipMsg struct {
+	Count int `command:",positional"`
+}
+
+type StudiosModelSortMsg struct {
+	Field string `command:",positional"`
+}
+
+type StudiosModelUndoMsg struct{}
+
+func (m *StudiosModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case StudiosModelFilterMsg:
+		return m.PushState(func(sm *StudiosModel) {
+			if msg.Query != nil {
+				sm.query = *msg.Query
+			}
+			if msg.Scenes != nil {
+				sm.studioFilter.SceneCount = &stash.IntCriterion{
+					Value:    *msg.Scenes,
+					Modifier: stash.CriterionModifierGreaterThan,
+				}
+			}
+			if msg.Galleries != nil {
+				sm.studioFilter.GalleryCount = &stash.IntCriterion{
+					Value:    *msg.Galleries,
+					Modifier: stash.CriterionModifierGreaterThan,
+				}
+			}
+		})
+
+	case StudiosModelExpandMsg:
+		if len(m.rows) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no studio selected"))
+		}
+		row := &m.rows[m.cursor]
+		if row.expanded || len(row.item.ChildStudios) == 0 {
+			return m, nil
+		}
+		row.expanded = true
+		return m, m.childrenCmd(row.item.ID)
+
+	case StudiosModelCollapseMsg:
+		m.collapseCurrent()
+
+	case studioChildrenLoadedMsg:
+		m.insertChildren(func(s stash.StudioDetail) bool { return s.ID == msg.parentID }, msg.studios)
+
+	case StudiosModelScenesMsg:
+		if len(m.rows) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no studio selected"))
+		}
+		filter := stash.SceneFilter{
+			Studios: m.studioCriterion(),
+		}
+		return m, func() tea.Msg { return openScenesTabMsg(filter) }
+
+	case StudiosModelGalleriesMsg:
+		if len(m.rows) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no studio selected"))
+		}
+		filter := stash.GalleryFilter{
+			Studios: m.studioCriterion(),
+		}
+		return m, func() tea.Msg { return openGalleriesTabMsg(filter) }
+
+	case StudiosModelOpenURLMsg:
+		if len(m.rows) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no studio selected"))
+		}
+		src := path.Join("studios", m.Current().ID)
+		return m, func() tea.Msg { return OpenMsg{src} }
+
+	case StudiosModelRefresh:
+		return m, m.updateCmd()
+
+	case StudiosModelResetMsg:
+		return m, m.reset()
+
+	case StudiosModelSortMsg:
+		sort, direction, err := studioSortFields.parse(msg.Field)
+		if err != nil {
+			return m, NewErrorCmd(err)
+		}
+		return m.PushState(func(sm *StudiosModel) {
+			sm.sort = sort
+			sm.sortDirection = direction
+		})
+
+	case StudiosModelSkipMsg:
+		if m.skip(msg.Count) {
+			return m, m.updateCmd()
+		}
+
+	case StudiosModelUndoMsg:
+		return m.Pop()
+
+	case tea.KeyMsg:
+		if cmd, ok := StudiosModelDefaultKeymap[msg.String()]; ok {
+			return m, func() tea.Msg { return ui.CommandExecMsg{Command: cmd} }
+		}
+
+	case studiosListLoadedMsg:
+		if msg.requestID != m.listRequestID {
+			return m, nil
+		}
+		m.setRows(msg.studios, msg.total)
+	}
+
+	return m, nil
+}
+
+// studioCriterion returns a criterion matching the current studio and all of its sub-studios.
+func (m *StudiosModel) studioCriterion() *stash.HierarchicalMultiCriterion {
+	return &stash.HierarchicalMultiCriterion{
+		Value:    []string{m.Current().ID},
+		Modifier: stash.CriterionModifierIncludes,
+		Depth:    -1,
+	}
+}
+
+func (m *StudiosModel) childrenCmd(parentID string) tea.Cmd {
+	cmd := m.StudioService.Studios(stash.FindFilter{
+		PerPage:   -1,
+		Sort:      stash.SortName,
+		Direction: stash.SortDirectionAsc,
+	}, stash.StudioFilter{
+		Parents: &stash.MultiCriterion{
+			Value:    []string{parentID},
+			Modifier: stash.CriterionModifierIncludes,
+		},
+	})
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		return wrapStudioChildrenLoadedMsg(cmd(), parentID)
+	}
+}
+
+func (m StudiosModel) View() string {
+	end := len(m.rows)
+	if m.pageState.PerPage > 0 {
+		end = min(m.offset+m.pageState.PerPage, len(m.rows))
+	}
+
+	var rows []ui.Row
+	for i := m.offset; i < end; i++ {
+		row := m.rows[i]
+		rows = append(rows, ui.Row{
+			Values: []string{
+				treeName(row.item.Name, row.depth, len(row.item.ChildStudios) > 0, row.expanded),
+				strings.Join(row.item.Aliases, ", "),
+				row.item.ParentStudio.Name,
+				strconv.Itoa(row.item.SceneCount),
+				strconv.Itoa(row.item.GalleryCount),
+			},
+		})
+		if m.cursor == i {
+			rows[len(rows)-1].Background = &ColorRowSelected
+		}
+	}
+
+	leftStatus := []string{
+		m.pageState.String(),
+		sort(m.sort, m.sortDirection),
+	}
+
+	rightStatus := studioFilterStatus(m.studioFilter, m.StashLookup)
+	if m.query != "" {
+		rightStatus = append(rightStatus, "\""+m.query+"\"")
+	}
+	if len(m.history) > 0 {
+		rightStatus = append(rightStatus, fmt.Sprintf("[%d]", len(m.history)))
+	}
+
+	return lipgloss.JoinVertical(0,
+		statusBar.Render(m.screen.Width, leftStatus, rightStatus),
+		studiosTable.Render(m.screen.Width, rows),
+	)
+}
+
+// updateCmd requests the current page of studios.  When there is no search query only top level studios are
+// requested, as their children are shown by expanding them.
+func (m *StudiosModel) updateCmd() tea.Cmd {
+	if m.pageState.PerPage == 0 {
+		return nil
+	}
+	filter := m.studioFilter
+	if m.query == "" && filter.Parents == nil {
+		filter.Parents = &stash.MultiCriterion{
+			Modifier: stash.CriterionModifierIsNull,
+		}
+	}
+	requestID := atomic.AddUint64(&m.listRequestID, 1)
+	cmd := m.StudioService.Studios(stash.FindFilter{
+		Query:     m.query,
+		Page:      m.pageState.page + 1,
+		PerPage:   m.pageState.PerPage,
+		Sort:      m.sort,
+		Direction: m.sortDirection,
+	}, filter)
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		return wrapStudiosLoadedMsg(cmd(), requestID)
+	}
+}
+
+func wrapStudiosLoadedMsg(msg tea.Msg, requestID uint64) tea.Msg {
+	switch msg := msg.(type) {
+	case studiosMsg:
+		return studiosListLoadedMsg{requestID: requestID, studios: msg.studios, total: msg.total}
+	case loadingMsg:
+		if payload, ok := msg.payload.(studiosMsg); ok {
+			msg.payload = studiosListLoadedMsg{requestID: requestID, studios: payload.studios, total: payload.total}
+		}
+		return msg
+	default:
+		return msg
+	}
+}
+
+func wrapStudioChildrenLoadedMsg(msg tea.Msg, parentID string) tea.Msg {
+	switch msg := msg.(type) {
+	case studiosMsg:
+		return studioChildrenLoadedMsg{parentID: parentID, studios: msg.studios}
+	case loadingMsg:
+		if payload, ok := msg.payload.(studiosMsg); ok {
+			msg.payload = studioChildrenLoadedMsg{parentID: parentID, studios: payload.studios}
+		}
+		return msg
+	default:
+		return msg
+	}
+}
+
+var (
+	studiosTable = &ui.Table{
+		AltBackground: ColorBlack,
+		Cols: []ui.Column{
+			{
+				Name:       "Name",
+				Foreground: &ColorYellow,
+				Bold:       true,
+				Weight:     2,
+			},
+			{
+				Name:       "Aliases",
+				Foreground: &ColorGrey,
+				Weight:     1,
+			},
+			{
+				Name:       "Parent",
+				Foreground: &ColorPurple,
+				Weight:     1,
+			},
+			{
+				Name:       "Scenes",
+				Foreground: &ColorBlue,
+				Align:      lipgloss.Right,
+			},
+			{
+				Name:       "Galleries",
+				Foreground: &ColorBlue,
+				Align:      lipgloss.Right,
+				Flex:       true,
+			},
+		},
+	}
+)
@@ -0,0 +1,89 @@
+package app
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/drakenstar/stash-cli/stash"
+	"github.com/stretchr/testify/require"
+)
+
+type studioTestService struct {
+	filters []stash.StudioFilter
+	results map[string][]stash.StudioDetail
+}
+
+func (s *studioTestService) Studios(_ stash.FindFilter, sf stash.StudioFilter) tea.Cmd {
+	s.filters = append(s.filters, sf)
+	parent := ""
+	if sf.Parents != nil && len(sf.Parents.Value) > 0 {
+		parent = sf.Parents.Value[0]
+	}
+	studios := s.results[parent]
+	return func() tea.Msg { return studiosMsg{studios: studios, total: len(studios)} }
+}
+
+func newStudioTestModel() (*StudiosModel, *studioTestService) {
+	srv := &studioTestService{results: map[string][]stash.StudioDetail{
+		"": {
+			{ID: "1", Name: "Network", ChildStudios: []stash.Studio{{ID: "2"}, {ID: "3"}}},
+			{ID: "4", Name: "Independent"},
+		},
+		"1": {
+			{ID: "2", Name: "Studio A", ParentStudio: stash.Studio{ID: "1", Name: "Network"}},
+			{ID: "3", Name: "Studio B", ParentStudio: stash.Studio{ID: "1", Name: "Network"}},
+		},
+	}}
+	m := NewStudiosModel(srv, tagResolveTestLookup{})
+	cmd := m.SetSize(Size{Width: 80, Height: 20})
+	m.Update(cmd())
+	return m, srv
+}
+
+func TestStudiosModelRequestsTopLevelStudios(t *testing.T) {
+	m, srv := newStudioTestModel()
+	require.Equal(t, stash.CriterionModifierIsNull, srv.filters[0].Parents.Modifier)
+	require.Len(t, m.rows, 2)
+
+	_, cmd := m.Update(StudiosModelFilterMsg{Query: ptr("studio")})
+	cmd()
+	require.Nil(t, srv.filters[len(srv.filters)-1].Parents)
+}
+
+func TestStudiosModelExpandCollapse(t *testing.T) {
+	m, _ := newStudioTestModel()
+
+	_, cmd := m.Update(StudiosModelExpandMsg{})
+	require.NotNil(t, cmd)
+	m.Update(cmd())
+	require.Len(t, m.rows, 4)
+	require.Equal(t, "Studio A", m.rows[1].item.Name)
+	require.Equal(t, 1, m.rows[1].depth)
+
+	m.Update(StudiosModelSkipMsg{Count: 2})
+	require.Equal(t, "Studio B", m.Current().Name)
+
+	// Collapsing a child collapses its parent and moves the cursor to it.
+	m.Update(StudiosModelCollapseMsg{})
+	require.Len(t, m.rows, 2)
+	require.Equal(t, "Network", m.Current().Name)
+	require.False(t, m.rows[0].expanded)
+}
+
+func TestStudiosModelOpensTabsWithSubStudios(t *testing.T) {
+	m, _ := newStudioTestModel()
+
+	_, cmd := m.Update(StudiosModelScenesMsg{})
+	open := cmd().(ModelTabOpenMsg)
+	scenes := NewScenesModel(deleteTestService{}, tagResolveTestLookup{})
+	open.Configure(scenes)
+	require.Equal(t, &stash.HierarchicalMultiCriterion{
+		Value:    []string{"1"},
+		Modifier: stash.CriterionModifierIncludes,
+		Depth:    -1,
+	}, scenes.sceneFilter.Studios)
+
+	_, cmd = m.Update(StudiosModelGalleriesMsg{})
+	open = cmd().(ModelTabOpenMsg)
+	require.Equal(t, "galleries", open.Name)
+}
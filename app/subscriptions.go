@@ -0,0 +1,45 @@
+package app
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/drakenstar/stash-cli/stash"
+)
+
+// Subscriber is a source of live events from stash.
+type Subscriber interface {
+	Jobs(func(stash.JobStatusUpdate)) error
+	ScanComplete(func()) error
+	Logs(func([]stash.LogEntry)) error
+}
+
+// Subscribe registers handlers with s that deliver stash events to the application through send, which is typically
+// the Send method of the tea.Program running the application.
+func Subscribe(s Subscriber, send func(tea.Msg)) error {
+	err := s.Jobs(func(update stash.JobStatusUpdate) {
+		send(jobUpdatedMsg{update})
+	})
+	if err != nil {
+		return err
+	}
+	err = s.ScanComplete(func() {
+		send(scanCompletedMsg{})
+	})
+	if err != nil {
+		return err
+	}
+	return s.Logs(func(entries []stash.LogEntry) {
+		send(logMsg{entries})
+	})
+}
+
+// jobUpdatedMsg is received when a job is added to the job queue, changes, or leaves the queue.
+type jobUpdatedMsg struct {
+	update stash.JobStatusUpdate
+}
+
+// scanCompletedMsg is received when a scan finishes, at which point open tabs may be showing stale content.
+type scanCompletedMsg struct{}
+
+type logMsg struct {
+	entries []stash.LogEntry
+}
@@ -0,0 +1,77 @@
+package app
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/drakenstar/stash-cli/stash"
+	"github.com/stretchr/testify/require"
+)
+
+type testSubscriber struct {
+	jobs         func(stash.JobStatusUpdate)
+	scanComplete func()
+	logs         func([]stash.LogEntry)
+}
+
+func (s *testSubscriber) Jobs(handler func(stash.JobStatusUpdate)) error {
+	s.jobs = handler
+	return nil
+}
+
+func (s *testSubscriber) ScanComplete(handler func()) error {
+	s.scanComplete = handler
+	return nil
+}
+
+func (s *testSubscriber) Logs(handler func([]stash.LogEntry)) error {
+	s.logs = handler
+	return nil
+}
+
+func TestSubscribe(t *testing.T) {
+	var msgs []tea.Msg
+	sub := &testSubscriber{}
+	require.NoError(t, Subscribe(sub, func(msg tea.Msg) { msgs = append(msgs, msg) }))
+
+	update := stash.JobStatusUpdate{Type: stash.JobStatusUpdateAdd, Job: stash.Job{ID: "1"}}
+	sub.jobs(update)
+	sub.scanComplete()
+	sub.logs([]stash.LogEntry{{Level: stash.LogLevelInfo, Message: "ok"}})
+	require.Equal(t, []tea.Msg{
+		jobUpdatedMsg{update},
+		scanCompletedMsg{},
+		logMsg{[]stash.LogEntry{{Level: stash.LogLevelInfo, Message: "ok"}}},
+	}, msgs)
+}
+
+func TestModelSubscriptionEvents(t *testing.T) {
+	var model tea.Model = New(&stash.LocalStash{}, nil)
+	model, _ = model.Update(taskStartedMsg{task: taskScan, jobID: "1"})
+
+	model, _ = model.Update(jobUpdatedMsg{stash.JobStatusUpdate{
+		Type: stash.JobStatusUpdateUpdate,
+		Job:  stash.Job{ID: "1", Status: stash.JobStatusRunning, Progress: ptr(0.5)},
+	}})
+	model, _ = model.Update(jobUpdatedMsg{stash.JobStatusUpdate{
+		Type: stash.JobStatusUpdateAdd,
+		Job:  stash.Job{ID: "2", Status: stash.JobStatusReady},
+	}})
+	m := model.(Model)
+	jobs := m.tabs[1].model.(*JobsModel).jobs
+	require.Len(t, jobs, 2)
+	require.Equal(t, "50%", jobProgress(jobs[0]))
+
+	// Each open tab is refreshed.
+	_, cmd := m.Update(scanCompletedMsg{})
+	require.NotNil(t, cmd)
+
+	_, cmd = m.Update(logMsg{[]stash.LogEntry{
+		{Level: stash.LogLevelError, Message: "scan failed"},
+		{Level: stash.LogLevelInfo, Message: "finished"},
+	}})
+	require.Equal(t, "scan failed", cmd().(ErrorMsg).Error())
+
+	_, cmd = m.Update(logMsg{[]stash.LogEntry{{Level: stash.LogLevelInfo, Message: "finished"}}})
+	require.Nil(t, cmd)
+}
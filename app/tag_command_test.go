@@ -6,12 +6,18 @@ import (
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/drakenstar/stash-cli/command"
 	"github.com/drakenstar/stash-cli/stash"
 	"github.com/stretchr/testify/require"
 )
 
 type sceneTagCommandTestService struct {
-	tags []string
+	savedFilterTestService
+	scrapeTestService
+	editTestService
+	performerEditTestService
+	tags   []string
+	retags []string
 }
 
 func (s *sceneTagCommandTestService) Scenes(stash.FindFilter, stash.SceneFilter) tea.Cmd { return nil }
@@ -19,6 +25,22 @@ func (s *sceneTagCommandTestService) DeleteScene(string) tea.Cmd
 func (s *sceneTagCommandTestService) ResolveTags([]string) tea.Cmd                       { return nil }
 func (s *sceneTagCommandTestService) ResolveStudios([]string) tea.Cmd                    { return nil }
 func (s *sceneTagCommandTestService) ResolvePerformers([]string) tea.Cmd                 { return nil }
+func (s *sceneTagCommandTestService) StartTask(taskRequest) tea.Cmd                      { return nil }
+func (s *sceneTagCommandTestService) RecordPlay(stash.Scene) tea.Cmd                     { return nil }
+func (s *sceneTagCommandTestService) OCounterScene(stash.Scene, oCounterChange) tea.Cmd  { return nil }
+func (s *sceneTagCommandTestService) UpdateScene(stash.Scene, stash.Scene) tea.Cmd       { return nil }
+func (s *sceneTagCommandTestService) StudioScene(stash.Scene, string) tea.Cmd            { return nil }
+func (s *sceneTagCommandTestService) SaveSceneActivity(stash.Scene, *float64, *float64) tea.Cmd {
+	return nil
+}
+func (s *sceneTagCommandTestService) TagScenes([]string, []string) tea.Cmd       { return nil }
+func (s *sceneTagCommandTestService) RetagScenes([]string, []string) tea.Cmd     { return nil }
+func (s *sceneTagCommandTestService) UpdateScenes(stash.BulkSceneUpdate) tea.Cmd { return nil }
+func (s *sceneTagCommandTestService) DeleteScenes([]string) tea.Cmd              { return nil }
+func (s *sceneTagCommandTestService) RetagScene(_ stash.Scene, tags []string) tea.Cmd {
+	s.retags = append([]string(nil), tags...)
+	return nil
+}
 func (s *sceneTagCommandTestService) TagScene(scene stash.Scene, tags []string) tea.Cmd {
 	s.tags = append([]string(nil), tags...)
 	updated := scene
@@ -27,16 +49,29 @@ func (s *sceneTagCommandTestService) TagScene(scene stash.Scene, tags []string)
 }
 
 type galleryTagCommandTestService struct {
-	tags []string
+	savedFilterTestService
+	scrapeTestService
+	editTestService
+	performerEditTestService
+	tags   []string
+	retags []string
 }
 
 func (s *galleryTagCommandTestService) Galleries(stash.FindFilter, stash.GalleryFilter) tea.Cmd {
 	return nil
 }
-func (s *galleryTagCommandTestService) DeleteGallery(string) tea.Cmd       { return nil }
-func (s *galleryTagCommandTestService) ResolveTags([]string) tea.Cmd       { return nil }
-func (s *galleryTagCommandTestService) ResolveStudios([]string) tea.Cmd    { return nil }
-func (s *galleryTagCommandTestService) ResolvePerformers([]string) tea.Cmd { return nil }
+func (s *galleryTagCommandTestService) DeleteGallery(string) tea.Cmd                    { return nil }
+func (s *galleryTagCommandTestService) ResolveTags([]string) tea.Cmd                    { return nil }
+func (s *galleryTagCommandTestService) ResolveStudios([]string) tea.Cmd                 { return nil }
+func (s *galleryTagCommandTestService) ResolvePerformers([]string) tea.Cmd              { return nil }
+func (s *galleryTagCommandTestService) TagGalleries([]string, []string) tea.Cmd         { return nil }
+func (s *galleryTagCommandTestService) RetagGalleries([]string, []string) tea.Cmd       { return nil }
+func (s *galleryTagCommandTestService) UpdateGalleries(stash.BulkGalleryUpdate) tea.Cmd { return nil }
+func (s *galleryTagCommandTestService) DeleteGalleries([]string) tea.Cmd                { return nil }
+func (s *galleryTagCommandTestService) RetagGallery(_ stash.Gallery, tags []string) tea.Cmd {
+	s.retags = append([]string(nil), tags...)
+	return nil
+}
 func (s *galleryTagCommandTestService) TagGallery(gallery stash.Gallery, tags []string) tea.Cmd {
 	s.tags = append([]string(nil), tags...)
 	updated := gallery
@@ -72,6 +107,47 @@ func TestGalleriesModelTagCommand(t *testing.T) {
 	require.Equal(t, []stash.Tag{{ID: "2", Name: "Foo Bar"}}, m.galleries[0].Tags)
 }
 
+func TestScenesModelUntagAndRetagCommands(t *testing.T) {
+	srv := &sceneTagCommandTestService{}
+	m := NewScenesModel(srv, tagResolveTestLookup{})
+	m.scenes = []stash.Scene{{ID: "scene-1"}}
+
+	for _, input := range []string{`untag "Foo Bar" baz`, `retag qux`} {
+		msg, err := ScenesModelCommandConfig.Resolve(command.Parser(input))
+		require.NoError(t, err)
+		m.Update(msg)
+	}
+	require.Equal(t, []string{"-Foo Bar", "-baz"}, srv.tags)
+	require.Equal(t, []string{"qux"}, srv.retags)
+
+	// A retag without tags would remove every tag, so it's refused rather than taken as a request to.
+	msg, err := ScenesModelCommandConfig.Resolve(command.Parser(`retag`))
+	require.NoError(t, err)
+	_, cmd := m.Update(msg)
+	require.EqualError(t, cmd().(ErrorMsg), "no tags specified")
+	require.Equal(t, []string{"qux"}, srv.retags)
+}
+
+func TestGalleriesModelUntagAndRetagCommands(t *testing.T) {
+	srv := &galleryTagCommandTestService{}
+	m := NewGalleriesModel(srv, tagResolveTestLookup{})
+	m.galleries = []stash.Gallery{{ID: "gallery-1"}}
+
+	for _, input := range []string{`untag baz`, `retag qux`} {
+		msg, err := GalleriesModelCommandConfig.Resolve(command.Parser(input))
+		require.NoError(t, err)
+		m.Update(msg)
+	}
+	require.Equal(t, []string{"-baz"}, srv.tags)
+	require.Equal(t, []string{"qux"}, srv.retags)
+
+	msg, err := GalleriesModelCommandConfig.Resolve(command.Parser(`retag`))
+	require.NoError(t, err)
+	_, cmd := m.Update(msg)
+	require.EqualError(t, cmd().(ErrorMsg), "no tags specified")
+	require.Equal(t, []string{"qux"}, srv.retags)
+}
+
 type tagCreateTestStash struct {
 	stash.Stash
 	created []string
@@ -100,6 +176,24 @@ func TestResolveOrCreateTagsCreatesMissingTags(t *testing.T) {
 	require.Equal(t, []string{"new"}, backend.created)
 }
 
+func TestApplyTagChanges(t *testing.T) {
+	backend := &tagCreateTestStash{}
+	svc := &cmdService{Stash: backend, cache: newCacheLookup()}
+	existing := []stash.Tag{{ID: "1", Name: "existing"}, {ID: "3", Name: "Other"}}
+
+	tags, err := svc.applyTagChanges(context.Background(), existing, []string{"-other", "new"}, false)
+	require.NoError(t, err)
+	require.Equal(t, []stash.Tag{{ID: "1", Name: "existing"}, {ID: "2", Name: "new"}}, tags)
+
+	tags, err = svc.applyTagChanges(context.Background(), existing, []string{"existing"}, true)
+	require.NoError(t, err)
+	require.Equal(t, []stash.Tag{{ID: "1", Name: "existing"}}, tags)
+
+	tags, err = svc.applyTagChanges(context.Background(), existing, nil, true)
+	require.NoError(t, err)
+	require.Empty(t, tags)
+}
+
 func TestLoadingErrorMsgIsShownGlobally(t *testing.T) {
 	m := New(&stash.LocalStash{}, nil)
 	err := errors.New("boom")
@@ -109,3 +203,22 @@ func TestLoadingErrorMsgIsShownGlobally(t *testing.T) {
 
 	require.Equal(t, err, updated.err)
 }
+
+func TestTagSceneCreatesTagsInStash(t *testing.T) {
+	backend, err := stash.LoadMemoryStash([]byte(`{
+		"tags": [{"id": "1", "name": "existing"}],
+		"scenes": [{"id": "2", "title": "Scene", "tags": [{"id": "1"}]}]
+	}`))
+	require.NoError(t, err)
+	svc := &cmdService{Stash: backend, cache: newCacheLookup()}
+	scenes, _, err := backend.Scenes(context.Background(), stash.FindFilter{}, stash.SceneFilter{})
+	require.NoError(t, err)
+
+	msg := svc.TagScene(scenes[0], []string{"-existing", "new"})()
+
+	require.IsType(t, sceneTaggedMsg{}, msg)
+	require.Equal(t, []stash.Tag{{ID: "3", Name: "new"}}, msg.(sceneTaggedMsg).scene.Tags)
+	tag, err := backend.TagFindByName(context.Background(), "new")
+	require.NoError(t, err)
+	require.Equal(t, stash.Tag{ID: "3", Name: "new"}, tag)
+}
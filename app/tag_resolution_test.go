@@ -8,11 +8,28 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-type sceneTagResolveTestService struct{}
+type sceneTagResolveTestService struct {
+	savedFilterTestService
+	scrapeTestService
+	editTestService
+	performerEditTestService
+}
 
 func (sceneTagResolveTestService) Scenes(stash.FindFilter, stash.SceneFilter) tea.Cmd { return nil }
 func (sceneTagResolveTestService) DeleteScene(string) tea.Cmd                         { return nil }
 func (sceneTagResolveTestService) TagScene(stash.Scene, []string) tea.Cmd             { return nil }
+func (sceneTagResolveTestService) TagScenes([]string, []string) tea.Cmd               { return nil }
+func (sceneTagResolveTestService) RetagScenes([]string, []string) tea.Cmd             { return nil }
+func (sceneTagResolveTestService) UpdateScenes(stash.BulkSceneUpdate) tea.Cmd         { return nil }
+func (sceneTagResolveTestService) DeleteScenes([]string) tea.Cmd                      { return nil }
+func (sceneTagResolveTestService) RetagScene(stash.Scene, []string) tea.Cmd           { return nil }
+func (sceneTagResolveTestService) RecordPlay(stash.Scene) tea.Cmd                     { return nil }
+func (sceneTagResolveTestService) OCounterScene(stash.Scene, oCounterChange) tea.Cmd  { return nil }
+func (sceneTagResolveTestService) UpdateScene(stash.Scene, stash.Scene) tea.Cmd       { return nil }
+func (sceneTagResolveTestService) StudioScene(stash.Scene, string) tea.Cmd            { return nil }
+func (sceneTagResolveTestService) SaveSceneActivity(stash.Scene, *float64, *float64) tea.Cmd {
+	return nil
+}
 func (sceneTagResolveTestService) ResolveTags([]string) tea.Cmd {
 	return func() tea.Msg {
 		return loadingMsg{
@@ -23,13 +40,24 @@ func (sceneTagResolveTestService) ResolveTags([]string) tea.Cmd {
 }
 func (sceneTagResolveTestService) ResolveStudios([]string) tea.Cmd    { return nil }
 func (sceneTagResolveTestService) ResolvePerformers([]string) tea.Cmd { return nil }
+func (sceneTagResolveTestService) StartTask(taskRequest) tea.Cmd      { return nil }
 
-type galleryTagResolveTestService struct{}
+type galleryTagResolveTestService struct {
+	savedFilterTestService
+	scrapeTestService
+	editTestService
+	performerEditTestService
+}
 
 func (galleryTagResolveTestService) Galleries(stash.FindFilter, stash.GalleryFilter) tea.Cmd {
 	return nil
 }
-func (galleryTagResolveTestService) DeleteGallery(string) tea.Cmd { return nil }
+func (galleryTagResolveTestService) DeleteGallery(string) tea.Cmd                    { return nil }
+func (galleryTagResolveTestService) TagGalleries([]string, []string) tea.Cmd         { return nil }
+func (galleryTagResolveTestService) RetagGalleries([]string, []string) tea.Cmd       { return nil }
+func (galleryTagResolveTestService) UpdateGalleries(stash.BulkGalleryUpdate) tea.Cmd { return nil }
+func (galleryTagResolveTestService) DeleteGalleries([]string) tea.Cmd                { return nil }
+func (galleryTagResolveTestService) RetagGallery(stash.Gallery, []string) tea.Cmd    { return nil }
 func (galleryTagResolveTestService) TagGallery(stash.Gallery, []string) tea.Cmd {
 	return nil
 }
@@ -51,6 +79,7 @@ func (tagResolveTestLookup) GetTag(string) (stash.Tag, error)       { return sta
 func (tagResolveTestLookup) GetPerformer(string) (stash.Performer, error) {
 	return stash.Performer{}, nil
 }
+func (tagResolveTestLookup) GetMovie(string) (stash.Movie, error) { return stash.Movie{}, nil }
 
 func TestResolveSceneTagsCmdWrapsLoadingPayload(t *testing.T) {
 	m := NewScenesModel(sceneTagResolveTestService{}, tagResolveTestLookup{})
@@ -0,0 +1,632 @@
+package app
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/drakenstar/stash-cli/command"
+	"github.com/drakenstar/stash-cli/stash"
+	"github.com/drakenstar/stash-cli/ui"
+	"github.com/hasura/go-graphql-client"
+)
+
+type tagFilterState struct {
+	query         string
+	sort          string
+	sortDirection string
+	tagFilter     stash.TagFilter
+
+	pageState pageState
+}
+
+type TagService interface {
+	Tags(stash.FindFilter, stash.TagFilter) tea.Cmd
+	CreateTag(string) tea.Cmd
+	UpdateTag(stash.TagUpdate) tea.Cmd
+	SetTagParents(string, []string) tea.Cmd
+	MergeTags([]string, string) tea.Cmd
+	DeleteTag(string) tea.Cmd
+}
+
+// TagsModel lists tags as a tree and provides commands to tidy them up.  Without a search query or filter only top
+// level tags are fetched, and child tags are loaded as they are expanded.  Otherwise all matching tags are listed
+// regardless of their parents.  As a tag may have many parents, the same tag can appear in more than one row.
+type TagsModel struct {
+	TagService
+	StashLookup
+
+	treeList[stash.TagDetail]
+
+	query         string
+	sort          string
+	sortDirection string
+	tagFilter     stash.TagFilter
+
+	history []tagFilterState
+
+	screen Size
+
+	listRequestID uint64
+}
+
+func NewTagsModel(tagService TagService, lookup StashLookup) *TagsModel {
+	m := &TagsModel{
+		TagService:  tagService,
+		StashLookup: lookup,
+	}
+	m.pageState.PerPage = 40
+	m.reset()
+	return m
+}
+
+func (m *TagsModel) reset() tea.Cmd {
+	m.query = ""
+	m.sort = stash.SortName
+	m.sortDirection = stash.SortDirectionAsc
+	m.tagFilter = stash.TagFilter{}
+	m.pageState.Reset()
+
+	return m.updateCmd()
+}
+
+func (m *TagsModel) SetSize(s Size) tea.Cmd {
+	m.screen = s
+	m.pageState.SetPerPage(s.Height - 1) // account for status line
+	return m.updateCmd()
+}
+
+func (m *TagsModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *TagsModel) Title() string {
+	t := "Tags"
+	if m.query != "" {
+		t = fmt.Sprintf("\"%s\"", m.query)
+	}
+	return fmt.Sprintf("%c %s (%s)", '\U000f04f9', t, humanNumber(m.pageState.total))
+}
+
+func (m *TagsModel) Current() stash.TagDetail {
+	return m.rows[m.cursor].item
+}
+
+func (m *TagsModel) PushState(mutate func(*TagsModel)) (*TagsModel, tea.Cmd) {
+	m.history = append(m.history, tagFilterState{
+		query:         m.query,
+		sort:          m.sort,
+		sortDirection: m.sortDirection,
+		tagFilter:     m.tagFilter,
+		pageState:     m.pageState,
+	})
+	mutate(m)
+	m.pageState.Reset()
+	return m, m.updateCmd()
+}
+
+// Pop sets the current state to the previous state from the history stack.  If the history stack is empty this is a
+// noop.
+func (m *TagsModel) Pop() (*TagsModel, tea.Cmd) {
+	if len(m.history) == 0 {
+		return m, nil
+	}
+
+	state := m.history[len(m.history)-1]
+	m.history = m.history[0 : len(m.history)-1]
+
+	m.pageState = state.pageState
+	m.query = state.query
+	m.sort = state.sort
+	m.sortDirection = state.sortDirection
+	m.tagFilter = state.tagFilter
+	m.rows = []treeRow[stash.TagDetail]{}
+
+	return m, m.updateCmd()
+}
+
+var TagsModelDefaultKeymap = map[string]string{
+	"up":    "skip -1",
+	"down":  "skip 1",
+	"right": "expand",
+	"left":  "collapse",
+	"D":     "delete",
+	"enter": "scenes",
+	"z":     "skip -1",
+	"x":     "skip 1",
+	"o":     "scenes",
+	"G":     "galleries",
+	"p":     "performers",
+	"u":     "undo",
+	"`":     "open-url",
+}
+
+var TagsModelCommandConfig command.Config = command.Config{
+	"alias":      binder[TagsModelAliasMsg](),
+	"collapse":   binder[TagsModelCollapseMsg](),
+	"create":     binder[TagsModelCreateMsg](),
+	"delete":     binder[TagsModelDeleteMsg](),
+	"expand":     binder[TagsModelExpandMsg](),
+	"filter":     binder[TagsModelFilterMsg](),
+	"galleries":  binder[TagsModelGalleriesMsg](),
+	"merge":      binder[TagsModelMergeMsg](),
+	"open-url":   binder[TagsModelOpenURLMsg](),
+	"parent":     binder[TagsModelParentMsg](),
+	"performers": binder[TagsModelPerformersMsg](),
+	"refresh":    binder[TagsModelRefresh](),
+	"rename":     binder[TagsModelRenameMsg](),
+	"reset":      binder[TagsModelResetMsg](),
+	"scenes":     binder[TagsModelScenesMsg](),
+	"sort":       binder[TagsModelSortMsg](),
+	"skip":       binder[TagsModelSkipMsg](),
+	"undo":       binder[TagsModelUndoMsg](),
+}
+
+var tagSortFields = sortFields{
+	"name":       stash.SortName,
+	"scenes":     stash.SortScenesCount,
+	"galleries":  stash.SortGalleriesCount,
+	"performers": stash.SortPerformersCount,
+	"created":    stash.SortCreatedAt,
+	"updated":    stash.SortUpdatedAt,
+}
+
+func (m TagsModel) CommandConfig() command.Config {
+	return TagsModelCommandConfig
+}
+
+func (m TagsModel) Search(query string) tea.Msg {
+	return TagsModelFilterMsg{
+		Query: &query,
+	}
+}
+
+// TagsModelFilterMsg controls the filtering of tags.  Scenes filters to tags on more than the given number of scenes.
+// Empty filters to tags that are not used on any content, which are typically left behind by typos.
+type TagsModelFilterMsg struct {
+	Query  *string
+	Scenes *int
+	Empty  *bool
+}
+
+// TagsModelExpandMsg loads and shows the child tags of the current tag.
+type TagsModelExpandMsg struct{}
+
+// TagsModelCollapseMsg hides the child tags of the current tag.  If the current tag is not expanded then its parent is
+// collapsed instead.
+type TagsModelCollapseMsg struct{}
+
+type TagsModelCreateMsg struct {
+	Name string `command:",positional"`
+}
+
+type TagsModelRenameMsg struct {
+	Name string `command:",positional"`
+}
+
+// TagsModelAliasMsg adds an alias to the current tag.  An alias prefixed with '-' is removed instead.
+type TagsModelAliasMsg struct {
+	Aliases []string `command:",positional"`
+}
+
+// TagsModelParentMsg replaces the parents of the current tag.  Without any parents the tag becomes a top level tag.
+type TagsModelParentMsg struct {
+	Parents []string `command:",positional"`
+}
+
+// TagsModelMergeMsg merges the current tag into the given tag, which keeps the current tag's name as an alias.
+type TagsModelMergeMsg struct {
+	Into string `command:",positional"`
+}
+
+type TagsModelDeleteMsg struct {
+	Confirm bool
+}
+
+// TagsModelScenesMsg opens a new scenes tab filtered to the current tag.
+type TagsModelScenesMsg struct{}
+
+// TagsModelGalleriesMsg opens a new galleries tab filtered to the current tag.
+type TagsModelGalleriesMsg struct{}
+
+// TagsModelPerformersMsg opens a new performers tab filtered to the current tag.
+type TagsModelPerformersMsg struct{}
+
+type TagsModelOpenURLMsg struct{}
+
+type TagsModelRefresh struct{}
+
+type TagsModelResetMsg struct{}
+
+type TagsModelSkipMsg struct {
+	Count int `command:",positional"`
+}
+
+type TagsModelSortMsg struct {
+	Field string `command:",positional"`
+}
+
+type TagsModelUndoMsg struct{}
+
+func (m *TagsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case TagsModelFilterMsg:
+		return m.PushState(func(tm *TagsModel) {
+			if msg.Query != nil {
+				tm.query = *msg.Query
+			}
+			if msg.Scenes != nil {
+				tm.tagFilter.SceneCount = &stash.IntCriterion{
+					Value:    *msg.Scenes,
+					Modifier: stash.CriterionModifierGreaterThan,
+				}
+			}
+			if msg.Empty != nil {
+				tm.setEmptyFilter(*msg.Empty)
+			}
+		})
+
+	case TagsModelExpandMsg:
+		if len(m.rows) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no tag selected"))
+		}
+		row := &m.rows[m.cursor]
+		if row.expanded || len(row.item.Children) == 0 {
+			return m, nil
+		}
+		row.expanded = true
+		return m, m.childrenCmd(row.item.ID)
+
+	case TagsModelCollapseMsg:
+		m.collapseCurrent()
+
+	case tagChildrenLoadedMsg:
+		m.insertChildren(func(t stash.TagDetail) bool { return t.ID == msg.parentID }, msg.tags)
+
+	case TagsModelCreateMsg:
+		if msg.Name == "" {
+			return m, NewErrorCmd(fmt.Errorf("no tag name specified"))
+		}
+		return m, m.TagService.CreateTag(msg.Name)
+
+	case TagsModelRenameMsg:
+		if len(m.rows) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no tag selected"))
+		}
+		if msg.Name == "" {
+			return m, NewErrorCmd(fmt.Errorf("no tag name specified"))
+		}
+		return m, m.TagService.UpdateTag(stash.TagUpdate{
+			ID:   graphql.ID(m.Current().ID),
+			Name: &msg.Name,
+		})
+
+	case TagsModelAliasMsg:
+		if len(m.rows) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no tag selected"))
+		}
+		if len(msg.Aliases) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no aliases specified"))
+		}
+		aliases := applyAliasChanges(m.Current().Aliases, msg.Aliases)
+		return m, m.TagService.UpdateTag(stash.TagUpdate{
+			ID:      graphql.ID(m.Current().ID),
+			Aliases: &aliases,
+		})
+
+	case TagsModelParentMsg:
+		if len(m.rows) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no tag selected"))
+		}
+		return m, m.TagService.SetTagParents(m.Current().ID, msg.Parents)
+
+	case TagsModelMergeMsg:
+		if len(m.rows) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no tag selected"))
+		}
+		if msg.Into == "" {
+			return m, NewErrorCmd(fmt.Errorf("no tag to merge into specified"))
+		}
+		return m, m.TagService.MergeTags([]string{m.Current().ID}, msg.Into)
+
+	case TagsModelDeleteMsg:
+		if len(m.rows) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no tag selected"))
+		}
+		tag := m.Current()
+		return m, func() tea.Msg {
+			return deleteRequestMsg{
+				Entity:      "tag",
+				Title:       tag.Name,
+				Path:        tagUsage(tag),
+				Detail:      "This will remove the tag from Stash and from all content tagged with it.",
+				SkipConfirm: msg.Confirm,
+				DeleteCmd:   m.TagService.DeleteTag(tag.ID),
+			}
+		}
+
+	case tagCreatedMsg:
+		return m, m.updateCmd()
+
+	case tagUpdatedMsg:
+		for i := range m.rows {
+			if m.rows[i].item.ID == msg.tag.ID {
+				m.rows[i].item = msg.tag
+			}
+		}
+
+	case tagParentsUpdatedMsg, tagsMergedMsg, tagDeletedMsg:
+		return m, m.updateCmd()
+
+	case TagsModelScenesMsg:
+		if len(m.rows) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no tag selected"))
+		}
+		filter := stash.SceneFilter{
+			Tags: m.tagCriterion(),
+		}
+		return m, func() tea.Msg { return openScenesTabMsg(filter) }
+
+	case TagsModelGalleriesMsg:
+		if len(m.rows) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no tag selected"))
+		}
+		filter := stash.GalleryFilter{
+			Tags: m.tagCriterion(),
+		}
+		return m, func() tea.Msg { return openGalleriesTabMsg(filter) }
+
+	case TagsModelPerformersMsg:
+		if len(m.rows) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no tag selected"))
+		}
+		filter := stash.PerformerFilter{
+			Tags: m.tagCriterion(),
+		}
+		return m, func() tea.Msg { return openPerformersTabMsg(filter) }
+
+	case TagsModelOpenURLMsg:
+		if len(m.rows) == 0 {
+			return m, NewErrorCmd(fmt.Errorf("no tag selected"))
+		}
+		src := path.Join("tags", m.Current().ID)
+		return m, func() tea.Msg { return OpenMsg{src} }
+
+	case TagsModelRefresh:
+		return m, m.updateCmd()
+
+	case TagsModelResetMsg:
+		return m, m.reset()
+
+	case TagsModelSortMsg:
+		sort, direction, err := tagSortFields.parse(msg.Field)
+		if err != nil {
+			return m, NewErrorCmd(err)
+		}
+		return m.PushState(func(tm *TagsModel) {
+			tm.sort = sort
+			tm.sortDirection = direction
+		})
+
+	case TagsModelSkipMsg:
+		if m.skip(msg.Count) {
+			return m, m.updateCmd()
+		}
+
+	case TagsModelUndoMsg:
+		return m.Pop()
+
+	case tea.KeyMsg:
+		if cmd, ok := TagsModelDefaultKeymap[msg.String()]; ok {
+			return m, func() tea.Msg { return ui.CommandExecMsg{Command: cmd} }
+		}
+
+	case tagsListLoadedMsg:
+		if msg.requestID != m.listRequestID {
+			return m, nil
+		}
+		m.setRows(msg.tags, msg.total)
+	}
+
+	return m, nil
+}
+
+// setEmptyFilter filters to tags without any content when empty is true, and clears that filter otherwise.
+func (m *TagsModel) setEmptyFilter(empty bool) {
+	var none *stash.IntCriterion
+	if empty {
+		none = &stash.IntCriterion{Value: 0, Modifier: stash.CriterionModifierEquals}
+	}
+	m.tagFilter.SceneCount = none
+	m.tagFilter.ImageCount = none
+	m.tagFilter.GalleryCount = none
+	m.tagFilter.PerformerCount = none
+	m.tagFilter.MarkerCount = none
+}
+
+// tagCriterion returns a criterion matching the current tag only, consistent with the counts shown.
+func (m *TagsModel) tagCriterion() *stash.HierarchicalMultiCriterion {
+	return &stash.HierarchicalMultiCriterion{
+		Value:    []string{m.Current().ID},
+		Modifier: stash.CriterionModifierIncludes,
+	}
+}
+
+// applyAliasChanges adds each alias to aliases, or removes it when prefixed with '-'.
+func applyAliasChanges(aliases []string, changes []string) []string {
+	updated := append([]string{}, aliases...)
+	for _, change := range changes {
+		if remove, ok := strings.CutPrefix(change, "-"); ok {
+			updated = slices.DeleteFunc(updated, func(a string) bool { return strings.EqualFold(a, remove) })
+			continue
+		}
+		if !slices.ContainsFunc(updated, func(a string) bool { return strings.EqualFold(a, change) }) {
+			updated = append(updated, change)
+		}
+	}
+	return updated
+}
+
+func (m *TagsModel) childrenCmd(parentID string) tea.Cmd {
+	cmd := m.TagService.Tags(stash.FindFilter{
+		PerPage:   -1,
+		Sort:      stash.SortName,
+		Direction: stash.SortDirectionAsc,
+	}, stash.TagFilter{
+		Parents: &stash.HierarchicalMultiCriterion{
+			Value:    []string{parentID},
+			Modifier: stash.CriterionModifierIncludes,
+		},
+	})
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		return wrapTagChildrenLoadedMsg(cmd(), parentID)
+	}
+}
+
+func (m TagsModel) View() string {
+	end := len(m.rows)
+	if m.pageState.PerPage > 0 {
+		end = min(m.offset+m.pageState.PerPage, len(m.rows))
+	}
+
+	var rows []ui.Row
+	for i := m.offset; i < end; i++ {
+		row := m.rows[i]
+		rows = append(rows, ui.Row{
+			Values: []string{
+				treeName(row.item.Name, row.depth, len(row.item.Children) > 0, row.expanded),
+				strings.Join(row.item.Aliases, ", "),
+				tagList(row.item.Parents),
+				strconv.Itoa(row.item.SceneCount),
+				strconv.Itoa(row.item.GalleryCount),
+				strconv.Itoa(row.item.PerformerCount),
+			},
+		})
+		if m.cursor == i {
+			rows[len(rows)-1].Background = &ColorRowSelected
+		}
+	}
+
+	leftStatus := []string{
+		m.pageState.String(),
+		sort(m.sort, m.sortDirection),
+	}
+
+	rightStatus := tagFilterStatus(m.tagFilter, m.StashLookup)
+	if m.query != "" {
+		rightStatus = append(rightStatus, "\""+m.query+"\"")
+	}
+	if len(m.history) > 0 {
+		rightStatus = append(rightStatus, fmt.Sprintf("[%d]", len(m.history)))
+	}
+
+	return lipgloss.JoinVertical(0,
+		statusBar.Render(m.screen.Width, leftStatus, rightStatus),
+		tagsTable.Render(m.screen.Width, rows),
+	)
+}
+
+// updateCmd requests the current page of tags.  When there is no search query or filter only top level tags are
+// requested, as their children are shown by expanding them.
+func (m *TagsModel) updateCmd() tea.Cmd {
+	if m.pageState.PerPage == 0 {
+		return nil
+	}
+	filter := m.tagFilter
+	if m.query == "" && filter == (stash.TagFilter{}) {
+		filter.ParentCount = &stash.IntCriterion{
+			Value:    0,
+			Modifier: stash.CriterionModifierEquals,
+		}
+	}
+	requestID := atomic.AddUint64(&m.listRequestID, 1)
+	cmd := m.TagService.Tags(stash.FindFilter{
+		Query:     m.query,
+		Page:      m.pageState.page + 1,
+		PerPage:   m.pageState.PerPage,
+		Sort:      m.sort,
+		Direction: m.sortDirection,
+	}, filter)
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		return wrapTagsLoadedMsg(cmd(), requestID)
+	}
+}
+
+func wrapTagsLoadedMsg(msg tea.Msg, requestID uint64) tea.Msg {
+	switch msg := msg.(type) {
+	case tagsMsg:
+		return tagsListLoadedMsg{requestID: requestID, tags: msg.tags, total: msg.total}
+	case loadingMsg:
+		if payload, ok := msg.payload.(tagsMsg); ok {
+			msg.payload = tagsListLoadedMsg{requestID: requestID, tags: payload.tags, total: payload.total}
+		}
+		return msg
+	default:
+		return msg
+	}
+}
+
+func wrapTagChildrenLoadedMsg(msg tea.Msg, parentID string) tea.Msg {
+	switch msg := msg.(type) {
+	case tagsMsg:
+		return tagChildrenLoadedMsg{parentID: parentID, tags: msg.tags}
+	case loadingMsg:
+		if payload, ok := msg.payload.(tagsMsg); ok {
+			msg.payload = tagChildrenLoadedMsg{parentID: parentID, tags: payload.tags}
+		}
+		return msg
+	default:
+		return msg
+	}
+}
+
+var (
+	tagsTable = &ui.Table{
+		AltBackground: ColorBlack,
+		Cols: []ui.Column{
+			{
+				Name:       "Name",
+				Foreground: &ColorYellow,
+				Bold:       true,
+				Weight:     2,
+			},
+			{
+				Name:       "Aliases",
+				Foreground: &ColorGrey,
+				Weight:     1,
+			},
+			{
+				Name:       "Parents",
+				Foreground: &ColorPurple,
+				Weight:     1,
+			},
+			{
+				Name:       "Scenes",
+				Foreground: &ColorBlue,
+				Align:      lipgloss.Right,
+			},
+			{
+				Name:       "Galleries",
+				Foreground: &ColorBlue,
+				Align:      lipgloss.Right,
+			},
+			{
+				Name:       "Performers",
+				Foreground: &ColorBlue,
+				Align:      lipgloss.Right,
+				Flex:       true,
+			},
+		},
+	}
+)
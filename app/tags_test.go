@@ -0,0 +1,121 @@
+package app
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/drakenstar/stash-cli/stash"
+	"github.com/stretchr/testify/require"
+)
+
+type tagTestService struct {
+	filters []stash.TagFilter
+	updates []stash.TagUpdate
+	merged  []string
+	into    string
+	results map[string][]stash.TagDetail
+}
+
+func (s *tagTestService) Tags(_ stash.FindFilter, tf stash.TagFilter) tea.Cmd {
+	s.filters = append(s.filters, tf)
+	parent := ""
+	if tf.Parents != nil {
+		parent = tf.Parents.Value[0]
+	}
+	tags := s.results[parent]
+	return func() tea.Msg { return tagsMsg{tags: tags, total: len(tags)} }
+}
+
+func (s *tagTestService) CreateTag(string) tea.Cmd { return nil }
+
+func (s *tagTestService) UpdateTag(update stash.TagUpdate) tea.Cmd {
+	s.updates = append(s.updates, update)
+	return nil
+}
+
+func (s *tagTestService) SetTagParents(string, []string) tea.Cmd { return nil }
+
+func (s *tagTestService) MergeTags(source []string, into string) tea.Cmd {
+	s.merged, s.into = source, into
+	return nil
+}
+
+func (s *tagTestService) DeleteTag(string) tea.Cmd {
+	return func() tea.Msg { return tagDeletedMsg{} }
+}
+
+func newTagTestModel() (*TagsModel, *tagTestService) {
+	srv := &tagTestService{results: map[string][]stash.TagDetail{
+		"": {
+			{ID: "1", Name: "Colour", Children: []stash.Tag{{ID: "2"}}},
+			{ID: "3", Name: "Bonde", Aliases: []string{"Bond"}},
+		},
+		"1": {
+			{ID: "2", Name: "Red", Parents: []stash.Tag{{ID: "1", Name: "Colour"}}},
+		},
+	}}
+	m := NewTagsModel(srv, tagResolveTestLookup{})
+	cmd := m.SetSize(Size{Width: 80, Height: 20})
+	m.Update(cmd())
+	return m, srv
+}
+
+func TestTagsModelTree(t *testing.T) {
+	m, srv := newTagTestModel()
+	require.Equal(t, &stash.IntCriterion{Value: 0, Modifier: stash.CriterionModifierEquals}, srv.filters[len(srv.filters)-1].ParentCount)
+
+	_, cmd := m.Update(TagsModelExpandMsg{})
+	m.Update(cmd())
+	require.Len(t, m.rows, 3)
+	require.Equal(t, "Red", m.rows[1].item.Name)
+
+	// Filtering lists all matching tags rather than only top level tags.
+	_, cmd = m.Update(TagsModelFilterMsg{Empty: ptr(true)})
+	cmd()
+	filter := srv.filters[len(srv.filters)-1]
+	require.Nil(t, filter.ParentCount)
+	require.Equal(t, 0, filter.SceneCount.Value)
+	require.Equal(t, 0, filter.PerformerCount.Value)
+}
+
+func TestTagsModelManagement(t *testing.T) {
+	m, srv := newTagTestModel()
+	m.Update(TagsModelSkipMsg{Count: 1})
+	require.Equal(t, "Bonde", m.Current().Name)
+
+	m.Update(TagsModelRenameMsg{Name: "Blonde"})
+	require.Equal(t, "Blonde", *srv.updates[0].Name)
+
+	m.Update(TagsModelAliasMsg{Aliases: []string{"Blond", "-bond"}})
+	require.Equal(t, []string{"Blond"}, *srv.updates[1].Aliases)
+
+	m.Update(tagUpdatedMsg{tag: stash.TagDetail{ID: "3", Name: "Blonde", Aliases: []string{"Blond"}}})
+	require.Equal(t, "Blonde", m.Current().Name)
+
+	m.Update(TagsModelMergeMsg{Into: "Blonde Hair"})
+	require.Equal(t, []string{"3"}, srv.merged)
+	require.Equal(t, "Blonde Hair", srv.into)
+
+	_, cmd := m.Update(TagsModelDeleteMsg{})
+	request := cmd().(deleteRequestMsg)
+	require.Equal(t, "tag", request.Entity)
+	require.Equal(t, "Blonde", request.Title)
+	require.NotEmpty(t, request.Detail)
+}
+
+func TestCacheLookupTagChanges(t *testing.T) {
+	c := newCacheLookup()
+	c.CacheTag(stash.Tag{ID: "1", Name: "Bonde"})
+
+	c.CacheTag(stash.Tag{ID: "1", Name: "Blonde"})
+	_, err := c.GetTagByName("Bonde")
+	require.Error(t, err)
+	tag, err := c.GetTagByName("Blonde")
+	require.NoError(t, err)
+	require.Equal(t, "1", tag.ID)
+
+	c.UncacheTag("1")
+	_, err = c.GetTag("1")
+	require.Error(t, err)
+	require.Empty(t, c.TagsByPrefix("Blo", 6))
+}
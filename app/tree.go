@@ -0,0 +1,137 @@
+package app
+
+// treeRow is a single visible row of a tree.  Child rows follow their parent with a greater depth.
+type treeRow[T any] struct {
+	item     T
+	depth    int
+	expanded bool
+}
+
+// treeList holds the rows of a tab that lists items as a tree, where children are loaded as their parent is expanded.
+//
+// Paging applies to the fetched items only, so the rows of expanded children are navigated with a separate cursor and
+// scrolled within the page.
+type treeList[T any] struct {
+	pageState pageState
+	rows      []treeRow[T]
+	cursor    int
+	offset    int
+
+	// cursorToEnd places the cursor on the last row once the next page is loaded, used when skipping backwards
+	// across a page boundary.
+	cursorToEnd bool
+}
+
+// setRows replaces the rows with a newly loaded page of top level items.
+func (l *treeList[T]) setRows(items []T, total int) {
+	l.rows = make([]treeRow[T], 0, len(items))
+	for _, item := range items {
+		l.rows = append(l.rows, treeRow[T]{item: item})
+	}
+	l.pageState.total = total
+	l.cursor, l.offset = 0, 0
+	if l.cursorToEnd && len(l.rows) > 0 {
+		l.cursor = len(l.rows) - 1
+	}
+	l.cursorToEnd = false
+	l.scroll()
+}
+
+// skip moves the cursor by count rows.  Moving past either end of the loaded rows moves to the next or previous page,
+// in which case true is returned and the page must be loaded.
+func (l *treeList[T]) skip(count int) bool {
+	if len(l.rows) == 0 {
+		return false
+	}
+
+	cursor := l.cursor + count
+	if cursor >= 0 && cursor < len(l.rows) {
+		l.cursor = cursor
+		l.scroll()
+		return false
+	}
+
+	if cursor < 0 {
+		l.cursorToEnd = true
+		l.pageState.Skip(-l.pageState.PerPage)
+	} else {
+		l.pageState.Skip(l.pageState.PerPage)
+	}
+	return true
+}
+
+// scroll updates the offset of the first visible row so that the cursor remains on screen.
+func (l *treeList[T]) scroll() {
+	height := l.pageState.PerPage
+	if height <= 0 {
+		return
+	}
+	if l.cursor < l.offset {
+		l.offset = l.cursor
+	} else if l.cursor >= l.offset+height {
+		l.offset = l.cursor - height + 1
+	}
+	l.offset = clampInt(l.offset, 0, max(len(l.rows)-height, 0))
+}
+
+// parentRow returns the index of the row that is the parent of the row at i, or -1 for top level rows.
+func (l *treeList[T]) parentRow(i int) int {
+	for j := i - 1; j >= 0; j-- {
+		if l.rows[j].depth < l.rows[i].depth {
+			return j
+		}
+	}
+	return -1
+}
+
+// collapse removes all descendant rows of the row at i.
+func (l *treeList[T]) collapse(i int) {
+	end := i + 1
+	for end < len(l.rows) && l.rows[end].depth > l.rows[i].depth {
+		end++
+	}
+	l.rows = append(l.rows[:i+1], l.rows[end:]...)
+	l.rows[i].expanded = false
+}
+
+// collapseCurrent hides the children of the current row.  If the current row is not expanded then its parent is
+// collapsed instead, and the cursor moves to it.
+func (l *treeList[T]) collapseCurrent() {
+	if len(l.rows) == 0 {
+		return
+	}
+	if !l.rows[l.cursor].expanded {
+		parent := l.parentRow(l.cursor)
+		if parent < 0 {
+			return
+		}
+		l.cursor = parent
+	}
+	l.collapse(l.cursor)
+	l.scroll()
+}
+
+// insertChildren adds loaded children beneath each expanded row for which isParent is true.  Children are discarded
+// for rows that have since been collapsed or already show them, and if their parent is no longer visible.
+func (l *treeList[T]) insertChildren(isParent func(T) bool, children []T) {
+	for i := 0; i < len(l.rows); i++ {
+		row := l.rows[i]
+		if !isParent(row.item) || !row.expanded {
+			continue
+		}
+		if i+1 < len(l.rows) && l.rows[i+1].depth > row.depth {
+			continue
+		}
+
+		inserted := make([]treeRow[T], 0, len(children))
+		for _, child := range children {
+			inserted = append(inserted, treeRow[T]{item: child, depth: row.depth + 1})
+		}
+		l.rows = append(l.rows[:i+1], append(inserted, l.rows[i+1:]...)...)
+		if l.cursor > i {
+			l.cursor += len(inserted)
+		}
+		i += len(inserted)
+	}
+	l.scroll()
+}
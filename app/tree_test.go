@@ -0,0 +1,59 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTreeListSkip(t *testing.T) {
+	l := treeList[string]{}
+	l.pageState.PerPage = 2
+	l.setRows([]string{"a", "b"}, 4)
+
+	require.False(t, l.skip(1))
+	require.Equal(t, 1, l.cursor)
+
+	// Skipping past the last row moves to the next page.
+	require.True(t, l.skip(1))
+	l.setRows([]string{"c", "d"}, 4)
+	require.Equal(t, 0, l.cursor)
+
+	// Skipping back before the first row places the cursor on the last row of the previous page.
+	require.True(t, l.skip(-1))
+	l.setRows([]string{"a", "b"}, 4)
+	require.Equal(t, 1, l.cursor)
+}
+
+func TestTreeListChildren(t *testing.T) {
+	l := treeList[string]{}
+	l.setRows([]string{"a", "b", "a"}, 3)
+	l.rows[0].expanded = true
+	l.rows[2].expanded = true
+	l.cursor = 2
+
+	// Children are shown beneath every expanded row of their parent.
+	isA := func(s string) bool { return s == "a" }
+	l.insertChildren(isA, []string{"x", "y"})
+	require.Equal(t, []treeRow[string]{
+		{item: "a", expanded: true},
+		{item: "x", depth: 1},
+		{item: "y", depth: 1},
+		{item: "b"},
+		{item: "a", expanded: true},
+		{item: "x", depth: 1},
+		{item: "y", depth: 1},
+	}, l.rows)
+	require.Equal(t, 4, l.cursor)
+
+	// Children already shown are not inserted again.
+	l.insertChildren(isA, []string{"x", "y"})
+	require.Len(t, l.rows, 7)
+
+	// Collapsing a child collapses its parent and moves the cursor to it.
+	l.cursor = 5
+	l.collapseCurrent()
+	require.Equal(t, 4, l.cursor)
+	require.Len(t, l.rows, 5)
+	require.False(t, l.rows[4].expanded)
+}
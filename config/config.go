@@ -7,6 +7,7 @@ import (
 	"net/url"
 	"path"
 	"runtime"
+	"strconv"
 	"strings"
 
 	"github.com/drakenstar/stash-cli/stash"
@@ -14,12 +15,17 @@ import (
 	"github.com/spf13/pflag"
 )
 
+// OpenCommands are the commands used to open content externally.  The path or URL being opened is substituted at
+// occurrences of {} or appended to the end.  The Scene command may also contain {start}, which is substituted with the
+// position in seconds to start playback from, such as "mpv --start={start} {}".  This is 0 unless opening a marker.
 type OpenCommands struct {
 	URL     string `json:"url"`
 	Scene   string `json:"scene"`
 	Gallery string `json:"gallery"`
+	Image   string `json:"image"`
 }
 
+// Config is the application configuration.
 type Config struct {
 	Debug         bool              `json:"-"`
 	NewSession    bool              `json:"-"`
@@ -27,6 +33,25 @@ type Config struct {
 	APIKey        string            `json:"apiKey"`
 	PathMappings  map[string]string `json:"pathMappings"`
 	OpenCommands  OpenCommands      `json:"openCommands"`
+	// DisableRecordPlay stops opening a scene from incrementing its play count and recording activity in stash.
+	DisableRecordPlay bool `json:"disableRecordPlay"`
+	// Stream opens scenes from their stream URL in stash rather than their file path, for when the library is not
+	// mounted locally.
+	Stream bool `json:"stream"`
+	// StreamLabel picks the stream to open by its label, such as "MP4 Standard (480p)" for a transcode, and is the
+	// direct stream if empty.
+	StreamLabel string `json:"streamLabel"`
+	// Preview opens the image preview pane at startup.
+	Preview bool `json:"preview"`
+	// Graphics overrides the protocol used to draw the preview, which is otherwise detected from the terminal.
+	Graphics string `json:"graphics"`
+}
+
+// Stream is a scene to be opened from a stream URL rather than its file path.  Start is the position in seconds to
+// start playback from.
+type Stream struct {
+	URL   string
+	Start float64
 }
 
 func (c Config) MapPath(path string) string {
@@ -38,6 +63,16 @@ func (c Config) MapPath(path string) string {
 	return path
 }
 
+// UnmapPath reverses MapPath, returning the path stash uses for a local path.
+func (c Config) UnmapPath(path string) string {
+	for prefix, replacement := range c.PathMappings {
+		if strings.HasPrefix(path, replacement) {
+			return strings.Replace(path, replacement, prefix, 1)
+		}
+	}
+	return path
+}
+
 func (c Config) URL(p string) *url.URL {
 	u := c.StashInstance.URL
 	u.Path = path.Join(u.Path, p)
@@ -48,6 +83,32 @@ func (c Config) GraphURL() *url.URL {
 	return c.URL("graphql")
 }
 
+// SubscriptionURL returns the websocket URL used for GraphQL subscriptions.
+func (c Config) SubscriptionURL() *url.URL {
+	u := c.GraphURL()
+	if u.Scheme == "https" {
+		u.Scheme = "wss"
+	} else {
+		u.Scheme = "ws"
+	}
+	return u
+}
+
+// StreamURL returns a stream URL with the API key added as a query parameter, since players opening the URL can't
+// send the ApiKey header.
+func (c Config) StreamURL(stream string) (string, error) {
+	u, err := url.Parse(stream)
+	if err != nil {
+		return "", fmt.Errorf("invalid stream URL: %w", err)
+	}
+	if c.APIKey != "" {
+		query := u.Query()
+		query.Set("apikey", c.APIKey)
+		u.RawQuery = query.Encode()
+	}
+	return u.String(), nil
+}
+
 // Opener is a function that the application can send a type at and have it act externally on the type.  Typically
 // this is used to open a media file or URL in an external application.
 type Opener func(content any) error
@@ -56,6 +117,7 @@ type Opener func(content any) error
 func (c Config) Opener(exec func(string, ...string) error) Opener {
 	return func(content any) error {
 		var cmdString, filePath string
+		var start float64
 		switch cnt := content.(type) {
 		case string:
 			cmdString = c.OpenCommands.URL
@@ -63,9 +125,24 @@ func (c Config) Opener(exec func(string, ...string) error) Opener {
 		case stash.Scene:
 			cmdString = c.OpenCommands.Scene
 			filePath = c.MapPath(cnt.FilePath())
+		case stash.SceneMarker:
+			cmdString = c.OpenCommands.Scene
+			filePath = c.MapPath(cnt.Scene.FilePath())
+			start = cnt.Seconds
+		case Stream:
+			cmdString = c.OpenCommands.Scene
+			streamURL, err := c.StreamURL(cnt.URL)
+			if err != nil {
+				return err
+			}
+			filePath = streamURL
+			start = cnt.Start
 		case stash.Gallery:
 			cmdString = c.OpenCommands.Gallery
 			filePath = c.MapPath(cnt.FilePath())
+		case stash.Image:
+			cmdString = c.OpenCommands.Image
+			filePath = c.MapPath(cnt.FilePath())
 		default:
 			return fmt.Errorf("unsupported content type (%T)", content)
 		}
@@ -92,11 +169,15 @@ func (c Config) Opener(exec func(string, ...string) error) Opener {
 			return err
 		}
 
-		// FilePath is substituted either at occurrences of {} or at the end.
+		// FilePath is substituted either at occurrences of {} or at the end.  The start position is substituted within
+		// any part, allowing for flags such as --start={start}.
+		startString := strconv.FormatFloat(start, 'f', -1, 64)
 		for i, part := range cmdParts {
 			if part == "{}" {
 				cmdParts[i] = filePath
+				continue
 			}
+			cmdParts[i] = strings.ReplaceAll(part, "{start}", startString)
 		}
 		if !strings.Contains(cmdString, "{}") {
 			cmdParts = append(cmdParts, filePath)
@@ -125,6 +206,12 @@ func FromArgs(c *Config, args []string) error {
 		openCommandURL     string
 		openCommandScene   string
 		openCommandGallery string
+		openCommandImage   string
+		noRecordPlay       bool
+		stream             bool
+		streamLabel        string
+		preview            bool
+		graphics           string
 	)
 
 	fs := pflag.NewFlagSet("stash-cli", pflag.ExitOnError)
@@ -134,8 +221,14 @@ func FromArgs(c *Config, args []string) error {
 	fs.StringVar(&stashInstanceStr, "stashInstance", "", "URL of the Stash instance")
 	fs.StringArrayVar(&pathMappingStrs, "pathMapping", []string{}, "path mapping (key:value), this flag can be repeated")
 	fs.StringVar(&openCommandURL, "openCommandURL", "", "command to open URL")
-	fs.StringVar(&openCommandScene, "openCommandScene", "", "command to open Scene")
+	fs.StringVar(&openCommandScene, "openCommandScene", "", "command to open Scene, {start} is replaced with a start position in seconds")
 	fs.StringVar(&openCommandGallery, "openCommandGallery", "", "command to open Gallery")
+	fs.StringVar(&openCommandImage, "openCommandImage", "", "command to open Image")
+	fs.BoolVar(&noRecordPlay, "noRecordPlay", false, "do not record play count and activity when opening scenes")
+	fs.BoolVar(&stream, "stream", false, "open scenes from their stream URL rather than their file path")
+	fs.StringVar(&streamLabel, "streamLabel", "", "label of the stream to open scenes from, such as a transcode")
+	fs.BoolVar(&preview, "preview", false, "show the image preview pane at startup")
+	fs.StringVar(&graphics, "graphics", "", "protocol used to draw images (kitty, sixel, iterm, blocks)")
 
 	fs.Parse(args)
 
@@ -170,6 +263,24 @@ func FromArgs(c *Config, args []string) error {
 	if openCommandGallery != "" {
 		c.OpenCommands.Gallery = openCommandGallery
 	}
+	if openCommandImage != "" {
+		c.OpenCommands.Image = openCommandImage
+	}
+	if noRecordPlay {
+		c.DisableRecordPlay = true
+	}
+	if stream {
+		c.Stream = true
+	}
+	if streamLabel != "" {
+		c.StreamLabel = streamLabel
+	}
+	if preview {
+		c.Preview = true
+	}
+	if graphics != "" {
+		c.Graphics = graphics
+	}
 
 	return nil
 }
@@ -42,6 +42,18 @@ func TestMapPath(t *testing.T) {
 	}
 }
 
+func TestUnmapPath(t *testing.T) {
+	c := Config{
+		PathMappings: map[string]string{
+			"/old/": "/new/",
+		},
+	}
+
+	assert.Equal(t, "/old/path/to/file.txt", c.UnmapPath("/new/path/to/file.txt"))
+	assert.Equal(t, "/another/path/to/file.txt", c.UnmapPath("/another/path/to/file.txt"))
+	assert.Equal(t, "/old/path/to/file.txt", c.UnmapPath(c.MapPath("/old/path/to/file.txt")))
+}
+
 func TestURL(t *testing.T) {
 	c := Config{
 		StashInstance: mustParseURL(t, "http://example.com"),
@@ -71,6 +83,14 @@ func TestURL(t *testing.T) {
 	}
 }
 
+func TestSubscriptionURL(t *testing.T) {
+	c := Config{StashInstance: mustParseURL(t, "https://example.com/stash")}
+	assert.Equal(t, "wss://example.com/stash/graphql", c.SubscriptionURL().String())
+
+	c = Config{StashInstance: mustParseURL(t, "http://example.com")}
+	assert.Equal(t, "ws://example.com/graphql", c.SubscriptionURL().String())
+}
+
 func TestOpen(t *testing.T) {
 	c := Config{
 		Debug:         true,
@@ -81,6 +101,7 @@ func TestOpen(t *testing.T) {
 		c.OpenCommands.URL = "open -a Safari {}"
 		c.OpenCommands.Scene = "open {} -a VLC"
 		c.OpenCommands.Gallery = "open -a Preview {}"
+		c.OpenCommands.Image = "feh {}"
 
 		tests := []struct {
 			name        string
@@ -102,6 +123,11 @@ func TestOpen(t *testing.T) {
 				content:     stash.Gallery{Folder: stash.Folder{Path: "/path/to/file.jpg"}},
 				expectedCmd: []string{"open", "-a", "Preview", "/path/to/file.jpg"},
 			},
+			{
+				name:        "image",
+				content:     stash.Image{Files: []stash.ImageFile{{Path: "/path/to/file.jpg"}}},
+				expectedCmd: []string{"feh", "/path/to/file.jpg"},
+			},
 		}
 
 		for _, tt := range tests {
@@ -121,6 +147,48 @@ func TestOpen(t *testing.T) {
 		}
 	})
 
+	t.Run("start placeholder", func(t *testing.T) {
+		c.OpenCommands.Scene = "mpv --start={start} {}"
+		c.APIKey = "secret"
+
+		tests := []struct {
+			name        string
+			content     interface{}
+			expectedCmd []string
+		}{
+			{
+				name:        "scene",
+				content:     stash.Scene{Files: []stash.VideoFile{{Path: "/path/to/file.mp4"}}},
+				expectedCmd: []string{"mpv", "--start=0", "/path/to/file.mp4"},
+			},
+			{
+				name: "marker",
+				content: stash.SceneMarker{
+					Seconds: 90.5,
+					Scene:   stash.Scene{Files: []stash.VideoFile{{Path: "/path/to/file.mp4"}}},
+				},
+				expectedCmd: []string{"mpv", "--start=90.5", "/path/to/file.mp4"},
+			},
+			{
+				name:        "stream",
+				content:     Stream{URL: "http://stash:9999/scene/1/stream.mp4?resolution=STANDARD", Start: 30},
+				expectedCmd: []string{"mpv", "--start=30", "http://stash:9999/scene/1/stream.mp4?apikey=secret&resolution=STANDARD"},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var cmd []string
+				opener := c.Opener(func(name string, arg ...string) error {
+					cmd = append([]string{name}, arg...)
+					return nil
+				})
+				require.NoError(t, opener(tt.content))
+				assert.Equal(t, tt.expectedCmd, cmd)
+			})
+		}
+	})
+
 	t.Run("defaults", func(t *testing.T) {
 		c.OpenCommands.URL = ""
 		c.OpenCommands.Scene = ""
@@ -182,8 +250,14 @@ func TestFromFuncs(t *testing.T) {
 				"openCommands": {
 					"url": "url command",
 					"scene": "scene command",
-					"gallery": "gallery command"
-				}
+					"gallery": "gallery command",
+					"image": "image command"
+				},
+				"disableRecordPlay": true,
+				"stream": true,
+				"streamLabel": "MP4",
+				"preview": true,
+				"graphics": "sixel"
 			}
 		`))
 		err := FromFile(c, f)
@@ -198,7 +272,13 @@ func TestFromFuncs(t *testing.T) {
 				URL:     "url command",
 				Scene:   "scene command",
 				Gallery: "gallery command",
+				Image:   "image command",
 			},
+			DisableRecordPlay: true,
+			Stream:            true,
+			StreamLabel:       "MP4",
+			Preview:           true,
+			Graphics:          "sixel",
 		}, *c)
 	})
 
@@ -239,6 +319,12 @@ func TestFromFuncs(t *testing.T) {
 			"--openCommandURL", "url command",
 			"--openCommandScene", "scene command",
 			"--openCommandGallery", "gallery command",
+			"--openCommandImage", "image command",
+			"--noRecordPlay",
+			"--stream",
+			"--streamLabel", "MP4",
+			"--preview",
+			"--graphics", "sixel",
 		})
 		require.Equal(t, Config{
 			Debug:         true,
@@ -252,7 +338,13 @@ func TestFromFuncs(t *testing.T) {
 				URL:     "url command",
 				Scene:   "scene command",
 				Gallery: "gallery command",
+				Image:   "image command",
 			},
+			DisableRecordPlay: true,
+			Stream:            true,
+			StreamLabel:       "MP4",
+			Preview:           true,
+			Graphics:          "sixel",
 		}, *c)
 	})
 }
@@ -10,11 +10,17 @@ const (
 	AppName     = "stash-cli"
 	ConfigFile  = "config.json"
 	SessionFile = "session.json"
+	PreviewDir  = "previews"
+	LocalDir    = "local"
 )
 
 type Paths struct {
 	ConfigPath  string
 	SessionPath string
+	// PreviewPath is the directory that fetched preview images are cached in.
+	PreviewPath string
+	// LocalPath is the directory that metadata given to the files of local folders is stored in.
+	LocalPath string
 }
 
 func DefaultPaths() (Paths, error) {
@@ -36,6 +42,8 @@ func DefaultPaths() (Paths, error) {
 	return Paths{
 		ConfigPath:  filepath.Join(configDir, AppName, ConfigFile),
 		SessionPath: filepath.Join(stateDir, AppName, SessionFile),
+		PreviewPath: filepath.Join(stateDir, AppName, PreviewDir),
+		LocalPath:   filepath.Join(stateDir, AppName, LocalDir),
 	}, nil
 }
 
@@ -16,6 +16,10 @@ func TestDefaultPaths(t *testing.T) {
 	require.Equal(t, AppName, filepath.Base(filepath.Dir(paths.ConfigPath)))
 	require.Equal(t, SessionFile, filepath.Base(paths.SessionPath))
 	require.Equal(t, AppName, filepath.Base(filepath.Dir(paths.SessionPath)))
+	require.Equal(t, PreviewDir, filepath.Base(paths.PreviewPath))
+	require.Equal(t, filepath.Dir(paths.SessionPath), filepath.Dir(paths.PreviewPath))
+	require.Equal(t, LocalDir, filepath.Base(paths.LocalPath))
+	require.Equal(t, filepath.Dir(paths.SessionPath), filepath.Dir(paths.LocalPath))
 }
 
 func TestConfigPathExists(t *testing.T) {
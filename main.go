@@ -1,7 +1,9 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httputil"
 	"os"
@@ -12,6 +14,7 @@ import (
 	"github.com/drakenstar/stash-cli/app"
 	"github.com/drakenstar/stash-cli/config"
 	"github.com/drakenstar/stash-cli/stash"
+	"github.com/drakenstar/stash-cli/ui"
 	"github.com/hasura/go-graphql-client"
 )
 
@@ -25,10 +28,21 @@ func main() {
 	}
 
 	var s stash.Stash
+	var subscriber *stash.Subscriber
+	var fetchImage app.ImageFetcher
 
-	if cfg.StashInstance.Scheme == "file" {
-		s = stash.NewLocalStash(cfg.StashInstance.Path)
-	} else {
+	switch cfg.StashInstance.Scheme {
+	case "file":
+		s, err = stash.NewLocalStash(cfg.StashInstance.Path, paths.LocalPath)
+		fatalOnErr(err)
+	case "mem":
+		// The fixture path is given after the scheme, so mem://fixture.json is relative to the working directory and
+		// mem:///path/to/fixture.json is absolute.  There is no server to subscribe to or fetch images from.
+		data, err := os.ReadFile(cfg.StashInstance.Host + cfg.StashInstance.Path)
+		fatalOnErr(err)
+		s, err = stash.LoadMemoryStash(data)
+		fatalOnErr(err)
+	default:
 		httpClient := &client{
 			Client: http.DefaultClient,
 			APIKey: cfg.APIKey,
@@ -36,6 +50,8 @@ func main() {
 		}
 		client := graphql.NewClient(cfg.GraphURL().String(), httpClient)
 		s = stash.New(client)
+		subscriber = stash.NewSubscriber(cfg.SubscriptionURL().String(), http.Header{"ApiKey": {cfg.APIKey}})
+		fetchImage = imageFetcher(httpClient, cfg)
 	}
 
 	opener := cfg.Opener(func(name string, args ...string) error {
@@ -46,6 +62,19 @@ func main() {
 	})
 
 	model := app.New(s, opener)
+	model.SetRecordPlay(!cfg.DisableRecordPlay)
+	model.SetStream(cfg.Stream, cfg.StreamLabel)
+	model.SetUnmapPath(cfg.UnmapPath)
+	if fetchImage != nil {
+		graphics := ui.DetectGraphics(os.Getenv)
+		if cfg.Graphics != "" {
+			graphics, err = ui.ParseGraphics(cfg.Graphics)
+			fatalOnErr(err)
+		}
+		renderer := ui.ImageRenderer{Graphics: graphics, Cell: ui.TerminalCellSize(os.Stdout.Fd())}
+		cache := app.NewFileImageCache(paths.PreviewPath, cfg.StashInstance.String(), fetchImage)
+		model.SetPreview(cache, renderer, cfg.Preview)
+	}
 	sessionStore := app.NewFileSessionStore(paths.SessionPath)
 	model.SetSessionStore(sessionStore, cfg.StashInstance.String())
 	if !cfg.NewSession {
@@ -61,6 +90,21 @@ func main() {
 		model,
 		// tea.WithAltScreen(), TODO buggy with emoji atm
 	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// Stashes that index content themselves, such as local folders, are indexed in the background once the TUI is up,
+	// and indexing stops when the app exits.
+	go model.Index(ctx, p.Send)
+	if subscriber != nil {
+		fatalOnErr(app.Subscribe(subscriber, p.Send))
+		go func() {
+			if err := subscriber.Run(ctx); err != nil {
+				p.Send(app.NewErrorCmd(fmt.Errorf("subscriptions stopped: %w", err))())
+			}
+		}()
+	}
+
 	if _, err := p.Run(); err != nil {
 		fatal(err)
 	}
@@ -112,6 +156,25 @@ type client struct {
 	Debug  bool
 }
 
+// imageFetcher returns an ImageFetcher that fetches images from paths of the stash instance with client.
+func imageFetcher(c *client, cfg *config.Config) app.ImageFetcher {
+	return func(ctx context.Context, path string) ([]byte, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, cfg.URL(path).String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := c.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unable to fetch preview: %s", resp.Status)
+		}
+		return io.ReadAll(resp.Body)
+	}
+}
+
 func (c *client) Do(req *http.Request) (*http.Response, error) {
 	req.Header.Set("ApiKey", c.APIKey)
 
@@ -21,11 +21,24 @@ func (FindFilter) GetGraphQLType() string {
 }
 
 const (
-	SortDate         = "date"
-	SortUpdatedAt    = "updated_at"
-	SortCreatedAt    = "created_at"
-	SortPath         = "path"
-	SortRandomPrefix = "random_"
+	SortDate            = "date"
+	SortUpdatedAt       = "updated_at"
+	SortCreatedAt       = "created_at"
+	SortPath            = "path"
+	SortName            = "name"
+	SortTitle           = "title"
+	SortSeconds         = "seconds"
+	SortDuration        = "duration"
+	SortFileSize        = "filesize"
+	SortSceneCount      = "scene_count"
+	SortPlayCount       = "play_count"
+	SortLastPlayedAt    = "last_played_at"
+	SortOCounter        = "o_counter"
+	SortMovieSceneIndex = "movie_scene_number"
+	SortScenesCount     = "scenes_count"
+	SortGalleriesCount  = "galleries_count"
+	SortPerformersCount = "performers_count"
+	SortRandomPrefix    = "random_"
 
 	SortDirectionAsc  = "ASC"
 	SortDirectionDesc = "DESC"
@@ -35,7 +48,7 @@ func RandomSort() string {
 	return fmt.Sprintf("%s%08d", SortRandomPrefix, rand.Intn(100000000))
 }
 
-type FilterCombinator[T SceneFilter | GalleryFilter] struct {
+type FilterCombinator[T SceneFilter | GalleryFilter | ImageFilter | PerformerFilter | StudioFilter] struct {
 	AND *T `json:"AND,omitempty"`
 	OR  *T `json:"OR,omitempty"`
 	NOT *T `json:"NOT,omitempty"`
@@ -129,6 +142,106 @@ func (GalleryFilter) GetGraphQLType() string {
 	return "GalleryFilterType"
 }
 
+type ImageFilter struct {
+	FilterCombinator[ImageFilter]
+	ID                 *IntCriterion               `json:"id,omitempty"`
+	Title              *StringCriterion            `json:"title,omitempty"`
+	Checksum           *StringCriterion            `json:"checksum,omitempty"`
+	Path               *StringCriterion            `json:"path,omitempty"`
+	FileCount          *IntCriterion               `json:"file_count,omitempty"`
+	Rating100          *IntCriterion               `json:"rating100,omitempty"`
+	Date               *DateCriterion              `json:"date,omitempty"`
+	URL                *StringCriterion            `json:"url,omitempty"`
+	Organized          *bool                       `json:"organized,omitempty"`
+	OCounter           *IntCriterion               `json:"o_counter,omitempty"`
+	Resolution         *ResolutionCriterion        `json:"resolution,omitempty"`
+	IsMissing          *string                     `json:"is_missing,omitempty"`
+	Studios            *HierarchicalMultiCriterion `json:"studios,omitempty"`
+	Tags               *HierarchicalMultiCriterion `json:"tags,omitempty"`
+	TagCount           *IntCriterion               `json:"tag_count,omitempty"`
+	PerformerTags      *HierarchicalMultiCriterion `json:"performer_tags,omitempty"`
+	Performers         *MultiCriterion             `json:"performers,omitempty"`
+	PerformerCount     *IntCriterion               `json:"performer_count,omitempty"`
+	PerformerFavourite *bool                       `json:"performer_favorite,omitempty"`
+	Galleries          *MultiCriterion             `json:"galleries,omitempty"`
+	CreatedAt          *TimestampCriterion         `json:"created_at,omitempty"`
+	UpdatedAt          *TimestampCriterion         `json:"updated_at,omitempty"`
+}
+
+func (ImageFilter) GetGraphQLType() string {
+	return "ImageFilterType"
+}
+
+type PerformerFilter struct {
+	FilterCombinator[PerformerFilter]
+	Name           *StringCriterion            `json:"name,omitempty"`
+	Disambiguation *StringCriterion            `json:"disambiguation,omitempty"`
+	Details        *StringCriterion            `json:"details,omitempty"`
+	Favourite      *bool                       `json:"filter_favorites,omitempty"`
+	BirthYear      *IntCriterion               `json:"birth_year,omitempty"`
+	Age            *IntCriterion               `json:"age,omitempty"`
+	Country        *StringCriterion            `json:"country,omitempty"`
+	Aliases        *StringCriterion            `json:"aliases,omitempty"`
+	Gender         *GenderCriterion            `json:"gender,omitempty"`
+	IsMissing      *string                     `json:"is_missing,omitempty"`
+	Tags           *HierarchicalMultiCriterion `json:"tags,omitempty"`
+	TagCount       *IntCriterion               `json:"tag_count,omitempty"`
+	SceneCount     *IntCriterion               `json:"scene_count,omitempty"`
+	ImageCount     *IntCriterion               `json:"image_count,omitempty"`
+	GalleryCount   *IntCriterion               `json:"gallery_count,omitempty"`
+	OCounter       *IntCriterion               `json:"o_counter,omitempty"`
+	Rating100      *IntCriterion               `json:"rating100,omitempty"`
+	URL            *StringCriterion            `json:"url,omitempty"`
+	Studios        *HierarchicalMultiCriterion `json:"studios,omitempty"`
+	Performers     *MultiCriterion             `json:"performers,omitempty"`
+	CreatedAt      *TimestampCriterion         `json:"created_at,omitempty"`
+	UpdatedAt      *TimestampCriterion         `json:"updated_at,omitempty"`
+}
+
+func (PerformerFilter) GetGraphQLType() string {
+	return "PerformerFilterType"
+}
+
+type MovieFilter struct {
+	Name       *StringCriterion            `json:"name,omitempty"`
+	Director   *StringCriterion            `json:"director,omitempty"`
+	Synopsis   *StringCriterion            `json:"synopsis,omitempty"`
+	Duration   *IntCriterion               `json:"duration,omitempty"`
+	Rating100  *IntCriterion               `json:"rating100,omitempty"`
+	Studios    *HierarchicalMultiCriterion `json:"studios,omitempty"`
+	IsMissing  *string                     `json:"is_missing,omitempty"`
+	URL        *StringCriterion            `json:"url,omitempty"`
+	Performers *MultiCriterion             `json:"performers,omitempty"`
+	Date       *DateCriterion              `json:"date,omitempty"`
+	CreatedAt  *TimestampCriterion         `json:"created_at,omitempty"`
+	UpdatedAt  *TimestampCriterion         `json:"updated_at,omitempty"`
+}
+
+func (MovieFilter) GetGraphQLType() string {
+	return "MovieFilterType"
+}
+
+type StudioFilter struct {
+	FilterCombinator[StudioFilter]
+	Name          *StringCriterion    `json:"name,omitempty"`
+	Details       *StringCriterion    `json:"details,omitempty"`
+	Parents       *MultiCriterion     `json:"parents,omitempty"`
+	IsMissing     *string             `json:"is_missing,omitempty"`
+	Rating100     *IntCriterion       `json:"rating100,omitempty"`
+	SceneCount    *IntCriterion       `json:"scene_count,omitempty"`
+	ImageCount    *IntCriterion       `json:"image_count,omitempty"`
+	GalleryCount  *IntCriterion       `json:"gallery_count,omitempty"`
+	URL           *StringCriterion    `json:"url,omitempty"`
+	Aliases       *StringCriterion    `json:"aliases,omitempty"`
+	IgnoreAutoTag *bool               `json:"ignore_auto_tag,omitempty"`
+	CreatedAt     *TimestampCriterion `json:"created_at,omitempty"`
+	UpdatedAt     *TimestampCriterion `json:"updated_at,omitempty"`
+}
+
+func (StudioFilter) GetGraphQLType() string {
+	return "StudioFilterType"
+}
+
 type MultiCriterion struct {
 	Value    []string          `json:"value"`
 	Modifier CriterionModifier `json:"modifier"`
@@ -212,6 +325,11 @@ type PHashDistanceCriterion struct {
 	Distance *int              `json:"distance,omitempty"`
 }
 
+type GenderCriterion struct {
+	Value    Gender            `json:"value"`
+	Modifier CriterionModifier `json:"modifier"`
+}
+
 type ResolutionCriterion struct {
 	Value    Resolution        `json:"value"`
 	Modifier CriterionModifier `json:"modifier"`
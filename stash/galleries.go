@@ -26,6 +26,10 @@ type Gallery struct {
 	Performers []Performer `graphql:"performers"`
 }
 
+func (g Gallery) EntityID() string {
+	return g.ID
+}
+
 func (g Gallery) FilePath() string {
 	if g.Folder.Path != "" {
 		return g.Folder.Path
@@ -67,19 +71,19 @@ func (s *stash) GalleryDelete(ctx context.Context, galleryID string) (bool, erro
 }
 
 type GalleryUpdate struct {
-	ClientMutationID *string      `json:"clientMutationId,omitempty"`
-	ID               graphql.ID   `json:"id"`
-	Title            *string      `json:"title,omitempty"`
-	URL              *string      `json:"url,omitempty"`
-	Date             *string      `json:"date,omitempty"`
-	Details          *string      `json:"details,omitempty"`
-	Rating           *int         `json:"rating100,omitempty"`
-	Organized        *bool        `json:"organized,omitempty"`
-	SceneIDs         []graphql.ID `json:"scene_ids,omitempty"`
-	StudioID         *graphql.ID  `json:"studio_id,omitempty"`
-	TagIDs           []graphql.ID `json:"tag_ids,omitempty"`
-	PerformerIDs     []graphql.ID `json:"performer_ids,omitempty"`
-	PrimaryFileID    *graphql.ID  `json:"primary_file_id,omitempty"`
+	ClientMutationID *string       `json:"clientMutationId,omitempty"`
+	ID               graphql.ID    `json:"id"`
+	Title            *string       `json:"title,omitempty"`
+	URL              *string       `json:"url,omitempty"`
+	Date             *string       `json:"date,omitempty"`
+	Details          *string       `json:"details,omitempty"`
+	Rating           *int          `json:"rating100,omitempty"`
+	Organized        *bool         `json:"organized,omitempty"`
+	SceneIDs         []graphql.ID  `json:"scene_ids,omitempty"`
+	StudioID         *graphql.ID   `json:"studio_id,omitempty"`
+	TagIDs           *[]graphql.ID `json:"tag_ids,omitempty"`
+	PerformerIDs     *[]graphql.ID `json:"performer_ids,omitempty"`
+	PrimaryFileID    *graphql.ID   `json:"primary_file_id,omitempty"`
 }
 
 func (GalleryUpdate) GetGraphQLType() string {
@@ -124,14 +128,14 @@ func NewGalleryUpdate(gOld, gNew Gallery) GalleryUpdate {
 		for i, t := range gNew.Tags {
 			tagIDs[i] = graphql.ID(t.ID)
 		}
-		g.TagIDs = tagIDs
+		g.TagIDs = &tagIDs
 	}
 	if !performerListsEqual(gOld.Performers, gNew.Performers) {
 		performerIDs := make([]graphql.ID, len(gNew.Performers))
 		for i, t := range gNew.Performers {
 			performerIDs[i] = graphql.ID(t.ID)
 		}
-		g.PerformerIDs = performerIDs
+		g.PerformerIDs = &performerIDs
 	}
 
 	return g
@@ -144,3 +148,44 @@ func (s *stash) GalleryUpdate(ctx context.Context, g GalleryUpdate) (Gallery, er
 	err := s.client.Mutate(ctx, &m, map[string]any{"input": g})
 	return m.GalleryUpdate, err
 }
+
+// BulkGalleryUpdate applies the same changes to every gallery in IDs.
+type BulkGalleryUpdate struct {
+	ClientMutationID *string        `json:"clientMutationId,omitempty"`
+	IDs              []graphql.ID   `json:"ids"`
+	Date             *string        `json:"date,omitempty"`
+	Rating           *int           `json:"rating100,omitempty"`
+	Organized        *bool          `json:"organized,omitempty"`
+	StudioID         *graphql.ID    `json:"studio_id,omitempty"`
+	PerformerIDs     *BulkUpdateIDs `json:"performer_ids,omitempty"`
+	TagIDs           *BulkUpdateIDs `json:"tag_ids,omitempty"`
+}
+
+func (BulkGalleryUpdate) GetGraphQLType() string {
+	return "BulkGalleryUpdateInput"
+}
+
+// NewBulkGalleryUpdate returns a BulkGalleryUpdate for the galleries with the given IDs.
+func NewBulkGalleryUpdate(ids []string) BulkGalleryUpdate {
+	return BulkGalleryUpdate{IDs: GraphQLIDs(ids)}
+}
+
+func (s *stash) BulkGalleryUpdate(ctx context.Context, update BulkGalleryUpdate) ([]Gallery, error) {
+	var m struct {
+		BulkGalleryUpdate []Gallery `graphql:"bulkGalleryUpdate(input: $input)"`
+	}
+	err := s.client.Mutate(ctx, &m, map[string]any{"input": update})
+	return m.BulkGalleryUpdate, err
+}
+
+// GalleriesDestroy deletes a number of galleries in a single request, along with their files and generated content.
+func (s *stash) GalleriesDestroy(ctx context.Context, galleryIDs []string) (bool, error) {
+	var m struct {
+		GalleryDestroy bool `graphql:"galleryDestroy(input: {ids: $ids, delete_file: true, delete_generated: true})"`
+	}
+	variables := map[string]any{
+		"ids": GraphQLIDs(galleryIDs),
+	}
+	err := s.client.Mutate(ctx, &m, variables)
+	return m.GalleryDestroy, err
+}
@@ -133,6 +133,42 @@ func TestGalleryDelete(t *testing.T) {
 	require.True(t, d)
 }
 
+func TestBulkGalleryUpdate(t *testing.T) {
+	doer := &captureEndpoint{
+		t:        t,
+		response: `{"data": {"bulkGalleryUpdate": [{"id": "1", "organized": true}, {"id": "2", "organized": true}]}}`,
+	}
+	client := graphql.NewClient("https://example.com/graph", doer)
+	s := stash{client}
+
+	organized := true
+	update := NewBulkGalleryUpdate([]string{"1", "2"})
+	update.Organized = &organized
+	galleries, err := s.BulkGalleryUpdate(context.Background(), update)
+
+	require.NoError(t, err)
+	require.Len(t, galleries, 2)
+	require.True(t, galleries[1].Organized)
+	require.Contains(t, doer.body, `$input:BulkGalleryUpdateInput!`)
+	require.Contains(t, doer.body, `"organized":true`)
+}
+
+func TestGalleriesDestroy(t *testing.T) {
+	doer := &captureEndpoint{
+		t:        t,
+		response: `{"data": {"galleryDestroy": true}}`,
+	}
+	client := graphql.NewClient("https://example.com/graph", doer)
+	s := stash{client}
+
+	ok, err := s.GalleriesDestroy(context.Background(), []string{"1", "2"})
+
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Contains(t, doer.body, `galleryDestroy(input: {ids: $ids, delete_file: true, delete_generated: true})`)
+	require.Contains(t, doer.body, `"ids":["1","2"]`)
+}
+
 func TestGalleryUpdate(t *testing.T) {
 
 }
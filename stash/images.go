@@ -0,0 +1,174 @@
+package stash
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hasura/go-graphql-client"
+)
+
+type Image struct {
+	ID         string      `graphql:"id"`
+	Title      string      `graphql:"title"`
+	Date       string      `graphql:"date"`
+	Rating     int         `graphql:"rating100"`
+	OCounter   int         `graphql:"o_counter"`
+	Organized  bool        `graphql:"organized"`
+	Files      []ImageFile `graphql:"files"`
+	CreatedAt  time.Time   `graphql:"created_at"`
+	UpdatedAt  time.Time   `graphql:"updated_at"`
+	Studio     Studio      `graphql:"studio"`
+	Tags       []Tag       `graphql:"tags"`
+	Performers []Performer `graphql:"performers"`
+}
+
+type ImageFile struct {
+	Path   string `graphql:"path"`
+	Size   int64  `graphql:"size"`
+	Width  int    `graphql:"width"`
+	Height int    `graphql:"height"`
+}
+
+func (i Image) FilePath() string {
+	if len(i.Files) > 0 {
+		return i.Files[0].Path
+	}
+	panic("no file found for image")
+}
+
+type imagesQuery struct {
+	FindImages struct {
+		Count  int     `graphql:"count"`
+		Images []Image `graphql:"images"`
+	} `graphql:"findImages(filter: $filter, image_filter: $image_filter)"`
+}
+
+// Images returns a page of images matching the given filters along with the total count of matches.
+func (s *stash) Images(ctx context.Context, filter FindFilter, imageFilter ImageFilter) ([]Image, int, error) {
+	resp := imagesQuery{}
+	err := s.client.Query(ctx, &resp, map[string]any{
+		"filter":       filter,
+		"image_filter": imageFilter,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return resp.FindImages.Images, resp.FindImages.Count, nil
+}
+
+func (s *stash) ImageDelete(ctx context.Context, imageID string) (bool, error) {
+	var m struct {
+		ImageDestroy bool `graphql:"imageDestroy(input: {id: $id, delete_file: true, delete_generated: true})"`
+	}
+	variables := map[string]any{
+		"id": graphql.ID(imageID),
+	}
+	err := s.client.Mutate(ctx, &m, variables)
+	return m.ImageDestroy, err
+}
+
+// ImageIncrementO increments the o-counter of an image, returning the new count.
+func (s *stash) ImageIncrementO(ctx context.Context, imageID string) (int, error) {
+	var m struct {
+		Count int `graphql:"imageIncrementO(id: $id)"`
+	}
+	variables := map[string]any{
+		"id": graphql.ID(imageID),
+	}
+	err := s.client.Mutate(ctx, &m, variables)
+	return m.Count, err
+}
+
+// ImageDecrementO decrements the o-counter of an image, returning the new count.
+func (s *stash) ImageDecrementO(ctx context.Context, imageID string) (int, error) {
+	var m struct {
+		Count int `graphql:"imageDecrementO(id: $id)"`
+	}
+	variables := map[string]any{
+		"id": graphql.ID(imageID),
+	}
+	err := s.client.Mutate(ctx, &m, variables)
+	return m.Count, err
+}
+
+// ImageResetO resets the o-counter of an image to zero, returning the new count.
+func (s *stash) ImageResetO(ctx context.Context, imageID string) (int, error) {
+	var m struct {
+		Count int `graphql:"imageResetO(id: $id)"`
+	}
+	variables := map[string]any{
+		"id": graphql.ID(imageID),
+	}
+	err := s.client.Mutate(ctx, &m, variables)
+	return m.Count, err
+}
+
+type ImageUpdate struct {
+	ClientMutationID *string       `json:"clientMutationId,omitempty"`
+	ID               graphql.ID    `json:"id"`
+	Title            *string       `json:"title,omitempty"`
+	Date             *string       `json:"date,omitempty"`
+	Rating           *int          `json:"rating100,omitempty"`
+	Organized        *bool         `json:"organized,omitempty"`
+	StudioID         *graphql.ID   `json:"studio_id,omitempty"`
+	TagIDs           *[]graphql.ID `json:"tag_ids,omitempty"`
+	PerformerIDs     []graphql.ID  `json:"performer_ids,omitempty"`
+}
+
+func (ImageUpdate) GetGraphQLType() string {
+	return "ImageUpdateInput"
+}
+
+// NewImageUpdate does a diff of an old and new Image and returns an ImageUpdate that can be passed to
+// stash.ImageUpdate.  A panic will occur if the IDs of the images do not match.
+func NewImageUpdate(iOld, iNew Image) ImageUpdate {
+	i := ImageUpdate{
+		ID: graphql.ID(iNew.ID),
+	}
+
+	if iOld.ID != iNew.ID {
+		panic(fmt.Errorf("images do not have the same id old: %s new: %s", iOld.ID, iNew.ID))
+	}
+
+	if iOld.Title != iNew.Title {
+		i.Title = &iNew.Title
+	}
+	if iOld.Date != iNew.Date {
+		i.Date = &iNew.Date
+	}
+	if iOld.Rating != iNew.Rating {
+		i.Rating = &iNew.Rating
+	}
+	if iOld.Organized != iNew.Organized {
+		i.Organized = &iNew.Organized
+	}
+	if iOld.Studio.ID != iNew.Studio.ID {
+		id := graphql.ID(iNew.Studio.ID)
+		i.StudioID = &id
+	}
+	if !tagListsEqual(iOld.Tags, iNew.Tags) {
+		tagIDs := make([]graphql.ID, len(iNew.Tags))
+		for j, t := range iNew.Tags {
+			tagIDs[j] = graphql.ID(t.ID)
+		}
+		i.TagIDs = &tagIDs
+	}
+	if !performerListsEqual(iOld.Performers, iNew.Performers) {
+		performerIDs := make([]graphql.ID, len(iNew.Performers))
+		for j, p := range iNew.Performers {
+			performerIDs[j] = graphql.ID(p.ID)
+		}
+		i.PerformerIDs = performerIDs
+	}
+
+	return i
+}
+
+func (s *stash) ImageUpdate(ctx context.Context, i ImageUpdate) (Image, error) {
+	var m struct {
+		ImageUpdate Image `graphql:"imageUpdate(input: $input)"`
+	}
+	err := s.client.Mutate(ctx, &m, map[string]any{"input": i})
+	return m.ImageUpdate, err
+}
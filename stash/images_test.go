@@ -0,0 +1,79 @@
+package stash
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hasura/go-graphql-client"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImages(t *testing.T) {
+	doer := &captureEndpoint{
+		t: t,
+		response: `{"data": {"findImages": {"count": 12, "images": [{
+			"id": "1",
+			"title": "",
+			"rating100": 60,
+			"o_counter": 2,
+			"organized": false,
+			"files": [{"path": "/gallery/001.jpg", "size": 2048, "width": 1920, "height": 1080}],
+			"created_at": "2023-07-01T00:00:00Z",
+			"updated_at": "2023-07-18T00:00:00Z",
+			"studio": null,
+			"tags": [],
+			"performers": []
+		}]}}}`,
+	}
+	client := graphql.NewClient("https://example.com/graph", doer)
+	s := stash{client}
+
+	images, count, err := s.Images(context.Background(), FindFilter{}, ImageFilter{
+		Galleries: &MultiCriterion{Value: []string{"5"}, Modifier: CriterionModifierIncludes},
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, 12, count)
+	require.Len(t, images, 1)
+	require.Equal(t, "/gallery/001.jpg", images[0].FilePath())
+	require.Equal(t, ImageFile{Path: "/gallery/001.jpg", Size: 2048, Width: 1920, Height: 1080}, images[0].Files[0])
+	require.Equal(t, 2, images[0].OCounter)
+	require.Contains(t, doer.body, `findImages(filter: $filter, image_filter: $image_filter)`)
+	require.Contains(t, doer.body, `"galleries":{"value":["5"],"modifier":"INCLUDES"}`)
+}
+
+func TestImageDelete(t *testing.T) {
+	doer := &captureEndpoint{t: t, response: `{"data": {"imageDestroy": true}}`}
+	client := graphql.NewClient("https://example.com/graph", doer)
+	s := stash{client}
+
+	ok, err := s.ImageDelete(context.Background(), "7")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Contains(t, doer.body, `imageDestroy(input: {id: $id, delete_file: true, delete_generated: true})`)
+}
+
+func TestImageResetO(t *testing.T) {
+	doer := &captureEndpoint{t: t, response: `{"data": {"imageResetO": 0}}`}
+	client := graphql.NewClient("https://example.com/graph", doer)
+	s := stash{client}
+
+	count, err := s.ImageResetO(context.Background(), "7")
+	require.NoError(t, err)
+	require.Equal(t, 0, count)
+	require.Contains(t, doer.body, `imageResetO(id: $id)`)
+}
+
+func TestNewImageUpdate(t *testing.T) {
+	old := Image{ID: "1", Rating: 20, Tags: []Tag{{ID: "a"}}}
+	updated := old
+	updated.Rating = 80
+	updated.Tags = []Tag{{ID: "a"}, {ID: "b"}}
+
+	update := NewImageUpdate(old, updated)
+	require.Equal(t, ImageUpdate{
+		ID:     graphql.ID("1"),
+		Rating: ptr(80),
+		TagIDs: &[]graphql.ID{"a", "b"},
+	}, update)
+}
@@ -0,0 +1,182 @@
+package stash
+
+import (
+	"context"
+	"time"
+
+	"github.com/hasura/go-graphql-client"
+)
+
+type JobStatus string
+
+const (
+	JobStatusReady     JobStatus = "READY"
+	JobStatusRunning   JobStatus = "RUNNING"
+	JobStatusFinished  JobStatus = "FINISHED"
+	JobStatusStopping  JobStatus = "STOPPING"
+	JobStatusCancelled JobStatus = "CANCELLED"
+)
+
+// Job is a task running in the stash job queue, such as a scan.  Progress is between 0 and 1, and is nil when the job
+// cannot report it.
+type Job struct {
+	ID          string     `graphql:"id"`
+	Status      JobStatus  `graphql:"status"`
+	SubTasks    []string   `graphql:"subTasks"`
+	Description string     `graphql:"description"`
+	Progress    *float64   `graphql:"progress"`
+	StartTime   *time.Time `graphql:"startTime"`
+	EndTime     *time.Time `graphql:"endTime"`
+	AddTime     time.Time  `graphql:"addTime"`
+}
+
+// Done returns true if the job has finished or been cancelled.
+func (j Job) Done() bool {
+	return j.Status == JobStatusFinished || j.Status == JobStatusCancelled
+}
+
+// ScanMetadata scans Paths for new and changed files, or the whole library if no paths are given.
+type ScanMetadata struct {
+	Paths []string `json:"paths,omitempty"`
+}
+
+func (ScanMetadata) GetGraphQLType() string {
+	return "ScanMetadataInput"
+}
+
+// GenerateMetadata generates the selected content for the scenes in SceneIDs, or for all scenes if none are given.
+type GenerateMetadata struct {
+	Covers    bool         `json:"covers,omitempty"`
+	Sprites   bool         `json:"sprites,omitempty"`
+	Previews  bool         `json:"previews,omitempty"`
+	Markers   bool         `json:"markers,omitempty"`
+	Phashes   bool         `json:"phashes,omitempty"`
+	SceneIDs  []graphql.ID `json:"sceneIDs,omitempty"`
+	Overwrite bool         `json:"overwrite,omitempty"`
+}
+
+func (GenerateMetadata) GetGraphQLType() string {
+	return "GenerateMetadataInput"
+}
+
+// AutoTagMetadata tags files in Paths, or all files if no paths are given, with performers, studios and tags whose
+// names appear in their paths.  Each of Performers, Studios and Tags is a list of IDs to match, or "*" for all.
+type AutoTagMetadata struct {
+	Paths      []string `json:"paths,omitempty"`
+	Performers []string `json:"performers,omitempty"`
+	Studios    []string `json:"studios,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+}
+
+func (AutoTagMetadata) GetGraphQLType() string {
+	return "AutoTagMetadataInput"
+}
+
+// IdentifyMetadata identifies scenes using each of Sources in order, until one finds a match.  Scenes are selected by
+// SceneIDs, or by Paths if no IDs are given.
+type IdentifyMetadata struct {
+	Sources  []IdentifySource `json:"sources"`
+	SceneIDs []graphql.ID     `json:"sceneIDs,omitempty"`
+	Paths    []string         `json:"paths,omitempty"`
+}
+
+func (IdentifyMetadata) GetGraphQLType() string {
+	return "IdentifyMetadataInput"
+}
+
+type IdentifySource struct {
+	Source ScraperSource `json:"source"`
+}
+
+// ScraperSource is either a stash-box endpoint or the ID of a configured scraper.
+type ScraperSource struct {
+	StashBoxEndpoint *string `json:"stash_box_endpoint,omitempty"`
+	ScraperID        *string `json:"scraper_id,omitempty"`
+}
+
+// CleanMetadata removes content whose files no longer exist in Paths, or the whole library if no paths are given.
+// DryRun only logs what would be removed.
+type CleanMetadata struct {
+	Paths  []string `json:"paths,omitempty"`
+	DryRun bool     `json:"dryRun"`
+}
+
+func (CleanMetadata) GetGraphQLType() string {
+	return "CleanMetadataInput"
+}
+
+// MetadataScan starts a scan job, returning the ID of the job.
+func (s *stash) MetadataScan(ctx context.Context, input ScanMetadata) (string, error) {
+	var m struct {
+		ID string `graphql:"metadataScan(input: $input)"`
+	}
+	err := s.client.Mutate(ctx, &m, map[string]any{"input": input})
+	return m.ID, err
+}
+
+// MetadataGenerate starts a generate job, returning the ID of the job.
+func (s *stash) MetadataGenerate(ctx context.Context, input GenerateMetadata) (string, error) {
+	var m struct {
+		ID string `graphql:"metadataGenerate(input: $input)"`
+	}
+	err := s.client.Mutate(ctx, &m, map[string]any{"input": input})
+	return m.ID, err
+}
+
+// MetadataAutoTag starts an auto-tag job, returning the ID of the job.
+func (s *stash) MetadataAutoTag(ctx context.Context, input AutoTagMetadata) (string, error) {
+	var m struct {
+		ID string `graphql:"metadataAutoTag(input: $input)"`
+	}
+	err := s.client.Mutate(ctx, &m, map[string]any{"input": input})
+	return m.ID, err
+}
+
+// MetadataIdentify starts an identify job, returning the ID of the job.
+func (s *stash) MetadataIdentify(ctx context.Context, input IdentifyMetadata) (string, error) {
+	var m struct {
+		ID string `graphql:"metadataIdentify(input: $input)"`
+	}
+	err := s.client.Mutate(ctx, &m, map[string]any{"input": input})
+	return m.ID, err
+}
+
+// MetadataClean starts a clean job, returning the ID of the job.
+func (s *stash) MetadataClean(ctx context.Context, input CleanMetadata) (string, error) {
+	var m struct {
+		ID string `graphql:"metadataClean(input: $input)"`
+	}
+	err := s.client.Mutate(ctx, &m, map[string]any{"input": input})
+	return m.ID, err
+}
+
+// JobQueue returns the jobs that are queued or running.
+func (s *stash) JobQueue(ctx context.Context) ([]Job, error) {
+	var q struct {
+		JobQueue []Job `graphql:"jobQueue"`
+	}
+	err := s.client.Query(ctx, &q, nil)
+	return q.JobQueue, err
+}
+
+// FindJob returns a job by ID.  Finished jobs can be found for a short time after they leave the queue, after which
+// false is returned.
+func (s *stash) FindJob(ctx context.Context, id string) (Job, bool, error) {
+	var q struct {
+		FindJob *Job `graphql:"findJob(input: {id: $id})"`
+	}
+	err := s.client.Query(ctx, &q, map[string]any{"id": graphql.ID(id)})
+	if err != nil || q.FindJob == nil {
+		return Job{}, false, err
+	}
+	return *q.FindJob, true, nil
+}
+
+// StopJob requests that a job be stopped.  Running jobs move to the STOPPING status until they have cleaned up.
+func (s *stash) StopJob(ctx context.Context, id string) (bool, error) {
+	var m struct {
+		Result bool `graphql:"stopJob(job_id: $job_id)"`
+	}
+	err := s.client.Mutate(ctx, &m, map[string]any{"job_id": graphql.ID(id)})
+	return m.Result, err
+}
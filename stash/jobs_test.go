@@ -0,0 +1,117 @@
+package stash
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hasura/go-graphql-client"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetadataScan(t *testing.T) {
+	doer := &captureEndpoint{
+		t:        t,
+		response: `{"data": {"metadataScan": "12"}}`,
+	}
+	client := graphql.NewClient("https://example.com/graph", doer)
+	s := stash{client}
+
+	id, err := s.MetadataScan(context.Background(), ScanMetadata{Paths: []string{"/library/new"}})
+
+	require.NoError(t, err)
+	require.Equal(t, "12", id)
+	require.Contains(t, doer.body, `$input:ScanMetadataInput!`)
+	require.Contains(t, doer.body, `"input":{"paths":["/library/new"]}`)
+}
+
+func TestMetadataIdentify(t *testing.T) {
+	doer := &captureEndpoint{
+		t:        t,
+		response: `{"data": {"metadataIdentify": "13"}}`,
+	}
+	client := graphql.NewClient("https://example.com/graph", doer)
+	s := stash{client}
+
+	endpoint := "https://stashdb.org/graphql"
+	id, err := s.MetadataIdentify(context.Background(), IdentifyMetadata{
+		Sources:  []IdentifySource{{Source: ScraperSource{StashBoxEndpoint: &endpoint}}},
+		SceneIDs: []graphql.ID{"1"},
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, "13", id)
+	require.Contains(t, doer.body, `$input:IdentifyMetadataInput!`)
+	require.Contains(t, doer.body, `"input":{"sources":[{"source":{"stash_box_endpoint":"https://stashdb.org/graphql"}}],"sceneIDs":["1"]}`)
+}
+
+func TestMetadataClean(t *testing.T) {
+	doer := &captureEndpoint{
+		t:        t,
+		response: `{"data": {"metadataClean": "14"}}`,
+	}
+	client := graphql.NewClient("https://example.com/graph", doer)
+	s := stash{client}
+
+	_, err := s.MetadataClean(context.Background(), CleanMetadata{})
+
+	require.NoError(t, err)
+	require.Contains(t, doer.body, `"input":{"dryRun":false}`)
+}
+
+func TestJobQueue(t *testing.T) {
+	doer := &captureEndpoint{
+		t: t,
+		response: `{"data": {"jobQueue": [{
+			"id": "12",
+			"status": "RUNNING",
+			"subTasks": ["Scanning /library/new/a.mp4"],
+			"description": "Scanning...",
+			"progress": 0.25,
+			"startTime": "2024-07-01T10:00:00Z",
+			"endTime": null,
+			"addTime": "2024-07-01T09:59:59Z"
+		}]}}`,
+	}
+	client := graphql.NewClient("https://example.com/graph", doer)
+	s := stash{client}
+
+	jobs, err := s.JobQueue(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	require.Equal(t, JobStatusRunning, jobs[0].Status)
+	require.Equal(t, 0.25, *jobs[0].Progress)
+	require.Nil(t, jobs[0].EndTime)
+	require.False(t, jobs[0].Done())
+}
+
+func TestFindJob(t *testing.T) {
+	doer := &captureEndpoint{
+		t:        t,
+		response: `{"data": {"findJob": null}}`,
+	}
+	client := graphql.NewClient("https://example.com/graph", doer)
+	s := stash{client}
+
+	_, ok, err := s.FindJob(context.Background(), "12")
+
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Contains(t, doer.body, `findJob(input: {id: $id})`)
+	require.Contains(t, doer.body, `"id":"12"`)
+}
+
+func TestStopJob(t *testing.T) {
+	doer := &captureEndpoint{
+		t:        t,
+		response: `{"data": {"stopJob": true}}`,
+	}
+	client := graphql.NewClient("https://example.com/graph", doer)
+	s := stash{client}
+
+	ok, err := s.StopJob(context.Background(), "12")
+
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Contains(t, doer.body, `$job_id:ID!`)
+}
@@ -1,152 +1,764 @@
 package stash
 
 import (
+	"cmp"
 	"context"
-	"hash/fnv"
-	"io/fs"
+	"errors"
+	"fmt"
 	"math/rand"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 )
 
-// LocalStash is a local backend for the application that can be used to browse local files.
+// LocalStash is a local backend for the application that can be used to browse local files.  Scenes and galleries
+// can be given titles, ratings and tags, which are stored in a metadata database rather than alongside the files.
+// Deleted files are moved to the trash, and can be restored from it.
 type LocalStash struct {
-	root string
+	mu    sync.Mutex
+	root  string
+	trash trash
 
 	scenes    []Scene
 	galleries []Gallery
+	index     localIndex
+	indexPath string
+
+	metadata     localMetadata
+	metadataPath string
 }
 
-func NewLocalStash(root string) *LocalStash {
+// NewLocalStash opens the folder at root, listing what was found when it was last scanned until Scan is called.  Its
+// metadata and index are stored in stateDir, or only kept in memory if stateDir is empty.
+func NewLocalStash(root, stateDir string) (*LocalStash, error) {
+	// The root is made absolute so that files can be found again when restored from the trash.
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
 	s := &LocalStash{root: root}
+	if s.trash, err = homeTrash(); err != nil {
+		return nil, err
+	}
+	if stateDir != "" {
+		s.metadataPath = localStatePath(stateDir, root, ".json")
+		s.indexPath = localStatePath(stateDir, root, ".index.json")
+	}
+	if s.metadata, err = loadLocalMetadata(s.metadataPath); err != nil {
+		return nil, err
+	}
+	s.index = loadLocalIndex(s.indexPath)
+	s.build()
+	return s, nil
+}
 
-	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return nil // skip bad paths
+func (s *LocalStash) Scenes(_ context.Context, f FindFilter, sf SceneFilter) ([]Scene, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := criteria{}
+	var scenes []Scene
+	for _, found := range s.scenes {
+		scene := s.scene(found)
+		if matchesQuery(f.Query, scene.Title, scene.FilePath()) && c.localScene(scene, sf) {
+			scenes = append(scenes, scene)
 		}
-
-		if !d.IsDir() {
-			ext := strings.ToLower(filepath.Ext(d.Name()))
-			switch ext {
-			case ".mp4", ".mkv", ".mov", ".avi":
-				s.scenes = append(s.scenes, Scene{Files: []VideoFile{{Path: path}}})
-			case ".zip", ".rar", ".pdf":
-				s.galleries = append(s.galleries, Gallery{Folder: Folder{Path: path}})
-			}
-			return nil
+		if c.err != nil {
+			return nil, 0, c.err
 		}
+	}
+	if err := sortEntities(scenes, f, localSceneSortKeys); err != nil {
+		return nil, 0, err
+	}
+	return page(scenes, f), len(scenes), nil
+}
 
-		if path == root {
-			return nil
-		}
+func (s *LocalStash) DeleteScene(ctx context.Context, id string) (bool, error) {
+	return s.ScenesDestroy(ctx, []string{id})
+}
 
-		// If directory: check for image gallery
-		entries, err := os.ReadDir(path)
-		if err != nil {
-			return nil
+func (s *LocalStash) SceneUpdate(_ context.Context, u SceneUpdate) (Scene, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := indexOf(s.scenes, string(u.ID))
+	if i < 0 {
+		return Scene{}, fmt.Errorf("scene %s not found", u.ID)
+	}
+	switch {
+	case u.Code != nil:
+		return Scene{}, localUnsupported("code")
+	case u.Director != nil:
+		return Scene{}, localUnsupported("director")
+	case u.URLs != nil:
+		return Scene{}, localUnsupported("urls")
+	case u.StudioID != nil:
+		return Scene{}, localUnsupported("studio")
+	case u.GalleryIDs != nil:
+		return Scene{}, localUnsupported("galleries")
+	case u.PerformerIDs != nil:
+		return Scene{}, localUnsupported("performers")
+	case u.Movies != nil:
+		return Scene{}, localUnsupported("movies")
+	}
+
+	entry := s.metadata.Scenes[string(u.ID)]
+	err := s.applyLocalUpdate(&entry, localUpdate{
+		Title:     u.Title,
+		Details:   u.Details,
+		Date:      u.Date,
+		Rating:    u.Rating,
+		Organized: u.Organized,
+		TagIDs:    u.TagIDs,
+	})
+	if err != nil {
+		return Scene{}, err
+	}
+	if err := s.update(&s.metadata.Scenes, map[string]localEntry{string(u.ID): entry}); err != nil {
+		return Scene{}, err
+	}
+	return s.scene(s.scenes[i]), nil
+}
+
+func (s *LocalStash) BulkSceneUpdate(_ context.Context, u BulkSceneUpdate) ([]Scene, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case u.StudioID != nil:
+		return nil, localUnsupported("studio")
+	case u.PerformerIDs != nil:
+		return nil, localUnsupported("performers")
+	}
+	changes := make(map[string]localEntry, len(u.IDs))
+	for _, id := range u.IDs {
+		if indexOf(s.scenes, string(id)) < 0 {
+			return nil, fmt.Errorf("scene %s not found", id)
+		}
+		entry := s.metadata.Scenes[string(id)]
+		if err := s.applyLocalUpdate(&entry, localUpdate{Date: u.Date, Rating: u.Rating, Organized: u.Organized}); err != nil {
+			return nil, err
 		}
+		if err := s.applyLocalBulkTags(&entry, u.TagIDs); err != nil {
+			return nil, err
+		}
+		changes[string(id)] = entry
+	}
+	if err := s.update(&s.metadata.Scenes, changes); err != nil {
+		return nil, err
+	}
 
-		hasImage := false
-		hasSubdir := false
+	scenes := []Scene{}
+	for _, id := range u.IDs {
+		scenes = append(scenes, s.scene(s.scenes[indexOf(s.scenes, string(id))]))
+	}
+	return scenes, nil
+}
+
+// ScenesDestroy moves the files of scenes to the trash.  Their metadata is kept, so that it is restored with them.
+func (s *LocalStash) ScenesDestroy(_ context.Context, ids []string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-		for _, entry := range entries {
-			if entry.IsDir() {
-				hasSubdir = true
-				break
-			}
-			if isImageFile(entry.Name()) {
-				hasImage = true
-			}
+	for _, id := range ids {
+		i := indexOf(s.scenes, id)
+		if i < 0 {
+			return false, fmt.Errorf("scene %s not found", id)
+		}
+		if _, err := s.trash.put(s.scenes[i].FilePath()); err != nil {
+			return false, err
 		}
+		s.scenes = slices.Delete(s.scenes, i, i+1)
+	}
+	return true, nil
+}
+
+func (s *LocalStash) SceneMerge(context.Context, SceneMerge) (Scene, error) {
+	return Scene{}, localNotSupported("merging scenes")
+}
 
-		if hasImage && !hasSubdir {
-			s.galleries = append(s.galleries, Gallery{Folder: Folder{Path: path}})
-			return fs.SkipDir // don’t walk deeper
+// SceneStreams is not supported, as local files are opened from their path rather than streamed from stash.
+func (s *LocalStash) SceneStreams(context.Context, string) ([]SceneStreamEndpoint, error) {
+	return nil, localNotSupported("streaming")
+}
+
+// FindDuplicateScenes is not supported, as duplicates are found by the perceptual hashes that stash generates.
+func (s *LocalStash) FindDuplicateScenes(context.Context, int, float64) ([][]Scene, error) {
+	return nil, localNotSupported("finding duplicate scenes")
+}
+
+// RecordPlay is a noop as play activity is not tracked for local files.
+func (s *LocalStash) RecordPlay(context.Context, string) (int, error) {
+	return 0, nil
+}
+
+// SceneSaveActivity is a noop as play activity is not tracked for local files.
+func (s *LocalStash) SceneSaveActivity(context.Context, string, *float64, *float64) (bool, error) {
+	return false, nil
+}
+
+// The o-counter is not tracked for local files.
+
+func (s *LocalStash) SceneIncrementO(context.Context, string) (int, error) {
+	return 0, localNotSupported("o-counter")
+}
+
+func (s *LocalStash) SceneDecrementO(context.Context, string) (int, error) {
+	return 0, localNotSupported("o-counter")
+}
+
+func (s *LocalStash) SceneResetO(context.Context, string) (int, error) {
+	return 0, localNotSupported("o-counter")
+}
+
+// Local files have no scene markers.
+func (s *LocalStash) SceneMarkers(context.Context, FindFilter, SceneMarkerFilter) ([]SceneMarker, int, error) {
+	return nil, 0, localNotSupported("listing scene markers")
+}
+
+func (s *LocalStash) Galleries(_ context.Context, f FindFilter, gf GalleryFilter) ([]Gallery, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := criteria{}
+	var galleries []Gallery
+	for _, found := range s.galleries {
+		gallery := s.gallery(found)
+		if matchesQuery(f.Query, gallery.Title, gallery.FilePath()) && c.localGallery(gallery, gf) {
+			galleries = append(galleries, gallery)
 		}
+		if c.err != nil {
+			return nil, 0, c.err
+		}
+	}
+	if err := sortEntities(galleries, f, localGallerySortKeys); err != nil {
+		return nil, 0, err
+	}
+	return page(galleries, f), len(galleries), nil
+}
+
+func (s *LocalStash) GalleryDelete(ctx context.Context, id string) (bool, error) {
+	return s.GalleriesDestroy(ctx, []string{id})
+}
+
+func (s *LocalStash) GalleryUpdate(_ context.Context, u GalleryUpdate) (Gallery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-		return nil
+	i := indexOf(s.galleries, string(u.ID))
+	if i < 0 {
+		return Gallery{}, fmt.Errorf("gallery %s not found", u.ID)
+	}
+	switch {
+	case u.URL != nil:
+		return Gallery{}, localUnsupported("url")
+	case u.SceneIDs != nil:
+		return Gallery{}, localUnsupported("scenes")
+	case u.StudioID != nil:
+		return Gallery{}, localUnsupported("studio")
+	case u.PerformerIDs != nil:
+		return Gallery{}, localUnsupported("performers")
+	case u.PrimaryFileID != nil:
+		return Gallery{}, localUnsupported("primary file")
+	}
+
+	entry := s.metadata.Galleries[string(u.ID)]
+	err := s.applyLocalUpdate(&entry, localUpdate{
+		Title:     u.Title,
+		Details:   u.Details,
+		Date:      u.Date,
+		Rating:    u.Rating,
+		Organized: u.Organized,
+		TagIDs:    u.TagIDs,
 	})
+	if err != nil {
+		return Gallery{}, err
+	}
+	if err := s.update(&s.metadata.Galleries, map[string]localEntry{string(u.ID): entry}); err != nil {
+		return Gallery{}, err
+	}
+	return s.gallery(s.galleries[i]), nil
+}
+
+func (s *LocalStash) BulkGalleryUpdate(_ context.Context, u BulkGalleryUpdate) ([]Gallery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	return s
+	switch {
+	case u.StudioID != nil:
+		return nil, localUnsupported("studio")
+	case u.PerformerIDs != nil:
+		return nil, localUnsupported("performers")
+	}
+	changes := make(map[string]localEntry, len(u.IDs))
+	for _, id := range u.IDs {
+		if indexOf(s.galleries, string(id)) < 0 {
+			return nil, fmt.Errorf("gallery %s not found", id)
+		}
+		entry := s.metadata.Galleries[string(id)]
+		if err := s.applyLocalUpdate(&entry, localUpdate{Date: u.Date, Rating: u.Rating, Organized: u.Organized}); err != nil {
+			return nil, err
+		}
+		if err := s.applyLocalBulkTags(&entry, u.TagIDs); err != nil {
+			return nil, err
+		}
+		changes[string(id)] = entry
+	}
+	if err := s.update(&s.metadata.Galleries, changes); err != nil {
+		return nil, err
+	}
+
+	galleries := []Gallery{}
+	for _, id := range u.IDs {
+		galleries = append(galleries, s.gallery(s.galleries[indexOf(s.galleries, string(id))]))
+	}
+	return galleries, nil
 }
 
-func isImageFile(name string) bool {
-	ext := strings.ToLower(filepath.Ext(name))
-	switch ext {
-	case ".jpg", ".jpeg", ".png", ".gif", ".bmp", ".webp":
-		return true
-	default:
-		return false
+// GalleriesDestroy moves galleries to the trash, which for a folder of images is the whole folder.
+func (s *LocalStash) GalleriesDestroy(_ context.Context, ids []string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, id := range ids {
+		i := indexOf(s.galleries, id)
+		if i < 0 {
+			return false, fmt.Errorf("gallery %s not found", id)
+		}
+		if _, err := s.trash.put(s.galleries[i].Folder.Path); err != nil {
+			return false, err
+		}
+		s.galleries = slices.Delete(s.galleries, i, i+1)
 	}
+	return true, nil
 }
 
-func (s *LocalStash) Scenes(_ context.Context, f FindFilter, sf SceneFilter) ([]Scene, int, error) {
-	scenes := s.scenes
-	if strings.HasPrefix(f.Sort, SortRandomPrefix) {
-		h := fnv.New64a()
-		h.Write([]byte(f.Sort))
-		scenes = shuffleSeeded(scenes, int64(h.Sum64()))
+// Restore moves the scene or gallery most recently deleted from the folder out of the trash, and returns its path.
+// Items deleted from the folder by other applications are restored too.
+func (s *LocalStash) Restore(context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items, err := s.trash.list()
+	if err != nil {
+		return "", err
+	}
+	items = slices.DeleteFunc(items, func(item trashed) bool {
+		return !strings.HasPrefix(item.path, s.root+string(filepath.Separator))
+	})
+	if len(items) == 0 {
+		return "", errors.New("nothing to restore")
+	}
+	item := slices.MaxFunc(items, func(a, b trashed) int {
+		return cmp.Or(a.deleted.Compare(b.deleted), a.modified.Compare(b.modified))
+	})
+	if err := s.trash.restore(item); err != nil {
+		return "", err
 	}
-	return paginate(scenes, f.Page, f.PerPage), len(s.scenes), nil
+	if info, err := os.Stat(item.path); err == nil {
+		s.add(item.path, info)
+	}
+	return item.path, nil
 }
 
-func (s *LocalStash) DeleteScene(context.Context, string) (bool, error) {
-	panic("not implemented")
+// Local files have no movies.
+func (s *LocalStash) Movies(context.Context, FindFilter, MovieFilter) ([]MovieDetail, int, error) {
+	return nil, 0, localNotSupported("listing movies")
 }
 
-func (s *LocalStash) SceneUpdate(context.Context, SceneUpdate) (Scene, error) {
-	panic("not implemented")
+func (s *LocalStash) MovieCreate(context.Context, MovieCreate) (MovieDetail, error) {
+	return MovieDetail{}, localNotSupported("creating movies")
 }
 
-func (s *LocalStash) Galleries(_ context.Context, f FindFilter, gf GalleryFilter) ([]Gallery, int, error) {
-	galleries := s.galleries
-	if strings.HasPrefix(f.Sort, SortRandomPrefix) {
-		h := fnv.New64a()
-		h.Write([]byte(f.Sort))
-		galleries = shuffleSeeded(galleries, int64(h.Sum64()))
-	}
-	return paginate(galleries, f.Page, f.PerPage), len(s.galleries), nil
+func (s *LocalStash) MovieUpdate(context.Context, MovieUpdate) (MovieDetail, error) {
+	return MovieDetail{}, localNotSupported("editing movies")
+}
+
+// Images of local galleries are opened with the gallery rather than listed.
+func (s *LocalStash) Images(context.Context, FindFilter, ImageFilter) ([]Image, int, error) {
+	return nil, 0, localNotSupported("listing images")
+}
+
+func (s *LocalStash) ImageDelete(context.Context, string) (bool, error) {
+	return false, localNotSupported("deleting images")
+}
+
+func (s *LocalStash) ImageUpdate(context.Context, ImageUpdate) (Image, error) {
+	return Image{}, localNotSupported("editing images")
+}
+
+func (s *LocalStash) ImageIncrementO(context.Context, string) (int, error) {
+	return 0, localNotSupported("o-counter")
+}
+
+func (s *LocalStash) ImageDecrementO(context.Context, string) (int, error) {
+	return 0, localNotSupported("o-counter")
 }
 
-func (s *LocalStash) GalleryDelete(context.Context, string) (bool, error) {
-	panic("not implemented")
+func (s *LocalStash) ImageResetO(context.Context, string) (int, error) {
+	return 0, localNotSupported("o-counter")
 }
 
-func (s *LocalStash) GalleryUpdate(context.Context, GalleryUpdate) (Gallery, error) {
-	panic("not implemented")
+// Local files have no performers.
+func (s *LocalStash) Performers(context.Context, FindFilter, PerformerFilter) ([]Performer, int, error) {
+	return nil, 0, localNotSupported("listing performers")
 }
 
 func (s *LocalStash) PerformersAll(context.Context) ([]PerformerSummary, error) {
-	panic("not implemented")
+	return nil, localNotSupported("listing performers")
 }
 
 func (s *LocalStash) PerformerCreate(context.Context, PerformerCreate) (Performer, error) {
-	panic("not implemented")
+	return Performer{}, localNotSupported("creating performers")
+}
+
+func (s *LocalStash) PerformerUpdate(context.Context, PerformerUpdate) (Performer, error) {
+	return Performer{}, localNotSupported("editing performers")
 }
 
 func (s *LocalStash) PerformerGet(context.Context, string) (Performer, error) {
-	panic("not implemented")
+	return Performer{}, localNotSupported("finding performers")
+}
+
+// Local files have no studios.
+func (s *LocalStash) Studios(context.Context, FindFilter, StudioFilter) ([]StudioDetail, int, error) {
+	return nil, 0, localNotSupported("listing studios")
 }
 
 func (s *LocalStash) StudiosAll(context.Context) ([]Studio, error) {
-	panic("not implemented")
+	return nil, localNotSupported("listing studios")
 }
 
-func (s *LocalStash) TagGet(context.Context, string) (Tag, error) {
-	panic("not implemented")
+func (s *LocalStash) TagGet(_ context.Context, id string) (Tag, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := indexOf(s.metadata.Tags, id)
+	if i < 0 {
+		return Tag{}, fmt.Errorf("%w: %s", ErrTagNotFound, id)
+	}
+	return s.metadata.Tags[i], nil
 }
 
-func (s *LocalStash) TagCreate(context.Context, TagCreate) (Tag, error) {
-	panic("not implemented")
+func (s *LocalStash) TagCreate(_ context.Context, c TagCreate) (Tag, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkTagName("", c.Name); err != nil {
+		return Tag{}, err
+	}
+	tag := Tag{ID: s.nextTagID(), Name: c.Name}
+	s.metadata.Tags = append(s.metadata.Tags, tag)
+	if err := writeJSON(s.metadataPath, s.metadata, ".metadata-*.json"); err != nil {
+		s.metadata.Tags = s.metadata.Tags[:len(s.metadata.Tags)-1]
+		return Tag{}, fmt.Errorf("unable to save local metadata: %w", err)
+	}
+	return tag, nil
+}
+
+func (s *LocalStash) checkTagName(id, name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("tag name must not be blank")
+	}
+	for _, tag := range s.metadata.Tags {
+		if tag.ID != id && strings.EqualFold(tag.Name, name) {
+			return fmt.Errorf("tag with name '%s' already exists", name)
+		}
+	}
+	return nil
 }
 
-func (s *LocalStash) TagFindByName(context.Context, string) (Tag, error) {
-	panic("not implemented")
+func (s *LocalStash) TagFindByName(_ context.Context, name string) (Tag, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, tag := range s.metadata.Tags {
+		if strings.EqualFold(tag.Name, name) {
+			return tag, nil
+		}
+	}
+	return Tag{}, fmt.Errorf("%w: %s", ErrTagNotFound, name)
 }
 
 func (s *LocalStash) TagsAll(context.Context) ([]Tag, error) {
-	panic("not implemented")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.metadata.Tags), nil
+}
+
+// Tags lists the tags given to local files.  Local tags have no aliases or hierarchy, so only their names and counts
+// can be filtered on.
+func (s *LocalStash) Tags(_ context.Context, f FindFilter, tf TagFilter) ([]TagDetail, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := criteria{}
+	var tags []TagDetail
+	for _, t := range s.metadata.Tags {
+		tag := s.tag(t)
+		if matchesQuery(f.Query, tag.Name) && c.localTag(tag, tf) {
+			tags = append(tags, tag)
+		}
+		if c.err != nil {
+			return nil, 0, c.err
+		}
+	}
+	if err := sortEntities(tags, f, tagSortKeys); err != nil {
+		return nil, 0, err
+	}
+	return page(tags, f), len(tags), nil
+}
+
+// tag returns the detail of a tag with counts of the scenes and galleries in the folder given it.
+func (s *LocalStash) tag(t Tag) TagDetail {
+	tag := TagDetail{ID: t.ID, Name: t.Name}
+	for _, scene := range s.scenes {
+		if slices.Contains(s.metadata.Scenes[scene.ID].Tags, t.ID) {
+			tag.SceneCount++
+		}
+	}
+	for _, gallery := range s.galleries {
+		if slices.Contains(s.metadata.Galleries[gallery.ID].Tags, t.ID) {
+			tag.GalleryCount++
+		}
+	}
+	return tag
+}
+
+// TagUpdate renames a tag.  Local tags have no description, aliases or parents.
+func (s *LocalStash) TagUpdate(_ context.Context, u TagUpdate) (TagDetail, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := indexOf(s.metadata.Tags, string(u.ID))
+	if i < 0 {
+		return TagDetail{}, fmt.Errorf("%w: %s", ErrTagNotFound, u.ID)
+	}
+	switch {
+	case u.Description != nil:
+		return TagDetail{}, localUnsupported("description")
+	case u.Aliases != nil:
+		return TagDetail{}, localUnsupported("aliases")
+	case u.ParentIDs != nil:
+		return TagDetail{}, localUnsupported("parents")
+	}
+	if u.Name != nil {
+		if err := s.checkTagName(string(u.ID), *u.Name); err != nil {
+			return TagDetail{}, err
+		}
+		err := s.save(func(m *localMetadata) {
+			m.Tags[i].Name = *u.Name
+		})
+		if err != nil {
+			return TagDetail{}, err
+		}
+	}
+	return s.tag(s.metadata.Tags[i]), nil
+}
+
+// TagsMerge retags files tagged with a source tag with the destination, and removes the source tags.  Local tags have
+// no aliases, so the names of the source tags are not kept.
+func (s *LocalStash) TagsMerge(_ context.Context, merge TagsMerge) (TagDetail, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := indexOf(s.metadata.Tags, string(merge.Destination))
+	if i < 0 {
+		return TagDetail{}, fmt.Errorf("%w: %s", ErrTagNotFound, merge.Destination)
+	}
+	destination := s.metadata.Tags[i]
+	var sources []string
+	for _, id := range merge.Source {
+		if !containsID(s.metadata.Tags, string(id)) {
+			return TagDetail{}, fmt.Errorf("%w: %s", ErrTagNotFound, id)
+		}
+		if id != merge.Destination {
+			sources = append(sources, string(id))
+		}
+	}
+	if err := s.save(func(m *localMetadata) { m.retagAll(sources, destination.ID) }); err != nil {
+		return TagDetail{}, err
+	}
+	return s.tag(destination), nil
+}
+
+// TagDelete removes a tag from all files.
+func (s *LocalStash) TagDelete(_ context.Context, id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !containsID(s.metadata.Tags, id) {
+		return false, fmt.Errorf("%w: %s", ErrTagNotFound, id)
+	}
+	if err := s.save(func(m *localMetadata) { m.retagAll([]string{id}, "") }); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Tasks are run by a stash server, and local files have none.  Local folders are indexed by Scan instead.
+func (s *LocalStash) MetadataScan(context.Context, ScanMetadata) (string, error) {
+	return "", localNotSupported("scanning")
+}
+
+func (s *LocalStash) MetadataGenerate(context.Context, GenerateMetadata) (string, error) {
+	return "", localNotSupported("generating")
+}
+
+func (s *LocalStash) MetadataAutoTag(context.Context, AutoTagMetadata) (string, error) {
+	return "", localNotSupported("auto tagging")
+}
+
+func (s *LocalStash) MetadataIdentify(context.Context, IdentifyMetadata) (string, error) {
+	return "", localNotSupported("identifying")
+}
+
+func (s *LocalStash) MetadataClean(context.Context, CleanMetadata) (string, error) {
+	return "", localNotSupported("cleaning")
+}
+
+func (s *LocalStash) JobQueue(context.Context) ([]Job, error) {
+	return nil, localNotSupported("the job queue")
+}
+
+func (s *LocalStash) FindJob(context.Context, string) (Job, bool, error) {
+	return Job{}, false, localNotSupported("the job queue")
+}
+
+func (s *LocalStash) StopJob(context.Context, string) (bool, error) {
+	return false, localNotSupported("the job queue")
+}
+
+// Filters can't be saved for local files, so there are none to list and no default.
+func (s *LocalStash) SavedFilters(context.Context, FilterMode) ([]SavedFilter, error) {
+	return nil, nil
+}
+
+func (s *LocalStash) DefaultFilter(context.Context, FilterMode) (SavedFilter, bool, error) {
+	return SavedFilter{}, false, nil
+}
+
+func (s *LocalStash) SaveFilter(context.Context, SaveFilter) (SavedFilter, error) {
+	return SavedFilter{}, localNotSupported("saving filters")
+}
+
+func (s *LocalStash) DestroySavedFilter(context.Context, string) (bool, error) {
+	return false, localNotSupported("saving filters")
+}
+
+// Local files have no studios, so scraped studios can't be created for them.
+func (s *LocalStash) StudioCreate(context.Context, StudioCreate) (Studio, error) {
+	return Studio{}, localNotSupported("creating studios")
+}
+
+// Scrapers are run by a stash server, and local files have none.
+func (s *LocalStash) ListScrapers(context.Context, []ScrapeContentType) ([]Scraper, error) {
+	return nil, localNotSupported("scraping")
+}
+
+func (s *LocalStash) ScrapeSingleScene(context.Context, ScraperSource, ScrapeSingleScene) ([]ScrapedScene, error) {
+	return nil, localNotSupported("scraping")
+}
+
+func (s *LocalStash) ScrapeSingleGallery(context.Context, ScraperSource, ScrapeSingleGallery) ([]ScrapedGallery, error) {
+	return nil, localNotSupported("scraping")
+}
+
+func (s *LocalStash) ScrapeSceneURL(context.Context, string) (ScrapedScene, bool, error) {
+	return ScrapedScene{}, false, localNotSupported("scraping")
+}
+
+func (s *LocalStash) ScrapeGalleryURL(context.Context, string) (ScrapedGallery, bool, error) {
+	return ScrapedGallery{}, false, localNotSupported("scraping")
+}
+
+// localScene matches a scene of a local folder, which has only a path and the metadata given to it, against a filter.
+func (c *criteria) localScene(scene Scene, f SceneFilter) bool {
+	if !c.supported(f, "Title", "Details", "Path", "Rating100", "Organized", "Tags", "TagCount", "Date") {
+		return false
+	}
+	matched := c.str("title", f.Title, scene.Title) &&
+		c.str("details", f.Details, scene.Details) &&
+		c.str("path", f.Path, scene.FilePath()) &&
+		c.int("rating100", f.Rating100, scene.Rating) &&
+		c.bool(f.Organized, scene.Organized) &&
+		c.hierarchical("tags", f.Tags, entityIDs(scene.Tags), localTagDescendants) &&
+		c.int("tag_count", f.TagCount, len(scene.Tags)) &&
+		c.date("date", f.Date, scene.Date)
+	return combine(matched, f.AND, f.OR, f.NOT, func(f SceneFilter) bool { return c.localScene(scene, f) })
+}
+
+func (c *criteria) localGallery(gallery Gallery, f GalleryFilter) bool {
+	if !c.supported(f, "Title", "Details", "Path", "Rating100", "Organized", "Tags", "TagCount", "Date") {
+		return false
+	}
+	matched := c.str("title", f.Title, gallery.Title) &&
+		c.str("details", f.Details, gallery.Details) &&
+		c.str("path", f.Path, gallery.FilePath()) &&
+		c.int("rating100", f.Rating100, gallery.Rating) &&
+		c.bool(f.Organized, gallery.Organized) &&
+		c.hierarchical("tags", f.Tags, entityIDs(gallery.Tags), localTagDescendants) &&
+		c.int("tag_count", f.TagCount, len(gallery.Tags)) &&
+		c.date("date", f.Date, gallery.Date)
+	return combine(matched, f.AND, f.OR, f.NOT, func(f GalleryFilter) bool { return c.localGallery(gallery, f) })
+}
+
+func (c *criteria) localTag(tag TagDetail, f TagFilter) bool {
+	if !c.supported(f, "Name", "SceneCount", "GalleryCount") {
+		return false
+	}
+	matched := c.str("name", f.Name, tag.Name) &&
+		c.int("scene_count", f.SceneCount, tag.SceneCount) &&
+		c.int("gallery_count", f.GalleryCount, tag.GalleryCount)
+	return combine(matched, f.AND, f.OR, f.NOT, func(f TagFilter) bool { return c.localTag(tag, f) })
+}
+
+// localTagDescendants returns only the tag itself, as local tags have no hierarchy.
+func localTagDescendants(id string, _ int) []string {
+	return []string{id}
+}
+
+// Sorting by date orders local files by the date given to them, or by when they were last modified if they have none.
+var localSceneSortKeys = sortKeys[Scene]{
+	SortPath:  func(a, b Scene) int { return compareStrings(a.FilePath(), b.FilePath()) },
+	SortTitle: func(a, b Scene) int { return compareStrings(localTitle(a.Title, a), localTitle(b.Title, b)) },
+	SortDate: func(a, b Scene) int {
+		return compareTimes(localDate(a.Date, a.UpdatedAt), localDate(b.Date, b.UpdatedAt))
+	},
+	SortUpdatedAt: func(a, b Scene) int { return compareTimes(a.UpdatedAt, b.UpdatedAt) },
+	SortFileSize:  func(a, b Scene) int { return compareInts(sceneSize(a), sceneSize(b)) },
+	"rating":      func(a, b Scene) int { return compareInts(a.Rating, b.Rating) },
+}
+
+var localGallerySortKeys = sortKeys[Gallery]{
+	SortPath:  func(a, b Gallery) int { return compareStrings(a.FilePath(), b.FilePath()) },
+	SortTitle: func(a, b Gallery) int { return compareStrings(localTitle(a.Title, a), localTitle(b.Title, b)) },
+	SortDate: func(a, b Gallery) int {
+		return compareTimes(localDate(a.Date, a.UpdatedAt), localDate(b.Date, b.UpdatedAt))
+	},
+	SortUpdatedAt: func(a, b Gallery) int { return compareTimes(a.UpdatedAt, b.UpdatedAt) },
+	SortFileSize:  func(a, b Gallery) int { return compareInts(gallerySize(a), gallerySize(b)) },
+	"rating":      func(a, b Gallery) int { return compareInts(a.Rating, b.Rating) },
+}
+
+// localDate returns the date given to a local file, or modified if it has none.
+func localDate(date string, modified time.Time) time.Time {
+	if t, err := time.ParseInLocation(time.DateOnly, date, time.Local); err == nil {
+		return t
+	}
+	return modified
+}
+
+// localTitle returns the title of a local file, which is its name unless it has been given one.
+func localTitle(title string, file interface{ FilePath() string }) string {
+	if title != "" {
+		return title
+	}
+	return filepath.Base(file.FilePath())
 }
 
 func paginate[T any](items []T, page, perPage int) []T {
@@ -0,0 +1,262 @@
+package stash
+
+import (
+	"context"
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// localIndex is what was found under the root of a local folder when it was last scanned, which is kept so that the
+// folder can be listed before it is scanned again, and so that directories that haven't changed aren't read again.
+type localIndex struct {
+	// Dirs are the directories under the root, keyed by their ID.
+	Dirs map[string]localDir `json:"dirs"`
+}
+
+// localDir is a directory as it was when last scanned.  A directory of images without subdirectories is a gallery,
+// and only its images are kept.
+type localDir struct {
+	Modified time.Time   `json:"modified"`
+	Dirs     []string    `json:"dirs,omitempty"`
+	Scenes   []localFile `json:"scenes,omitempty"`
+	Archives []localFile `json:"archives,omitempty"`
+	Images   []localFile `json:"images,omitempty"`
+}
+
+type localFile struct {
+	Name     string    `json:"name"`
+	Size     int64     `json:"size"`
+	Modified time.Time `json:"modified"`
+}
+
+// ScanProgress reports the progress of a scan of a local folder.
+type ScanProgress struct {
+	// Dirs is the number of directories scanned.
+	Dirs int
+	// Changed is the number of directories that were read, as they were new or modified since last scanned.
+	Changed int
+}
+
+// loadLocalIndex reads the index at path.  The index is only a cache of the folder, so an index that can't be read is
+// treated as empty, and the folder is scanned in full.
+func loadLocalIndex(path string) localIndex {
+	var index localIndex
+	if path == "" {
+		return index
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return index
+	}
+	if err := json.Unmarshal(data, &index); err != nil {
+		return localIndex{}
+	}
+	return index
+}
+
+// Scan scans the folder for changes since it was last scanned, calling progress, if given, as each directory is
+// scanned.  Directories that haven't been modified since are not read again, so rescanning a folder that hasn't changed
+// only needs each directory to be stat'd.  As a result, files changed in place rather than added, removed or renamed
+// are not noticed.  Nothing is changed if ctx is cancelled before the scan completes.
+func (s *LocalStash) Scan(ctx context.Context, progress func(ScanProgress)) error {
+	s.mu.Lock()
+	previous := s.index.Dirs
+	s.mu.Unlock()
+
+	scan := localScan{root: s.root, previous: previous, dirs: map[string]localDir{}, report: progress}
+	if err := scan.dir(ctx, "."); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.index = localIndex{Dirs: scan.dirs}
+	s.build()
+	return writeJSON(s.indexPath, s.index, ".index-*.json")
+}
+
+// localScan is a scan in progress, which only reads the directories that aren't in previous as they were.
+type localScan struct {
+	root     string
+	previous map[string]localDir
+	dirs     map[string]localDir
+	progress ScanProgress
+	report   func(ScanProgress)
+}
+
+func (sc *localScan) dir(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	path := filepath.Join(sc.root, filepath.FromSlash(id))
+	info, err := os.Stat(path)
+	if err != nil || !info.IsDir() {
+		return nil // skip bad paths
+	}
+
+	dir, ok := sc.previous[id]
+	if !ok || !dir.Modified.Equal(info.ModTime()) {
+		if dir, err = readLocalDir(path, info.ModTime(), id == "."); err != nil {
+			return nil
+		}
+		sc.progress.Changed++
+	}
+	sc.dirs[id] = dir
+	sc.progress.Dirs++
+	if sc.report != nil {
+		sc.report(sc.progress)
+	}
+
+	for _, name := range dir.Dirs {
+		if err := sc.dir(ctx, localChildID(id, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readLocalDir(path string, modified time.Time, root bool) (localDir, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return localDir{}, err
+	}
+
+	dir := localDir{Modified: modified}
+	var images []localFile
+	for _, entry := range entries {
+		if entry.IsDir() {
+			dir.Dirs = append(dir.Dirs, entry.Name())
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		file := localFile{Name: entry.Name(), Size: info.Size(), Modified: info.ModTime()}
+		switch localKind(entry.Name()) {
+		case localSceneFile:
+			dir.Scenes = append(dir.Scenes, file)
+		case localArchiveFile:
+			dir.Archives = append(dir.Archives, file)
+		case localImageFile:
+			images = append(images, file)
+		}
+	}
+
+	// The root is never a gallery, so that a folder of images can still be browsed.
+	if !root && len(dir.Dirs) == 0 && len(images) > 0 {
+		return localDir{Modified: modified, Images: images}, nil
+	}
+	return dir, nil
+}
+
+// localChildID returns the ID of the directory name in the directory with id.
+func localChildID(id, name string) string {
+	if id == "." {
+		return name
+	}
+	return id + "/" + name
+}
+
+const (
+	localOtherFile = iota
+	localSceneFile
+	localArchiveFile
+	localImageFile
+)
+
+// localKind returns the kind of content of a file by its name.
+func localKind(name string) int {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".mp4", ".mkv", ".mov", ".avi":
+		return localSceneFile
+	case ".zip", ".rar", ".pdf":
+		return localArchiveFile
+	case ".jpg", ".jpeg", ".png", ".gif", ".bmp", ".webp":
+		return localImageFile
+	default:
+		return localOtherFile
+	}
+}
+
+// build lists the scenes and galleries of the index, walking its directories from the root.
+func (s *LocalStash) build() {
+	s.scenes, s.galleries = nil, nil
+	s.buildDir(".")
+}
+
+func (s *LocalStash) buildDir(id string) {
+	dir, ok := s.index.Dirs[id]
+	if !ok {
+		return
+	}
+	path := filepath.Join(s.root, filepath.FromSlash(id))
+	for _, file := range dir.Scenes {
+		s.scenes = append(s.scenes, s.localScene(filepath.Join(path, file.Name), file))
+	}
+	for _, file := range dir.Archives {
+		s.galleries = append(s.galleries, s.localArchive(filepath.Join(path, file.Name), file))
+	}
+	if len(dir.Images) > 0 {
+		s.galleries = append(s.galleries, s.localFolder(path, dir))
+	}
+	for _, name := range dir.Dirs {
+		s.buildDir(localChildID(id, name))
+	}
+}
+
+func (s *LocalStash) localScene(path string, file localFile) Scene {
+	return Scene{
+		ID:        localID(s.root, path),
+		Files:     []VideoFile{{Path: path, Size: file.Size}},
+		UpdatedAt: file.Modified,
+	}
+}
+
+func (s *LocalStash) localArchive(path string, file localFile) Gallery {
+	return Gallery{
+		ID:        localID(s.root, path),
+		Folder:    Folder{Path: path},
+		Files:     []File{{Path: path, Size: file.Size}},
+		UpdatedAt: file.Modified,
+	}
+}
+
+// localFolder returns the gallery of a directory of images, which is modified when any of its images are.
+func (s *LocalStash) localFolder(path string, dir localDir) Gallery {
+	gallery := Gallery{
+		ID:         localID(s.root, path),
+		Folder:     Folder{Path: path},
+		UpdatedAt:  dir.Modified,
+		ImageCount: len(dir.Images),
+	}
+	for _, image := range dir.Images {
+		gallery.Files = append(gallery.Files, File{Path: filepath.Join(path, image.Name), Size: image.Size})
+		if image.Modified.After(gallery.UpdatedAt) {
+			gallery.UpdatedAt = image.Modified
+		}
+	}
+	return gallery
+}
+
+// add lists the scene or gallery at path, which has been restored from the trash since the folder was last scanned.
+func (s *LocalStash) add(path string, info fs.FileInfo) {
+	if info.IsDir() {
+		dir, err := readLocalDir(path, info.ModTime(), false)
+		if err == nil && len(dir.Images) > 0 {
+			s.galleries = append(s.galleries, s.localFolder(path, dir))
+		}
+		return
+	}
+	file := localFile{Name: info.Name(), Size: info.Size(), Modified: info.ModTime()}
+	switch localKind(info.Name()) {
+	case localSceneFile:
+		s.scenes = append(s.scenes, s.localScene(path, file))
+	case localArchiveFile:
+		s.galleries = append(s.galleries, s.localArchive(path, file))
+	}
+}
@@ -0,0 +1,291 @@
+package stash
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+
+	"github.com/hasura/go-graphql-client"
+)
+
+// localMetadata is the metadata given to the files of a local folder, which is kept in a JSON database of its own
+// rather than alongside the files.  Scenes and galleries are keyed by their path relative to the root of the folder,
+// which is also their ID.
+type localMetadata struct {
+	Tags      []Tag                 `json:"tags"`
+	Scenes    map[string]localEntry `json:"scenes"`
+	Galleries map[string]localEntry `json:"galleries"`
+}
+
+// localEntry is the metadata of a single scene or gallery.  Tags are listed by ID.
+type localEntry struct {
+	Title     string   `json:"title,omitempty"`
+	Details   string   `json:"details,omitempty"`
+	Date      string   `json:"date,omitempty"`
+	Rating    int      `json:"rating100,omitempty"`
+	Organized bool     `json:"organized,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+}
+
+func (e localEntry) isZero() bool {
+	return e.Title == "" && e.Details == "" && e.Date == "" && e.Rating == 0 && !e.Organized && len(e.Tags) == 0
+}
+
+// localStatePath returns the path of a file of the state of root in dir, such as its metadata database.  Each folder has
+// state of its own, in files named for its absolute path.
+func localStatePath(dir, root, ext string) string {
+	sum := sha256.Sum256([]byte(root))
+	return filepath.Join(dir, hex.EncodeToString(sum[:])+ext)
+}
+
+func loadLocalMetadata(path string) (localMetadata, error) {
+	var m localMetadata
+	if path == "" {
+		return m, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return m, err
+	}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return m, fmt.Errorf("unable to read local metadata %s: %w", path, err)
+	}
+	return m, nil
+}
+
+// writeJSON writes v to path through a temporary file named for pattern, so that a partially written file is never
+// read back.  Nothing is written if path is empty, for state that is only kept in memory.
+func writeJSON(path string, v any, pattern string) error {
+	if path == "" {
+		return nil
+	}
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, pattern)
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	encoder := json.NewEncoder(tmp)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// update sets the entries of changes in entries and saves the metadata.  The previous entries are restored if they
+// can't be saved, so that what is shown never differs from what is stored.
+func (s *LocalStash) update(entries *map[string]localEntry, changes map[string]localEntry) error {
+	if *entries == nil {
+		*entries = map[string]localEntry{}
+	}
+	previous := make(map[string]localEntry, len(changes))
+	for id, entry := range changes {
+		if existing, ok := (*entries)[id]; ok {
+			previous[id] = existing
+		}
+		if entry.isZero() {
+			delete(*entries, id)
+		} else {
+			(*entries)[id] = entry
+		}
+	}
+	if err := writeJSON(s.metadataPath, s.metadata, ".metadata-*.json"); err != nil {
+		for id := range changes {
+			if entry, ok := previous[id]; ok {
+				(*entries)[id] = entry
+			} else {
+				delete(*entries, id)
+			}
+		}
+		return fmt.Errorf("unable to save local metadata: %w", err)
+	}
+	return nil
+}
+
+// save saves the metadata as changed by change.  The metadata is only changed once saved, so that what is shown never
+// differs from what is stored.
+func (s *LocalStash) save(change func(m *localMetadata)) error {
+	m := localMetadata{
+		Tags:      slices.Clone(s.metadata.Tags),
+		Scenes:    maps.Clone(s.metadata.Scenes),
+		Galleries: maps.Clone(s.metadata.Galleries),
+	}
+	change(&m)
+	if err := writeJSON(s.metadataPath, m, ".metadata-*.json"); err != nil {
+		return fmt.Errorf("unable to save local metadata: %w", err)
+	}
+	s.metadata = m
+	return nil
+}
+
+// retagAll replaces the tags with ids on all entries with the tag to, or removes them if to is empty, and removes the
+// tags with ids.  Entries are replaced rather than changed in place, as they may be shared with the metadata it was
+// cloned from.
+func (m *localMetadata) retagAll(ids []string, to string) {
+	for _, entries := range []map[string]localEntry{m.Scenes, m.Galleries} {
+		for id, entry := range entries {
+			if !slices.ContainsFunc(entry.Tags, func(tag string) bool { return slices.Contains(ids, tag) }) {
+				continue
+			}
+			var tags []string
+			for _, tag := range entry.Tags {
+				if slices.Contains(ids, tag) {
+					tag = to
+				}
+				if tag != "" && !slices.Contains(tags, tag) {
+					tags = append(tags, tag)
+				}
+			}
+			entry.Tags = tags
+			if entry.isZero() {
+				delete(entries, id)
+			} else {
+				entries[id] = entry
+			}
+		}
+	}
+	m.Tags = slices.DeleteFunc(m.Tags, func(tag Tag) bool { return slices.Contains(ids, tag.ID) })
+}
+
+// localID returns the ID of a file under root, which is its slash separated path relative to root.
+func localID(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+func (s *LocalStash) tagRefs(ids []string) []Tag {
+	tags := []Tag{}
+	for _, id := range ids {
+		if i := indexOf(s.metadata.Tags, id); i >= 0 {
+			tags = append(tags, s.metadata.Tags[i])
+		}
+	}
+	return tags
+}
+
+// scene returns a scene found in the folder with the metadata given to it.
+func (s *LocalStash) scene(scene Scene) Scene {
+	entry := s.metadata.Scenes[scene.ID]
+	scene.Title = entry.Title
+	scene.Details = entry.Details
+	scene.Date = entry.Date
+	scene.Rating = entry.Rating
+	scene.Organized = entry.Organized
+	scene.Tags = s.tagRefs(entry.Tags)
+	return scene
+}
+
+func (s *LocalStash) gallery(gallery Gallery) Gallery {
+	entry := s.metadata.Galleries[gallery.ID]
+	gallery.Title = entry.Title
+	gallery.Details = entry.Details
+	gallery.Date = entry.Date
+	gallery.Rating = entry.Rating
+	gallery.Organized = entry.Organized
+	gallery.Tags = s.tagRefs(entry.Tags)
+	return gallery
+}
+
+func (s *LocalStash) tagsByID(ids []graphql.ID) ([]Tag, error) {
+	tags := []Tag{}
+	for _, id := range ids {
+		i := indexOf(s.metadata.Tags, string(id))
+		if i < 0 {
+			return nil, fmt.Errorf("%w: %s", ErrTagNotFound, id)
+		}
+		if !containsID(tags, string(id)) {
+			tags = append(tags, s.metadata.Tags[i])
+		}
+	}
+	return tags, nil
+}
+
+func (s *LocalStash) nextTagID() string {
+	last := 0
+	for _, tag := range s.metadata.Tags {
+		if id, err := strconv.Atoi(tag.ID); err == nil {
+			last = max(last, id)
+		}
+	}
+	return strconv.Itoa(last + 1)
+}
+
+// localUnsupported returns the error given when a change is made that can't be stored for local files.
+func localUnsupported(field string) error {
+	return fmt.Errorf("%s can't be set on local files", field)
+}
+
+// localNotSupported returns the error given when an operation is used that local files have no equivalent of.
+func localNotSupported(operation string) error {
+	return fmt.Errorf("%s is not supported for local files", operation)
+}
+
+// localUpdate is the part of an update of a scene or gallery that can be stored for local files.
+type localUpdate struct {
+	Title     *string
+	Details   *string
+	Date      *string
+	Rating    *int
+	Organized *bool
+	TagIDs    *[]graphql.ID
+}
+
+func (s *LocalStash) applyLocalUpdate(entry *localEntry, u localUpdate) error {
+	if u.Title != nil {
+		entry.Title = *u.Title
+	}
+	if u.Details != nil {
+		entry.Details = *u.Details
+	}
+	if u.Date != nil {
+		entry.Date = *u.Date
+	}
+	if u.Rating != nil {
+		entry.Rating = *u.Rating
+	}
+	if u.Organized != nil {
+		entry.Organized = *u.Organized
+	}
+	if u.TagIDs != nil {
+		tags, err := s.tagsByID(*u.TagIDs)
+		if err != nil {
+			return err
+		}
+		entry.Tags = entityIDs(tags)
+	}
+	return nil
+}
+
+// applyLocalBulkTags sets, adds or removes tags from entry.
+func (s *LocalStash) applyLocalBulkTags(entry *localEntry, update *BulkUpdateIDs) error {
+	tags, err := applyBulkIDs(s.tagRefs(entry.Tags), update, s.tagsByID)
+	if err != nil {
+		return err
+	}
+	entry.Tags = entityIDs(tags)
+	return nil
+}
@@ -0,0 +1,502 @@
+package stash
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hasura/go-graphql-client"
+	"github.com/stretchr/testify/require"
+)
+
+// writeLocalFile writes a file of size bytes under root, last modified at modified.
+func writeLocalFile(t *testing.T, root, name string, size int, modified time.Time) string {
+	t.Helper()
+	path := filepath.Join(root, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, make([]byte, size), 0o644))
+	require.NoError(t, os.Chtimes(path, modified, modified))
+	return path
+}
+
+// scanLocalStash opens and scans the folder at root.
+func scanLocalStash(t *testing.T, root, stateDir string) *LocalStash {
+	t.Helper()
+	s, err := NewLocalStash(root, stateDir)
+	require.NoError(t, err)
+	require.NoError(t, s.Scan(context.Background(), nil))
+	return s
+}
+
+func localPaths[T interface{ FilePath() string }](items []T) []string {
+	paths := make([]string, len(items))
+	for i, item := range items {
+		paths[i] = item.FilePath()
+	}
+	return paths
+}
+
+func TestLocalStashScenes(t *testing.T) {
+	root := t.TempDir()
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	beach := writeLocalFile(t, root, "holiday/beach.mp4", 300, day)
+	forest := writeLocalFile(t, root, "holiday/Forest.mkv", 100, day.Add(48*time.Hour))
+	city := writeLocalFile(t, root, "city.mov", 200, day.Add(24*time.Hour))
+	writeLocalFile(t, root, "notes.txt", 10, day)
+	s := scanLocalStash(t, root, "")
+	ctx := context.Background()
+	all := FindFilter{PerPage: -1}
+
+	scenes, count, err := s.Scenes(ctx, FindFilter{PerPage: -1, Sort: SortPath}, SceneFilter{})
+	require.NoError(t, err)
+	require.Equal(t, 3, count)
+	require.Equal(t, []string{city, beach, forest}, localPaths(scenes))
+	require.Equal(t, int64(200), scenes[0].Files[0].Size)
+	require.True(t, scenes[0].UpdatedAt.Equal(day.Add(24*time.Hour)))
+
+	scenes, _, err = s.Scenes(ctx, FindFilter{PerPage: -1, Sort: SortDate, Direction: SortDirectionDesc}, SceneFilter{})
+	require.NoError(t, err)
+	require.Equal(t, []string{forest, city, beach}, localPaths(scenes))
+
+	// Dates given to files order them in place of when they were modified.
+	_, err = s.SceneUpdate(ctx, SceneUpdate{ID: "holiday/beach.mp4", Date: ptr("2030-01-01")})
+	require.NoError(t, err)
+	_, err = s.SceneUpdate(ctx, SceneUpdate{ID: "city.mov", Date: ptr("2020-05-01")})
+	require.NoError(t, err)
+	scenes, _, err = s.Scenes(ctx, FindFilter{PerPage: -1, Sort: SortDate, Direction: SortDirectionDesc}, SceneFilter{})
+	require.NoError(t, err)
+	require.Equal(t, []string{beach, forest, city}, localPaths(scenes))
+
+	scenes, _, err = s.Scenes(ctx, FindFilter{PerPage: -1, Sort: SortFileSize}, SceneFilter{})
+	require.NoError(t, err)
+	require.Equal(t, []string{forest, city, beach}, localPaths(scenes))
+
+	scenes, count, err = s.Scenes(ctx, FindFilter{Page: 2, PerPage: 2, Sort: SortUpdatedAt}, SceneFilter{})
+	require.NoError(t, err)
+	require.Equal(t, 3, count)
+	require.Equal(t, []string{forest}, localPaths(scenes))
+
+	scenes, _, err = s.Scenes(ctx, FindFilter{Query: "HOLIDAY forest", PerPage: -1}, SceneFilter{})
+	require.NoError(t, err)
+	require.Equal(t, []string{forest}, localPaths(scenes))
+
+	scenes, count, err = s.Scenes(ctx, all, SceneFilter{
+		Path: &StringCriterion{Value: `holiday/.*\.MP4$`, Modifier: CriterionModifierMatchesRegex},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+	require.Equal(t, []string{beach}, localPaths(scenes))
+
+	scenes, _, err = s.Scenes(ctx, FindFilter{PerPage: -1, Sort: SortPath}, SceneFilter{
+		Path: &StringCriterion{Value: "holiday", Modifier: CriterionModifierExcludes},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{city}, localPaths(scenes))
+
+	_, _, err = s.Scenes(ctx, all, SceneFilter{PerformerCount: &IntCriterion{Value: 1, Modifier: CriterionModifierEquals}})
+	require.ErrorContains(t, err, "performer_count criterion is not supported")
+
+	_, _, err = s.Scenes(ctx, FindFilter{Sort: SortPlayCount}, SceneFilter{})
+	require.ErrorContains(t, err, "sort 'play_count' is not supported")
+}
+
+func TestLocalStashGalleries(t *testing.T) {
+	root := t.TempDir()
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	writeLocalFile(t, root, "beach/1.jpg", 100, day)
+	writeLocalFile(t, root, "beach/2.png", 150, day.Add(72*time.Hour))
+	zip := writeLocalFile(t, root, "forest.zip", 200, day.Add(24*time.Hour))
+	beach := filepath.Join(root, "beach")
+	require.NoError(t, os.Chtimes(beach, day, day))
+	s := scanLocalStash(t, root, "")
+	ctx := context.Background()
+
+	galleries, count, err := s.Galleries(ctx, FindFilter{PerPage: -1, Sort: SortPath}, GalleryFilter{})
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+	require.Equal(t, []string{beach, zip}, localPaths(galleries))
+	require.Equal(t, 2, galleries[0].ImageCount)
+	require.True(t, galleries[0].UpdatedAt.Equal(day.Add(72*time.Hour)), "galleries are modified with their images")
+
+	galleries, _, err = s.Galleries(ctx, FindFilter{PerPage: -1, Sort: SortFileSize, Direction: SortDirectionDesc}, GalleryFilter{})
+	require.NoError(t, err)
+	require.Equal(t, []string{beach, zip}, localPaths(galleries))
+
+	galleries, _, err = s.Galleries(ctx, FindFilter{PerPage: -1, Sort: SortDate, Direction: SortDirectionAsc}, GalleryFilter{})
+	require.NoError(t, err)
+	require.Equal(t, []string{zip, beach}, localPaths(galleries))
+
+	galleries, _, err = s.Galleries(ctx, FindFilter{PerPage: -1}, GalleryFilter{
+		Path: &StringCriterion{Value: `\.zip$`, Modifier: CriterionModifierNotMatchesRegex},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{beach}, localPaths(galleries))
+}
+
+func TestLocalStashMetadata(t *testing.T) {
+	root := t.TempDir()
+	state := t.TempDir()
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	beach := writeLocalFile(t, root, "holiday/beach.mp4", 100, day)
+	city := writeLocalFile(t, root, "city.mov", 100, day)
+	writeLocalFile(t, root, "album/1.jpg", 100, day)
+	s := scanLocalStash(t, root, state)
+	ctx := context.Background()
+	all := FindFilter{PerPage: -1, Sort: SortPath}
+
+	outdoor, err := s.TagCreate(ctx, TagCreate{Name: "Outdoor"})
+	require.NoError(t, err)
+	_, err = s.TagCreate(ctx, TagCreate{Name: "outdoor"})
+	require.ErrorContains(t, err, "already exists")
+	found, err := s.TagFindByName(ctx, "OUTDOOR")
+	require.NoError(t, err)
+	require.Equal(t, outdoor, found)
+	_, err = s.TagFindByName(ctx, "Indoor")
+	require.ErrorIs(t, err, ErrTagNotFound)
+
+	scene, err := s.SceneUpdate(ctx, SceneUpdate{
+		ID:     "holiday/beach.mp4",
+		Title:  ptr("Beach day"),
+		Rating: ptr(80),
+		TagIDs: &[]graphql.ID{graphql.ID(outdoor.ID)},
+	})
+	require.NoError(t, err)
+	require.Equal(t, beach, scene.FilePath())
+	require.Equal(t, "Beach day", scene.Title)
+	require.Equal(t, []Tag{outdoor}, scene.Tags)
+
+	_, err = s.SceneUpdate(ctx, SceneUpdate{ID: "city.mov", TagIDs: &[]graphql.ID{"99"}})
+	require.ErrorIs(t, err, ErrTagNotFound)
+	_, err = s.SceneUpdate(ctx, SceneUpdate{ID: "city.mov", StudioID: ptr(graphql.ID("1"))})
+	require.ErrorContains(t, err, "studio can't be set on local files")
+	_, err = s.SceneUpdate(ctx, SceneUpdate{ID: "missing.mp4"})
+	require.ErrorContains(t, err, "scene missing.mp4 not found")
+
+	indoor, err := s.TagCreate(ctx, TagCreate{Name: "Indoor"})
+	require.NoError(t, err)
+	scenes, err := s.BulkSceneUpdate(ctx, BulkSceneUpdate{
+		IDs:       []graphql.ID{"holiday/beach.mp4", "city.mov"},
+		Organized: ptr(true),
+		TagIDs:    &BulkUpdateIDs{IDs: []graphql.ID{graphql.ID(indoor.ID)}, Mode: BulkUpdateIDModeAdd},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []Tag{outdoor, indoor}, scenes[0].Tags)
+	require.Equal(t, []Tag{indoor}, scenes[1].Tags)
+
+	scenes, _, err = s.Scenes(ctx, all, SceneFilter{
+		Tags: &HierarchicalMultiCriterion{Value: []string{outdoor.ID}, Modifier: CriterionModifierIncludes},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{beach}, localPaths(scenes))
+
+	scenes, _, err = s.Scenes(ctx, all, SceneFilter{Organized: ptr(true), Rating100: &IntCriterion{Value: 50, Modifier: CriterionModifierLessThan}})
+	require.NoError(t, err)
+	require.Equal(t, []string{city}, localPaths(scenes))
+
+	gallery, err := s.GalleryUpdate(ctx, GalleryUpdate{ID: "album", TagIDs: &[]graphql.ID{graphql.ID(outdoor.ID)}})
+	require.NoError(t, err)
+	require.Equal(t, []Tag{outdoor}, gallery.Tags)
+
+	tags, count, err := s.Tags(ctx, FindFilter{PerPage: -1, Sort: SortName}, TagFilter{})
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+	require.Equal(t, []TagDetail{
+		{ID: indoor.ID, Name: "Indoor", SceneCount: 2},
+		{ID: outdoor.ID, Name: "Outdoor", SceneCount: 1, GalleryCount: 1},
+	}, tags)
+
+	// Metadata is read back when the folder is opened again.
+	s = scanLocalStash(t, root, state)
+	scenes, _, err = s.Scenes(ctx, FindFilter{Query: "beach day", PerPage: -1}, SceneFilter{})
+	require.NoError(t, err)
+	require.Len(t, scenes, 1)
+	require.Equal(t, 80, scenes[0].Rating)
+	require.True(t, scenes[0].Organized)
+	require.Equal(t, []Tag{outdoor, indoor}, scenes[0].Tags)
+	allTags, err := s.TagsAll(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []Tag{outdoor, indoor}, allTags)
+}
+
+func TestLocalStashTags(t *testing.T) {
+	root := t.TempDir()
+	state := t.TempDir()
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	writeLocalFile(t, root, "beach.mp4", 100, day)
+	writeLocalFile(t, root, "city.mov", 100, day)
+	writeLocalFile(t, root, "album/1.jpg", 100, day)
+	s := scanLocalStash(t, root, state)
+	ctx := context.Background()
+
+	var tags []Tag
+	for _, name := range []string{"Outdoor", "Sand", "Sea"} {
+		tag, err := s.TagCreate(ctx, TagCreate{Name: name})
+		require.NoError(t, err)
+		tags = append(tags, tag)
+	}
+	outdoor, sand, sea := tags[0], tags[1], tags[2]
+	_, err := s.SceneUpdate(ctx, SceneUpdate{ID: "beach.mp4", TagIDs: &[]graphql.ID{graphql.ID(outdoor.ID), graphql.ID(sand.ID)}})
+	require.NoError(t, err)
+	_, err = s.SceneUpdate(ctx, SceneUpdate{ID: "city.mov", TagIDs: &[]graphql.ID{graphql.ID(sea.ID)}})
+	require.NoError(t, err)
+	_, err = s.GalleryUpdate(ctx, GalleryUpdate{ID: "album", TagIDs: &[]graphql.ID{graphql.ID(sand.ID)}})
+	require.NoError(t, err)
+
+	tag, err := s.TagUpdate(ctx, TagUpdate{ID: graphql.ID(outdoor.ID), Name: ptr("Outside")})
+	require.NoError(t, err)
+	require.Equal(t, TagDetail{ID: outdoor.ID, Name: "Outside", SceneCount: 1}, tag)
+	_, err = s.TagUpdate(ctx, TagUpdate{ID: graphql.ID(outdoor.ID), Name: ptr("sand")})
+	require.ErrorContains(t, err, "already exists")
+	_, err = s.TagUpdate(ctx, TagUpdate{ID: graphql.ID(outdoor.ID), Aliases: &[]string{"Outdoors"}})
+	require.ErrorContains(t, err, "aliases can't be set on local files")
+	_, err = s.TagUpdate(ctx, TagUpdate{ID: "99", Name: ptr("Missing")})
+	require.ErrorIs(t, err, ErrTagNotFound)
+
+	// Files are retagged with the destination, and aren't given it twice.
+	tag, err = s.TagsMerge(ctx, TagsMerge{Source: []graphql.ID{graphql.ID(sand.ID), graphql.ID(sea.ID)}, Destination: graphql.ID(outdoor.ID)})
+	require.NoError(t, err)
+	require.Equal(t, TagDetail{ID: outdoor.ID, Name: "Outside", SceneCount: 2, GalleryCount: 1}, tag)
+	all, err := s.TagsAll(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []Tag{{ID: outdoor.ID, Name: "Outside"}}, all)
+	scenes, _, err := s.Scenes(ctx, FindFilter{PerPage: -1, Sort: SortPath}, SceneFilter{})
+	require.NoError(t, err)
+	require.Equal(t, []Tag{{ID: outdoor.ID, Name: "Outside"}}, scenes[0].Tags)
+	require.Equal(t, []Tag{{ID: outdoor.ID, Name: "Outside"}}, scenes[1].Tags)
+
+	// Tags are kept in the metadata database.
+	reopened := scanLocalStash(t, root, state)
+	all, err = reopened.TagsAll(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []Tag{{ID: outdoor.ID, Name: "Outside"}}, all)
+
+	ok, err := s.TagDelete(ctx, outdoor.ID)
+	require.NoError(t, err)
+	require.True(t, ok)
+	_, err = s.TagDelete(ctx, outdoor.ID)
+	require.ErrorIs(t, err, ErrTagNotFound)
+	scenes, _, err = s.Scenes(ctx, FindFilter{PerPage: -1}, SceneFilter{})
+	require.NoError(t, err)
+	for _, scene := range scenes {
+		require.Empty(t, scene.Tags)
+	}
+	galleries, _, err := s.Galleries(ctx, FindFilter{PerPage: -1}, GalleryFilter{})
+	require.NoError(t, err)
+	require.Empty(t, galleries[0].Tags)
+}
+
+func TestLocalStashDelete(t *testing.T) {
+	root := t.TempDir()
+	data := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", data)
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	beach := writeLocalFile(t, root, "holiday/beach day.mp4", 100, day)
+	city := writeLocalFile(t, root, "city.mov", 100, day)
+	writeLocalFile(t, root, "album/1.jpg", 100, day)
+	album := filepath.Join(root, "album")
+	s := scanLocalStash(t, root, "")
+	s.trash.now = func() time.Time { return time.Date(2024, 2, 1, 12, 30, 0, 0, time.Local) }
+	ctx := context.Background()
+	all := FindFilter{PerPage: -1, Sort: SortPath}
+	trash := filepath.Join(data, "Trash")
+
+	_, err := s.Restore(ctx)
+	require.ErrorContains(t, err, "nothing to restore")
+
+	ok, err := s.DeleteScene(ctx, "holiday/beach day.mp4")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.NoFileExists(t, beach)
+	require.FileExists(t, filepath.Join(trash, "files", "beach day.mp4"))
+	info, err := os.ReadFile(filepath.Join(trash, "info", "beach day.mp4.trashinfo"))
+	require.NoError(t, err)
+	require.Equal(t, "[Trash Info]\nPath="+filepath.Dir(beach)+"/beach%20day.mp4\nDeletionDate=2024-02-01T12:30:00\n", string(info))
+
+	scenes, _, err := s.Scenes(ctx, all, SceneFilter{})
+	require.NoError(t, err)
+	require.Equal(t, []string{city}, localPaths(scenes))
+
+	// A file deleted with the name of one already in the trash is given a name of its own.
+	writeLocalFile(t, root, "holiday/beach day.mp4", 100, day)
+	s = scanLocalStash(t, root, "")
+	_, err = s.DeleteScene(ctx, "holiday/beach day.mp4")
+	require.NoError(t, err)
+	require.FileExists(t, filepath.Join(trash, "files", "beach day.2.mp4"))
+
+	_, err = s.GalleryDelete(ctx, "album")
+	require.NoError(t, err)
+	require.NoDirExists(t, album)
+	galleries, _, err := s.Galleries(ctx, all, GalleryFilter{})
+	require.NoError(t, err)
+	require.Empty(t, galleries)
+
+	// Items are restored most recently deleted first.
+	path, err := s.Restore(ctx)
+	require.NoError(t, err)
+	require.Equal(t, album, path)
+	galleries, _, err = s.Galleries(ctx, all, GalleryFilter{})
+	require.NoError(t, err)
+	require.Equal(t, []string{album}, localPaths(galleries))
+
+	path, err = s.Restore(ctx)
+	require.NoError(t, err)
+	require.Equal(t, beach, path)
+	scenes, _, err = s.Scenes(ctx, all, SceneFilter{})
+	require.NoError(t, err)
+	require.Equal(t, []string{city, beach}, localPaths(scenes))
+
+	_, err = s.Restore(ctx)
+	require.ErrorContains(t, err, "already exists")
+}
+
+func TestLocalStashScan(t *testing.T) {
+	root := t.TempDir()
+	state := t.TempDir()
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	beach := writeLocalFile(t, root, "holiday/beach.mp4", 100, day)
+	writeLocalFile(t, root, "album/1.jpg", 100, day)
+	album := filepath.Join(root, "album")
+	for _, dir := range []string{root, filepath.Join(root, "holiday"), album} {
+		require.NoError(t, os.Chtimes(dir, day, day))
+	}
+	ctx := context.Background()
+	all := FindFilter{PerPage: -1, Sort: SortPath}
+	var last ScanProgress
+	progress := func(p ScanProgress) { last = p }
+
+	s, err := NewLocalStash(root, state)
+	require.NoError(t, err)
+	scenes, _, err := s.Scenes(ctx, all, SceneFilter{})
+	require.NoError(t, err)
+	require.Empty(t, scenes, "the folder is not listed until it is scanned")
+	require.NoError(t, s.Scan(ctx, progress))
+	require.Equal(t, ScanProgress{Dirs: 3, Changed: 3}, last)
+
+	// The index is read back, so the folder is listed before it is scanned again.
+	s, err = NewLocalStash(root, state)
+	require.NoError(t, err)
+	scenes, _, err = s.Scenes(ctx, all, SceneFilter{})
+	require.NoError(t, err)
+	require.Equal(t, []string{beach}, localPaths(scenes))
+	galleries, _, err := s.Galleries(ctx, all, GalleryFilter{})
+	require.NoError(t, err)
+	require.Equal(t, []string{album}, localPaths(galleries))
+
+	// Only the directories that have changed are read again.
+	city := writeLocalFile(t, root, "city.mov", 100, day)
+	require.NoError(t, os.RemoveAll(album))
+	require.NoError(t, os.Chtimes(root, day.Add(time.Hour), day.Add(time.Hour)))
+	require.NoError(t, s.Scan(ctx, progress))
+	require.Equal(t, ScanProgress{Dirs: 2, Changed: 1}, last)
+	scenes, _, err = s.Scenes(ctx, all, SceneFilter{})
+	require.NoError(t, err)
+	require.Equal(t, []string{city, beach}, localPaths(scenes))
+	galleries, _, err = s.Galleries(ctx, all, GalleryFilter{})
+	require.NoError(t, err)
+	require.Empty(t, galleries)
+
+	// A cancelled scan changes nothing.
+	writeLocalFile(t, root, "forest.mp4", 100, day)
+	require.NoError(t, os.Chtimes(root, day.Add(2*time.Hour), day.Add(2*time.Hour)))
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	require.ErrorIs(t, s.Scan(cancelled, progress), context.Canceled)
+	scenes, _, err = s.Scenes(ctx, all, SceneFilter{})
+	require.NoError(t, err)
+	require.Equal(t, []string{city, beach}, localPaths(scenes))
+}
+
+func TestLocalStashUnsupported(t *testing.T) {
+	s := scanLocalStash(t, t.TempDir(), "")
+	ctx := context.Background()
+
+	_, err := s.SceneIncrementO(ctx, "scene.mp4")
+	require.ErrorContains(t, err, "o-counter is not supported for local files")
+	_, err = s.SceneDecrementO(ctx, "scene.mp4")
+	require.ErrorContains(t, err, "not supported")
+	_, err = s.SceneResetO(ctx, "scene.mp4")
+	require.ErrorContains(t, err, "not supported")
+
+	_, err = s.MetadataScan(ctx, ScanMetadata{})
+	require.ErrorContains(t, err, "scanning is not supported for local files")
+	_, err = s.MetadataGenerate(ctx, GenerateMetadata{})
+	require.ErrorContains(t, err, "not supported")
+	_, err = s.MetadataAutoTag(ctx, AutoTagMetadata{})
+	require.ErrorContains(t, err, "not supported")
+	_, err = s.MetadataIdentify(ctx, IdentifyMetadata{})
+	require.ErrorContains(t, err, "not supported")
+	_, err = s.MetadataClean(ctx, CleanMetadata{})
+	require.ErrorContains(t, err, "not supported")
+	_, err = s.JobQueue(ctx)
+	require.ErrorContains(t, err, "the job queue is not supported for local files")
+	_, _, err = s.FindJob(ctx, "1")
+	require.ErrorContains(t, err, "not supported")
+	_, err = s.StopJob(ctx, "1")
+	require.ErrorContains(t, err, "not supported")
+
+	filters, err := s.SavedFilters(ctx, FilterModeScenes)
+	require.NoError(t, err)
+	require.Empty(t, filters)
+	_, ok, err := s.DefaultFilter(ctx, FilterModeScenes)
+	require.NoError(t, err)
+	require.False(t, ok)
+	_, err = s.SaveFilter(ctx, SaveFilter{Mode: FilterModeScenes, Name: "beach"})
+	require.ErrorContains(t, err, "saving filters is not supported for local files")
+	_, err = s.DestroySavedFilter(ctx, "1")
+	require.ErrorContains(t, err, "not supported")
+
+	_, err = s.ListScrapers(ctx, []ScrapeContentType{ScrapeContentTypeScene})
+	require.ErrorContains(t, err, "scraping is not supported for local files")
+	_, err = s.ScrapeSingleScene(ctx, ScraperSource{}, ScrapeSingleScene{})
+	require.ErrorContains(t, err, "not supported")
+	_, err = s.ScrapeSingleGallery(ctx, ScraperSource{}, ScrapeSingleGallery{})
+	require.ErrorContains(t, err, "not supported")
+	_, _, err = s.ScrapeSceneURL(ctx, "https://example.com/scene")
+	require.ErrorContains(t, err, "not supported")
+	_, _, err = s.ScrapeGalleryURL(ctx, "https://example.com/gallery")
+	require.ErrorContains(t, err, "not supported")
+	_, err = s.StudioCreate(ctx, StudioCreate{Name: "Studio"})
+	require.ErrorContains(t, err, "creating studios is not supported for local files")
+	_, _, err = s.Performers(ctx, FindFilter{}, PerformerFilter{})
+	require.ErrorContains(t, err, "listing performers is not supported for local files")
+	_, err = s.PerformersAll(ctx)
+	require.ErrorContains(t, err, "not supported")
+	_, err = s.PerformerCreate(ctx, PerformerCreate{Name: "Jane"})
+	require.ErrorContains(t, err, "not supported")
+	_, err = s.PerformerUpdate(ctx, PerformerUpdate{ID: "1"})
+	require.ErrorContains(t, err, "not supported")
+	_, err = s.PerformerGet(ctx, "1")
+	require.ErrorContains(t, err, "not supported")
+	_, _, err = s.Studios(ctx, FindFilter{}, StudioFilter{})
+	require.ErrorContains(t, err, "listing studios is not supported for local files")
+	_, err = s.StudiosAll(ctx)
+	require.ErrorContains(t, err, "not supported")
+	_, _, err = s.SceneMarkers(ctx, FindFilter{}, SceneMarkerFilter{})
+	require.ErrorContains(t, err, "listing scene markers is not supported for local files")
+	_, _, err = s.Movies(ctx, FindFilter{}, MovieFilter{})
+	require.ErrorContains(t, err, "listing movies is not supported for local files")
+	_, err = s.MovieCreate(ctx, MovieCreate{Name: "Movie"})
+	require.ErrorContains(t, err, "not supported")
+	_, err = s.MovieUpdate(ctx, MovieUpdate{ID: "1"})
+	require.ErrorContains(t, err, "not supported")
+	_, err = s.SceneMerge(ctx, SceneMerge{Destination: "scene.mp4"})
+	require.ErrorContains(t, err, "merging scenes is not supported for local files")
+	_, err = s.FindDuplicateScenes(ctx, 0, -1)
+	require.ErrorContains(t, err, "not supported")
+	_, err = s.SceneStreams(ctx, "scene.mp4")
+	require.ErrorContains(t, err, "streaming is not supported for local files")
+	_, _, err = s.Images(ctx, FindFilter{}, ImageFilter{})
+	require.ErrorContains(t, err, "listing images is not supported for local files")
+	_, err = s.ImageDelete(ctx, "1")
+	require.ErrorContains(t, err, "not supported")
+	_, err = s.ImageUpdate(ctx, ImageUpdate{ID: "1"})
+	require.ErrorContains(t, err, "not supported")
+	_, err = s.ImageIncrementO(ctx, "1")
+	require.ErrorContains(t, err, "not supported")
+	_, err = s.ImageDecrementO(ctx, "1")
+	require.ErrorContains(t, err, "not supported")
+	_, err = s.ImageResetO(ctx, "1")
+	require.ErrorContains(t, err, "not supported")
+}
@@ -0,0 +1,53 @@
+package stash
+
+import "context"
+
+// SceneMarker is a named position within a scene, typically used to navigate to a particular section of it.
+type SceneMarker struct {
+	ID         string  `graphql:"id"`
+	Title      string  `graphql:"title"`
+	Seconds    float64 `graphql:"seconds"`
+	PrimaryTag Tag     `graphql:"primary_tag"`
+	Tags       []Tag   `graphql:"tags"`
+	Scene      Scene   `graphql:"scene"`
+}
+
+func (m SceneMarker) EntityID() string {
+	return m.ID
+}
+
+type SceneMarkerFilter struct {
+	TagID          *string                     `json:"tag_id,omitempty"`
+	Tags           *HierarchicalMultiCriterion `json:"tags,omitempty"`
+	SceneTags      *HierarchicalMultiCriterion `json:"scene_tags,omitempty"`
+	Performers     *MultiCriterion             `json:"performers,omitempty"`
+	CreatedAt      *TimestampCriterion         `json:"created_at,omitempty"`
+	UpdatedAt      *TimestampCriterion         `json:"updated_at,omitempty"`
+	SceneDate      *DateCriterion              `json:"scene_date,omitempty"`
+	SceneCreatedAt *TimestampCriterion         `json:"scene_created_at,omitempty"`
+	SceneUpdatedAt *TimestampCriterion         `json:"scene_updated_at,omitempty"`
+}
+
+func (SceneMarkerFilter) GetGraphQLType() string {
+	return "SceneMarkerFilterType"
+}
+
+type sceneMarkersQuery struct {
+	FindSceneMarkers struct {
+		Count        int           `graphql:"count"`
+		SceneMarkers []SceneMarker `graphql:"scene_markers"`
+	} `graphql:"findSceneMarkers(filter: $filter, scene_marker_filter: $scene_marker_filter)"`
+}
+
+// SceneMarkers returns a page of scene markers matching the given filters along with the total count of matches.
+func (s stash) SceneMarkers(ctx context.Context, filter FindFilter, markerFilter SceneMarkerFilter) ([]SceneMarker, int, error) {
+	resp := sceneMarkersQuery{}
+	err := s.client.Query(ctx, &resp, map[string]any{
+		"filter":              filter,
+		"scene_marker_filter": markerFilter,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return resp.FindSceneMarkers.SceneMarkers, resp.FindSceneMarkers.Count, nil
+}
@@ -0,0 +1,38 @@
+package stash
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hasura/go-graphql-client"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSceneMarkers(t *testing.T) {
+	doer := &captureEndpoint{
+		t: t,
+		response: `{"data": {"findSceneMarkers": {"count": 1, "scene_markers": [{
+			"id": "1",
+			"title": "Intro",
+			"seconds": 90.5,
+			"primary_tag": {"id": "tag1", "name": "Foo"},
+			"tags": [],
+			"scene": {"id": "scene1", "title": "Scene 1", "files": [{"path": "/a.mp4", "duration": 600, "size": 1}]}
+		}]}}}`,
+	}
+	client := graphql.NewClient("https://example.com/graph", doer)
+	s := stash{client}
+
+	markers, count, err := s.SceneMarkers(context.Background(), FindFilter{}, SceneMarkerFilter{
+		Tags: &HierarchicalMultiCriterion{Value: []string{"tag1"}, Modifier: CriterionModifierIncludes},
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+	require.Len(t, markers, 1)
+	require.Equal(t, 90.5, markers[0].Seconds)
+	require.Equal(t, Tag{ID: "tag1", Name: "Foo"}, markers[0].PrimaryTag)
+	require.Equal(t, "/a.mp4", markers[0].Scene.FilePath())
+	require.Contains(t, doer.body, `findSceneMarkers(filter: $filter, scene_marker_filter: $scene_marker_filter)`)
+	require.Contains(t, doer.body, `"tags":{"value":["tag1"],"modifier":"INCLUDES"}`)
+}
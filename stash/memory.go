@@ -0,0 +1,1843 @@
+package stash
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/bits"
+	"mime"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hasura/go-graphql-client"
+)
+
+// MemoryStash is a backend that holds all content in memory, and applies filters, sorting and changes to it much as a
+// stash instance would.  It is loaded from a JSON fixture, and is used to demo and test the application offline.
+// Changes are not saved.
+//
+// Anything that needs stash itself to do work is stubbed.  Metadata tasks are recorded as jobs that finish
+// immediately without doing anything, there are no scrapers, and streams point directly at the file paths given in
+// the fixture.
+type MemoryStash struct {
+	mu  sync.Mutex
+	now func() time.Time
+
+	scenes         []memoryScene
+	galleries      []Gallery
+	images         []memoryImage
+	markers        []SceneMarker
+	performers     []Performer
+	studios        []StudioDetail
+	movies         []MovieDetail
+	tags           []TagDetail
+	savedFilters   []SavedFilter
+	defaultFilters map[FilterMode]SavedFilter
+	jobs           []Job
+
+	// lastID is the highest numeric ID of any entity, and new entities are given IDs after it.
+	lastID int
+}
+
+// memoryScene is a scene along with the perceptual hash of its file, which is used to find duplicates.
+type memoryScene struct {
+	Scene
+	PHash string `json:"phash"`
+}
+
+// memoryImage is an image along with the IDs of the galleries it belongs to.
+type memoryImage struct {
+	Image
+	Galleries []string `json:"galleries"`
+}
+
+func (i memoryImage) EntityID() string {
+	return i.ID
+}
+
+// memoryFixture is the JSON form of a MemoryStash.  Entities refer to each other by ID, and only the IDs of embedded
+// entities such as the tags of a scene are needed.  Tags, studios, performers and movies that are embedded with a name
+// but not listed are added, so that small fixtures need not list them separately.
+type memoryFixture struct {
+	Scenes         []memoryScene              `json:"scenes"`
+	Galleries      []Gallery                  `json:"galleries"`
+	Images         []memoryImage              `json:"images"`
+	Markers        []SceneMarker              `json:"markers"`
+	Performers     []Performer                `json:"performers"`
+	Studios        []StudioDetail             `json:"studios"`
+	Movies         []MovieDetail              `json:"movies"`
+	Tags           []TagDetail                `json:"tags"`
+	SavedFilters   []SavedFilter              `json:"savedFilters"`
+	DefaultFilters map[FilterMode]SavedFilter `json:"defaultFilters"`
+}
+
+// NewMemoryStash returns an empty MemoryStash.
+func NewMemoryStash() *MemoryStash {
+	return &MemoryStash{
+		now:            time.Now,
+		defaultFilters: make(map[FilterMode]SavedFilter),
+	}
+}
+
+// LoadMemoryStash returns a MemoryStash holding the content of a JSON fixture.
+func LoadMemoryStash(data []byte) (*MemoryStash, error) {
+	var fixture memoryFixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("invalid fixture: %w", err)
+	}
+
+	s := NewMemoryStash()
+	s.scenes = fixture.Scenes
+	s.galleries = fixture.Galleries
+	s.images = fixture.Images
+	s.markers = fixture.Markers
+	s.performers = fixture.Performers
+	s.studios = fixture.Studios
+	s.movies = fixture.Movies
+	s.tags = fixture.Tags
+	s.savedFilters = fixture.SavedFilters
+	for mode, filter := range fixture.DefaultFilters {
+		filter.Mode = mode
+		s.defaultFilters[mode] = filter
+	}
+
+	for _, scene := range s.scenes {
+		s.addEmbedded(scene.Tags, scene.Studio, scene.Performers)
+		for _, movie := range scene.Movies {
+			if movie.Movie.ID != "" && indexOf(s.movies, movie.Movie.ID) < 0 {
+				s.movies = append(s.movies, MovieDetail{ID: movie.Movie.ID, Name: movie.Movie.Name})
+			}
+		}
+	}
+	for _, gallery := range s.galleries {
+		s.addEmbedded(gallery.Tags, gallery.Studio, gallery.Performers)
+	}
+	for _, image := range s.images {
+		s.addEmbedded(image.Tags, image.Studio, image.Performers)
+	}
+	for _, marker := range s.markers {
+		s.addEmbedded(append([]Tag{marker.PrimaryTag}, marker.Tags...), Studio{}, nil)
+	}
+	for _, performer := range s.performers {
+		s.addEmbedded(performer.Tags, Studio{}, nil)
+	}
+
+	ids := []string{}
+	for _, scene := range s.scenes {
+		ids = append(ids, scene.ID)
+	}
+	for _, gallery := range s.galleries {
+		ids = append(ids, gallery.ID)
+	}
+	for _, image := range s.images {
+		ids = append(ids, image.ID)
+	}
+	for _, marker := range s.markers {
+		ids = append(ids, marker.ID)
+	}
+	for _, performer := range s.performers {
+		ids = append(ids, performer.ID)
+	}
+	for _, studio := range s.studios {
+		ids = append(ids, studio.ID)
+	}
+	for _, movie := range s.movies {
+		ids = append(ids, movie.ID)
+	}
+	for _, tag := range s.tags {
+		ids = append(ids, tag.ID)
+	}
+	for _, filter := range s.savedFilters {
+		ids = append(ids, filter.ID)
+	}
+	for _, filter := range s.defaultFilters {
+		ids = append(ids, filter.ID)
+	}
+	for _, id := range ids {
+		if n, err := strconv.Atoi(id); err == nil {
+			s.lastID = max(s.lastID, n)
+		}
+	}
+	return s, nil
+}
+
+// addEmbedded adds tags, a studio and performers embedded in other content if they are not already listed.
+func (s *MemoryStash) addEmbedded(tags []Tag, studio Studio, performers []Performer) {
+	for _, tag := range tags {
+		if tag.ID != "" && indexOf(s.tags, tag.ID) < 0 {
+			s.tags = append(s.tags, TagDetail{ID: tag.ID, Name: tag.Name})
+		}
+	}
+	if studio.ID != "" && indexOf(s.studios, studio.ID) < 0 {
+		s.studios = append(s.studios, StudioDetail{ID: studio.ID, Name: studio.Name})
+	}
+	for _, performer := range performers {
+		if performer.ID != "" && indexOf(s.performers, performer.ID) < 0 {
+			s.performers = append(s.performers, Performer{ID: performer.ID, Name: performer.Name})
+		}
+	}
+}
+
+func (s *MemoryStash) nextID() string {
+	s.lastID++
+	return strconv.Itoa(s.lastID)
+}
+
+type entity interface {
+	EntityID() string
+}
+
+// indexOf returns the index of the entity with id in items, or -1 if there is none.
+func indexOf[T entity](items []T, id string) int {
+	return slices.IndexFunc(items, func(item T) bool { return item.EntityID() == id })
+}
+
+func containsID[T entity](items []T, id string) bool {
+	return indexOf(items, id) >= 0
+}
+
+func entityIDs[T entity](items []T) []string {
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.EntityID()
+	}
+	return ids
+}
+
+// The entities stored only hold the IDs of the entities embedded in them, which are filled in with their current
+// values and counts as they are read.
+
+func (s *MemoryStash) tagRefs(tags []Tag) []Tag {
+	refs := []Tag{}
+	for _, tag := range tags {
+		if i := indexOf(s.tags, tag.ID); i >= 0 {
+			refs = append(refs, s.tags[i].Tag())
+		}
+	}
+	return refs
+}
+
+func (s *MemoryStash) studioRef(studio Studio) Studio {
+	if i := indexOf(s.studios, studio.ID); i >= 0 {
+		return Studio{ID: s.studios[i].ID, Name: s.studios[i].Name}
+	}
+	return Studio{}
+}
+
+func (s *MemoryStash) performerRefs(performers []Performer) []Performer {
+	refs := []Performer{}
+	for _, performer := range performers {
+		if i := indexOf(s.performers, performer.ID); i >= 0 {
+			refs = append(refs, s.performer(s.performers[i]))
+		}
+	}
+	return refs
+}
+
+func (s *MemoryStash) scene(m memoryScene) Scene {
+	scene := m.Scene
+	scene.URLs = slices.Clone(scene.URLs)
+	scene.Files = slices.Clone(scene.Files)
+	if scene.LastPlayedAt != nil {
+		lastPlayedAt := *scene.LastPlayedAt
+		scene.LastPlayedAt = &lastPlayedAt
+	}
+	scene.Studio = s.studioRef(scene.Studio)
+	scene.Tags = s.tagRefs(scene.Tags)
+	scene.Performers = s.performerRefs(scene.Performers)
+	scene.Movies = []SceneMovie{}
+	for _, movie := range m.Movies {
+		if i := indexOf(s.movies, movie.Movie.ID); i >= 0 {
+			scene.Movies = append(scene.Movies, SceneMovie{Movie: s.movies[i].Movie(), SceneIndex: movie.SceneIndex})
+		}
+	}
+	scene.Galleries = []SceneGallery{}
+	for _, gallery := range m.Galleries {
+		if i := indexOf(s.galleries, gallery.ID); i >= 0 {
+			scene.Galleries = append(scene.Galleries, SceneGallery{ID: gallery.ID, Title: s.galleries[i].Title})
+		}
+	}
+	return scene
+}
+
+func (s *MemoryStash) gallery(g Gallery) Gallery {
+	g.Files = slices.Clone(g.Files)
+	g.Studio = s.studioRef(g.Studio)
+	g.Tags = s.tagRefs(g.Tags)
+	g.Performers = s.performerRefs(g.Performers)
+	g.ImageCount = 0
+	for _, image := range s.images {
+		if slices.Contains(image.Galleries, g.ID) {
+			g.ImageCount++
+		}
+	}
+	return g
+}
+
+func (s *MemoryStash) image(m memoryImage) Image {
+	image := m.Image
+	image.Files = slices.Clone(image.Files)
+	image.Studio = s.studioRef(image.Studio)
+	image.Tags = s.tagRefs(image.Tags)
+	image.Performers = s.performerRefs(image.Performers)
+	return image
+}
+
+func (s *MemoryStash) marker(m SceneMarker) SceneMarker {
+	if i := indexOf(s.tags, m.PrimaryTag.ID); i >= 0 {
+		m.PrimaryTag = s.tags[i].Tag()
+	}
+	m.Tags = s.tagRefs(m.Tags)
+	if i := indexOf(s.scenes, m.Scene.ID); i >= 0 {
+		m.Scene = s.scene(s.scenes[i])
+	}
+	return m
+}
+
+func (s *MemoryStash) performer(p Performer) Performer {
+	p.Aliases = slices.Clone(p.Aliases)
+	p.URLs = slices.Clone(p.URLs)
+	p.Tags = s.tagRefs(p.Tags)
+	p.SceneCount = 0
+	for _, scene := range s.scenes {
+		if containsID(scene.Performers, p.ID) {
+			p.SceneCount++
+		}
+	}
+	return p
+}
+
+func (s *MemoryStash) studio(st StudioDetail) StudioDetail {
+	st.Aliases = slices.Clone(st.Aliases)
+	st.ParentStudio = s.studioRef(st.ParentStudio)
+	st.ChildStudios = []Studio{}
+	for _, child := range s.studios {
+		if child.ParentStudio.ID == st.ID {
+			st.ChildStudios = append(st.ChildStudios, Studio{ID: child.ID, Name: child.Name})
+		}
+	}
+	// Counts include all sub-studios, as they do in stash.
+	studios := s.studioDescendants(st.ID, -1)
+	st.SceneCount, st.GalleryCount = 0, 0
+	for _, scene := range s.scenes {
+		if slices.Contains(studios, scene.Studio.ID) {
+			st.SceneCount++
+		}
+	}
+	for _, gallery := range s.galleries {
+		if slices.Contains(studios, gallery.Studio.ID) {
+			st.GalleryCount++
+		}
+	}
+	return st
+}
+
+func (s *MemoryStash) movie(m MovieDetail) MovieDetail {
+	m.Studio = s.studioRef(m.Studio)
+	m.SceneCount = 0
+	for _, scene := range s.scenes {
+		if slices.ContainsFunc(scene.Movies, func(movie SceneMovie) bool { return movie.Movie.ID == m.ID }) {
+			m.SceneCount++
+		}
+	}
+	return m
+}
+
+// tag returns a tag with its children and the counts of content tagged directly with it.
+func (s *MemoryStash) tag(t TagDetail) TagDetail {
+	t.Aliases = slices.Clone(t.Aliases)
+	t.Parents = s.tagRefs(t.Parents)
+	t.Children = []Tag{}
+	for _, child := range s.tags {
+		if containsID(child.Parents, t.ID) {
+			t.Children = append(t.Children, child.Tag())
+		}
+	}
+	t.SceneCount, t.GalleryCount, t.PerformerCount = 0, 0, 0
+	for _, scene := range s.scenes {
+		if containsID(scene.Tags, t.ID) {
+			t.SceneCount++
+		}
+	}
+	for _, gallery := range s.galleries {
+		if containsID(gallery.Tags, t.ID) {
+			t.GalleryCount++
+		}
+	}
+	for _, performer := range s.performers {
+		if containsID(performer.Tags, t.ID) {
+			t.PerformerCount++
+		}
+	}
+	return t
+}
+
+// tagDescendants returns id along with the IDs of its children to depth levels, or all levels if depth is negative.
+func (s *MemoryStash) tagDescendants(id string, depth int) []string {
+	return descendants(id, depth, func(id string) []string {
+		var children []string
+		for _, tag := range s.tags {
+			if containsID(tag.Parents, id) {
+				children = append(children, tag.ID)
+			}
+		}
+		return children
+	})
+}
+
+// tagAncestors returns id along with the IDs of its parents to depth levels, or all levels if depth is negative.
+func (s *MemoryStash) tagAncestors(id string, depth int) []string {
+	return descendants(id, depth, func(id string) []string {
+		if i := indexOf(s.tags, id); i >= 0 {
+			return entityIDs(s.tags[i].Parents)
+		}
+		return nil
+	})
+}
+
+// studioDescendants returns id along with the IDs of its sub-studios to depth levels, or all levels if depth is
+// negative.
+func (s *MemoryStash) studioDescendants(id string, depth int) []string {
+	return descendants(id, depth, func(id string) []string {
+		var children []string
+		for _, studio := range s.studios {
+			if studio.ParentStudio.ID == id {
+				children = append(children, studio.ID)
+			}
+		}
+		return children
+	})
+}
+
+// descendants walks a hierarchy from id with children, to depth levels or all levels if depth is negative.  Each ID is
+// visited once, so that cycles end the walk.
+func descendants(id string, depth int, children func(string) []string) []string {
+	ids := []string{id}
+	level := []string{id}
+	for d := 0; (depth < 0 || d < depth) && len(level) > 0; d++ {
+		var next []string
+		for _, parent := range level {
+			for _, child := range children(parent) {
+				if !slices.Contains(ids, child) {
+					ids = append(ids, child)
+					next = append(next, child)
+				}
+			}
+		}
+		level = next
+	}
+	return ids
+}
+
+// The following resolve IDs given in updates to the entities embedded in content, returning an error for IDs that do
+// not exist.
+
+func (s *MemoryStash) tagsByID(ids []graphql.ID) ([]Tag, error) {
+	tags := []Tag{}
+	for _, id := range ids {
+		if !containsID(s.tags, string(id)) {
+			return nil, fmt.Errorf("%w: %s", ErrTagNotFound, id)
+		}
+		if !containsID(tags, string(id)) {
+			tags = append(tags, Tag{ID: string(id)})
+		}
+	}
+	return tags, nil
+}
+
+func (s *MemoryStash) performersByID(ids []graphql.ID) ([]Performer, error) {
+	performers := []Performer{}
+	for _, id := range ids {
+		if !containsID(s.performers, string(id)) {
+			return nil, fmt.Errorf("performer %s not found", id)
+		}
+		if !containsID(performers, string(id)) {
+			performers = append(performers, Performer{ID: string(id)})
+		}
+	}
+	return performers, nil
+}
+
+func (s *MemoryStash) galleriesByID(ids []graphql.ID) ([]SceneGallery, error) {
+	galleries := []SceneGallery{}
+	for _, id := range ids {
+		if !containsID(s.galleries, string(id)) {
+			return nil, fmt.Errorf("gallery %s not found", id)
+		}
+		if !slices.ContainsFunc(galleries, func(g SceneGallery) bool { return g.ID == string(id) }) {
+			galleries = append(galleries, SceneGallery{ID: string(id)})
+		}
+	}
+	return galleries, nil
+}
+
+// studioByID returns the studio with id, or no studio if id is empty.
+func (s *MemoryStash) studioByID(id graphql.ID) (Studio, error) {
+	if id == "" {
+		return Studio{}, nil
+	}
+	if !containsID(s.studios, string(id)) {
+		return Studio{}, fmt.Errorf("studio %s not found", id)
+	}
+	return Studio{ID: string(id)}, nil
+}
+
+// applyBulkIDs sets, adds or removes the entities with the IDs of update from current.
+func applyBulkIDs[T entity](current []T, update *BulkUpdateIDs, resolve func([]graphql.ID) ([]T, error)) ([]T, error) {
+	if update == nil {
+		return current, nil
+	}
+	switch update.Mode {
+	case BulkUpdateIDModeSet:
+		return resolve(update.IDs)
+	case BulkUpdateIDModeAdd:
+		added, err := resolve(update.IDs)
+		if err != nil {
+			return nil, err
+		}
+		result := slices.Clone(current)
+		for _, item := range added {
+			if !containsID(result, item.EntityID()) {
+				result = append(result, item)
+			}
+		}
+		return result, nil
+	case BulkUpdateIDModeRemove:
+		return slices.DeleteFunc(slices.Clone(current), func(item T) bool {
+			return slices.Contains(update.IDs, graphql.ID(item.EntityID()))
+		}), nil
+	}
+	return nil, fmt.Errorf("unknown bulk update mode '%s'", update.Mode)
+}
+
+func (s *MemoryStash) Scenes(_ context.Context, f FindFilter, sf SceneFilter) ([]Scene, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := criteria{s: s}
+	var scenes []Scene
+	for _, m := range s.scenes {
+		scene := s.scene(m)
+		if matchesQuery(f.Query, scene.Title, scene.Details, scene.Code, strings.Join(scenePaths(scene), " ")) &&
+			c.scene(scene, m.PHash, sf) {
+			scenes = append(scenes, scene)
+		}
+		if c.err != nil {
+			return nil, 0, c.err
+		}
+	}
+
+	keys := sceneSortKeys
+	if f.Sort == SortMovieSceneIndex && sf.Movies != nil && len(sf.Movies.Value) > 0 {
+		keys = sceneSortKeys.with(SortMovieSceneIndex, func(a, b Scene) int {
+			return compareInts(sceneIndex(a, sf.Movies.Value[0]), sceneIndex(b, sf.Movies.Value[0]))
+		})
+	}
+	if err := sortEntities(scenes, f, keys); err != nil {
+		return nil, 0, err
+	}
+	return page(scenes, f), len(scenes), nil
+}
+
+func (s *MemoryStash) DeleteScene(ctx context.Context, id string) (bool, error) {
+	return s.ScenesDestroy(ctx, []string{id})
+}
+
+// removeScenes removes the scenes with ids along with their markers.
+func (s *MemoryStash) removeScenes(ids []string) {
+	s.scenes = slices.DeleteFunc(s.scenes, func(scene memoryScene) bool { return slices.Contains(ids, scene.ID) })
+	s.markers = slices.DeleteFunc(s.markers, func(marker SceneMarker) bool { return slices.Contains(ids, marker.Scene.ID) })
+}
+
+func (s *MemoryStash) SceneUpdate(_ context.Context, u SceneUpdate) (Scene, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := indexOf(s.scenes, string(u.ID))
+	if i < 0 {
+		return Scene{}, fmt.Errorf("scene %s not found", u.ID)
+	}
+	scene := s.scenes[i]
+	if err := s.applySceneUpdate(&scene, u); err != nil {
+		return Scene{}, err
+	}
+	s.scenes[i] = scene
+	return s.scene(scene), nil
+}
+
+func (s *MemoryStash) applySceneUpdate(scene *memoryScene, u SceneUpdate) error {
+	if u.Title != nil {
+		scene.Title = *u.Title
+	}
+	if u.Code != nil {
+		scene.Code = *u.Code
+	}
+	if u.Details != nil {
+		scene.Details = *u.Details
+	}
+	if u.Director != nil {
+		scene.Director = *u.Director
+	}
+	if u.URLs != nil {
+		scene.URLs = slices.Clone(*u.URLs)
+	}
+	if u.Date != nil {
+		scene.Date = *u.Date
+	}
+	if u.Rating != nil {
+		scene.Rating = *u.Rating
+	}
+	if u.Organized != nil {
+		scene.Organized = *u.Organized
+	}
+	if u.StudioID != nil {
+		studio, err := s.studioByID(*u.StudioID)
+		if err != nil {
+			return err
+		}
+		scene.Studio = studio
+	}
+	if u.GalleryIDs != nil {
+		galleries, err := s.galleriesByID(*u.GalleryIDs)
+		if err != nil {
+			return err
+		}
+		scene.Galleries = galleries
+	}
+	if u.PerformerIDs != nil {
+		performers, err := s.performersByID(*u.PerformerIDs)
+		if err != nil {
+			return err
+		}
+		scene.Performers = performers
+	}
+	if u.Movies != nil {
+		movies := []SceneMovie{}
+		for _, movie := range *u.Movies {
+			if !containsID(s.movies, string(movie.MovieID)) {
+				return fmt.Errorf("movie %s not found", movie.MovieID)
+			}
+			sceneMovie := SceneMovie{Movie: Movie{ID: string(movie.MovieID)}}
+			if movie.SceneIndex != nil {
+				sceneMovie.SceneIndex = *movie.SceneIndex
+			}
+			movies = append(movies, sceneMovie)
+		}
+		scene.Movies = movies
+	}
+	if u.TagIDs != nil {
+		tags, err := s.tagsByID(*u.TagIDs)
+		if err != nil {
+			return err
+		}
+		scene.Tags = tags
+	}
+	scene.UpdatedAt = s.now()
+	return nil
+}
+
+func (s *MemoryStash) BulkSceneUpdate(_ context.Context, u BulkSceneUpdate) ([]Scene, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Changes are applied to copies, so that no scene is changed if any change fails.
+	updated := make(map[int]memoryScene, len(u.IDs))
+	for _, id := range u.IDs {
+		i := indexOf(s.scenes, string(id))
+		if i < 0 {
+			return nil, fmt.Errorf("scene %s not found", id)
+		}
+		scene := s.scenes[i]
+		err := s.applySceneUpdate(&scene, SceneUpdate{
+			Date:      u.Date,
+			Rating:    u.Rating,
+			Organized: u.Organized,
+			StudioID:  u.StudioID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if scene.Performers, err = applyBulkIDs(scene.Performers, u.PerformerIDs, s.performersByID); err != nil {
+			return nil, err
+		}
+		if scene.Tags, err = applyBulkIDs(scene.Tags, u.TagIDs, s.tagsByID); err != nil {
+			return nil, err
+		}
+		updated[i] = scene
+	}
+
+	scenes := []Scene{}
+	for _, id := range u.IDs {
+		i := indexOf(s.scenes, string(id))
+		s.scenes[i] = updated[i]
+		scenes = append(scenes, s.scene(updated[i]))
+	}
+	return scenes, nil
+}
+
+func (s *MemoryStash) ScenesDestroy(_ context.Context, ids []string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, id := range ids {
+		if !containsID(s.scenes, id) {
+			return false, fmt.Errorf("scene %s not found", id)
+		}
+	}
+	s.removeScenes(ids)
+	return true, nil
+}
+
+func (s *MemoryStash) SceneMerge(_ context.Context, merge SceneMerge) (Scene, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := indexOf(s.scenes, string(merge.Destination))
+	if i < 0 {
+		return Scene{}, fmt.Errorf("scene %s not found", merge.Destination)
+	}
+	destination := s.scenes[i]
+	var sources []string
+	for _, id := range merge.Source {
+		j := indexOf(s.scenes, string(id))
+		if j < 0 {
+			return Scene{}, fmt.Errorf("scene %s not found", id)
+		}
+		if id == merge.Destination {
+			continue
+		}
+		destination.Files = append(destination.Files, s.scenes[j].Files...)
+		sources = append(sources, string(id))
+	}
+	if merge.Values != nil {
+		if err := s.applySceneUpdate(&destination, *merge.Values); err != nil {
+			return Scene{}, err
+		}
+	}
+
+	// Markers of the source scenes are kept on the destination.
+	for j, marker := range s.markers {
+		if slices.Contains(sources, marker.Scene.ID) {
+			s.markers[j].Scene = Scene{ID: destination.ID}
+		}
+	}
+	s.scenes[i] = destination
+	s.removeScenes(sources)
+	return s.scene(destination), nil
+}
+
+// FindDuplicateScenes groups scenes with perceptual hashes within distance bits of each other.
+func (s *MemoryStash) FindDuplicateScenes(_ context.Context, distance int, durationDiff float64) ([][]Scene, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	type hashed struct {
+		scene    memoryScene
+		hash     uint64
+		duration float64
+	}
+	var candidates []hashed
+	for _, scene := range s.scenes {
+		if scene.PHash == "" {
+			continue
+		}
+		hash, err := strconv.ParseUint(scene.PHash, 16, 64)
+		if err != nil {
+			return nil, fmt.Errorf("scene %s has an invalid phash: %w", scene.ID, err)
+		}
+		var duration float64
+		if len(scene.Files) > 0 {
+			duration = scene.Files[0].Duration
+		}
+		candidates = append(candidates, hashed{scene, hash, duration})
+	}
+	similar := func(a, b hashed) bool {
+		if durationDiff >= 0 && max(a.duration-b.duration, b.duration-a.duration) > durationDiff {
+			return false
+		}
+		return bits.OnesCount64(a.hash^b.hash) <= distance
+	}
+
+	// Groups are grown from each scene not yet grouped, so that scenes similar to any member of a group join it.
+	grouped := make([]bool, len(candidates))
+	groups := [][]Scene{}
+	for i := range candidates {
+		if grouped[i] {
+			continue
+		}
+		grouped[i] = true
+		members := []int{i}
+		for k := 0; k < len(members); k++ {
+			for j := range candidates {
+				if !grouped[j] && similar(candidates[members[k]], candidates[j]) {
+					grouped[j] = true
+					members = append(members, j)
+				}
+			}
+		}
+		if len(members) < 2 {
+			continue
+		}
+		group := make([]Scene, len(members))
+		for k, member := range members {
+			group[k] = s.scene(candidates[member].scene)
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+func (s *MemoryStash) RecordPlay(_ context.Context, id string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := indexOf(s.scenes, id)
+	if i < 0 {
+		return 0, fmt.Errorf("scene %s not found", id)
+	}
+	now := s.now()
+	s.scenes[i].PlayCount++
+	s.scenes[i].LastPlayedAt = &now
+	return s.scenes[i].PlayCount, nil
+}
+
+// SceneSaveActivity records the resume time of a scene.  Play duration is not tracked.
+func (s *MemoryStash) SceneSaveActivity(_ context.Context, id string, resumeTime, _ *float64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := indexOf(s.scenes, id)
+	if i < 0 {
+		return false, fmt.Errorf("scene %s not found", id)
+	}
+	if resumeTime != nil {
+		s.scenes[i].ResumeTime = *resumeTime
+	}
+	return true, nil
+}
+
+// SceneStreams returns a direct stream of each file of a scene as a file URL.
+func (s *MemoryStash) SceneStreams(_ context.Context, id string) ([]SceneStreamEndpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := indexOf(s.scenes, id)
+	if i < 0 {
+		return nil, fmt.Errorf("scene %s not found", id)
+	}
+	streams := []SceneStreamEndpoint{}
+	for _, file := range s.scenes[i].Files {
+		streams = append(streams, SceneStreamEndpoint{
+			URL:      (&url.URL{Scheme: "file", Path: filepath.ToSlash(file.Path)}).String(),
+			MimeType: mime.TypeByExtension(filepath.Ext(file.Path)),
+			Label:    "Direct stream",
+		})
+	}
+	return streams, nil
+}
+
+// changeSceneO sets the o-counter of a scene to the result of change.
+func (s *MemoryStash) changeSceneO(id string, change func(int) int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := indexOf(s.scenes, id)
+	if i < 0 {
+		return 0, fmt.Errorf("scene %s not found", id)
+	}
+	s.scenes[i].OCounter = change(s.scenes[i].OCounter)
+	return s.scenes[i].OCounter, nil
+}
+
+func (s *MemoryStash) SceneIncrementO(_ context.Context, id string) (int, error) {
+	return s.changeSceneO(id, func(o int) int { return o + 1 })
+}
+
+func (s *MemoryStash) SceneDecrementO(_ context.Context, id string) (int, error) {
+	return s.changeSceneO(id, func(o int) int { return max(o-1, 0) })
+}
+
+func (s *MemoryStash) SceneResetO(_ context.Context, id string) (int, error) {
+	return s.changeSceneO(id, func(int) int { return 0 })
+}
+
+func (s *MemoryStash) SceneMarkers(_ context.Context, f FindFilter, mf SceneMarkerFilter) ([]SceneMarker, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := criteria{s: s}
+	var markers []SceneMarker
+	for _, m := range s.markers {
+		marker := s.marker(m)
+		if matchesQuery(f.Query, marker.Title, marker.PrimaryTag.Name, marker.Scene.Title) && c.marker(marker, mf) {
+			markers = append(markers, marker)
+		}
+		if c.err != nil {
+			return nil, 0, c.err
+		}
+	}
+	if err := sortEntities(markers, f, markerSortKeys); err != nil {
+		return nil, 0, err
+	}
+	return page(markers, f), len(markers), nil
+}
+
+func (s *MemoryStash) Galleries(_ context.Context, f FindFilter, gf GalleryFilter) ([]Gallery, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := criteria{s: s}
+	var galleries []Gallery
+	for _, g := range s.galleries {
+		gallery := s.gallery(g)
+		if matchesQuery(f.Query, gallery.Title, gallery.Details, strings.Join(galleryPaths(gallery), " ")) &&
+			c.gallery(gallery, gf) {
+			galleries = append(galleries, gallery)
+		}
+		if c.err != nil {
+			return nil, 0, c.err
+		}
+	}
+	if err := sortEntities(galleries, f, gallerySortKeys); err != nil {
+		return nil, 0, err
+	}
+	return page(galleries, f), len(galleries), nil
+}
+
+func (s *MemoryStash) GalleryDelete(ctx context.Context, id string) (bool, error) {
+	return s.GalleriesDestroy(ctx, []string{id})
+}
+
+func (s *MemoryStash) GalleryUpdate(_ context.Context, u GalleryUpdate) (Gallery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := indexOf(s.galleries, string(u.ID))
+	if i < 0 {
+		return Gallery{}, fmt.Errorf("gallery %s not found", u.ID)
+	}
+	gallery := s.galleries[i]
+	if err := s.applyGalleryUpdate(&gallery, u); err != nil {
+		return Gallery{}, err
+	}
+
+	// Scenes are linked to galleries from the scene, so the scenes of a gallery are set by updating them.
+	if u.SceneIDs != nil {
+		for _, id := range u.SceneIDs {
+			if !containsID(s.scenes, string(id)) {
+				return Gallery{}, fmt.Errorf("scene %s not found", id)
+			}
+		}
+		for j, scene := range s.scenes {
+			linked := slices.Contains(u.SceneIDs, graphql.ID(scene.ID))
+			k := slices.IndexFunc(scene.Galleries, func(g SceneGallery) bool { return g.ID == gallery.ID })
+			if linked && k < 0 {
+				s.scenes[j].Galleries = append(slices.Clone(scene.Galleries), SceneGallery{ID: gallery.ID})
+			} else if !linked && k >= 0 {
+				s.scenes[j].Galleries = slices.Delete(slices.Clone(scene.Galleries), k, k+1)
+			}
+		}
+	}
+	s.galleries[i] = gallery
+	return s.gallery(gallery), nil
+}
+
+func (s *MemoryStash) applyGalleryUpdate(gallery *Gallery, u GalleryUpdate) error {
+	if u.Title != nil {
+		gallery.Title = *u.Title
+	}
+	if u.URL != nil {
+		gallery.URL = *u.URL
+	}
+	if u.Date != nil {
+		gallery.Date = *u.Date
+	}
+	if u.Details != nil {
+		gallery.Details = *u.Details
+	}
+	if u.Rating != nil {
+		gallery.Rating = *u.Rating
+	}
+	if u.Organized != nil {
+		gallery.Organized = *u.Organized
+	}
+	if u.StudioID != nil {
+		studio, err := s.studioByID(*u.StudioID)
+		if err != nil {
+			return err
+		}
+		gallery.Studio = studio
+	}
+	if u.TagIDs != nil {
+		tags, err := s.tagsByID(*u.TagIDs)
+		if err != nil {
+			return err
+		}
+		gallery.Tags = tags
+	}
+	if u.PerformerIDs != nil {
+		performers, err := s.performersByID(*u.PerformerIDs)
+		if err != nil {
+			return err
+		}
+		gallery.Performers = performers
+	}
+	gallery.UpdatedAt = s.now()
+	return nil
+}
+
+func (s *MemoryStash) BulkGalleryUpdate(_ context.Context, u BulkGalleryUpdate) ([]Gallery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	updated := make(map[int]Gallery, len(u.IDs))
+	for _, id := range u.IDs {
+		i := indexOf(s.galleries, string(id))
+		if i < 0 {
+			return nil, fmt.Errorf("gallery %s not found", id)
+		}
+		gallery := s.galleries[i]
+		err := s.applyGalleryUpdate(&gallery, GalleryUpdate{
+			Date:      u.Date,
+			Rating:    u.Rating,
+			Organized: u.Organized,
+			StudioID:  u.StudioID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if gallery.Performers, err = applyBulkIDs(gallery.Performers, u.PerformerIDs, s.performersByID); err != nil {
+			return nil, err
+		}
+		if gallery.Tags, err = applyBulkIDs(gallery.Tags, u.TagIDs, s.tagsByID); err != nil {
+			return nil, err
+		}
+		updated[i] = gallery
+	}
+
+	galleries := []Gallery{}
+	for _, id := range u.IDs {
+		i := indexOf(s.galleries, string(id))
+		s.galleries[i] = updated[i]
+		galleries = append(galleries, s.gallery(updated[i]))
+	}
+	return galleries, nil
+}
+
+// GalleriesDestroy removes galleries.  Their images are kept, no longer belonging to them.
+func (s *MemoryStash) GalleriesDestroy(_ context.Context, ids []string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, id := range ids {
+		if !containsID(s.galleries, id) {
+			return false, fmt.Errorf("gallery %s not found", id)
+		}
+	}
+	s.galleries = slices.DeleteFunc(s.galleries, func(g Gallery) bool { return slices.Contains(ids, g.ID) })
+	for i, image := range s.images {
+		s.images[i].Galleries = slices.DeleteFunc(slices.Clone(image.Galleries), func(id string) bool {
+			return slices.Contains(ids, id)
+		})
+	}
+	return true, nil
+}
+
+func (s *MemoryStash) Images(_ context.Context, f FindFilter, imf ImageFilter) ([]Image, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := criteria{s: s}
+	var images []Image
+	for _, m := range s.images {
+		image := s.image(m)
+		if matchesQuery(f.Query, image.Title, strings.Join(imagePaths(image), " ")) && c.image(image, m.Galleries, imf) {
+			images = append(images, image)
+		}
+		if c.err != nil {
+			return nil, 0, c.err
+		}
+	}
+	if err := sortEntities(images, f, imageSortKeys); err != nil {
+		return nil, 0, err
+	}
+	return page(images, f), len(images), nil
+}
+
+func (s *MemoryStash) ImageDelete(_ context.Context, id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !containsID(s.images, id) {
+		return false, fmt.Errorf("image %s not found", id)
+	}
+	s.images = slices.DeleteFunc(s.images, func(image memoryImage) bool { return image.ID == id })
+	return true, nil
+}
+
+func (s *MemoryStash) ImageUpdate(_ context.Context, u ImageUpdate) (Image, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := indexOf(s.images, string(u.ID))
+	if i < 0 {
+		return Image{}, fmt.Errorf("image %s not found", u.ID)
+	}
+	image := s.images[i]
+	if u.Title != nil {
+		image.Title = *u.Title
+	}
+	if u.Date != nil {
+		image.Date = *u.Date
+	}
+	if u.Rating != nil {
+		image.Rating = *u.Rating
+	}
+	if u.Organized != nil {
+		image.Organized = *u.Organized
+	}
+	if u.StudioID != nil {
+		studio, err := s.studioByID(*u.StudioID)
+		if err != nil {
+			return Image{}, err
+		}
+		image.Studio = studio
+	}
+	if u.TagIDs != nil {
+		tags, err := s.tagsByID(*u.TagIDs)
+		if err != nil {
+			return Image{}, err
+		}
+		image.Tags = tags
+	}
+	if u.PerformerIDs != nil {
+		performers, err := s.performersByID(u.PerformerIDs)
+		if err != nil {
+			return Image{}, err
+		}
+		image.Performers = performers
+	}
+	image.UpdatedAt = s.now()
+	s.images[i] = image
+	return s.image(image), nil
+}
+
+// changeImageO sets the o-counter of an image to the result of change.
+func (s *MemoryStash) changeImageO(id string, change func(int) int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := indexOf(s.images, id)
+	if i < 0 {
+		return 0, fmt.Errorf("image %s not found", id)
+	}
+	s.images[i].OCounter = change(s.images[i].OCounter)
+	return s.images[i].OCounter, nil
+}
+
+func (s *MemoryStash) ImageIncrementO(_ context.Context, id string) (int, error) {
+	return s.changeImageO(id, func(o int) int { return o + 1 })
+}
+
+func (s *MemoryStash) ImageDecrementO(_ context.Context, id string) (int, error) {
+	return s.changeImageO(id, func(o int) int { return max(o-1, 0) })
+}
+
+func (s *MemoryStash) ImageResetO(_ context.Context, id string) (int, error) {
+	return s.changeImageO(id, func(int) int { return 0 })
+}
+
+func (s *MemoryStash) Performers(_ context.Context, f FindFilter, pf PerformerFilter) ([]Performer, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := criteria{s: s}
+	var performers []Performer
+	for _, p := range s.performers {
+		performer := s.performer(p)
+		if matchesQuery(f.Query, performer.Name, performer.Disambiguation, strings.Join(performer.Aliases, " ")) &&
+			c.performer(performer, pf) {
+			performers = append(performers, performer)
+		}
+		if c.err != nil {
+			return nil, 0, c.err
+		}
+	}
+	if err := sortEntities(performers, f, performerSortKeys); err != nil {
+		return nil, 0, err
+	}
+	return page(performers, f), len(performers), nil
+}
+
+func (s *MemoryStash) PerformersAll(context.Context) ([]PerformerSummary, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	performers := make([]PerformerSummary, len(s.performers))
+	for i, p := range s.performers {
+		performers[i] = PerformerSummary{
+			ID:             p.ID,
+			Name:           p.Name,
+			Disambiguation: p.Disambiguation,
+			Aliases:        slices.Clone(p.Aliases),
+		}
+	}
+	return performers, nil
+}
+
+// PerformerCreate creates a performer.  As in stash, the name and disambiguation of a performer must be unique.
+func (s *MemoryStash) PerformerCreate(_ context.Context, c PerformerCreate) (Performer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkPerformerName("", c.Name, c.Disambiguation); err != nil {
+		return Performer{}, err
+	}
+	tags, err := s.tagsByID(c.TagIDs)
+	if err != nil {
+		return Performer{}, err
+	}
+	p := Performer{
+		ID:             s.nextID(),
+		Name:           c.Name,
+		Disambiguation: c.Disambiguation,
+		URLs:           slices.Clone(c.URLs),
+		Aliases:        slices.Clone(c.Aliases),
+		Tags:           tags,
+	}
+	if c.URL != nil {
+		p.URL = *c.URL
+	}
+	if c.Gender != nil {
+		p.Gender = *c.Gender
+	}
+	if c.Birthdate != nil {
+		p.Birthdate = *c.Birthdate
+	}
+	if c.Country != nil {
+		p.Country = Country(*c.Country)
+	}
+	if c.Favorite != nil {
+		p.Favorite = *c.Favorite
+	}
+	s.performers = append(s.performers, p)
+	return s.performer(p), nil
+}
+
+func (s *MemoryStash) checkPerformerName(id, name, disambiguation string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("performer name must not be blank")
+	}
+	for _, p := range s.performers {
+		if p.ID != id && strings.EqualFold(p.Name, name) && strings.EqualFold(p.Disambiguation, disambiguation) {
+			return fmt.Errorf("performer with name '%s' already exists", name)
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStash) PerformerUpdate(_ context.Context, u PerformerUpdate) (Performer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := indexOf(s.performers, string(u.ID))
+	if i < 0 {
+		return Performer{}, fmt.Errorf("performer %s not found", u.ID)
+	}
+	p := s.performers[i]
+	if u.Name != nil {
+		p.Name = *u.Name
+	}
+	if u.Disambiguation != nil {
+		p.Disambiguation = *u.Disambiguation
+	}
+	if err := s.checkPerformerName(p.ID, p.Name, p.Disambiguation); err != nil {
+		return Performer{}, err
+	}
+	if u.URLs != nil {
+		p.URLs = slices.Clone(*u.URLs)
+	}
+	if u.Aliases != nil {
+		p.Aliases = slices.Clone(*u.Aliases)
+	}
+	if u.Gender != nil {
+		p.Gender = *u.Gender
+	}
+	if u.Birthdate != nil {
+		p.Birthdate = *u.Birthdate
+	}
+	if u.Country != nil {
+		p.Country = Country(*u.Country)
+	}
+	if u.Favorite != nil {
+		p.Favorite = *u.Favorite
+	}
+	if u.TagIDs != nil {
+		tags, err := s.tagsByID(*u.TagIDs)
+		if err != nil {
+			return Performer{}, err
+		}
+		p.Tags = tags
+	}
+	s.performers[i] = p
+	return s.performer(p), nil
+}
+
+func (s *MemoryStash) PerformerGet(_ context.Context, id string) (Performer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := indexOf(s.performers, id)
+	if i < 0 {
+		return Performer{}, fmt.Errorf("performer %s not found", id)
+	}
+	return s.performer(s.performers[i]), nil
+}
+
+func (s *MemoryStash) Studios(_ context.Context, f FindFilter, sf StudioFilter) ([]StudioDetail, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := criteria{s: s}
+	var studios []StudioDetail
+	for _, st := range s.studios {
+		studio := s.studio(st)
+		if matchesQuery(f.Query, studio.Name, strings.Join(studio.Aliases, " ")) && c.studio(studio, sf) {
+			studios = append(studios, studio)
+		}
+		if c.err != nil {
+			return nil, 0, c.err
+		}
+	}
+	if err := sortEntities(studios, f, studioSortKeys); err != nil {
+		return nil, 0, err
+	}
+	return page(studios, f), len(studios), nil
+}
+
+func (s *MemoryStash) StudiosAll(context.Context) ([]Studio, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	studios := make([]Studio, len(s.studios))
+	for i, st := range s.studios {
+		studios[i] = Studio{ID: st.ID, Name: st.Name}
+	}
+	return studios, nil
+}
+
+// StudioCreate creates a studio.  As in stash, the name of a studio must be unique.
+func (s *MemoryStash) StudioCreate(_ context.Context, c StudioCreate) (Studio, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if strings.TrimSpace(c.Name) == "" {
+		return Studio{}, fmt.Errorf("studio name must not be blank")
+	}
+	for _, st := range s.studios {
+		if strings.EqualFold(st.Name, c.Name) {
+			return Studio{}, fmt.Errorf("studio with name '%s' already exists", c.Name)
+		}
+	}
+	studio := StudioDetail{ID: s.nextID(), Name: c.Name}
+	if c.URL != nil {
+		studio.URL = *c.URL
+	}
+	s.studios = append(s.studios, studio)
+	return Studio{ID: studio.ID, Name: studio.Name}, nil
+}
+
+func (s *MemoryStash) Movies(_ context.Context, f FindFilter, mf MovieFilter) ([]MovieDetail, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := criteria{s: s}
+	var movies []MovieDetail
+	for _, m := range s.movies {
+		movie := s.movie(m)
+		if matchesQuery(f.Query, movie.Name, movie.Aliases) && c.movie(movie, mf) {
+			movies = append(movies, movie)
+		}
+		if c.err != nil {
+			return nil, 0, c.err
+		}
+	}
+	if err := sortEntities(movies, f, movieSortKeys); err != nil {
+		return nil, 0, err
+	}
+	return page(movies, f), len(movies), nil
+}
+
+func (s *MemoryStash) MovieCreate(_ context.Context, c MovieCreate) (MovieDetail, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	movie := MovieDetail{ID: s.nextID(), CreatedAt: now}
+	err := s.applyMovieUpdate(&movie, MovieUpdate{
+		Name:     &c.Name,
+		Aliases:  c.Aliases,
+		Duration: c.Duration,
+		Date:     c.Date,
+		StudioID: c.StudioID,
+		Director: c.Director,
+		Synopsis: c.Synopsis,
+		URL:      c.URL,
+	})
+	if err != nil {
+		return MovieDetail{}, err
+	}
+	s.movies = append(s.movies, movie)
+	return s.movie(movie), nil
+}
+
+func (s *MemoryStash) MovieUpdate(_ context.Context, u MovieUpdate) (MovieDetail, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := indexOf(s.movies, string(u.ID))
+	if i < 0 {
+		return MovieDetail{}, fmt.Errorf("movie %s not found", u.ID)
+	}
+	movie := s.movies[i]
+	if err := s.applyMovieUpdate(&movie, u); err != nil {
+		return MovieDetail{}, err
+	}
+	s.movies[i] = movie
+	return s.movie(movie), nil
+}
+
+func (s *MemoryStash) applyMovieUpdate(movie *MovieDetail, u MovieUpdate) error {
+	if u.Name != nil {
+		if strings.TrimSpace(*u.Name) == "" {
+			return fmt.Errorf("movie name must not be blank")
+		}
+		movie.Name = *u.Name
+	}
+	if u.Aliases != nil {
+		movie.Aliases = *u.Aliases
+	}
+	if u.Duration != nil {
+		movie.Duration = *u.Duration
+	}
+	if u.Date != nil {
+		movie.Date = *u.Date
+	}
+	if u.Rating != nil {
+		movie.Rating = *u.Rating
+	}
+	if u.StudioID != nil {
+		studio, err := s.studioByID(*u.StudioID)
+		if err != nil {
+			return err
+		}
+		movie.Studio = studio
+	}
+	if u.Director != nil {
+		movie.Director = *u.Director
+	}
+	if u.Synopsis != nil {
+		movie.Synopsis = *u.Synopsis
+	}
+	if u.URL != nil {
+		movie.URL = *u.URL
+	}
+	movie.UpdatedAt = s.now()
+	return nil
+}
+
+func (s *MemoryStash) TagGet(_ context.Context, id string) (Tag, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := indexOf(s.tags, id)
+	if i < 0 {
+		return Tag{}, fmt.Errorf("%w: %s", ErrTagNotFound, id)
+	}
+	return s.tags[i].Tag(), nil
+}
+
+// TagCreate creates a tag.  As in stash, the name of a tag must not be used by another tag, either as a name or an
+// alias.
+func (s *MemoryStash) TagCreate(_ context.Context, c TagCreate) (Tag, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkTagName("", c.Name); err != nil {
+		return Tag{}, err
+	}
+	tag := TagDetail{ID: s.nextID(), Name: c.Name}
+	s.tags = append(s.tags, tag)
+	return tag.Tag(), nil
+}
+
+func (s *MemoryStash) checkTagName(id, name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("tag name must not be blank")
+	}
+	for _, tag := range s.tags {
+		if tag.ID == id {
+			continue
+		}
+		if strings.EqualFold(tag.Name, name) {
+			return fmt.Errorf("tag with name '%s' already exists", name)
+		}
+		if slices.ContainsFunc(tag.Aliases, func(alias string) bool { return strings.EqualFold(alias, name) }) {
+			return fmt.Errorf("name '%s' is used as an alias of tag '%s'", name, tag.Name)
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStash) TagFindByName(_ context.Context, name string) (Tag, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, tag := range s.tags {
+		if strings.EqualFold(tag.Name, name) {
+			return tag.Tag(), nil
+		}
+	}
+	return Tag{}, fmt.Errorf("%w: %s", ErrTagNotFound, name)
+}
+
+func (s *MemoryStash) TagsAll(context.Context) ([]Tag, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tags := make([]Tag, len(s.tags))
+	for i, tag := range s.tags {
+		tags[i] = tag.Tag()
+	}
+	return tags, nil
+}
+
+func (s *MemoryStash) Tags(_ context.Context, f FindFilter, tf TagFilter) ([]TagDetail, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := criteria{s: s}
+	var tags []TagDetail
+	for _, t := range s.tags {
+		tag := s.tag(t)
+		if matchesQuery(f.Query, tag.Name, strings.Join(tag.Aliases, " ")) && c.tag(tag, tf) {
+			tags = append(tags, tag)
+		}
+		if c.err != nil {
+			return nil, 0, c.err
+		}
+	}
+	if err := sortEntities(tags, f, tagSortKeys); err != nil {
+		return nil, 0, err
+	}
+	return page(tags, f), len(tags), nil
+}
+
+func (s *MemoryStash) TagUpdate(_ context.Context, u TagUpdate) (TagDetail, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := indexOf(s.tags, string(u.ID))
+	if i < 0 {
+		return TagDetail{}, fmt.Errorf("%w: %s", ErrTagNotFound, u.ID)
+	}
+	tag := s.tags[i]
+	if u.Name != nil {
+		if err := s.checkTagName(tag.ID, *u.Name); err != nil {
+			return TagDetail{}, err
+		}
+		tag.Name = *u.Name
+	}
+	if u.Description != nil {
+		tag.Description = *u.Description
+	}
+	if u.Aliases != nil {
+		tag.Aliases = slices.Clone(*u.Aliases)
+	}
+	if u.ParentIDs != nil {
+		parents, err := s.tagsByID(*u.ParentIDs)
+		if err != nil {
+			return TagDetail{}, err
+		}
+		// A tag can't be its own ancestor.
+		descendants := s.tagDescendants(tag.ID, -1)
+		for _, parent := range parents {
+			if slices.Contains(descendants, parent.ID) {
+				return TagDetail{}, fmt.Errorf("tag '%s' can't be a parent of itself or its children", s.tags[indexOf(s.tags, parent.ID)].Name)
+			}
+		}
+		tag.Parents = parents
+	}
+	s.tags[i] = tag
+	return s.tag(tag), nil
+}
+
+// TagsMerge retags content tagged with a source tag with the destination, adds the names of the source tags as aliases
+// of the destination, and removes the source tags.
+func (s *MemoryStash) TagsMerge(_ context.Context, merge TagsMerge) (TagDetail, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := indexOf(s.tags, string(merge.Destination))
+	if i < 0 {
+		return TagDetail{}, fmt.Errorf("%w: %s", ErrTagNotFound, merge.Destination)
+	}
+	destination := s.tags[i]
+	var sources []string
+	for _, id := range merge.Source {
+		j := indexOf(s.tags, string(id))
+		if j < 0 {
+			return TagDetail{}, fmt.Errorf("%w: %s", ErrTagNotFound, id)
+		}
+		if id == merge.Destination {
+			continue
+		}
+		sources = append(sources, string(id))
+		destination.Aliases = append(slices.Clone(destination.Aliases), s.tags[j].Name)
+		destination.Aliases = append(destination.Aliases, s.tags[j].Aliases...)
+		destination.Parents = append(slices.Clone(destination.Parents), s.tags[j].Parents...)
+	}
+	destination.Parents = slices.DeleteFunc(destination.Parents, func(t Tag) bool {
+		return t.ID == destination.ID || slices.Contains(sources, t.ID)
+	})
+	destination.Parents = s.retag(destination.Parents, sources, "")
+	s.tags[i] = destination
+
+	s.retagAll(sources, destination.ID)
+	s.tags = slices.DeleteFunc(s.tags, func(t TagDetail) bool { return slices.Contains(sources, t.ID) })
+	return s.tag(destination), nil
+}
+
+// retag replaces the tags with ids in tags with the tag to, or removes them if to is empty.  Tags are not repeated.
+func (s *MemoryStash) retag(tags []Tag, ids []string, to string) []Tag {
+	result := []Tag{}
+	for _, tag := range tags {
+		if slices.Contains(ids, tag.ID) {
+			if to == "" {
+				continue
+			}
+			tag = Tag{ID: to}
+		}
+		if !containsID(result, tag.ID) {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+// retagAll retags all content and child tags tagged with the tags with ids.
+func (s *MemoryStash) retagAll(ids []string, to string) {
+	for i := range s.scenes {
+		s.scenes[i].Tags = s.retag(s.scenes[i].Tags, ids, to)
+	}
+	for i := range s.galleries {
+		s.galleries[i].Tags = s.retag(s.galleries[i].Tags, ids, to)
+	}
+	for i := range s.images {
+		s.images[i].Tags = s.retag(s.images[i].Tags, ids, to)
+	}
+	for i := range s.performers {
+		s.performers[i].Tags = s.retag(s.performers[i].Tags, ids, to)
+	}
+	for i := range s.markers {
+		s.markers[i].Tags = s.retag(s.markers[i].Tags, ids, to)
+		if slices.Contains(ids, s.markers[i].PrimaryTag.ID) {
+			s.markers[i].PrimaryTag = Tag{ID: to}
+		}
+	}
+	for i := range s.tags {
+		if s.tags[i].ID != to {
+			s.tags[i].Parents = s.retag(s.tags[i].Parents, ids, to)
+		}
+	}
+	// Markers must have a primary tag, so those left without one are removed.
+	s.markers = slices.DeleteFunc(s.markers, func(m SceneMarker) bool { return m.PrimaryTag.ID == "" })
+}
+
+// TagDelete removes a tag from all content and child tags.  Markers with it as their primary tag are removed.
+func (s *MemoryStash) TagDelete(_ context.Context, id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !containsID(s.tags, id) {
+		return false, fmt.Errorf("%w: %s", ErrTagNotFound, id)
+	}
+	s.retagAll([]string{id}, "")
+	s.tags = slices.DeleteFunc(s.tags, func(t TagDetail) bool { return t.ID == id })
+	return true, nil
+}
+
+// startJob records a job with description.  There is nothing for jobs to do in memory, so they finish immediately.
+func (s *MemoryStash) startJob(description string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	progress := 1.0
+	job := Job{
+		ID:          s.nextID(),
+		Status:      JobStatusFinished,
+		Description: description,
+		Progress:    &progress,
+		StartTime:   &now,
+		EndTime:     &now,
+		AddTime:     now,
+	}
+	s.jobs = append(s.jobs, job)
+	return job.ID
+}
+
+func (s *MemoryStash) MetadataScan(context.Context, ScanMetadata) (string, error) {
+	return s.startJob("Scanning..."), nil
+}
+
+func (s *MemoryStash) MetadataGenerate(context.Context, GenerateMetadata) (string, error) {
+	return s.startJob("Generating content"), nil
+}
+
+func (s *MemoryStash) MetadataAutoTag(context.Context, AutoTagMetadata) (string, error) {
+	return s.startJob("Auto tagging..."), nil
+}
+
+func (s *MemoryStash) MetadataIdentify(context.Context, IdentifyMetadata) (string, error) {
+	return s.startJob("Identifying scenes"), nil
+}
+
+func (s *MemoryStash) MetadataClean(context.Context, CleanMetadata) (string, error) {
+	return s.startJob("Cleaning..."), nil
+}
+
+func (s *MemoryStash) JobQueue(context.Context) ([]Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobs := []Job{}
+	for _, job := range s.jobs {
+		if !job.Done() {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, nil
+}
+
+func (s *MemoryStash) FindJob(_ context.Context, id string) (Job, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, job := range s.jobs {
+		if job.ID == id {
+			return job, true, nil
+		}
+	}
+	return Job{}, false, nil
+}
+
+func (s *MemoryStash) StopJob(_ context.Context, id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, job := range s.jobs {
+		if job.ID == id {
+			if !job.Done() {
+				s.jobs[i].Status = JobStatusCancelled
+			}
+			return true, nil
+		}
+	}
+	return false, fmt.Errorf("job %s not found", id)
+}
+
+func (s *MemoryStash) SavedFilters(_ context.Context, mode FilterMode) ([]SavedFilter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	filters := []SavedFilter{}
+	for _, filter := range s.savedFilters {
+		if filter.Mode == mode {
+			filters = append(filters, filter)
+		}
+	}
+	return filters, nil
+}
+
+func (s *MemoryStash) DefaultFilter(_ context.Context, mode FilterMode) (SavedFilter, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	filter, ok := s.defaultFilters[mode]
+	return filter, ok, nil
+}
+
+func (s *MemoryStash) SaveFilter(_ context.Context, input SaveFilter) (SavedFilter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	objectFilter, err := json.Marshal(input.ObjectFilter)
+	if err != nil {
+		return SavedFilter{}, err
+	}
+	filter := SavedFilter{
+		Mode:         input.Mode,
+		Name:         input.Name,
+		ObjectFilter: objectFilter,
+	}
+	if input.FindFilter != nil {
+		filter.FindFilter = &SavedFindFilter{
+			Query:     input.FindFilter.Query,
+			PerPage:   input.FindFilter.PerPage,
+			Sort:      input.FindFilter.Sort,
+			Direction: input.FindFilter.Direction,
+		}
+	}
+
+	if input.ID != nil {
+		i := slices.IndexFunc(s.savedFilters, func(f SavedFilter) bool { return f.ID == *input.ID })
+		if i < 0 {
+			return SavedFilter{}, fmt.Errorf("saved filter %s not found", *input.ID)
+		}
+		filter.ID = *input.ID
+		s.savedFilters[i] = filter
+		return filter, nil
+	}
+	filter.ID = s.nextID()
+	s.savedFilters = append(s.savedFilters, filter)
+	return filter, nil
+}
+
+func (s *MemoryStash) DestroySavedFilter(_ context.Context, id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := slices.IndexFunc(s.savedFilters, func(f SavedFilter) bool { return f.ID == id })
+	if i < 0 {
+		return false, fmt.Errorf("saved filter %s not found", id)
+	}
+	s.savedFilters = slices.Delete(s.savedFilters, i, i+1)
+	return true, nil
+}
+
+// ListScrapers returns no scrapers, as scraping needs a stash instance.
+func (s *MemoryStash) ListScrapers(context.Context, []ScrapeContentType) ([]Scraper, error) {
+	return []Scraper{}, nil
+}
+
+func (s *MemoryStash) ScrapeSingleScene(context.Context, ScraperSource, ScrapeSingleScene) ([]ScrapedScene, error) {
+	return nil, fmt.Errorf("scrapers are not available in memory")
+}
+
+func (s *MemoryStash) ScrapeSingleGallery(context.Context, ScraperSource, ScrapeSingleGallery) ([]ScrapedGallery, error) {
+	return nil, fmt.Errorf("scrapers are not available in memory")
+}
+
+func (s *MemoryStash) ScrapeSceneURL(context.Context, string) (ScrapedScene, bool, error) {
+	return ScrapedScene{}, false, nil
+}
+
+func (s *MemoryStash) ScrapeGalleryURL(context.Context, string) (ScrapedGallery, bool, error) {
+	return ScrapedGallery{}, false, nil
+}
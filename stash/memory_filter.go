@@ -0,0 +1,850 @@
+package stash
+
+import (
+	"cmp"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// criteria matches the entities of a MemoryStash against filter criteria.  Criteria that can't be applied, such as an
+// invalid regular expression or a modifier that makes no sense for a criterion, record an error in err and match
+// nothing, so that a filter is never silently ignored.
+type criteria struct {
+	s   *MemoryStash
+	err error
+}
+
+func (c *criteria) fail(err error) bool {
+	if c.err == nil {
+		c.err = err
+	}
+	return false
+}
+
+func (c *criteria) unsupportedModifier(name string, modifier CriterionModifier) bool {
+	return c.fail(fmt.Errorf("modifier %s is not supported for %s", modifier, name))
+}
+
+// supported fails if any criterion of filter is set other than those named in fields, which are the names of the
+// struct fields that are applied.  Embedded combinators are always supported.
+func (c *criteria) supported(filter any, fields ...string) bool {
+	v := reflect.ValueOf(filter)
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous || slices.Contains(fields, field.Name) || v.Field(i).IsZero() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		return c.fail(fmt.Errorf("%s criterion is not supported", name))
+	}
+	return true
+}
+
+// combine applies the AND, OR and NOT filters of a combinator to the result of the filter holding them.
+func combine[T any](matched bool, and, or, not *T, match func(T) bool) bool {
+	if and != nil {
+		matched = matched && match(*and)
+	}
+	if or != nil {
+		matched = matched || match(*or)
+	}
+	if not != nil {
+		matched = matched && !match(*not)
+	}
+	return matched
+}
+
+// matchesQuery reports whether each word of query is found in any of fields, ignoring case.
+func matchesQuery(query string, fields ...string) bool {
+	text := strings.ToLower(strings.Join(fields, "\n"))
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		if !strings.Contains(text, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// str matches any of values against a string criterion.  Negative modifiers match when none of values match, and
+// comparisons ignore case as they do in stash.
+func (c *criteria) str(name string, cr *StringCriterion, values ...string) bool {
+	if cr == nil {
+		return true
+	}
+	var match func(string) bool
+	negate := false
+	switch cr.Modifier {
+	case CriterionModifierEquals, CriterionModifierNotEquals:
+		match = func(v string) bool { return strings.EqualFold(v, cr.Value) }
+		negate = cr.Modifier == CriterionModifierNotEquals
+	case CriterionModifierIncludes, CriterionModifierExcludes:
+		value := strings.ToLower(cr.Value)
+		match = func(v string) bool { return strings.Contains(strings.ToLower(v), value) }
+		negate = cr.Modifier == CriterionModifierExcludes
+	case CriterionModifierMatchesRegex, CriterionModifierNotMatchesRegex:
+		re, err := regexp.Compile("(?i)" + cr.Value)
+		if err != nil {
+			return c.fail(fmt.Errorf("invalid %s regex: %w", name, err))
+		}
+		match = re.MatchString
+		negate = cr.Modifier == CriterionModifierNotMatchesRegex
+	case CriterionModifierIsNull, CriterionModifierNotNull:
+		match = func(v string) bool { return v != "" }
+		negate = cr.Modifier == CriterionModifierIsNull
+	default:
+		return c.unsupportedModifier(name, cr.Modifier)
+	}
+	return slices.ContainsFunc(values, match) != negate
+}
+
+func (c *criteria) int(name string, cr *IntCriterion, value int) bool {
+	if cr == nil {
+		return true
+	}
+	switch cr.Modifier {
+	case CriterionModifierEquals:
+		return value == cr.Value
+	case CriterionModifierNotEquals:
+		return value != cr.Value
+	case CriterionModifierGreaterThan:
+		return value > cr.Value
+	case CriterionModifierLessThan:
+		return value < cr.Value
+	case CriterionModifierIsNull:
+		return value == 0
+	case CriterionModifierNotNull:
+		return value != 0
+	case CriterionModifierBetween, CriterionModifierNotBetween:
+		if cr.Value2 == nil {
+			return c.fail(fmt.Errorf("%s criterion needs a second value for %s", name, cr.Modifier))
+		}
+		between := value >= cr.Value && value <= *cr.Value2
+		return between == (cr.Modifier == CriterionModifierBetween)
+	}
+	return c.unsupportedModifier(name, cr.Modifier)
+}
+
+// id matches the numeric value of an ID against an int criterion.
+func (c *criteria) id(cr *IntCriterion, id string) bool {
+	if cr == nil {
+		return true
+	}
+	n, err := strconv.Atoi(id)
+	if err != nil {
+		return c.fail(fmt.Errorf("id %s is not numeric", id))
+	}
+	return c.int("id", cr, n)
+}
+
+// time matches value against a range given by a criterion.  A zero value is null.
+func (c *criteria) time(name string, modifier CriterionModifier, value, from time.Time, to *time.Time) bool {
+	switch modifier {
+	case CriterionModifierEquals:
+		return !value.IsZero() && value.Equal(from)
+	case CriterionModifierNotEquals:
+		return !value.Equal(from)
+	case CriterionModifierGreaterThan:
+		return value.After(from)
+	case CriterionModifierLessThan:
+		return !value.IsZero() && value.Before(from)
+	case CriterionModifierIsNull:
+		return value.IsZero()
+	case CriterionModifierNotNull:
+		return !value.IsZero()
+	case CriterionModifierBetween, CriterionModifierNotBetween:
+		if to == nil {
+			return c.fail(fmt.Errorf("%s criterion needs a second value for %s", name, modifier))
+		}
+		between := !value.IsZero() && !value.Before(from) && !value.After(*to)
+		return between == (modifier == CriterionModifierBetween)
+	}
+	return c.unsupportedModifier(name, modifier)
+}
+
+// date matches a date in the form 2006-01-02 against a date criterion.  Dates that can't be parsed are null.
+func (c *criteria) date(name string, cr *DateCriterion, date string) bool {
+	if cr == nil {
+		return true
+	}
+	value, _ := time.Parse("2006-01-02", date)
+	return c.time(name, cr.Modifier, value, cr.Value, cr.Value2)
+}
+
+func (c *criteria) timestamp(name string, cr *TimestampCriterion, value time.Time) bool {
+	if cr == nil {
+		return true
+	}
+	return c.time(name, cr.Modifier, value, cr.Value, cr.Value2)
+}
+
+func (c *criteria) bool(cr *bool, value bool) bool {
+	return cr == nil || *cr == value
+}
+
+// multi matches the IDs of the entities linked to an entity against a multi criterion.
+func (c *criteria) multi(name string, cr *MultiCriterion, ids []string) bool {
+	if cr == nil {
+		return true
+	}
+	return c.set(name, cr.Modifier, ids, cr.Value, func(value string) []string { return []string{value} })
+}
+
+// hierarchical matches the IDs of the entities linked to an entity against a hierarchical criterion.  Each value
+// matches itself and the entities beneath it to the depth of the criterion, as given by expand.
+func (c *criteria) hierarchical(name string, cr *HierarchicalMultiCriterion, ids []string, expand func(string, int) []string) bool {
+	if cr == nil {
+		return true
+	}
+	return c.set(name, cr.Modifier, ids, cr.Value, func(value string) []string {
+		return slices.DeleteFunc(expand(value, cr.Depth), func(id string) bool {
+			return id != value && slices.Contains(cr.Excludes, id)
+		})
+	})
+}
+
+func (c *criteria) set(name string, modifier CriterionModifier, ids, values []string, expand func(string) []string) bool {
+	matches := func(value string) bool {
+		return slices.ContainsFunc(expand(value), func(id string) bool { return slices.Contains(ids, id) })
+	}
+	switch modifier {
+	case CriterionModifierIncludes:
+		return slices.ContainsFunc(values, matches)
+	case CriterionModifierIncludesAll:
+		for _, value := range values {
+			if !matches(value) {
+				return false
+			}
+		}
+		return true
+	case CriterionModifierExcludes:
+		return !slices.ContainsFunc(values, matches)
+	case CriterionModifierEquals:
+		if len(ids) != len(values) {
+			return false
+		}
+		for _, value := range values {
+			if !slices.Contains(ids, value) {
+				return false
+			}
+		}
+		return true
+	case CriterionModifierIsNull:
+		return len(ids) == 0
+	case CriterionModifierNotNull:
+		return len(ids) > 0
+	}
+	return c.unsupportedModifier(name, modifier)
+}
+
+// resolutionHeights are the heights from which each Resolution starts.
+var resolutionHeights = []int{144, 240, 360, 480, 540, 720, 1080, 1440, 1920, 2560, 3000, 3584, 3840, 5120}
+
+// resolution returns the Resolution of a video or image, which is given by its shorter side.
+func resolution(width, height int) Resolution {
+	side := min(width, height)
+	r := ResolutionVeryLow
+	for i, h := range resolutionHeights {
+		if side >= h {
+			r = Resolution(i)
+		}
+	}
+	return r
+}
+
+func (c *criteria) resolution(name string, cr *ResolutionCriterion, width, height int) bool {
+	if cr == nil {
+		return true
+	}
+	if width == 0 && height == 0 {
+		return false
+	}
+	r := resolution(width, height)
+	switch cr.Modifier {
+	case CriterionModifierEquals:
+		return r == cr.Value
+	case CriterionModifierNotEquals:
+		return r != cr.Value
+	case CriterionModifierGreaterThan:
+		return r > cr.Value
+	case CriterionModifierLessThan:
+		return r < cr.Value
+	}
+	return c.unsupportedModifier(name, cr.Modifier)
+}
+
+// isMissing matches entities missing the value named by field, where missing holds whether each value that can be
+// checked is missing.
+func (c *criteria) isMissing(field *string, missing map[string]bool) bool {
+	if field == nil || *field == "" {
+		return true
+	}
+	isMissing, ok := missing[*field]
+	if !ok {
+		return c.fail(fmt.Errorf("is_missing criterion is not supported for %s", *field))
+	}
+	return isMissing
+}
+
+func scenePaths(scene Scene) []string {
+	paths := make([]string, len(scene.Files))
+	for i, file := range scene.Files {
+		paths[i] = file.Path
+	}
+	return paths
+}
+
+func galleryPaths(gallery Gallery) []string {
+	paths := []string{}
+	if gallery.Folder.Path != "" {
+		paths = append(paths, gallery.Folder.Path)
+	}
+	for _, file := range gallery.Files {
+		paths = append(paths, file.Path)
+	}
+	return paths
+}
+
+func imagePaths(image Image) []string {
+	paths := make([]string, len(image.Files))
+	for i, file := range image.Files {
+		paths[i] = file.Path
+	}
+	return paths
+}
+
+// performerTags returns the IDs of the tags of performers.
+func performerTags(performers []Performer) []string {
+	var ids []string
+	for _, performer := range performers {
+		ids = append(ids, entityIDs(performer.Tags)...)
+	}
+	return ids
+}
+
+// studioIDs returns the ID of studio, or no IDs if there is no studio.
+func studioIDs(studio Studio) []string {
+	if studio.ID == "" {
+		return nil
+	}
+	return []string{studio.ID}
+}
+
+func anyFavourite(performers []Performer) bool {
+	return slices.ContainsFunc(performers, func(p Performer) bool { return p.Favorite })
+}
+
+func (c *criteria) scene(scene Scene, phash string, f SceneFilter) bool {
+	if !c.supported(f, "ID", "Title", "Code", "Details", "Director", "PHash", "Path", "FileCount", "Rating100",
+		"Organized", "OCounter", "Resolution", "FrameRate", "VideoCodec", "AudioCodec", "Duration", "HasMarkers",
+		"IsMissing", "Studios", "Movies", "Tags", "TagCount", "PerformerTags", "PerformerFavourite", "Performers",
+		"PerformerCount", "URL", "ResumeTime", "PlayCount", "Date", "CreatedAt", "UpdatedAt") {
+		return false
+	}
+
+	var file VideoFile
+	if len(scene.Files) > 0 {
+		file = scene.Files[0]
+	}
+	movies := make([]string, len(scene.Movies))
+	for i, movie := range scene.Movies {
+		movies[i] = movie.Movie.ID
+	}
+	hasMarkers := slices.ContainsFunc(c.s.markers, func(m SceneMarker) bool { return m.Scene.ID == scene.ID })
+
+	matched := c.id(f.ID, scene.ID) &&
+		c.str("title", f.Title, scene.Title) &&
+		c.str("code", f.Code, scene.Code) &&
+		c.str("details", f.Details, scene.Details) &&
+		c.str("director", f.Director, scene.Director) &&
+		c.str("phash", f.PHash, phash) &&
+		c.str("path", f.Path, scenePaths(scene)...) &&
+		c.int("file_count", f.FileCount, len(scene.Files)) &&
+		c.int("rating100", f.Rating100, scene.Rating) &&
+		c.bool(f.Organized, scene.Organized) &&
+		c.int("o_counter", f.OCounter, scene.OCounter) &&
+		c.resolution("resolution", f.Resolution, file.Width, file.Height) &&
+		c.int("framerate", f.FrameRate, int(file.FrameRate)) &&
+		c.str("video_codec", f.VideoCodec, file.VideoCodec) &&
+		c.str("audio_codec", f.AudioCodec, file.AudioCodec) &&
+		c.int("duration", f.Duration, int(file.Duration)) &&
+		(f.HasMarkers == nil || (*f.HasMarkers == "true") == hasMarkers) &&
+		c.isMissing(f.IsMissing, map[string]bool{
+			"title":      scene.Title == "",
+			"details":    scene.Details == "",
+			"date":       scene.Date == "",
+			"url":        len(scene.URLs) == 0,
+			"studio":     scene.Studio.ID == "",
+			"movie":      len(scene.Movies) == 0,
+			"performers": len(scene.Performers) == 0,
+			"tags":       len(scene.Tags) == 0,
+			"galleries":  len(scene.Galleries) == 0,
+		}) &&
+		c.hierarchical("studios", f.Studios, studioIDs(scene.Studio), c.s.studioDescendants) &&
+		c.multi("movies", f.Movies, movies) &&
+		c.hierarchical("tags", f.Tags, entityIDs(scene.Tags), c.s.tagDescendants) &&
+		c.int("tag_count", f.TagCount, len(scene.Tags)) &&
+		c.hierarchical("performer_tags", f.PerformerTags, performerTags(scene.Performers), c.s.tagDescendants) &&
+		c.bool(f.PerformerFavourite, anyFavourite(scene.Performers)) &&
+		c.multi("performers", f.Performers, entityIDs(scene.Performers)) &&
+		c.int("performer_count", f.PerformerCount, len(scene.Performers)) &&
+		c.str("url", f.URL, scene.URLs...) &&
+		c.int("resume_time", f.ResumeTime, int(scene.ResumeTime)) &&
+		c.int("play_count", f.PlayCount, scene.PlayCount) &&
+		c.date("date", f.Date, scene.Date) &&
+		c.timestamp("created_at", f.CreatedAt, scene.CreatedAt) &&
+		c.timestamp("updated_at", f.UpdatedAt, scene.UpdatedAt)
+	return combine(matched, f.AND, f.OR, f.NOT, func(f SceneFilter) bool { return c.scene(scene, phash, f) })
+}
+
+func (c *criteria) gallery(gallery Gallery, f GalleryFilter) bool {
+	if !c.supported(f, "ID", "Title", "Details", "Path", "FileCount", "IsMissing", "Rating100", "Organized", "Studios",
+		"Tags", "TagCount", "PerformerTags", "Performers", "PerformerCount", "PerformerFavourite", "ImageCount", "URL",
+		"Date", "CreatedAt", "UpdatedAt") {
+		return false
+	}
+
+	isMissing := &f.IsMissing
+	matched := c.id(f.ID, gallery.ID) &&
+		c.str("title", f.Title, gallery.Title) &&
+		c.str("details", f.Details, gallery.Details) &&
+		c.str("path", f.Path, galleryPaths(gallery)...) &&
+		c.int("file_count", f.FileCount, len(gallery.Files)) &&
+		c.isMissing(isMissing, map[string]bool{
+			"title":      gallery.Title == "",
+			"details":    gallery.Details == "",
+			"date":       gallery.Date == "",
+			"url":        gallery.URL == "",
+			"studio":     gallery.Studio.ID == "",
+			"performers": len(gallery.Performers) == 0,
+			"tags":       len(gallery.Tags) == 0,
+		}) &&
+		c.int("rating100", f.Rating100, gallery.Rating) &&
+		c.bool(f.Organized, gallery.Organized) &&
+		c.hierarchical("studios", f.Studios, studioIDs(gallery.Studio), c.s.studioDescendants) &&
+		c.hierarchical("tags", f.Tags, entityIDs(gallery.Tags), c.s.tagDescendants) &&
+		c.int("tag_count", f.TagCount, len(gallery.Tags)) &&
+		c.hierarchical("performer_tags", f.PerformerTags, performerTags(gallery.Performers), c.s.tagDescendants) &&
+		c.multi("performers", f.Performers, entityIDs(gallery.Performers)) &&
+		c.int("performer_count", f.PerformerCount, len(gallery.Performers)) &&
+		c.bool(f.PerformerFavourite, anyFavourite(gallery.Performers)) &&
+		c.int("image_count", f.ImageCount, gallery.ImageCount) &&
+		c.str("url", f.URL, gallery.URL) &&
+		c.date("date", f.Date, gallery.Date) &&
+		c.timestamp("created_at", f.CreatedAt, gallery.CreatedAt) &&
+		c.timestamp("updated_at", f.UpdatedAt, gallery.UpdatedAt)
+	return combine(matched, f.AND, f.OR, f.NOT, func(f GalleryFilter) bool { return c.gallery(gallery, f) })
+}
+
+func (c *criteria) image(image Image, galleries []string, f ImageFilter) bool {
+	if !c.supported(f, "ID", "Title", "Path", "FileCount", "Rating100", "Date", "Organized", "OCounter", "Resolution",
+		"IsMissing", "Studios", "Tags", "TagCount", "PerformerTags", "Performers", "PerformerCount",
+		"PerformerFavourite", "Galleries", "CreatedAt", "UpdatedAt") {
+		return false
+	}
+
+	var file ImageFile
+	if len(image.Files) > 0 {
+		file = image.Files[0]
+	}
+	matched := c.id(f.ID, image.ID) &&
+		c.str("title", f.Title, image.Title) &&
+		c.str("path", f.Path, imagePaths(image)...) &&
+		c.int("file_count", f.FileCount, len(image.Files)) &&
+		c.int("rating100", f.Rating100, image.Rating) &&
+		c.date("date", f.Date, image.Date) &&
+		c.bool(f.Organized, image.Organized) &&
+		c.int("o_counter", f.OCounter, image.OCounter) &&
+		c.resolution("resolution", f.Resolution, file.Width, file.Height) &&
+		c.isMissing(f.IsMissing, map[string]bool{
+			"title":      image.Title == "",
+			"date":       image.Date == "",
+			"studio":     image.Studio.ID == "",
+			"performers": len(image.Performers) == 0,
+			"tags":       len(image.Tags) == 0,
+			"galleries":  len(galleries) == 0,
+		}) &&
+		c.hierarchical("studios", f.Studios, studioIDs(image.Studio), c.s.studioDescendants) &&
+		c.hierarchical("tags", f.Tags, entityIDs(image.Tags), c.s.tagDescendants) &&
+		c.int("tag_count", f.TagCount, len(image.Tags)) &&
+		c.hierarchical("performer_tags", f.PerformerTags, performerTags(image.Performers), c.s.tagDescendants) &&
+		c.multi("performers", f.Performers, entityIDs(image.Performers)) &&
+		c.int("performer_count", f.PerformerCount, len(image.Performers)) &&
+		c.bool(f.PerformerFavourite, anyFavourite(image.Performers)) &&
+		c.multi("galleries", f.Galleries, galleries) &&
+		c.timestamp("created_at", f.CreatedAt, image.CreatedAt) &&
+		c.timestamp("updated_at", f.UpdatedAt, image.UpdatedAt)
+	return combine(matched, f.AND, f.OR, f.NOT, func(f ImageFilter) bool { return c.image(image, galleries, f) })
+}
+
+func (c *criteria) marker(marker SceneMarker, f SceneMarkerFilter) bool {
+	if !c.supported(f, "TagID", "Tags", "SceneTags", "Performers", "SceneDate", "SceneCreatedAt", "SceneUpdatedAt") {
+		return false
+	}
+
+	tags := append([]string{marker.PrimaryTag.ID}, entityIDs(marker.Tags)...)
+	return (f.TagID == nil || slices.Contains(tags, *f.TagID)) &&
+		c.hierarchical("tags", f.Tags, tags, c.s.tagDescendants) &&
+		c.hierarchical("scene_tags", f.SceneTags, entityIDs(marker.Scene.Tags), c.s.tagDescendants) &&
+		c.multi("performers", f.Performers, entityIDs(marker.Scene.Performers)) &&
+		c.date("scene_date", f.SceneDate, marker.Scene.Date) &&
+		c.timestamp("scene_created_at", f.SceneCreatedAt, marker.Scene.CreatedAt) &&
+		c.timestamp("scene_updated_at", f.SceneUpdatedAt, marker.Scene.UpdatedAt)
+}
+
+func (c *criteria) performer(performer Performer, f PerformerFilter) bool {
+	if !c.supported(f, "Name", "Disambiguation", "Favourite", "BirthYear", "Country", "Aliases", "Gender", "IsMissing",
+		"Tags", "TagCount", "SceneCount", "URL") {
+		return false
+	}
+
+	birthYear := 0
+	if birthdate, err := time.Parse("2006-01-02", performer.Birthdate); err == nil {
+		birthYear = birthdate.Year()
+	}
+	matched := c.str("name", f.Name, performer.Name) &&
+		c.str("disambiguation", f.Disambiguation, performer.Disambiguation) &&
+		c.bool(f.Favourite, performer.Favorite) &&
+		c.int("birth_year", f.BirthYear, birthYear) &&
+		c.str("country", f.Country, string(performer.Country)) &&
+		c.str("aliases", f.Aliases, performer.Aliases...) &&
+		c.gender(f.Gender, performer.Gender) &&
+		c.isMissing(f.IsMissing, map[string]bool{
+			"disambiguation": performer.Disambiguation == "",
+			"url":            performer.URL == "" && len(performer.URLs) == 0,
+			"aliases":        len(performer.Aliases) == 0,
+			"gender":         performer.Gender == GenderNotSpecified,
+			"birthdate":      performer.Birthdate == "",
+			"country":        performer.Country == "",
+			"tags":           len(performer.Tags) == 0,
+		}) &&
+		c.hierarchical("tags", f.Tags, entityIDs(performer.Tags), c.s.tagDescendants) &&
+		c.int("tag_count", f.TagCount, len(performer.Tags)) &&
+		c.int("scene_count", f.SceneCount, performer.SceneCount) &&
+		c.str("url", f.URL, append([]string{performer.URL}, performer.URLs...)...)
+	return combine(matched, f.AND, f.OR, f.NOT, func(f PerformerFilter) bool { return c.performer(performer, f) })
+}
+
+func (c *criteria) gender(cr *GenderCriterion, gender Gender) bool {
+	if cr == nil {
+		return true
+	}
+	switch cr.Modifier {
+	case CriterionModifierEquals:
+		return gender == cr.Value
+	case CriterionModifierNotEquals:
+		return gender != cr.Value
+	case CriterionModifierIsNull:
+		return gender == GenderNotSpecified
+	case CriterionModifierNotNull:
+		return gender != GenderNotSpecified
+	}
+	return c.unsupportedModifier("gender", cr.Modifier)
+}
+
+func (c *criteria) studio(studio StudioDetail, f StudioFilter) bool {
+	if !c.supported(f, "Name", "Parents", "IsMissing", "SceneCount", "GalleryCount", "URL", "Aliases") {
+		return false
+	}
+
+	var parents []string
+	if studio.ParentStudio.ID != "" {
+		parents = []string{studio.ParentStudio.ID}
+	}
+	matched := c.str("name", f.Name, studio.Name) &&
+		c.multi("parents", f.Parents, parents) &&
+		c.isMissing(f.IsMissing, map[string]bool{
+			"url":     studio.URL == "",
+			"aliases": len(studio.Aliases) == 0,
+		}) &&
+		c.int("scene_count", f.SceneCount, studio.SceneCount) &&
+		c.int("gallery_count", f.GalleryCount, studio.GalleryCount) &&
+		c.str("url", f.URL, studio.URL) &&
+		c.str("aliases", f.Aliases, studio.Aliases...)
+	return combine(matched, f.AND, f.OR, f.NOT, func(f StudioFilter) bool { return c.studio(studio, f) })
+}
+
+func (c *criteria) movie(movie MovieDetail, f MovieFilter) bool {
+	if !c.supported(f, "Name", "Director", "Synopsis", "Duration", "Rating100", "Studios", "IsMissing", "URL",
+		"Performers", "Date", "CreatedAt", "UpdatedAt") {
+		return false
+	}
+
+	// The performers of a movie are those of its scenes.
+	var performers []string
+	for _, scene := range c.s.scenes {
+		if slices.ContainsFunc(scene.Movies, func(m SceneMovie) bool { return m.Movie.ID == movie.ID }) {
+			performers = append(performers, entityIDs(scene.Performers)...)
+		}
+	}
+	return c.str("name", f.Name, movie.Name) &&
+		c.str("director", f.Director, movie.Director) &&
+		c.str("synopsis", f.Synopsis, movie.Synopsis) &&
+		c.int("duration", f.Duration, movie.Duration) &&
+		c.int("rating100", f.Rating100, movie.Rating) &&
+		c.hierarchical("studios", f.Studios, studioIDs(movie.Studio), c.s.studioDescendants) &&
+		c.isMissing(f.IsMissing, map[string]bool{
+			"date":     movie.Date == "",
+			"studio":   movie.Studio.ID == "",
+			"director": movie.Director == "",
+			"synopsis": movie.Synopsis == "",
+			"url":      movie.URL == "",
+		}) &&
+		c.str("url", f.URL, movie.URL) &&
+		c.multi("performers", f.Performers, performers) &&
+		c.date("date", f.Date, movie.Date) &&
+		c.timestamp("created_at", f.CreatedAt, movie.CreatedAt) &&
+		c.timestamp("updated_at", f.UpdatedAt, movie.UpdatedAt)
+}
+
+func (c *criteria) tag(tag TagDetail, f TagFilter) bool {
+	if !c.supported(f, "AND", "OR", "NOT", "Name", "Aliases", "Description", "IsMissing", "SceneCount", "GalleryCount",
+		"PerformerCount", "MarkerCount", "Parents", "Children", "ParentCount", "ChildCount") {
+		return false
+	}
+
+	markerCount := 0
+	for _, marker := range c.s.markers {
+		if marker.PrimaryTag.ID == tag.ID || containsID(marker.Tags, tag.ID) {
+			markerCount++
+		}
+	}
+	// Parents match the ancestors of a tag to the depth of the criterion, and children its descendants.
+	var parents, children []string
+	if f.Parents != nil {
+		parents = c.s.tagAncestors(tag.ID, levels(f.Parents.Depth))[1:]
+	}
+	if f.Children != nil {
+		children = c.s.tagDescendants(tag.ID, levels(f.Children.Depth))[1:]
+	}
+	self := func(id string, _ int) []string { return []string{id} }
+
+	matched := c.str("name", f.Name, tag.Name) &&
+		c.str("aliases", f.Aliases, tag.Aliases...) &&
+		c.str("description", f.Description, tag.Description) &&
+		c.isMissing(f.IsMissing, map[string]bool{
+			"description": tag.Description == "",
+			"aliases":     len(tag.Aliases) == 0,
+		}) &&
+		c.int("scene_count", f.SceneCount, tag.SceneCount) &&
+		c.int("gallery_count", f.GalleryCount, tag.GalleryCount) &&
+		c.int("performer_count", f.PerformerCount, tag.PerformerCount) &&
+		c.int("marker_count", f.MarkerCount, markerCount) &&
+		c.hierarchical("parents", f.Parents, parents, self) &&
+		c.hierarchical("children", f.Children, children, self) &&
+		c.int("parent_count", f.ParentCount, len(tag.Parents)) &&
+		c.int("child_count", f.ChildCount, len(tag.Children))
+	return combine(matched, f.AND, f.OR, f.NOT, func(f TagFilter) bool { return c.tag(tag, f) })
+}
+
+// levels returns the number of levels of a hierarchy spanned by a criterion of depth, where a depth of 0 spans only
+// the first level and -1 spans all of them.
+func levels(depth int) int {
+	if depth < 0 {
+		return -1
+	}
+	return depth + 1
+}
+
+// sortKeys maps each sort supported for an entity to a comparison of two entities in ascending order.
+type sortKeys[T any] map[string]func(a, b T) int
+
+// with returns a copy of keys with an additional sort.
+func (keys sortKeys[T]) with(sort string, compare func(a, b T) int) sortKeys[T] {
+	result := make(sortKeys[T], len(keys)+1)
+	for k, v := range keys {
+		result[k] = v
+	}
+	result[sort] = compare
+	return result
+}
+
+// sortEntities sorts items by the sort and direction of f, keeping the order of items that are equal.  Random sorts
+// shuffle items with a seed given by the sort, so that pages of the same random sort don't overlap.
+func sortEntities[T any](items []T, f FindFilter, keys sortKeys[T]) error {
+	if f.Sort == "" {
+		return nil
+	}
+	if strings.HasPrefix(f.Sort, SortRandomPrefix) {
+		h := fnv.New64a()
+		h.Write([]byte(f.Sort))
+		copy(items, shuffleSeeded(items, int64(h.Sum64())))
+		return nil
+	}
+	compare, ok := keys[f.Sort]
+	if !ok {
+		return fmt.Errorf("sort '%s' is not supported", f.Sort)
+	}
+	if strings.EqualFold(f.Direction, SortDirectionDesc) {
+		slices.SortStableFunc(items, func(a, b T) int { return compare(b, a) })
+	} else {
+		slices.SortStableFunc(items, compare)
+	}
+	return nil
+}
+
+// page returns the page of items given by f.  A negative PerPage returns all items, as it does in stash.
+func page[T any](items []T, f FindFilter) []T {
+	if f.PerPage < 0 {
+		if items == nil {
+			return []T{}
+		}
+		return items
+	}
+	return paginate(items, max(f.Page, 1), cmp.Or(f.PerPage, 25))
+}
+
+func compareStrings(a, b string) int {
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+func compareInts[T int | int64 | float64](a, b T) int {
+	return cmp.Compare(a, b)
+}
+
+func compareTimes(a, b time.Time) int {
+	return a.Compare(b)
+}
+
+// compareIDs orders IDs numerically, which is the order entities were created in.
+func compareIDs(a, b string) int {
+	x, errX := strconv.Atoi(a)
+	y, errY := strconv.Atoi(b)
+	if errX != nil || errY != nil {
+		return strings.Compare(a, b)
+	}
+	return cmp.Compare(x, y)
+}
+
+func firstPath(paths []string) string {
+	if len(paths) == 0 {
+		return ""
+	}
+	return paths[0]
+}
+
+// sceneIndex returns the position of a scene within a movie, or 0 if it is not in the movie.
+func sceneIndex(scene Scene, movieID string) int {
+	for _, movie := range scene.Movies {
+		if movie.Movie.ID == movieID {
+			return movie.SceneIndex
+		}
+	}
+	return 0
+}
+
+var sceneSortKeys = sortKeys[Scene]{
+	SortTitle:        func(a, b Scene) int { return compareStrings(a.Title, b.Title) },
+	SortPath:         func(a, b Scene) int { return strings.Compare(firstPath(scenePaths(a)), firstPath(scenePaths(b))) },
+	SortDate:         func(a, b Scene) int { return strings.Compare(a.Date, b.Date) },
+	"rating":         func(a, b Scene) int { return compareInts(a.Rating, b.Rating) },
+	SortOCounter:     func(a, b Scene) int { return compareInts(a.OCounter, b.OCounter) },
+	SortPlayCount:    func(a, b Scene) int { return compareInts(a.PlayCount, b.PlayCount) },
+	SortLastPlayedAt: func(a, b Scene) int { return compareTimes(lastPlayed(a), lastPlayed(b)) },
+	SortDuration:     func(a, b Scene) int { return compareInts(sceneDuration(a), sceneDuration(b)) },
+	SortFileSize:     func(a, b Scene) int { return compareInts(sceneSize(a), sceneSize(b)) },
+	SortCreatedAt:    func(a, b Scene) int { return compareTimes(a.CreatedAt, b.CreatedAt) },
+	SortUpdatedAt:    func(a, b Scene) int { return compareTimes(a.UpdatedAt, b.UpdatedAt) },
+}
+
+func lastPlayed(scene Scene) time.Time {
+	if scene.LastPlayedAt == nil {
+		return time.Time{}
+	}
+	return *scene.LastPlayedAt
+}
+
+func sceneDuration(scene Scene) float64 {
+	if len(scene.Files) == 0 {
+		return 0
+	}
+	return scene.Files[0].Duration
+}
+
+func sceneSize(scene Scene) int64 {
+	if len(scene.Files) == 0 {
+		return 0
+	}
+	return scene.Files[0].Size
+}
+
+// gallerySize returns the total size of the files of a gallery.
+func gallerySize(gallery Gallery) int64 {
+	var size int64
+	for _, file := range gallery.Files {
+		size += file.Size
+	}
+	return size
+}
+
+var gallerySortKeys = sortKeys[Gallery]{
+	SortTitle:      func(a, b Gallery) int { return compareStrings(a.Title, b.Title) },
+	SortPath:       func(a, b Gallery) int { return strings.Compare(firstPath(galleryPaths(a)), firstPath(galleryPaths(b))) },
+	SortDate:       func(a, b Gallery) int { return strings.Compare(a.Date, b.Date) },
+	"rating":       func(a, b Gallery) int { return compareInts(a.Rating, b.Rating) },
+	"images_count": func(a, b Gallery) int { return compareInts(a.ImageCount, b.ImageCount) },
+	SortFileSize:   func(a, b Gallery) int { return compareInts(gallerySize(a), gallerySize(b)) },
+	SortCreatedAt:  func(a, b Gallery) int { return compareTimes(a.CreatedAt, b.CreatedAt) },
+	SortUpdatedAt:  func(a, b Gallery) int { return compareTimes(a.UpdatedAt, b.UpdatedAt) },
+}
+
+var imageSortKeys = sortKeys[Image]{
+	SortTitle:     func(a, b Image) int { return compareStrings(a.Title, b.Title) },
+	SortPath:      func(a, b Image) int { return strings.Compare(firstPath(imagePaths(a)), firstPath(imagePaths(b))) },
+	SortDate:      func(a, b Image) int { return strings.Compare(a.Date, b.Date) },
+	"rating":      func(a, b Image) int { return compareInts(a.Rating, b.Rating) },
+	SortOCounter:  func(a, b Image) int { return compareInts(a.OCounter, b.OCounter) },
+	SortCreatedAt: func(a, b Image) int { return compareTimes(a.CreatedAt, b.CreatedAt) },
+	SortUpdatedAt: func(a, b Image) int { return compareTimes(a.UpdatedAt, b.UpdatedAt) },
+}
+
+// Markers, performers, studios and tags don't carry timestamps, so they are sorted by ID when sorted by when they
+// were created or updated.
+
+var markerSortKeys = sortKeys[SceneMarker]{
+	SortTitle:     func(a, b SceneMarker) int { return compareStrings(a.Title, b.Title) },
+	SortSeconds:   func(a, b SceneMarker) int { return compareInts(a.Seconds, b.Seconds) },
+	SortCreatedAt: func(a, b SceneMarker) int { return compareIDs(a.ID, b.ID) },
+	SortUpdatedAt: func(a, b SceneMarker) int { return compareIDs(a.ID, b.ID) },
+}
+
+var performerSortKeys = sortKeys[Performer]{
+	SortName:        func(a, b Performer) int { return compareStrings(a.Name, b.Name) },
+	SortScenesCount: func(a, b Performer) int { return compareInts(a.SceneCount, b.SceneCount) },
+	"birthdate":     func(a, b Performer) int { return strings.Compare(a.Birthdate, b.Birthdate) },
+	SortCreatedAt:   func(a, b Performer) int { return compareIDs(a.ID, b.ID) },
+	SortUpdatedAt:   func(a, b Performer) int { return compareIDs(a.ID, b.ID) },
+}
+
+var studioSortKeys = sortKeys[StudioDetail]{
+	SortName:           func(a, b StudioDetail) int { return compareStrings(a.Name, b.Name) },
+	SortScenesCount:    func(a, b StudioDetail) int { return compareInts(a.SceneCount, b.SceneCount) },
+	SortGalleriesCount: func(a, b StudioDetail) int { return compareInts(a.GalleryCount, b.GalleryCount) },
+	SortCreatedAt:      func(a, b StudioDetail) int { return compareIDs(a.ID, b.ID) },
+	SortUpdatedAt:      func(a, b StudioDetail) int { return compareIDs(a.ID, b.ID) },
+}
+
+var movieSortKeys = sortKeys[MovieDetail]{
+	SortName:       func(a, b MovieDetail) int { return compareStrings(a.Name, b.Name) },
+	SortDate:       func(a, b MovieDetail) int { return strings.Compare(a.Date, b.Date) },
+	SortDuration:   func(a, b MovieDetail) int { return compareInts(a.Duration, b.Duration) },
+	"rating":       func(a, b MovieDetail) int { return compareInts(a.Rating, b.Rating) },
+	SortSceneCount: func(a, b MovieDetail) int { return compareInts(a.SceneCount, b.SceneCount) },
+	SortCreatedAt:  func(a, b MovieDetail) int { return compareTimes(a.CreatedAt, b.CreatedAt) },
+	SortUpdatedAt:  func(a, b MovieDetail) int { return compareTimes(a.UpdatedAt, b.UpdatedAt) },
+}
+
+var tagSortKeys = sortKeys[TagDetail]{
+	SortName:            func(a, b TagDetail) int { return compareStrings(a.Name, b.Name) },
+	SortScenesCount:     func(a, b TagDetail) int { return compareInts(a.SceneCount, b.SceneCount) },
+	SortGalleriesCount:  func(a, b TagDetail) int { return compareInts(a.GalleryCount, b.GalleryCount) },
+	SortPerformersCount: func(a, b TagDetail) int { return compareInts(a.PerformerCount, b.PerformerCount) },
+	SortCreatedAt:       func(a, b TagDetail) int { return compareIDs(a.ID, b.ID) },
+	SortUpdatedAt:       func(a, b TagDetail) int { return compareIDs(a.ID, b.ID) },
+}
@@ -0,0 +1,335 @@
+package stash
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hasura/go-graphql-client"
+	"github.com/stretchr/testify/require"
+)
+
+func loadFixture(t *testing.T) *MemoryStash {
+	t.Helper()
+	data, err := os.ReadFile("testdata/fixture.json")
+	require.NoError(t, err)
+	s, err := LoadMemoryStash(data)
+	require.NoError(t, err)
+	s.now = func() time.Time { return time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC) }
+	return s
+}
+
+func TestMemoryStashLoad(t *testing.T) {
+	s := loadFixture(t)
+	ctx := context.Background()
+
+	scenes, count, err := s.Scenes(ctx, FindFilter{PerPage: -1}, SceneFilter{})
+	require.NoError(t, err)
+	require.Equal(t, 4, count)
+	scene := scenes[0]
+	require.Equal(t, Studio{ID: "11", Name: "Acme Coast"}, scene.Studio)
+	require.Equal(t, []Tag{{ID: "2", Name: "Beach"}}, scene.Tags)
+	require.Equal(t, "Alice", scene.Performers[0].Name)
+	require.Equal(t, 2, scene.Performers[0].SceneCount)
+	require.Equal(t, []SceneMovie{{Movie: Movie{ID: "30", Name: "Road Trip"}, SceneIndex: 2}}, scene.Movies)
+	require.Equal(t, []SceneGallery{{ID: "50", Title: "Beach Day"}}, scene.Galleries)
+
+	studios, _, err := s.Studios(ctx, FindFilter{PerPage: -1}, StudioFilter{})
+	require.NoError(t, err)
+	require.Equal(t, "Acme", studios[0].Name)
+	require.Equal(t, []Studio{{ID: "11", Name: "Acme Coast"}}, studios[0].ChildStudios)
+	require.Equal(t, 2, studios[0].SceneCount, "counts include sub-studios")
+	require.Equal(t, 1, studios[0].GalleryCount)
+
+	tags, _, err := s.Tags(ctx, FindFilter{PerPage: -1}, TagFilter{})
+	require.NoError(t, err)
+	require.Equal(t, []Tag{{ID: "2", Name: "Beach"}, {ID: "3", Name: "Forest"}}, tags[0].Children)
+	require.Equal(t, 0, tags[0].SceneCount, "counts only include content tagged directly")
+	require.Equal(t, 1, tags[1].SceneCount)
+
+	galleries, _, err := s.Galleries(ctx, FindFilter{PerPage: -1}, GalleryFilter{})
+	require.NoError(t, err)
+	require.Equal(t, 2, galleries[0].ImageCount)
+
+	filter, ok, err := s.DefaultFilter(ctx, FilterModeScenes)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, FilterModeScenes, filter.Mode)
+	require.Equal(t, "title", filter.FindFilter.Sort)
+
+	_, err = LoadMemoryStash([]byte(`{"scenes": {}}`))
+	require.Error(t, err)
+}
+
+func TestMemoryStashScenesFilter(t *testing.T) {
+	s := loadFixture(t)
+	ctx := context.Background()
+	all := FindFilter{PerPage: -1}
+
+	cases := []struct {
+		name     string
+		find     FindFilter
+		filter   SceneFilter
+		expected []string
+	}{
+		{"query", FindFilter{Query: "sunset WALK", PerPage: -1}, SceneFilter{}, []string{"40", "42"}},
+		{"query path", FindFilter{Query: "forest/", PerPage: -1}, SceneFilter{}, []string{"41"}},
+		{"tags with children", all, SceneFilter{
+			Tags: &HierarchicalMultiCriterion{Value: []string{"1"}, Modifier: CriterionModifierIncludes, Depth: -1},
+		}, []string{"40", "41"}},
+		{"tags without children", all, SceneFilter{
+			Tags: &HierarchicalMultiCriterion{Value: []string{"1"}, Modifier: CriterionModifierIncludes},
+		}, []string{}},
+		{"tags excluding children", all, SceneFilter{
+			Tags: &HierarchicalMultiCriterion{Value: []string{"1"}, Modifier: CriterionModifierIncludes, Depth: -1, Excludes: []string{"3"}},
+		}, []string{"40"}},
+		{"tags missing", all, SceneFilter{
+			Tags: &HierarchicalMultiCriterion{Modifier: CriterionModifierIsNull},
+		}, []string{"42"}},
+		{"studios with sub-studios", all, SceneFilter{
+			Studios: &HierarchicalMultiCriterion{Value: []string{"10"}, Modifier: CriterionModifierIncludes, Depth: -1},
+		}, []string{"40", "42"}},
+		{"performers all", all, SceneFilter{
+			Performers: &MultiCriterion{Value: []string{"20", "21"}, Modifier: CriterionModifierIncludesAll},
+		}, []string{"41"}},
+		{"performer tags", all, SceneFilter{
+			PerformerTags: &HierarchicalMultiCriterion{Value: []string{"4"}, Modifier: CriterionModifierIncludes},
+		}, []string{"40", "41"}},
+		{"rating between", all, SceneFilter{
+			Rating100: &IntCriterion{Value: 60, Value2: ptr(80), Modifier: CriterionModifierBetween},
+		}, []string{"40", "41"}},
+		{"path regex", all, SceneFilter{
+			Path: &StringCriterion{Value: `\.MP4$`, Modifier: CriterionModifierMatchesRegex},
+		}, []string{"40", "42", "43"}},
+		{"resolution", all, SceneFilter{
+			Resolution: &ResolutionCriterion{Value: ResolutionFullHD, Modifier: CriterionModifierGreaterThan},
+		}, []string{"43"}},
+		{"date", all, SceneFilter{
+			Date: &DateCriterion{Value: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC), Modifier: CriterionModifierGreaterThan},
+		}, []string{"41", "43"}},
+		{"has markers", all, SceneFilter{HasMarkers: ptr("true")}, []string{"40", "41"}},
+		{"is missing", all, SceneFilter{IsMissing: ptr("studio")}, []string{"43"}},
+		{"combinators", all, SceneFilter{
+			FilterCombinator: FilterCombinator[SceneFilter]{
+				OR:  &SceneFilter{Title: &StringCriterion{Value: "city", Modifier: CriterionModifierIncludes}},
+				NOT: &SceneFilter{Organized: ptr(true)},
+			},
+			Title: &StringCriterion{Value: "sunset", Modifier: CriterionModifierIncludes},
+		}, []string{"42", "43"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			scenes, count, err := s.Scenes(ctx, c.find, c.filter)
+			require.NoError(t, err)
+			require.Equal(t, len(c.expected), count)
+			require.Equal(t, c.expected, entityIDs(scenes))
+		})
+	}
+
+	_, _, err := s.Scenes(ctx, all, SceneFilter{Captions: &StringCriterion{Value: "en"}})
+	require.ErrorContains(t, err, "captions criterion is not supported")
+
+	_, _, err = s.Scenes(ctx, all, SceneFilter{Path: &StringCriterion{Value: "(", Modifier: CriterionModifierMatchesRegex}})
+	require.ErrorContains(t, err, "invalid path regex")
+}
+
+func TestMemoryStashScenesSortAndPage(t *testing.T) {
+	s := loadFixture(t)
+	ctx := context.Background()
+
+	scenes, count, err := s.Scenes(ctx, FindFilter{Page: 1, PerPage: 2, Sort: SortDate, Direction: SortDirectionDesc}, SceneFilter{})
+	require.NoError(t, err)
+	require.Equal(t, 4, count)
+	require.Equal(t, []string{"43", "41"}, entityIDs(scenes))
+
+	scenes, _, err = s.Scenes(ctx, FindFilter{Page: 2, PerPage: 2, Sort: SortDate, Direction: SortDirectionDesc}, SceneFilter{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"40", "42"}, entityIDs(scenes), "equal dates keep their order")
+
+	scenes, _, err = s.Scenes(ctx, FindFilter{PerPage: -1, Sort: SortMovieSceneIndex}, SceneFilter{
+		Movies: &MultiCriterion{Value: []string{"30"}, Modifier: CriterionModifierIncludes},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"41", "40"}, entityIDs(scenes))
+
+	sort := SortRandomPrefix + "1234"
+	first, _, err := s.Scenes(ctx, FindFilter{Page: 1, PerPage: 2, Sort: sort}, SceneFilter{})
+	require.NoError(t, err)
+	second, _, err := s.Scenes(ctx, FindFilter{Page: 2, PerPage: 2, Sort: sort}, SceneFilter{})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"40", "41", "42", "43"}, append(entityIDs(first), entityIDs(second)...))
+
+	_, _, err = s.Scenes(ctx, FindFilter{Sort: "unknown"}, SceneFilter{})
+	require.ErrorContains(t, err, "sort 'unknown' is not supported")
+}
+
+func TestMemoryStashOtherFilters(t *testing.T) {
+	s := loadFixture(t)
+	ctx := context.Background()
+	all := FindFilter{PerPage: -1}
+
+	images, _, err := s.Images(ctx, all, ImageFilter{Galleries: &MultiCriterion{Value: []string{"50"}, Modifier: CriterionModifierIncludes}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"60", "61"}, []string{images[0].ID, images[1].ID})
+
+	markers, _, err := s.SceneMarkers(ctx, FindFilter{PerPage: -1, Sort: SortSeconds}, SceneMarkerFilter{TagID: ptr("4")})
+	require.NoError(t, err)
+	require.Equal(t, []string{"71"}, entityIDs(markers))
+
+	performers, _, err := s.Performers(ctx, FindFilter{Query: "robert", PerPage: -1}, PerformerFilter{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"21"}, entityIDs(performers))
+
+	performers, _, err = s.Performers(ctx, all, PerformerFilter{Gender: &GenderCriterion{Value: GenderFemale}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"20"}, entityIDs(performers))
+
+	tags, _, err := s.Tags(ctx, all, TagFilter{Parents: &HierarchicalMultiCriterion{Value: []string{"1"}, Modifier: CriterionModifierIncludes}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"2", "3"}, entityIDs(tags))
+
+	tags, _, err = s.Tags(ctx, FindFilter{PerPage: -1, Sort: SortScenesCount, Direction: SortDirectionDesc}, TagFilter{
+		SceneCount: &IntCriterion{Value: 0, Modifier: CriterionModifierGreaterThan},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"2", "3", "4"}, entityIDs(tags))
+
+	movies, _, err := s.Movies(ctx, all, MovieFilter{Performers: &MultiCriterion{Value: []string{"21"}, Modifier: CriterionModifierIncludes}})
+	require.NoError(t, err)
+	require.Equal(t, 2, movies[0].SceneCount)
+
+	studios, _, err := s.Studios(ctx, all, StudioFilter{Parents: &MultiCriterion{Value: []string{"10"}, Modifier: CriterionModifierIncludes}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"11"}, entityIDs(studios))
+}
+
+func TestMemoryStashSceneUpdate(t *testing.T) {
+	s := loadFixture(t)
+	ctx := context.Background()
+
+	tagIDs := []graphql.ID{"3", "4"}
+	scene, err := s.SceneUpdate(ctx, SceneUpdate{ID: "40", Title: ptr("Dusk"), TagIDs: &tagIDs})
+	require.NoError(t, err)
+	require.Equal(t, "Dusk", scene.Title)
+	require.Equal(t, []Tag{{ID: "3", Name: "Forest"}, {ID: "4", Name: "Favourite"}}, scene.Tags)
+	require.Equal(t, s.now(), scene.UpdatedAt)
+
+	scenes, _, err := s.Scenes(ctx, FindFilter{Query: "dusk"}, SceneFilter{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"40"}, entityIDs(scenes))
+
+	unknown := []graphql.ID{"99"}
+	_, err = s.SceneUpdate(ctx, SceneUpdate{ID: "40", TagIDs: &unknown})
+	require.ErrorIs(t, err, ErrTagNotFound)
+
+	scenes, err = s.BulkSceneUpdate(ctx, BulkSceneUpdate{
+		IDs:    []graphql.ID{"42", "43"},
+		TagIDs: &BulkUpdateIDs{IDs: []graphql.ID{"1"}, Mode: BulkUpdateIDModeAdd},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []Tag{{ID: "1", Name: "Outdoor"}}, scenes[0].Tags)
+	require.Equal(t, []Tag{{ID: "4", Name: "Favourite"}, {ID: "1", Name: "Outdoor"}}, scenes[1].Tags)
+
+	ok, err := s.ScenesDestroy(ctx, []string{"40"})
+	require.NoError(t, err)
+	require.True(t, ok)
+	_, count, err := s.Scenes(ctx, FindFilter{}, SceneFilter{})
+	require.NoError(t, err)
+	require.Equal(t, 3, count)
+	_, count, err = s.SceneMarkers(ctx, FindFilter{}, SceneMarkerFilter{})
+	require.NoError(t, err)
+	require.Equal(t, 1, count, "markers of deleted scenes are removed")
+
+	plays, err := s.RecordPlay(ctx, "43")
+	require.NoError(t, err)
+	require.Equal(t, 4, plays)
+}
+
+func TestMemoryStashDuplicates(t *testing.T) {
+	s := loadFixture(t)
+	ctx := context.Background()
+
+	groups, err := s.FindDuplicateScenes(ctx, 4, 5)
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	require.Equal(t, []string{"40", "42"}, entityIDs(groups[0]))
+
+	groups, err = s.FindDuplicateScenes(ctx, 4, 0.5)
+	require.NoError(t, err)
+	require.Empty(t, groups)
+
+	scene, err := s.SceneMerge(ctx, SceneMerge{Source: []graphql.ID{"42"}, Destination: "40"})
+	require.NoError(t, err)
+	require.Len(t, scene.Files, 2)
+}
+
+func TestMemoryStashTags(t *testing.T) {
+	s := loadFixture(t)
+	ctx := context.Background()
+
+	tag, err := s.TagCreate(ctx, TagCreate{Name: "Night"})
+	require.NoError(t, err)
+	require.Equal(t, Tag{ID: "82", Name: "Night"}, tag)
+
+	_, err = s.TagCreate(ctx, TagCreate{Name: "night"})
+	require.ErrorContains(t, err, "already exists")
+	_, err = s.TagCreate(ctx, TagCreate{Name: "Woods"})
+	require.ErrorContains(t, err, "alias")
+
+	tag, err = s.TagFindByName(ctx, "BEACH")
+	require.NoError(t, err)
+	require.Equal(t, Tag{ID: "2", Name: "Beach"}, tag)
+	_, err = s.TagFindByName(ctx, "Missing")
+	require.ErrorIs(t, err, ErrTagNotFound)
+
+	parents := []graphql.ID{"2"}
+	_, err = s.TagUpdate(ctx, TagUpdate{ID: "1", ParentIDs: &parents})
+	require.ErrorContains(t, err, "can't be a parent")
+
+	merged, err := s.TagsMerge(ctx, TagsMerge{Source: []graphql.ID{"3"}, Destination: "2"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Forest", "Woods"}, merged.Aliases)
+	require.Equal(t, 2, merged.SceneCount)
+	markers, _, err := s.SceneMarkers(ctx, FindFilter{PerPage: -1}, SceneMarkerFilter{})
+	require.NoError(t, err)
+	require.Equal(t, Tag{ID: "2", Name: "Beach"}, markers[1].PrimaryTag)
+
+	ok, err := s.TagDelete(ctx, "2")
+	require.NoError(t, err)
+	require.True(t, ok)
+	_, count, err := s.SceneMarkers(ctx, FindFilter{}, SceneMarkerFilter{})
+	require.NoError(t, err)
+	require.Equal(t, 0, count, "markers with a deleted primary tag are removed")
+	scenes, _, err := s.Scenes(ctx, FindFilter{PerPage: -1}, SceneFilter{})
+	require.NoError(t, err)
+	require.Empty(t, scenes[0].Tags)
+}
+
+func TestMemoryStashSavedFilters(t *testing.T) {
+	s := loadFixture(t)
+	ctx := context.Background()
+
+	filters, err := s.SavedFilters(ctx, FilterModeScenes)
+	require.NoError(t, err)
+	require.Len(t, filters, 1)
+	sceneFilter, err := filters[0].SceneFilter()
+	require.NoError(t, err)
+	require.Equal(t, []string{"1"}, sceneFilter.Tags.Value)
+
+	saved, err := s.SaveFilter(ctx, SaveFilter{
+		Mode:         FilterModeGalleries,
+		Name:         "Zips",
+		FindFilter:   &FindFilter{Sort: SortPath},
+		ObjectFilter: map[string]any{},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "82", saved.ID)
+	filters, err = s.SavedFilters(ctx, FilterModeGalleries)
+	require.NoError(t, err)
+	require.Equal(t, []SavedFilter{saved}, filters)
+
+	ok, err := s.DestroySavedFilter(ctx, saved.ID)
+	require.NoError(t, err)
+	require.True(t, ok)
+}
@@ -0,0 +1,121 @@
+package stash
+
+import (
+	"context"
+	"time"
+
+	"github.com/hasura/go-graphql-client"
+)
+
+// Movie is a movie as embedded in scenes.  MovieDetail carries the additional fields listed in the movies browser.
+type Movie struct {
+	ID   string `graphql:"id"`
+	Name string `graphql:"name"`
+}
+
+func (m Movie) EntityID() string {
+	return m.ID
+}
+
+// SceneMovie is the membership of a scene in a movie.  SceneIndex is the position of the scene within the movie, or 0
+// if it has not been set.
+type SceneMovie struct {
+	Movie      Movie `graphql:"movie"`
+	SceneIndex int   `graphql:"scene_index"`
+}
+
+// MovieDetail is a movie as listed in the movies browser.  Duration is in seconds.
+type MovieDetail struct {
+	ID         string    `graphql:"id"`
+	Name       string    `graphql:"name"`
+	Aliases    string    `graphql:"aliases"`
+	Duration   int       `graphql:"duration"`
+	Date       string    `graphql:"date"`
+	Rating     int       `graphql:"rating100"`
+	Director   string    `graphql:"director"`
+	Synopsis   string    `graphql:"synopsis"`
+	URL        string    `graphql:"url"`
+	Studio     Studio    `graphql:"studio"`
+	SceneCount int       `graphql:"scene_count"`
+	CreatedAt  time.Time `graphql:"created_at"`
+	UpdatedAt  time.Time `graphql:"updated_at"`
+}
+
+func (m MovieDetail) EntityID() string {
+	return m.ID
+}
+
+// Movie returns the subset of the movie embedded in scenes.
+func (m MovieDetail) Movie() Movie {
+	return Movie{ID: m.ID, Name: m.Name}
+}
+
+type moviesQuery struct {
+	FindMovies struct {
+		Count  int           `graphql:"count"`
+		Movies []MovieDetail `graphql:"movies"`
+	} `graphql:"findMovies(filter: $filter, movie_filter: $movie_filter)"`
+}
+
+// Movies returns a page of movies matching the given filters along with the total count of matches.
+func (s stash) Movies(ctx context.Context, filter FindFilter, movieFilter MovieFilter) ([]MovieDetail, int, error) {
+	resp := moviesQuery{}
+	err := s.client.Query(ctx, &resp, map[string]any{
+		"filter":       filter,
+		"movie_filter": movieFilter,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return resp.FindMovies.Movies, resp.FindMovies.Count, nil
+}
+
+type MovieCreate struct {
+	Name     string      `json:"name"`
+	Aliases  *string     `json:"aliases,omitempty"`
+	Duration *int        `json:"duration,omitempty"`
+	Date     *string     `json:"date,omitempty"`
+	StudioID *graphql.ID `json:"studio_id,omitempty"`
+	Director *string     `json:"director,omitempty"`
+	Synopsis *string     `json:"synopsis,omitempty"`
+	URL      *string     `json:"url,omitempty"`
+}
+
+func (MovieCreate) GetGraphQLType() string {
+	return "MovieCreateInput"
+}
+
+// MovieCreate creates a new movie in the stash instance and returns it with it's ID value.
+func (s stash) MovieCreate(ctx context.Context, movie MovieCreate) (MovieDetail, error) {
+	var m struct {
+		Movie MovieDetail `graphql:"movieCreate(input: $input)"`
+	}
+	err := s.client.Mutate(ctx, &m, map[string]any{"input": movie})
+	return m.Movie, err
+}
+
+// MovieUpdate is the input for updating a movie.  Nil fields are left unchanged.
+type MovieUpdate struct {
+	ID       graphql.ID  `json:"id"`
+	Name     *string     `json:"name,omitempty"`
+	Aliases  *string     `json:"aliases,omitempty"`
+	Duration *int        `json:"duration,omitempty"`
+	Date     *string     `json:"date,omitempty"`
+	Rating   *int        `json:"rating100,omitempty"`
+	StudioID *graphql.ID `json:"studio_id,omitempty"`
+	Director *string     `json:"director,omitempty"`
+	Synopsis *string     `json:"synopsis,omitempty"`
+	URL      *string     `json:"url,omitempty"`
+}
+
+func (MovieUpdate) GetGraphQLType() string {
+	return "MovieUpdateInput"
+}
+
+func (s stash) MovieUpdate(ctx context.Context, movie MovieUpdate) (MovieDetail, error) {
+	var m struct {
+		Movie MovieDetail `graphql:"movieUpdate(input: $input)"`
+	}
+	err := s.client.Mutate(ctx, &m, map[string]any{"input": movie})
+	return m.Movie, err
+}
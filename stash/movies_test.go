@@ -0,0 +1,60 @@
+package stash
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hasura/go-graphql-client"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMovies(t *testing.T) {
+	doer := &captureEndpoint{
+		t: t,
+		response: `{"data": {"findMovies": {"count": 3, "movies": [{
+			"id": "1",
+			"name": "Movie 1",
+			"aliases": "",
+			"duration": 5400,
+			"date": "2023-07-19",
+			"rating100": 0,
+			"director": "",
+			"synopsis": "",
+			"url": "",
+			"studio": {"id": "studio1", "name": "Studio 1"},
+			"scene_count": 4,
+			"created_at": "2023-07-01T00:00:00Z",
+			"updated_at": "2023-07-18T00:00:00Z"
+		}]}}}`,
+	}
+	client := graphql.NewClient("https://example.com/graph", doer)
+	s := stash{client}
+
+	movies, count, err := s.Movies(context.Background(), FindFilter{}, MovieFilter{
+		Studios: &HierarchicalMultiCriterion{Value: []string{"studio1"}, Modifier: CriterionModifierIncludes},
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, 3, count)
+	require.Len(t, movies, 1)
+	require.Equal(t, Movie{ID: "1", Name: "Movie 1"}, movies[0].Movie())
+	require.Equal(t, 5400, movies[0].Duration)
+	require.Equal(t, 4, movies[0].SceneCount)
+	require.Contains(t, doer.body, `findMovies(filter: $filter, movie_filter: $movie_filter)`)
+	require.Contains(t, doer.body, `"studios":{"value":["studio1"],"modifier":"INCLUDES"}`)
+}
+
+func TestMovieUpdate(t *testing.T) {
+	doer := &captureEndpoint{
+		t:        t,
+		response: `{"data": {"movieUpdate": {"id": "1", "name": "Renamed"}}}`,
+	}
+	client := graphql.NewClient("https://example.com/graph", doer)
+	s := stash{client}
+
+	movie, err := s.MovieUpdate(context.Background(), MovieUpdate{ID: "1", Name: ptr("Renamed")})
+	require.NoError(t, err)
+	require.Equal(t, "Renamed", movie.Name)
+	require.Contains(t, doer.body, `movieUpdate(input: $input)`)
+	require.Contains(t, doer.body, `"input":{"id":"1","name":"Renamed"}`)
+}
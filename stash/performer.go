@@ -4,19 +4,26 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"slices"
 	"strings"
+
+	"github.com/hasura/go-graphql-client"
 )
 
 type Performer struct {
-	ID         string  `graphql:"id"`
-	Name       string  `graphql:"name"`
-	URL        string  `graphql:"url"`
-	Birthdate  string  `graphql:"birthdate"`
-	Gender     Gender  `graphql:"gender"`
-	Country    Country `graphql:"country"`
-	Favorite   bool    `graphql:"favorite"`
-	SceneCount int     `graphql:"scene_count"`
-	Tags       []Tag   `graphql:"tags"`
+	ID             string   `graphql:"id"`
+	Name           string   `graphql:"name"`
+	Disambiguation string   `graphql:"disambiguation"`
+	Aliases        []string `graphql:"alias_list"`
+	URL            string   `graphql:"url"`
+	URLs           []string `graphql:"urls"`
+	Birthdate      string   `graphql:"birthdate"`
+	Gender         Gender   `graphql:"gender"`
+	Country        Country  `graphql:"country"`
+	Favorite       bool     `graphql:"favorite"`
+	SceneCount     int      `graphql:"scene_count"`
+	Tags           []Tag    `graphql:"tags"`
 }
 
 func (p Performer) EntityID() string {
@@ -36,23 +43,29 @@ const (
 )
 
 func (g Gender) MarshalJSON() ([]byte, error) {
+	if g < GenderNotSpecified || g > GenderNonBinary {
+		return nil, errors.New("invalid Gender value")
+	}
+	return json.Marshal(g.Enum())
+}
+
+// Enum returns the GenderEnum string value of g such as "FEMALE", or an empty string if not specified.
+func (g Gender) Enum() string {
 	switch g {
-	case GenderNotSpecified:
-		return json.Marshal("")
 	case GenderMale:
-		return json.Marshal("MALE")
+		return "MALE"
 	case GenderFemale:
-		return json.Marshal("FEMALE")
+		return "FEMALE"
 	case GenderTransMale:
-		return json.Marshal("TRANSGENDER_MALE")
+		return "TRANSGENDER_MALE"
 	case GenderTransFemale:
-		return json.Marshal("TRANSGENDER_FEMALE")
+		return "TRANSGENDER_FEMALE"
 	case GenderIntersex:
-		return json.Marshal("INTERSEX")
+		return "INTERSEX"
 	case GenderNonBinary:
-		return json.Marshal("NON_BINARY")
+		return "NON_BINARY"
 	default:
-		return nil, errors.New("invalid Gender value")
+		return ""
 	}
 }
 
@@ -62,26 +75,35 @@ func (g *Gender) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	switch s {
+	parsed, err := ParseGender(s)
+	if err != nil {
+		return err
+	}
+	*g = parsed
+	return nil
+}
+
+// ParseGender returns the Gender for a GenderEnum string value such as "FEMALE" or "TRANSGENDER_MALE".  Matching is
+// case insensitive so that values can be accepted from user input.
+func ParseGender(s string) (Gender, error) {
+	switch strings.ToUpper(s) {
 	case "":
-		*g = GenderNotSpecified
+		return GenderNotSpecified, nil
 	case "MALE":
-		*g = GenderMale
+		return GenderMale, nil
 	case "FEMALE":
-		*g = GenderFemale
+		return GenderFemale, nil
 	case "TRANSGENDER_MALE":
-		*g = GenderTransMale
+		return GenderTransMale, nil
 	case "TRANSGENDER_FEMALE":
-		*g = GenderTransFemale
+		return GenderTransFemale, nil
 	case "INTERSEX":
-		*g = GenderIntersex
+		return GenderIntersex, nil
 	case "NON_BINARY":
-		*g = GenderNonBinary
+		return GenderNonBinary, nil
 	default:
-		return errors.New("invalid Gender string")
+		return GenderNotSpecified, errors.New("invalid Gender string")
 	}
-
-	return nil
 }
 
 func (g Gender) String() string {
@@ -143,10 +165,64 @@ func (s stash) PerformersAll(ctx context.Context) ([]PerformerSummary, error) {
 	return resp.Performers, nil
 }
 
+type performersQuery struct {
+	FindPerformers struct {
+		Count      int         `graphql:"count"`
+		Performers []Performer `graphql:"performers"`
+	} `graphql:"findPerformers(filter: $filter, performer_filter: $performer_filter)"`
+}
+
+// Performers returns a page of performers matching the given filters along with the total count of matches.
+func (s stash) Performers(ctx context.Context, filter FindFilter, performerFilter PerformerFilter) ([]Performer, int, error) {
+	resp := performersQuery{}
+	err := s.client.Query(ctx, &resp, map[string]any{
+		"filter":           filter,
+		"performer_filter": performerFilter,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return resp.FindPerformers.Performers, resp.FindPerformers.Count, nil
+}
+
 type PerformerCreate struct {
-	Name           string  `json:"name"`
-	Disambiguation string  `json:"disambiguation,omitempty"`
-	URL            *string `json:"url,omitempty"`
+	Name           string       `json:"name"`
+	Disambiguation string       `json:"disambiguation,omitempty"`
+	URL            *string      `json:"url,omitempty"`
+	URLs           []string     `json:"urls,omitempty"`
+	Aliases        []string     `json:"alias_list,omitempty"`
+	Gender         *Gender      `json:"gender,omitempty"`
+	Birthdate      *string      `json:"birthdate,omitempty"`
+	Country        *string      `json:"country,omitempty"`
+	Favorite       *bool        `json:"favorite,omitempty"`
+	TagIDs         []graphql.ID `json:"tag_ids,omitempty"`
+}
+
+// NewPerformerCreate returns a PerformerCreate for p.  Fields that are empty are left unset.
+func NewPerformerCreate(p Performer) PerformerCreate {
+	c := PerformerCreate{
+		Name:           p.Name,
+		Disambiguation: p.Disambiguation,
+		URLs:           p.URLs,
+		Aliases:        p.Aliases,
+	}
+	if p.Gender != GenderNotSpecified {
+		c.Gender = &p.Gender
+	}
+	if p.Birthdate != "" {
+		c.Birthdate = &p.Birthdate
+	}
+	if p.Country != "" {
+		country := string(p.Country)
+		c.Country = &country
+	}
+	if p.Favorite {
+		c.Favorite = &p.Favorite
+	}
+	for _, t := range p.Tags {
+		c.TagIDs = append(c.TagIDs, graphql.ID(t.ID))
+	}
+	return c
 }
 
 func (PerformerCreate) GetGraphQLType() string {
@@ -162,6 +238,82 @@ func (s stash) PerformerCreate(ctx context.Context, p PerformerCreate) (Performe
 	return m.Performer, err
 }
 
+type PerformerUpdate struct {
+	ID             graphql.ID    `json:"id"`
+	Name           *string       `json:"name,omitempty"`
+	Disambiguation *string       `json:"disambiguation,omitempty"`
+	URLs           *[]string     `json:"urls,omitempty"`
+	Aliases        *[]string     `json:"alias_list,omitempty"`
+	Gender         *Gender       `json:"gender,omitempty"`
+	Birthdate      *string       `json:"birthdate,omitempty"`
+	Country        *string       `json:"country,omitempty"`
+	Favorite       *bool         `json:"favorite,omitempty"`
+	TagIDs         *[]graphql.ID `json:"tag_ids,omitempty"`
+}
+
+func (PerformerUpdate) GetGraphQLType() string {
+	return "PerformerUpdateInput"
+}
+
+// NewPerformerUpdate does a diff of an old and new Performer and returns a PerformerUpdate that can be passed to
+// stash.PerformerUpdate.  A gender cannot be cleared once set, so a new gender that is not specified is left unchanged.
+// A panic will occur if the IDs of the performers do not match.
+func NewPerformerUpdate(pOld, pNew Performer) PerformerUpdate {
+	p := PerformerUpdate{
+		ID: graphql.ID(pNew.ID),
+	}
+
+	if pOld.ID != pNew.ID {
+		panic(fmt.Errorf("performers do not have the same id old: %s new: %s", pOld.ID, pNew.ID))
+	}
+
+	if pOld.Name != pNew.Name {
+		p.Name = &pNew.Name
+	}
+	if pOld.Disambiguation != pNew.Disambiguation {
+		p.Disambiguation = &pNew.Disambiguation
+	}
+	if !slices.Equal(pOld.URLs, pNew.URLs) {
+		urls := append([]string{}, pNew.URLs...)
+		p.URLs = &urls
+	}
+	if !slices.Equal(pOld.Aliases, pNew.Aliases) {
+		aliases := append([]string{}, pNew.Aliases...)
+		p.Aliases = &aliases
+	}
+	if pOld.Gender != pNew.Gender && pNew.Gender != GenderNotSpecified {
+		p.Gender = &pNew.Gender
+	}
+	if pOld.Birthdate != pNew.Birthdate {
+		p.Birthdate = &pNew.Birthdate
+	}
+	if pOld.Country != pNew.Country {
+		country := string(pNew.Country)
+		p.Country = &country
+	}
+	if pOld.Favorite != pNew.Favorite {
+		p.Favorite = &pNew.Favorite
+	}
+	if !tagListsEqual(pOld.Tags, pNew.Tags) {
+		tagIDs := make([]graphql.ID, len(pNew.Tags))
+		for i, t := range pNew.Tags {
+			tagIDs[i] = graphql.ID(t.ID)
+		}
+		p.TagIDs = &tagIDs
+	}
+
+	return p
+}
+
+// PerformerUpdate saves changes to a performer and returns it as updated.
+func (s stash) PerformerUpdate(ctx context.Context, p PerformerUpdate) (Performer, error) {
+	var m struct {
+		Performer Performer `graphql:"performerUpdate(input: $input)"`
+	}
+	err := s.client.Mutate(ctx, &m, map[string]any{"input": p})
+	return m.Performer, err
+}
+
 type findPerformerQuery struct {
 	Performer Performer `graphql:"findPerformer(id: $id)"`
 }
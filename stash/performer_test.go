@@ -0,0 +1,133 @@
+package stash
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hasura/go-graphql-client"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindPerformers(t *testing.T) {
+	doer := &captureEndpoint{
+		t: t,
+		response: `{"data": {"findPerformers": {
+			"count": 2,
+			"performers": [
+				{
+					"id": "1",
+					"name": "Performer 1",
+					"disambiguation": "",
+					"alias_list": ["Alias 1"],
+					"gender": "FEMALE",
+					"country": "AU",
+					"favorite": true,
+					"scene_count": 12,
+					"tags": [{"id": "tag1", "name": "Tag 1"}]
+				}
+			]
+		}}}`,
+	}
+	client := graphql.NewClient("https://example.com/graph", doer)
+	s := stash{client}
+
+	performers, count, err := s.Performers(context.Background(), FindFilter{}, PerformerFilter{
+		Favourite: ptr(true),
+		Gender: &GenderCriterion{
+			Value:    GenderFemale,
+			Modifier: CriterionModifierEquals,
+		},
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+	require.Equal(t, []Performer{{
+		ID:         "1",
+		Name:       "Performer 1",
+		Aliases:    []string{"Alias 1"},
+		Gender:     GenderFemale,
+		Country:    "AU",
+		Favorite:   true,
+		SceneCount: 12,
+		Tags:       []Tag{{ID: "tag1", Name: "Tag 1"}},
+	}}, performers)
+	require.Contains(t, doer.body, `findPerformers(filter: $filter, performer_filter: $performer_filter)`)
+	require.Contains(t, doer.body, `"filter_favorites":true`)
+	require.Contains(t, doer.body, `"gender":{"value":"FEMALE","modifier":"EQUALS"}`)
+}
+
+func TestParseGender(t *testing.T) {
+	g, err := ParseGender("transgender_female")
+	require.NoError(t, err)
+	require.Equal(t, Gender(GenderTransFemale), g)
+
+	_, err = ParseGender("unknown")
+	require.Error(t, err)
+}
+
+func TestNewPerformerUpdate(t *testing.T) {
+	old := Performer{
+		ID:      "1",
+		Name:    "Performer 1",
+		Gender:  GenderFemale,
+		Aliases: []string{"Alias 1"},
+		Tags:    []Tag{{ID: "tag1"}},
+	}
+	updated := old
+	updated.Name = "Performer One"
+	updated.Gender = GenderNotSpecified
+	updated.Aliases = nil
+	updated.Favorite = true
+	updated.Country = "AU"
+
+	require.Equal(t, PerformerUpdate{
+		ID:       "1",
+		Name:     ptr("Performer One"),
+		Aliases:  &[]string{},
+		Country:  ptr("AU"),
+		Favorite: ptr(true),
+	}, NewPerformerUpdate(old, updated))
+}
+
+func TestPerformerUpdate(t *testing.T) {
+	doer := &captureEndpoint{
+		t:        t,
+		response: `{"data": {"performerUpdate": {"id": "1", "name": "Performer One", "gender": "MALE", "favorite": true}}}`,
+	}
+	client := graphql.NewClient("https://example.com/graph", doer)
+	s := stash{client}
+
+	gender := Gender(GenderMale)
+	performer, err := s.PerformerUpdate(context.Background(), PerformerUpdate{
+		ID:       "1",
+		Name:     ptr("Performer One"),
+		Gender:   &gender,
+		Favorite: ptr(true),
+		TagIDs:   &[]graphql.ID{},
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, Performer{ID: "1", Name: "Performer One", Gender: GenderMale, Favorite: true}, performer)
+	require.Contains(t, doer.body, `performerUpdate(input: $input)`)
+	require.Contains(t, doer.body, `$input:PerformerUpdateInput!`)
+	require.Contains(t, doer.body, `"input":{"id":"1","name":"Performer One","gender":"MALE","favorite":true,"tag_ids":[]}`)
+}
+
+func TestPerformerCreate(t *testing.T) {
+	doer := &captureEndpoint{
+		t:        t,
+		response: `{"data": {"performerCreate": {"id": "2", "name": "New"}}}`,
+	}
+	client := graphql.NewClient("https://example.com/graph", doer)
+	s := stash{client}
+
+	_, err := s.PerformerCreate(context.Background(), NewPerformerCreate(Performer{
+		Name:    "New",
+		URLs:    []string{"https://example.com/new"},
+		Country: "AU",
+		Tags:    []Tag{{ID: "tag1"}},
+	}))
+
+	require.NoError(t, err)
+	require.Contains(t, doer.body, `"input":{"name":"New","urls":["https://example.com/new"],"country":"AU","tag_ids":["tag1"]}`)
+}
@@ -0,0 +1,325 @@
+package stash
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/hasura/go-graphql-client"
+)
+
+type FilterMode string
+
+const (
+	FilterModeScenes       FilterMode = "SCENES"
+	FilterModePerformers   FilterMode = "PERFORMERS"
+	FilterModeStudios      FilterMode = "STUDIOS"
+	FilterModeGalleries    FilterMode = "GALLERIES"
+	FilterModeSceneMarkers FilterMode = "SCENE_MARKERS"
+	FilterModeMovies       FilterMode = "MOVIES"
+	FilterModeTags         FilterMode = "TAGS"
+	FilterModeImages       FilterMode = "IMAGES"
+)
+
+// SavedFilter is a named filter stored by stash, which is shared with the web UI.  ObjectFilter holds criteria in the
+// form saved by the web UI, and is mapped to a filter type by SceneFilter or GalleryFilter.
+type SavedFilter struct {
+	ID           string           `graphql:"id"`
+	Mode         FilterMode       `graphql:"mode"`
+	Name         string           `graphql:"name"`
+	FindFilter   *SavedFindFilter `graphql:"find_filter"`
+	ObjectFilter json.RawMessage  `graphql:"object_filter"`
+}
+
+type SavedFindFilter struct {
+	Query     string `graphql:"q"`
+	PerPage   int    `graphql:"per_page"`
+	Sort      string `graphql:"sort"`
+	Direction string `graphql:"direction"`
+}
+
+// SceneFilter returns the criteria of a SCENES filter.  Criteria that SceneFilter does not support are dropped.
+func (f SavedFilter) SceneFilter() (SceneFilter, error) {
+	var filter SceneFilter
+	err := decodeObjectFilter(f.ObjectFilter, &filter)
+	return filter, err
+}
+
+// GalleryFilter returns the criteria of a GALLERIES filter.  Criteria that GalleryFilter does not support are dropped.
+func (f SavedFilter) GalleryFilter() (GalleryFilter, error) {
+	var filter GalleryFilter
+	err := decodeObjectFilter(f.ObjectFilter, &filter)
+	return filter, err
+}
+
+// SaveFilter saves a filter under Name, replacing the filter with ID if given.  ObjectFilter is built from a filter
+// type by ObjectFilter.
+type SaveFilter struct {
+	ID           *string        `json:"id,omitempty"`
+	Mode         FilterMode     `json:"mode"`
+	Name         string         `json:"name"`
+	FindFilter   *FindFilter    `json:"find_filter,omitempty"`
+	ObjectFilter map[string]any `json:"object_filter,omitempty"`
+}
+
+func (SaveFilter) GetGraphQLType() string {
+	return "SaveFilterInput"
+}
+
+// SavedFilters returns the filters saved for mode.
+func (s *stash) SavedFilters(ctx context.Context, mode FilterMode) ([]SavedFilter, error) {
+	var q struct {
+		FindSavedFilters []SavedFilter `graphql:"findSavedFilters(mode: $mode)"`
+	}
+	err := s.client.Query(ctx, &q, map[string]any{"mode": mode})
+	return q.FindSavedFilters, err
+}
+
+// DefaultFilter returns the default filter for mode, or false if there is none.
+func (s *stash) DefaultFilter(ctx context.Context, mode FilterMode) (SavedFilter, bool, error) {
+	var q struct {
+		FindDefaultFilter *SavedFilter `graphql:"findDefaultFilter(mode: $mode)"`
+	}
+	err := s.client.Query(ctx, &q, map[string]any{"mode": mode})
+	if err != nil || q.FindDefaultFilter == nil {
+		return SavedFilter{}, false, err
+	}
+	return *q.FindDefaultFilter, true, nil
+}
+
+func (s *stash) SaveFilter(ctx context.Context, input SaveFilter) (SavedFilter, error) {
+	var m struct {
+		SaveFilter SavedFilter `graphql:"saveFilter(input: $input)"`
+	}
+	err := s.client.Mutate(ctx, &m, map[string]any{"input": input})
+	return m.SaveFilter, err
+}
+
+func (s *stash) DestroySavedFilter(ctx context.Context, id string) (bool, error) {
+	var m struct {
+		Result bool `graphql:"destroySavedFilter(input: {id: $id})"`
+	}
+	err := s.client.Mutate(ctx, &m, map[string]any{"id": graphql.ID(id)})
+	return m.Result, err
+}
+
+// The web UI saves criteria in the form it edits them, which differs from the filter input types for several kinds of
+// criterion.  Lists of IDs are saved as items labelled with the name of each entity, ranges nest their values in an
+// object, and booleans and plain values such as is_missing are saved as criteria with an EQUALS modifier.  Criteria are mapped between the two
+// forms using the type of the matching filter field.
+
+var (
+	boolType                       = reflect.TypeOf(false)
+	stringType                     = reflect.TypeOf("")
+	multiCriterionType             = reflect.TypeOf(MultiCriterion{})
+	hierarchicalMultiCriterionType = reflect.TypeOf(HierarchicalMultiCriterion{})
+	intCriterionType               = reflect.TypeOf(IntCriterion{})
+	dateCriterionType              = reflect.TypeOf(DateCriterion{})
+	timestampCriterionType         = reflect.TypeOf(TimestampCriterion{})
+)
+
+// ObjectFilter returns filter, which is a filter type such as SceneFilter, in the form saved by the web UI.  Entities
+// are labelled by ID, since filters only hold the IDs of the entities they match.
+func ObjectFilter(filter any) (map[string]any, error) {
+	data, err := json.Marshal(filter)
+	if err != nil {
+		return nil, err
+	}
+	var criteria map[string]any
+	if err := json.Unmarshal(data, &criteria); err != nil {
+		return nil, err
+	}
+	return toSavedCriteria(criteria, reflect.Indirect(reflect.ValueOf(filter)).Type()), nil
+}
+
+func decodeObjectFilter(data json.RawMessage, filter any) error {
+	if len(data) == 0 || string(data) == "null" {
+		return nil
+	}
+	var saved map[string]any
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return err
+	}
+	criteria := fromSavedCriteria(saved, reflect.TypeOf(filter).Elem())
+	data, err := json.Marshal(criteria)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, filter)
+}
+
+// filterFields returns the type of each field of the filter type t by its JSON name, including the AND, OR and NOT
+// sub-filters.
+func filterFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous {
+			for name, ft := range filterFields(f.Type) {
+				fields[name] = ft
+			}
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+func isSubFilter(name string) bool {
+	return name == "AND" || name == "OR" || name == "NOT"
+}
+
+func toSavedCriteria(criteria map[string]any, t reflect.Type) map[string]any {
+	fields := filterFields(t)
+	saved := make(map[string]any, len(criteria))
+	for name, value := range criteria {
+		ft, ok := fields[name]
+		if !ok {
+			continue
+		}
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if sub, ok := value.(map[string]any); ok && isSubFilter(name) {
+			saved[name] = toSavedCriteria(sub, ft)
+			continue
+		}
+		saved[name] = toSavedCriterion(value, ft)
+	}
+	return saved
+}
+
+func toSavedCriterion(value any, t reflect.Type) any {
+	switch t {
+	case boolType:
+		if b, ok := value.(bool); ok {
+			return map[string]any{"modifier": CriterionModifierEquals.String(), "value": strconv.FormatBool(b)}
+		}
+	case stringType:
+		return map[string]any{"modifier": CriterionModifierEquals.String(), "value": value}
+	}
+
+	c, ok := value.(map[string]any)
+	if !ok {
+		return value
+	}
+	saved := map[string]any{"modifier": c["modifier"]}
+	switch t {
+	case multiCriterionType:
+		saved["value"] = labelledItems(c["value"])
+	case hierarchicalMultiCriterionType:
+		depth, _ := c["depth"].(float64)
+		saved["value"] = map[string]any{
+			"items":    labelledItems(c["value"]),
+			"excluded": labelledItems(c["excludes"]),
+			"depth":    depth,
+		}
+	case intCriterionType, dateCriterionType, timestampCriterionType:
+		v := map[string]any{"value": c["value"]}
+		if value2, ok := c["value2"]; ok && value2 != nil {
+			v["value2"] = value2
+		}
+		saved["value"] = v
+	default:
+		return c
+	}
+	return saved
+}
+
+func labelledItems(ids any) []any {
+	items := []any{}
+	list, _ := ids.([]any)
+	for _, id := range list {
+		items = append(items, map[string]any{"id": id, "label": id})
+	}
+	return items
+}
+
+func fromSavedCriteria(saved map[string]any, t reflect.Type) map[string]any {
+	fields := filterFields(t)
+	criteria := make(map[string]any, len(saved))
+	for name, value := range saved {
+		ft, ok := fields[name]
+		if !ok {
+			continue
+		}
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if sub, ok := value.(map[string]any); ok && isSubFilter(name) {
+			criteria[name] = fromSavedCriteria(sub, ft)
+			continue
+		}
+		criteria[name] = fromSavedCriterion(value, ft)
+	}
+	return criteria
+}
+
+func fromSavedCriterion(value any, t reflect.Type) any {
+	c, ok := value.(map[string]any)
+	if !ok {
+		return value
+	}
+
+	switch t {
+	case boolType:
+		if s, ok := c["value"].(string); ok {
+			b, err := strconv.ParseBool(s)
+			if err == nil {
+				return b
+			}
+		}
+		return c["value"]
+	case stringType:
+		return c["value"]
+	}
+
+	criterion := make(map[string]any, len(c))
+	for k, v := range c {
+		criterion[k] = v
+	}
+	switch t {
+	case multiCriterionType:
+		if items, ok := c["value"].([]any); ok {
+			criterion["value"] = itemIDs(items)
+		}
+	case hierarchicalMultiCriterionType:
+		if v, ok := c["value"].(map[string]any); ok {
+			items, _ := v["items"].([]any)
+			criterion["value"] = itemIDs(items)
+			if excluded, _ := v["excluded"].([]any); len(excluded) > 0 {
+				criterion["excludes"] = itemIDs(excluded)
+			}
+			if depth, ok := v["depth"]; ok {
+				criterion["depth"] = depth
+			}
+		}
+	case intCriterionType, dateCriterionType, timestampCriterionType:
+		if v, ok := c["value"].(map[string]any); ok {
+			criterion["value"] = v["value"]
+			if value2, ok := v["value2"]; ok && value2 != nil {
+				criterion["value2"] = value2
+			}
+		}
+	}
+	return criterion
+}
+
+// itemIDs returns the IDs of labelled items.  Items that are already plain IDs are kept as they are.
+func itemIDs(items []any) []any {
+	ids := make([]any, 0, len(items))
+	for _, item := range items {
+		if labelled, ok := item.(map[string]any); ok {
+			ids = append(ids, labelled["id"])
+			continue
+		}
+		ids = append(ids, item)
+	}
+	return ids
+}
@@ -0,0 +1,120 @@
+package stash
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hasura/go-graphql-client"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSavedFilters(t *testing.T) {
+	doer := &captureEndpoint{
+		t: t,
+		response: `{"data": {"findSavedFilters": [{
+			"id": "3",
+			"mode": "SCENES",
+			"name": "Favourites",
+			"find_filter": {"q": "beach", "per_page": 40, "sort": "date", "direction": "DESC"},
+			"object_filter": {
+				"organized": {"modifier": "EQUALS", "value": "true"},
+				"tags": {"modifier": "INCLUDES", "value": {"items": [{"id": "1", "label": "Outdoors"}], "excluded": [{"id": "2", "label": "Indoors"}], "depth": -1}},
+				"performers": {"modifier": "INCLUDES_ALL", "value": [{"id": "7", "label": "Jane"}]},
+				"rating100": {"modifier": "BETWEEN", "value": {"value": 60, "value2": 80}},
+				"title": {"modifier": "INCLUDES", "value": "sunset"},
+				"is_missing": {"modifier": "EQUALS", "value": "cover"},
+				"unknown": {"modifier": "EQUALS", "value": "ignored"}
+			}
+		}]}}`,
+	}
+	client := graphql.NewClient("https://example.com/graph", doer)
+	s := stash{client}
+
+	filters, err := s.SavedFilters(context.Background(), FilterModeScenes)
+
+	require.NoError(t, err)
+	require.Contains(t, doer.body, `findSavedFilters(mode: $mode)`)
+	requireValidQuery(t, doer.body)
+	require.Contains(t, doer.body, `"variables":{"mode":"SCENES"}`)
+	require.Len(t, filters, 1)
+	require.Equal(t, "Favourites", filters[0].Name)
+	require.Equal(t, &SavedFindFilter{Query: "beach", PerPage: 40, Sort: "date", Direction: "DESC"}, filters[0].FindFilter)
+
+	filter, err := filters[0].SceneFilter()
+	require.NoError(t, err)
+	organized := true
+	missing := "cover"
+	value2 := 80
+	require.Equal(t, SceneFilter{
+		Organized:  &organized,
+		Tags:       &HierarchicalMultiCriterion{Value: []string{"1"}, Excludes: []string{"2"}, Depth: -1, Modifier: CriterionModifierIncludes},
+		Performers: &MultiCriterion{Value: []string{"7"}, Modifier: CriterionModifierIncludesAll},
+		Rating100:  &IntCriterion{Value: 60, Value2: &value2, Modifier: CriterionModifierBetween},
+		Title:      &StringCriterion{Value: "sunset", Modifier: CriterionModifierIncludes},
+		IsMissing:  &missing,
+	}, filter)
+}
+
+func TestDefaultFilter(t *testing.T) {
+	doer := &captureEndpoint{
+		t:        t,
+		response: `{"data": {"findDefaultFilter": null}}`,
+	}
+	client := graphql.NewClient("https://example.com/graph", doer)
+	s := stash{client}
+
+	_, ok, err := s.DefaultFilter(context.Background(), FilterModeGalleries)
+
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Contains(t, doer.body, `"variables":{"mode":"GALLERIES"}`)
+	requireValidQuery(t, doer.body)
+}
+
+func TestSaveFilter(t *testing.T) {
+	doer := &captureEndpoint{
+		t:        t,
+		response: `{"data": {"saveFilter": {"id": "4", "mode": "GALLERIES", "name": "Tagged"}}}`,
+	}
+	client := graphql.NewClient("https://example.com/graph", doer)
+	s := stash{client}
+
+	objectFilter, err := ObjectFilter(GalleryFilter{
+		Tags: &HierarchicalMultiCriterion{Value: []string{"1"}, Modifier: CriterionModifierIncludes},
+	})
+	require.NoError(t, err)
+	saved, err := s.SaveFilter(context.Background(), SaveFilter{
+		Mode:         FilterModeGalleries,
+		Name:         "Tagged",
+		ObjectFilter: objectFilter,
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, "4", saved.ID)
+	require.Contains(t, doer.body, `$input:SaveFilterInput!`)
+	requireValidQuery(t, doer.body)
+	require.Contains(t, doer.body, `"input":{"mode":"GALLERIES","name":"Tagged","object_filter":{"tags":{"modifier":"INCLUDES","value":{"depth":0,"excluded":[],"items":[{"id":"1","label":"1"}]}}}}`)
+
+	// Filters saved by ObjectFilter are read back unchanged.
+	filter, err := SavedFilter{ObjectFilter: []byte(`{"tags":{"modifier":"INCLUDES","value":{"depth":0,"excluded":[],"items":[{"id":"1","label":"1"}]}}}`)}.GalleryFilter()
+	require.NoError(t, err)
+	require.Equal(t, GalleryFilter{
+		Tags: &HierarchicalMultiCriterion{Value: []string{"1"}, Modifier: CriterionModifierIncludes},
+	}, filter)
+}
+
+func TestDestroySavedFilter(t *testing.T) {
+	doer := &captureEndpoint{
+		t:        t,
+		response: `{"data": {"destroySavedFilter": true}}`,
+	}
+	client := graphql.NewClient("https://example.com/graph", doer)
+	s := stash{client}
+
+	ok, err := s.DestroySavedFilter(context.Background(), "4")
+
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Contains(t, doer.body, `destroySavedFilter(input: {id: $id})`)
+	requireValidQuery(t, doer.body)
+}
@@ -2,24 +2,45 @@ package stash
 
 import (
 	"context"
+	"fmt"
+	"slices"
 	"time"
 
 	"github.com/hasura/go-graphql-client"
 )
 
 type Scene struct {
-	ID         string      `graphql:"id"`
-	Title      string      `graphql:"title"`
-	Date       string      `graphql:"date"`
-	Details    string      `graphql:"details"`
-	Rating     int         `graphql:"rating100"`
-	Organized  bool        `graphql:"organized"`
-	CreatedAt  time.Time   `graphql:"created_at"`
-	UpdatedAt  time.Time   `graphql:"updated_at"`
-	Files      []VideoFile `graphql:"files"`
-	Studio     Studio      `graphql:"studio"`
-	Tags       []Tag       `graphql:"tags"`
-	Performers []Performer `graphql:"performers"`
+	ID           string         `graphql:"id"`
+	Title        string         `graphql:"title"`
+	Date         string         `graphql:"date"`
+	Code         string         `graphql:"code"`
+	Details      string         `graphql:"details"`
+	Director     string         `graphql:"director"`
+	URLs         []string       `graphql:"urls"`
+	Rating       int            `graphql:"rating100"`
+	OCounter     int            `graphql:"o_counter"`
+	Organized    bool           `graphql:"organized"`
+	PlayCount    int            `graphql:"play_count"`
+	ResumeTime   float64        `graphql:"resume_time"`
+	LastPlayedAt *time.Time     `graphql:"last_played_at"`
+	CreatedAt    time.Time      `graphql:"created_at"`
+	UpdatedAt    time.Time      `graphql:"updated_at"`
+	Files        []VideoFile    `graphql:"files"`
+	Studio       Studio         `graphql:"studio"`
+	Tags         []Tag          `graphql:"tags"`
+	Performers   []Performer    `graphql:"performers"`
+	Movies       []SceneMovie   `graphql:"movies"`
+	Galleries    []SceneGallery `graphql:"galleries"`
+}
+
+// SceneGallery is a gallery as linked to a scene.
+type SceneGallery struct {
+	ID    string `graphql:"id"`
+	Title string `graphql:"title"`
+}
+
+func (s Scene) EntityID() string {
+	return s.ID
 }
 
 func (s Scene) FilePath() string {
@@ -47,14 +68,87 @@ func (s *stash) Scenes(ctx context.Context, filter FindFilter, sceneFilter Scene
 	return resp.FindScenes.Scenes, resp.FindScenes.Count, nil
 }
 
-func (s *stash) RecordPlay(ctx context.Context, sceneID string) error {
+// RecordPlay increments the play count of a scene, returning the new count.
+func (s *stash) RecordPlay(ctx context.Context, sceneID string) (int, error) {
 	var m struct {
 		SceneIncrementPlayCount int `graphql:"sceneIncrementPlayCount(id: $id)"`
 	}
 	variables := map[string]any{
-		"id": sceneID,
+		"id": graphql.ID(sceneID),
 	}
-	return s.client.Mutate(ctx, &m, variables)
+	err := s.client.Mutate(ctx, &m, variables)
+	return m.SceneIncrementPlayCount, err
+}
+
+// SceneSaveActivity records the resume time and additional play duration of a scene, both in seconds.  Either may be
+// nil to leave it unchanged.
+func (s *stash) SceneSaveActivity(ctx context.Context, sceneID string, resumeTime, playDuration *float64) (bool, error) {
+	var m struct {
+		Result bool `graphql:"sceneSaveActivity(id: $id, resume_time: $resume_time, playDuration: $playDuration)"`
+	}
+	variables := map[string]any{
+		"id":           graphql.ID(sceneID),
+		"resume_time":  resumeTime,
+		"playDuration": playDuration,
+	}
+	err := s.client.Mutate(ctx, &m, variables)
+	return m.Result, err
+}
+
+// SceneStreamEndpoint is a URL that a scene can be streamed from, either directly or transcoded to the format and
+// resolution given by Label.
+type SceneStreamEndpoint struct {
+	URL      string `graphql:"url"`
+	MimeType string `graphql:"mime_type"`
+	Label    string `graphql:"label"`
+}
+
+// SceneStreams returns the endpoints a scene can be streamed from.
+func (s *stash) SceneStreams(ctx context.Context, sceneID string) ([]SceneStreamEndpoint, error) {
+	var q struct {
+		Streams []SceneStreamEndpoint `graphql:"sceneStreams(id: $id)"`
+	}
+	variables := map[string]any{
+		"id": graphql.ID(sceneID),
+	}
+	err := s.client.Query(ctx, &q, variables)
+	return q.Streams, err
+}
+
+// SceneIncrementO increments the o-counter of a scene, returning the new count.
+func (s *stash) SceneIncrementO(ctx context.Context, sceneID string) (int, error) {
+	var m struct {
+		Count int `graphql:"sceneIncrementO(id: $id)"`
+	}
+	variables := map[string]any{
+		"id": graphql.ID(sceneID),
+	}
+	err := s.client.Mutate(ctx, &m, variables)
+	return m.Count, err
+}
+
+// SceneDecrementO decrements the o-counter of a scene, returning the new count.
+func (s *stash) SceneDecrementO(ctx context.Context, sceneID string) (int, error) {
+	var m struct {
+		Count int `graphql:"sceneDecrementO(id: $id)"`
+	}
+	variables := map[string]any{
+		"id": graphql.ID(sceneID),
+	}
+	err := s.client.Mutate(ctx, &m, variables)
+	return m.Count, err
+}
+
+// SceneResetO resets the o-counter of a scene to zero, returning the new count.
+func (s *stash) SceneResetO(ctx context.Context, sceneID string) (int, error) {
+	var m struct {
+		Count int `graphql:"sceneResetO(id: $id)"`
+	}
+	variables := map[string]any{
+		"id": graphql.ID(sceneID),
+	}
+	err := s.client.Mutate(ctx, &m, variables)
+	return m.Count, err
 }
 
 func (s *stash) DeleteScene(ctx context.Context, sceneID string) (bool, error) {
@@ -69,15 +163,108 @@ func (s *stash) DeleteScene(ctx context.Context, sceneID string) (bool, error) {
 }
 
 type SceneUpdate struct {
-	ClientMutationID *string      `json:"clientMutationId,omitempty"`
-	ID               graphql.ID   `json:"id"`
-	TagIDs           []graphql.ID `json:"tag_ids,omitempty"`
+	ClientMutationID *string            `json:"clientMutationId,omitempty"`
+	ID               graphql.ID         `json:"id"`
+	Title            *string            `json:"title,omitempty"`
+	Code             *string            `json:"code,omitempty"`
+	Details          *string            `json:"details,omitempty"`
+	Director         *string            `json:"director,omitempty"`
+	URLs             *[]string          `json:"urls,omitempty"`
+	Date             *string            `json:"date,omitempty"`
+	Rating           *int               `json:"rating100,omitempty"`
+	Organized        *bool              `json:"organized,omitempty"`
+	StudioID         *graphql.ID        `json:"studio_id,omitempty"`
+	GalleryIDs       *[]graphql.ID      `json:"gallery_ids,omitempty"`
+	PerformerIDs     *[]graphql.ID      `json:"performer_ids,omitempty"`
+	Movies           *[]SceneMovieInput `json:"movies,omitempty"`
+	TagIDs           *[]graphql.ID      `json:"tag_ids,omitempty"`
 }
 
 func (SceneUpdate) GetGraphQLType() string {
 	return "SceneUpdateInput"
 }
 
+type SceneMovieInput struct {
+	MovieID    graphql.ID `json:"movie_id"`
+	SceneIndex *int       `json:"scene_index,omitempty"`
+}
+
+// NewSceneUpdate does a diff of an old and new Scene and returns a SceneUpdate that can be passed to
+// stash.SceneUpdate.  A panic will occur if the IDs of the scenes do not match.
+func NewSceneUpdate(sOld, sNew Scene) SceneUpdate {
+	s := SceneUpdate{
+		ID: graphql.ID(sNew.ID),
+	}
+
+	if sOld.ID != sNew.ID {
+		panic(fmt.Errorf("scenes do not have the same id old: %s new: %s", sOld.ID, sNew.ID))
+	}
+
+	if sOld.Title != sNew.Title {
+		s.Title = &sNew.Title
+	}
+	if sOld.Code != sNew.Code {
+		s.Code = &sNew.Code
+	}
+	if sOld.Details != sNew.Details {
+		s.Details = &sNew.Details
+	}
+	if sOld.Director != sNew.Director {
+		s.Director = &sNew.Director
+	}
+	if !slices.Equal(sOld.URLs, sNew.URLs) {
+		urls := slices.Clone(sNew.URLs)
+		if urls == nil {
+			urls = []string{}
+		}
+		s.URLs = &urls
+	}
+	if sOld.Date != sNew.Date {
+		s.Date = &sNew.Date
+	}
+	if sOld.Rating != sNew.Rating {
+		s.Rating = &sNew.Rating
+	}
+	if sOld.Organized != sNew.Organized {
+		s.Organized = &sNew.Organized
+	}
+	if sOld.Studio.ID != sNew.Studio.ID {
+		id := graphql.ID(sNew.Studio.ID)
+		s.StudioID = &id
+	}
+	if !galleryListsEqual(sOld.Galleries, sNew.Galleries) {
+		galleryIDs := make([]graphql.ID, len(sNew.Galleries))
+		for i, g := range sNew.Galleries {
+			galleryIDs[i] = graphql.ID(g.ID)
+		}
+		s.GalleryIDs = &galleryIDs
+	}
+	if !performerListsEqual(sOld.Performers, sNew.Performers) {
+		performerIDs := make([]graphql.ID, len(sNew.Performers))
+		for i, p := range sNew.Performers {
+			performerIDs[i] = graphql.ID(p.ID)
+		}
+		s.PerformerIDs = &performerIDs
+	}
+	if !slices.Equal(sOld.Movies, sNew.Movies) {
+		movies := make([]SceneMovieInput, len(sNew.Movies))
+		for i, m := range sNew.Movies {
+			index := m.SceneIndex
+			movies[i] = SceneMovieInput{MovieID: graphql.ID(m.Movie.ID), SceneIndex: &index}
+		}
+		s.Movies = &movies
+	}
+	if !tagListsEqual(sOld.Tags, sNew.Tags) {
+		tagIDs := make([]graphql.ID, len(sNew.Tags))
+		for i, t := range sNew.Tags {
+			tagIDs[i] = graphql.ID(t.ID)
+		}
+		s.TagIDs = &tagIDs
+	}
+
+	return s
+}
+
 func (s *stash) SceneUpdate(ctx context.Context, scene SceneUpdate) (Scene, error) {
 	var m struct {
 		SceneUpdate Scene `graphql:"sceneUpdate(input: $input)"`
@@ -85,3 +272,78 @@ func (s *stash) SceneUpdate(ctx context.Context, scene SceneUpdate) (Scene, erro
 	err := s.client.Mutate(ctx, &m, map[string]any{"input": scene})
 	return m.SceneUpdate, err
 }
+
+// BulkSceneUpdate applies the same changes to every scene in IDs.
+type BulkSceneUpdate struct {
+	ClientMutationID *string        `json:"clientMutationId,omitempty"`
+	IDs              []graphql.ID   `json:"ids"`
+	Date             *string        `json:"date,omitempty"`
+	Rating           *int           `json:"rating100,omitempty"`
+	Organized        *bool          `json:"organized,omitempty"`
+	StudioID         *graphql.ID    `json:"studio_id,omitempty"`
+	PerformerIDs     *BulkUpdateIDs `json:"performer_ids,omitempty"`
+	TagIDs           *BulkUpdateIDs `json:"tag_ids,omitempty"`
+}
+
+func (BulkSceneUpdate) GetGraphQLType() string {
+	return "BulkSceneUpdateInput"
+}
+
+// NewBulkSceneUpdate returns a BulkSceneUpdate for the scenes with the given IDs.
+func NewBulkSceneUpdate(ids []string) BulkSceneUpdate {
+	return BulkSceneUpdate{IDs: GraphQLIDs(ids)}
+}
+
+func (s *stash) BulkSceneUpdate(ctx context.Context, update BulkSceneUpdate) ([]Scene, error) {
+	var m struct {
+		BulkSceneUpdate []Scene `graphql:"bulkSceneUpdate(input: $input)"`
+	}
+	err := s.client.Mutate(ctx, &m, map[string]any{"input": update})
+	return m.BulkSceneUpdate, err
+}
+
+// ScenesDestroy deletes a number of scenes in a single request, along with their files and generated content.
+func (s *stash) ScenesDestroy(ctx context.Context, sceneIDs []string) (bool, error) {
+	var m struct {
+		Result bool `graphql:"scenesDestroy(input: {ids: $ids, delete_file: true, delete_generated: true})"`
+	}
+	variables := map[string]any{
+		"ids": GraphQLIDs(sceneIDs),
+	}
+	err := s.client.Mutate(ctx, &m, variables)
+	return m.Result, err
+}
+
+// SceneMerge merges the Source scenes into the Destination scene, which takes on their files.  The source scenes are
+// removed.  Values optionally updates the destination scene as part of the merge.
+type SceneMerge struct {
+	Source      []graphql.ID `json:"source"`
+	Destination graphql.ID   `json:"destination"`
+	Values      *SceneUpdate `json:"values,omitempty"`
+}
+
+func (SceneMerge) GetGraphQLType() string {
+	return "SceneMergeInput"
+}
+
+func (s *stash) SceneMerge(ctx context.Context, input SceneMerge) (Scene, error) {
+	var m struct {
+		SceneMerge Scene `graphql:"sceneMerge(input: $input)"`
+	}
+	err := s.client.Mutate(ctx, &m, map[string]any{"input": input})
+	return m.SceneMerge, err
+}
+
+// FindDuplicateScenes returns groups of scenes having files with perceptual hashes within distance of each other.  Only
+// files with durations within durationDiff seconds of each other are matched, or files of any duration if durationDiff
+// is negative.
+func (s *stash) FindDuplicateScenes(ctx context.Context, distance int, durationDiff float64) ([][]Scene, error) {
+	var q struct {
+		FindDuplicateScenes [][]Scene `graphql:"findDuplicateScenes(distance: $distance, duration_diff: $duration_diff)"`
+	}
+	err := s.client.Query(ctx, &q, map[string]any{
+		"distance":      distance,
+		"duration_diff": durationDiff,
+	})
+	return q.FindDuplicateScenes, err
+}
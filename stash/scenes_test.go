@@ -3,6 +3,7 @@ package stash
 import (
 	"context"
 	_ "embed"
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -142,6 +143,78 @@ func TestRecordPlay(t *testing.T) {
 	require.True(t, doer.called)
 }
 
+func TestRecordPlayCount(t *testing.T) {
+	doer := &captureEndpoint{
+		t:        t,
+		response: `{"data": {"sceneIncrementPlayCount": 3}}`,
+	}
+	client := graphql.NewClient("https://example.com/graph", doer)
+	s := stash{client}
+
+	count, err := s.RecordPlay(context.Background(), "1234")
+
+	require.NoError(t, err)
+	require.Equal(t, 3, count)
+	require.Contains(t, doer.body, `$id:ID!`)
+	require.Contains(t, doer.body, `"id":"1234"`)
+}
+
+func TestSceneSaveActivity(t *testing.T) {
+	doer := &captureEndpoint{
+		t:        t,
+		response: `{"data": {"sceneSaveActivity": true}}`,
+	}
+	client := graphql.NewClient("https://example.com/graph", doer)
+	s := stash{client}
+	resume := 90.5
+
+	ok, err := s.SceneSaveActivity(context.Background(), "1234", &resume, nil)
+
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Contains(t, doer.body, `sceneSaveActivity(id: $id, resume_time: $resume_time, playDuration: $playDuration)`)
+	require.Contains(t, doer.body, `"resume_time":90.5`)
+	require.Contains(t, doer.body, `"playDuration":null`)
+}
+
+func TestSceneStreams(t *testing.T) {
+	doer := &captureEndpoint{
+		t: t,
+		response: `{"data": {"sceneStreams": [
+			{"url": "http://stash:9999/scene/1/stream", "mime_type": "video/mp4", "label": "Direct stream"},
+			{"url": "http://stash:9999/scene/1/stream.mp4?resolution=STANDARD", "mime_type": "video/mp4", "label": "MP4 Standard (480p)"}
+		]}}`,
+	}
+	client := graphql.NewClient("https://example.com/graph", doer)
+	s := stash{client}
+
+	streams, err := s.SceneStreams(context.Background(), "1")
+
+	require.NoError(t, err)
+	require.Len(t, streams, 2)
+	require.Equal(t, SceneStreamEndpoint{
+		URL:      "http://stash:9999/scene/1/stream.mp4?resolution=STANDARD",
+		MimeType: "video/mp4",
+		Label:    "MP4 Standard (480p)",
+	}, streams[1])
+	require.Contains(t, doer.body, `sceneStreams(id: $id)`)
+}
+
+func TestSceneIncrementO(t *testing.T) {
+	doer := &captureEndpoint{
+		t:        t,
+		response: `{"data": {"sceneIncrementO": 4}}`,
+	}
+	client := graphql.NewClient("https://example.com/graph", doer)
+	s := stash{client}
+
+	count, err := s.SceneIncrementO(context.Background(), "1234")
+
+	require.NoError(t, err)
+	require.Equal(t, 4, count)
+	require.Contains(t, doer.body, `sceneIncrementO(id: $id)`)
+}
+
 func TestSceneUpdate(t *testing.T) {
 	doer := &mockEndpoint{
 		t: t,
@@ -165,7 +238,7 @@ func TestSceneUpdate(t *testing.T) {
 
 	scene, err := s.SceneUpdate(context.Background(), SceneUpdate{
 		ID:     graphql.ID("1"),
-		TagIDs: []graphql.ID{graphql.ID("tag1")},
+		TagIDs: &[]graphql.ID{graphql.ID("tag1")},
 	})
 
 	require.NoError(t, err)
@@ -173,3 +246,130 @@ func TestSceneUpdate(t *testing.T) {
 	require.Equal(t, []Tag{{ID: "tag1", Name: "Foo"}}, scene.Tags)
 	require.True(t, doer.called)
 }
+
+func TestNewSceneUpdate(t *testing.T) {
+	old := Scene{
+		ID:         "1",
+		Title:      "Scene",
+		Rating:     20,
+		Studio:     Studio{ID: "s1"},
+		Tags:       []Tag{{ID: "a"}},
+		Performers: []Performer{{ID: "p1"}},
+		Movies:     []SceneMovie{{Movie: Movie{ID: "m1"}, SceneIndex: 1}},
+	}
+	updated := old
+	updated.Title = "Renamed"
+	updated.Organized = true
+	updated.Studio = Studio{ID: "s2"}
+	updated.URLs = []string{"https://example.com"}
+	updated.Movies = []SceneMovie{{Movie: Movie{ID: "m1"}, SceneIndex: 2}}
+	updated.Galleries = []SceneGallery{{ID: "g1"}}
+
+	update := NewSceneUpdate(old, updated)
+	studioID := graphql.ID("s2")
+	require.Equal(t, SceneUpdate{
+		ID:         graphql.ID("1"),
+		Title:      ptr("Renamed"),
+		URLs:       &[]string{"https://example.com"},
+		Organized:  ptr(true),
+		StudioID:   &studioID,
+		GalleryIDs: &[]graphql.ID{"g1"},
+		Movies:     &[]SceneMovieInput{{MovieID: "m1", SceneIndex: ptr(2)}},
+	}, update)
+
+	require.Panics(t, func() { NewSceneUpdate(old, Scene{ID: "2"}) })
+
+	// Removing every tag must still send an empty list rather than omitting the field.
+	untagged := old
+	untagged.Tags = nil
+	body, err := json.Marshal(NewSceneUpdate(old, untagged))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"id":"1","tag_ids":[]}`, string(body))
+
+	// As must removing every URL, gallery, performer and movie.
+	cleared := updated
+	cleared.URLs = nil
+	cleared.Galleries = nil
+	cleared.Performers = nil
+	cleared.Movies = nil
+	body, err = json.Marshal(NewSceneUpdate(updated, cleared))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"id":"1","urls":[],"gallery_ids":[],"performer_ids":[],"movies":[]}`, string(body))
+}
+
+func TestBulkSceneUpdate(t *testing.T) {
+	doer := &captureEndpoint{
+		t:        t,
+		response: `{"data": {"bulkSceneUpdate": [{"id": "1", "tags": [{"id": "tag1", "name": "Foo"}]}, {"id": "2"}]}}`,
+	}
+	client := graphql.NewClient("https://example.com/graph", doer)
+	s := stash{client}
+
+	update := NewBulkSceneUpdate([]string{"1", "2"})
+	update.TagIDs = &BulkUpdateIDs{IDs: []graphql.ID{"tag1"}, Mode: BulkUpdateIDModeAdd}
+	scenes, err := s.BulkSceneUpdate(context.Background(), update)
+
+	require.NoError(t, err)
+	require.Len(t, scenes, 2)
+	require.Equal(t, []Tag{{ID: "tag1", Name: "Foo"}}, scenes[0].Tags)
+	require.Contains(t, doer.body, `$input:BulkSceneUpdateInput!`)
+	require.Contains(t, doer.body, `"ids":["1","2"]`)
+	require.Contains(t, doer.body, `"tag_ids":{"ids":["tag1"],"mode":"ADD"}`)
+}
+
+func TestScenesDestroy(t *testing.T) {
+	doer := &captureEndpoint{
+		t:        t,
+		response: `{"data": {"scenesDestroy": true}}`,
+	}
+	client := graphql.NewClient("https://example.com/graph", doer)
+	s := stash{client}
+
+	ok, err := s.ScenesDestroy(context.Background(), []string{"1", "2"})
+
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Contains(t, doer.body, `$ids:[ID!]!`)
+	require.Contains(t, doer.body, `"ids":["1","2"]`)
+}
+
+func TestFindDuplicateScenes(t *testing.T) {
+	doer := &captureEndpoint{
+		t: t,
+		response: `{"data": {"findDuplicateScenes": [[
+			{"id": "1", "files": [{"path": "/a.mp4", "width": 1920, "height": 1080, "video_codec": "h264", "bit_rate": 8000000}]},
+			{"id": "2", "files": [{"path": "/b.mp4", "width": 1280, "height": 720}]}
+		]]}}`,
+	}
+	client := graphql.NewClient("https://example.com/graph", doer)
+	s := stash{client}
+
+	groups, err := s.FindDuplicateScenes(context.Background(), 4, -1)
+
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	require.Len(t, groups[0], 2)
+	require.Equal(t, VideoFile{Path: "/a.mp4", Width: 1920, Height: 1080, VideoCodec: "h264", BitRate: 8000000}, groups[0][0].Files[0])
+	require.Contains(t, doer.body, `$distance:Int!$duration_diff:Float!`)
+	require.Contains(t, doer.body, `findDuplicateScenes(distance: $distance, duration_diff: $duration_diff)`)
+	require.Contains(t, doer.body, `"duration_diff":-1`)
+}
+
+func TestSceneMerge(t *testing.T) {
+	doer := &captureEndpoint{
+		t:        t,
+		response: `{"data": {"sceneMerge": {"id": "1"}}}`,
+	}
+	client := graphql.NewClient("https://example.com/graph", doer)
+	s := stash{client}
+
+	scene, err := s.SceneMerge(context.Background(), SceneMerge{
+		Source:      []graphql.ID{"2", "3"},
+		Destination: "1",
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, "1", scene.ID)
+	require.Contains(t, doer.body, `$input:SceneMergeInput!`)
+	require.Contains(t, doer.body, `"input":{"source":["2","3"],"destination":"1"}`)
+}
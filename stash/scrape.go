@@ -0,0 +1,154 @@
+package stash
+
+import (
+	"context"
+
+	"github.com/hasura/go-graphql-client"
+)
+
+type ScrapeContentType string
+
+const (
+	ScrapeContentTypeGallery   ScrapeContentType = "GALLERY"
+	ScrapeContentTypeMovie     ScrapeContentType = "MOVIE"
+	ScrapeContentTypePerformer ScrapeContentType = "PERFORMER"
+	ScrapeContentTypeScene     ScrapeContentType = "SCENE"
+)
+
+// Scraper is a scraper configured in stash.  Scene and Gallery are nil if the scraper cannot scrape that content.
+type Scraper struct {
+	ID      string       `graphql:"id"`
+	Name    string       `graphql:"name"`
+	Scene   *ScraperSpec `graphql:"scene"`
+	Gallery *ScraperSpec `graphql:"gallery"`
+}
+
+// ScraperSpec lists the URLs a scraper can scrape and the kinds of scrape it supports, such as NAME or FRAGMENT.
+type ScraperSpec struct {
+	URLs             []string `graphql:"urls"`
+	SupportedScrapes []string `graphql:"supported_scrapes"`
+}
+
+// ScrapedScene is scene metadata found by a scraper.  Fields are empty when the scraper found no value.
+type ScrapedScene struct {
+	Title      string             `graphql:"title"`
+	Code       string             `graphql:"code"`
+	Details    string             `graphql:"details"`
+	Director   string             `graphql:"director"`
+	URLs       []string           `graphql:"urls"`
+	Date       string             `graphql:"date"`
+	Studio     *ScrapedStudio     `graphql:"studio"`
+	Tags       []ScrapedTag       `graphql:"tags"`
+	Performers []ScrapedPerformer `graphql:"performers"`
+}
+
+// ScrapedGallery is gallery metadata found by a scraper.  Fields are empty when the scraper found no value.
+type ScrapedGallery struct {
+	Title      string             `graphql:"title"`
+	Details    string             `graphql:"details"`
+	URL        string             `graphql:"url"`
+	Date       string             `graphql:"date"`
+	Studio     *ScrapedStudio     `graphql:"studio"`
+	Tags       []ScrapedTag       `graphql:"tags"`
+	Performers []ScrapedPerformer `graphql:"performers"`
+}
+
+// ScrapedStudio is a studio found by a scraper.  StoredID is the ID of the matching studio in stash, and is empty if
+// there is none.
+type ScrapedStudio struct {
+	StoredID string `graphql:"stored_id"`
+	Name     string `graphql:"name"`
+	URL      string `graphql:"url"`
+}
+
+// ScrapedTag is a tag found by a scraper.  StoredID is the ID of the matching tag in stash, and is empty if there is
+// none.
+type ScrapedTag struct {
+	StoredID string `graphql:"stored_id"`
+	Name     string `graphql:"name"`
+}
+
+// ScrapedPerformer is a performer found by a scraper.  StoredID is the ID of the matching performer in stash, and is
+// empty if there is none.
+type ScrapedPerformer struct {
+	StoredID       string `graphql:"stored_id"`
+	Name           string `graphql:"name"`
+	Disambiguation string `graphql:"disambiguation"`
+	URL            string `graphql:"url"`
+}
+
+func (ScraperSource) GetGraphQLType() string {
+	return "ScraperSourceInput"
+}
+
+// ScrapeSingleScene selects the scene to scrape, either by search Query or by the ID of a scene in stash.
+type ScrapeSingleScene struct {
+	Query   *string     `json:"query,omitempty"`
+	SceneID *graphql.ID `json:"scene_id,omitempty"`
+}
+
+func (ScrapeSingleScene) GetGraphQLType() string {
+	return "ScrapeSingleSceneInput"
+}
+
+// ScrapeSingleGallery selects the gallery to scrape, either by search Query or by the ID of a gallery in stash.
+type ScrapeSingleGallery struct {
+	Query     *string     `json:"query,omitempty"`
+	GalleryID *graphql.ID `json:"gallery_id,omitempty"`
+}
+
+func (ScrapeSingleGallery) GetGraphQLType() string {
+	return "ScrapeSingleGalleryInput"
+}
+
+// ListScrapers returns the scrapers that can scrape any of types.
+func (s *stash) ListScrapers(ctx context.Context, types []ScrapeContentType) ([]Scraper, error) {
+	var q struct {
+		ListScrapers []Scraper `graphql:"listScrapers(types: $types)"`
+	}
+	err := s.client.Query(ctx, &q, map[string]any{"types": types})
+	return q.ListScrapers, err
+}
+
+// ScrapeSingleScene scrapes a scene with source, returning each candidate found.
+func (s *stash) ScrapeSingleScene(ctx context.Context, source ScraperSource, input ScrapeSingleScene) ([]ScrapedScene, error) {
+	var q struct {
+		ScrapeSingleScene []ScrapedScene `graphql:"scrapeSingleScene(source: $source, input: $input)"`
+	}
+	err := s.client.Query(ctx, &q, map[string]any{"source": source, "input": input})
+	return q.ScrapeSingleScene, err
+}
+
+// ScrapeSingleGallery scrapes a gallery with source, returning each candidate found.
+func (s *stash) ScrapeSingleGallery(ctx context.Context, source ScraperSource, input ScrapeSingleGallery) ([]ScrapedGallery, error) {
+	var q struct {
+		ScrapeSingleGallery []ScrapedGallery `graphql:"scrapeSingleGallery(source: $source, input: $input)"`
+	}
+	err := s.client.Query(ctx, &q, map[string]any{"source": source, "input": input})
+	return q.ScrapeSingleGallery, err
+}
+
+// ScrapeSceneURL scrapes a scene from url with whichever scraper supports it.  False is returned if nothing was found.
+func (s *stash) ScrapeSceneURL(ctx context.Context, url string) (ScrapedScene, bool, error) {
+	var q struct {
+		ScrapeSceneURL *ScrapedScene `graphql:"scrapeSceneURL(url: $url)"`
+	}
+	err := s.client.Query(ctx, &q, map[string]any{"url": url})
+	if err != nil || q.ScrapeSceneURL == nil {
+		return ScrapedScene{}, false, err
+	}
+	return *q.ScrapeSceneURL, true, nil
+}
+
+// ScrapeGalleryURL scrapes a gallery from url with whichever scraper supports it.  False is returned if nothing was
+// found.
+func (s *stash) ScrapeGalleryURL(ctx context.Context, url string) (ScrapedGallery, bool, error) {
+	var q struct {
+		ScrapeGalleryURL *ScrapedGallery `graphql:"scrapeGalleryURL(url: $url)"`
+	}
+	err := s.client.Query(ctx, &q, map[string]any{"url": url})
+	if err != nil || q.ScrapeGalleryURL == nil {
+		return ScrapedGallery{}, false, err
+	}
+	return *q.ScrapeGalleryURL, true, nil
+}
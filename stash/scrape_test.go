@@ -0,0 +1,78 @@
+package stash
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hasura/go-graphql-client"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListScrapers(t *testing.T) {
+	doer := &captureEndpoint{
+		t:        t,
+		response: `{"data": {"listScrapers": [{"id": "example", "name": "Example", "scene": {"urls": ["example.com"], "supported_scrapes": ["URL", "FRAGMENT"]}, "gallery": null}]}}`,
+	}
+	client := graphql.NewClient("https://example.com/graph", doer)
+	s := stash{client}
+
+	scrapers, err := s.ListScrapers(context.Background(), []ScrapeContentType{ScrapeContentTypeScene})
+
+	require.NoError(t, err)
+	require.Contains(t, doer.body, `$types:[ScrapeContentType!]!`)
+	require.Contains(t, doer.body, `"variables":{"types":["SCENE"]}`)
+	require.Equal(t, []Scraper{{
+		ID:    "example",
+		Name:  "Example",
+		Scene: &ScraperSpec{URLs: []string{"example.com"}, SupportedScrapes: []string{"URL", "FRAGMENT"}},
+	}}, scrapers)
+}
+
+func TestScrapeSingleScene(t *testing.T) {
+	doer := &captureEndpoint{
+		t: t,
+		response: `{"data": {"scrapeSingleScene": [{
+			"title": "Sunset",
+			"urls": ["https://example.com/sunset"],
+			"date": "2024-05-01",
+			"studio": {"stored_id": "4", "name": "Example Studio", "url": null},
+			"tags": [{"stored_id": null, "name": "Beach"}],
+			"performers": [{"stored_id": "7", "name": "Jane", "disambiguation": null, "url": null}]
+		}]}}`,
+	}
+	client := graphql.NewClient("https://example.com/graph", doer)
+	s := stash{client}
+
+	scraperID := "example"
+	id := graphql.ID("1")
+	scenes, err := s.ScrapeSingleScene(context.Background(), ScraperSource{ScraperID: &scraperID}, ScrapeSingleScene{SceneID: &id})
+
+	require.NoError(t, err)
+	require.Contains(t, doer.body, `$source:ScraperSourceInput!`)
+	require.Contains(t, doer.body, `$input:ScrapeSingleSceneInput!`)
+	require.Contains(t, doer.body, `"variables":{"input":{"scene_id":"1"},"source":{"scraper_id":"example"}}`)
+	require.Equal(t, []ScrapedScene{{
+		Title:      "Sunset",
+		URLs:       []string{"https://example.com/sunset"},
+		Date:       "2024-05-01",
+		Studio:     &ScrapedStudio{StoredID: "4", Name: "Example Studio"},
+		Tags:       []ScrapedTag{{Name: "Beach"}},
+		Performers: []ScrapedPerformer{{StoredID: "7", Name: "Jane"}},
+	}}, scenes)
+}
+
+func TestScrapeGalleryURL(t *testing.T) {
+	doer := &captureEndpoint{
+		t:        t,
+		response: `{"data": {"scrapeGalleryURL": null}}`,
+	}
+	client := graphql.NewClient("https://example.com/graph", doer)
+	s := stash{client}
+
+	_, ok, err := s.ScrapeGalleryURL(context.Background(), "https://example.com/gallery")
+
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Contains(t, doer.body, `scrapeGalleryURL(url: $url)`)
+	require.Contains(t, doer.body, `"variables":{"url":"https://example.com/gallery"}`)
+}
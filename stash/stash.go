@@ -10,20 +10,72 @@ type Stash interface {
 	Scenes(context.Context, FindFilter, SceneFilter) ([]Scene, int, error)
 	DeleteScene(context.Context, string) (bool, error)
 	SceneUpdate(context.Context, SceneUpdate) (Scene, error)
+	BulkSceneUpdate(context.Context, BulkSceneUpdate) ([]Scene, error)
+	ScenesDestroy(context.Context, []string) (bool, error)
+	SceneMerge(context.Context, SceneMerge) (Scene, error)
+	FindDuplicateScenes(context.Context, int, float64) ([][]Scene, error)
+	RecordPlay(context.Context, string) (int, error)
+	SceneSaveActivity(context.Context, string, *float64, *float64) (bool, error)
+	SceneStreams(context.Context, string) ([]SceneStreamEndpoint, error)
+	SceneIncrementO(context.Context, string) (int, error)
+	SceneDecrementO(context.Context, string) (int, error)
+	SceneResetO(context.Context, string) (int, error)
+	SceneMarkers(context.Context, FindFilter, SceneMarkerFilter) ([]SceneMarker, int, error)
 
 	Galleries(context.Context, FindFilter, GalleryFilter) ([]Gallery, int, error)
 	GalleryDelete(context.Context, string) (bool, error)
 	GalleryUpdate(context.Context, GalleryUpdate) (Gallery, error)
+	BulkGalleryUpdate(context.Context, BulkGalleryUpdate) ([]Gallery, error)
+	GalleriesDestroy(context.Context, []string) (bool, error)
 
+	Images(context.Context, FindFilter, ImageFilter) ([]Image, int, error)
+	ImageDelete(context.Context, string) (bool, error)
+	ImageUpdate(context.Context, ImageUpdate) (Image, error)
+	ImageIncrementO(context.Context, string) (int, error)
+	ImageDecrementO(context.Context, string) (int, error)
+	ImageResetO(context.Context, string) (int, error)
+
+	Performers(context.Context, FindFilter, PerformerFilter) ([]Performer, int, error)
 	PerformersAll(context.Context) ([]PerformerSummary, error)
 	PerformerCreate(context.Context, PerformerCreate) (Performer, error)
+	PerformerUpdate(context.Context, PerformerUpdate) (Performer, error)
 	PerformerGet(context.Context, string) (Performer, error)
+	Studios(context.Context, FindFilter, StudioFilter) ([]StudioDetail, int, error)
 	StudiosAll(context.Context) ([]Studio, error)
+	StudioCreate(context.Context, StudioCreate) (Studio, error)
+
+	Movies(context.Context, FindFilter, MovieFilter) ([]MovieDetail, int, error)
+	MovieCreate(context.Context, MovieCreate) (MovieDetail, error)
+	MovieUpdate(context.Context, MovieUpdate) (MovieDetail, error)
 
 	TagGet(context.Context, string) (Tag, error)
 	TagCreate(context.Context, TagCreate) (Tag, error)
 	TagFindByName(context.Context, string) (Tag, error)
 	TagsAll(context.Context) ([]Tag, error)
+	Tags(context.Context, FindFilter, TagFilter) ([]TagDetail, int, error)
+	TagUpdate(context.Context, TagUpdate) (TagDetail, error)
+	TagsMerge(context.Context, TagsMerge) (TagDetail, error)
+	TagDelete(context.Context, string) (bool, error)
+
+	MetadataScan(context.Context, ScanMetadata) (string, error)
+	MetadataGenerate(context.Context, GenerateMetadata) (string, error)
+	MetadataAutoTag(context.Context, AutoTagMetadata) (string, error)
+	MetadataIdentify(context.Context, IdentifyMetadata) (string, error)
+	MetadataClean(context.Context, CleanMetadata) (string, error)
+	JobQueue(context.Context) ([]Job, error)
+	FindJob(context.Context, string) (Job, bool, error)
+	StopJob(context.Context, string) (bool, error)
+
+	SavedFilters(context.Context, FilterMode) ([]SavedFilter, error)
+	DefaultFilter(context.Context, FilterMode) (SavedFilter, bool, error)
+	SaveFilter(context.Context, SaveFilter) (SavedFilter, error)
+	DestroySavedFilter(context.Context, string) (bool, error)
+
+	ListScrapers(context.Context, []ScrapeContentType) ([]Scraper, error)
+	ScrapeSingleScene(context.Context, ScraperSource, ScrapeSingleScene) ([]ScrapedScene, error)
+	ScrapeSingleGallery(context.Context, ScraperSource, ScrapeSingleGallery) ([]ScrapedGallery, error)
+	ScrapeSceneURL(context.Context, string) (ScrapedScene, bool, error)
+	ScrapeGalleryURL(context.Context, string) (ScrapedGallery, bool, error)
 }
 
 func New(client *graphql.Client) Stash {
@@ -53,9 +105,40 @@ type File struct {
 }
 
 type VideoFile struct {
-	Path     string  `graphql:"path"`
-	Duration float64 `graphql:"duration"`
-	Size     int64   `graphql:"size"`
+	Path       string  `graphql:"path"`
+	Duration   float64 `graphql:"duration"`
+	Size       int64   `graphql:"size"`
+	Width      int     `graphql:"width"`
+	Height     int     `graphql:"height"`
+	VideoCodec string  `graphql:"video_codec"`
+	AudioCodec string  `graphql:"audio_codec"`
+	FrameRate  float64 `graphql:"frame_rate"`
+	BitRate    int64   `graphql:"bit_rate"`
+}
+
+// BulkUpdateIDMode determines how the IDs of a BulkUpdateIDs are applied to each entity in a bulk update.
+type BulkUpdateIDMode string
+
+const (
+	BulkUpdateIDModeSet    BulkUpdateIDMode = "SET"
+	BulkUpdateIDModeAdd    BulkUpdateIDMode = "ADD"
+	BulkUpdateIDModeRemove BulkUpdateIDMode = "REMOVE"
+)
+
+// BulkUpdateIDs is a list of related entity IDs to be set on, added to or removed from each entity in a bulk update.
+type BulkUpdateIDs struct {
+	IDs  []graphql.ID     `json:"ids"`
+	Mode BulkUpdateIDMode `json:"mode"`
+}
+
+// GraphQLIDs converts IDs to the graphql.ID type used in inputs.  An empty list is converted to an empty list rather than
+// nil, so that it still clears a list it is set on.
+func GraphQLIDs(ids []string) []graphql.ID {
+	gids := make([]graphql.ID, len(ids))
+	for i, id := range ids {
+		gids[i] = graphql.ID(id)
+	}
+	return gids
 }
 
 func tagListsEqual(a, b []Tag) bool {
@@ -83,6 +166,31 @@ func tagListsEqual(a, b []Tag) bool {
 	return true
 }
 
+func galleryListsEqual(a, b []SceneGallery) bool {
+	set1 := make(map[string]struct{})
+	set2 := make(map[string]struct{})
+
+	for _, item := range a {
+		set1[item.ID] = struct{}{}
+	}
+
+	for _, item := range b {
+		set2[item.ID] = struct{}{}
+	}
+
+	if len(set1) != len(set2) {
+		return false
+	}
+
+	for id := range set1 {
+		if _, exists := set2[id]; !exists {
+			return false
+		}
+	}
+
+	return true
+}
+
 func performerListsEqual(a, b []Performer) bool {
 	set1 := make(map[string]struct{})
 	set2 := make(map[string]struct{})
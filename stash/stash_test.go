@@ -10,6 +10,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
 	"github.com/vektah/gqlparser/v2/parser"
 	"github.com/vektah/gqlparser/v2/validator"
 )
@@ -32,6 +33,21 @@ func (m *mockEndpoint) Do(r *http.Request) (*http.Response, error) {
 	require.NoError(m.t, err)
 	json.Unmarshal(body, &g)
 
+	for _, validationErr := range validateQuery(m.t, g.Query) {
+		fmt.Printf("Validation error: %v\n", validationErr)
+	}
+
+	rw := httptest.NewRecorder()
+	rw.WriteHeader(http.StatusOK)
+	rw.Header().Set("Content-Type", "application/json")
+	rw.Write([]byte(m.response))
+
+	return rw.Result(), nil
+}
+
+// validateQuery validates query against the bundled schema.
+func validateQuery(t *testing.T, query string) gqlerror.List {
+	t.Helper()
 	schema, err := validator.LoadSchema(
 		validator.Prelude,
 		&ast.Source{
@@ -39,25 +55,23 @@ func (m *mockEndpoint) Do(r *http.Request) (*http.Response, error) {
 			Input: schemaStr,
 		},
 	)
-	require.NoError(m.t, err)
+	require.NoError(t, err)
 
 	doc, err := parser.ParseQuery(&ast.Source{
 		Name:  "TestQuery",
-		Input: g.Query,
+		Input: query,
 	})
-	require.NoError(m.t, err)
-
-	validationErrs := validator.Validate(schema, doc)
-	if len(validationErrs) > 0 {
-		for _, validationErr := range validationErrs {
-			fmt.Printf("Validation error: %v\n", validationErr)
-		}
-	}
+	require.NoError(t, err)
 
-	rw := httptest.NewRecorder()
-	rw.WriteHeader(http.StatusOK)
-	rw.Header().Set("Content-Type", "application/json")
-	rw.Write([]byte(m.response))
+	return validator.Validate(schema, doc)
+}
 
-	return rw.Result(), nil
+// requireValidQuery fails the test if the query of a request body isn't valid against the bundled schema.
+func requireValidQuery(t *testing.T, body string) {
+	t.Helper()
+	var g struct {
+		Query string
+	}
+	require.NoError(t, json.Unmarshal([]byte(body), &g))
+	require.Empty(t, validateQuery(t, g.Query))
 }
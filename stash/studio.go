@@ -2,6 +2,23 @@ package stash
 
 import "context"
 
+// StudioDetail is a studio as listed in the studios browser.  Unlike Studio, which is embedded in scenes and galleries,
+// it carries its position in the studio hierarchy along with content counts.  Counts include all sub-studios.
+type StudioDetail struct {
+	ID           string   `graphql:"id"`
+	Name         string   `graphql:"name"`
+	Aliases      []string `graphql:"aliases"`
+	URL          string   `graphql:"url"`
+	ParentStudio Studio   `graphql:"parent_studio"`
+	ChildStudios []Studio `graphql:"child_studios"`
+	SceneCount   int      `graphql:"scene_count(depth: -1)"`
+	GalleryCount int      `graphql:"gallery_count(depth: -1)"`
+}
+
+func (s StudioDetail) EntityID() string {
+	return s.ID
+}
+
 type allStudiosQuery struct {
 	Studios []Studio `graphql:"allStudios"`
 }
@@ -16,3 +33,41 @@ func (s stash) StudiosAll(ctx context.Context) ([]Studio, error) {
 	}
 	return resp.Studios, nil
 }
+
+type studiosQuery struct {
+	FindStudios struct {
+		Count   int            `graphql:"count"`
+		Studios []StudioDetail `graphql:"studios"`
+	} `graphql:"findStudios(filter: $filter, studio_filter: $studio_filter)"`
+}
+
+// Studios returns a page of studios matching the given filters along with the total count of matches.
+func (s stash) Studios(ctx context.Context, filter FindFilter, studioFilter StudioFilter) ([]StudioDetail, int, error) {
+	resp := studiosQuery{}
+	err := s.client.Query(ctx, &resp, map[string]any{
+		"filter":        filter,
+		"studio_filter": studioFilter,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return resp.FindStudios.Studios, resp.FindStudios.Count, nil
+}
+
+type StudioCreate struct {
+	Name string  `json:"name"`
+	URL  *string `json:"url,omitempty"`
+}
+
+func (StudioCreate) GetGraphQLType() string {
+	return "StudioCreateInput"
+}
+
+// StudioCreate creates a new studio and returns it with its ID.
+func (s stash) StudioCreate(ctx context.Context, input StudioCreate) (Studio, error) {
+	var m struct {
+		Studio Studio `graphql:"studioCreate(input: $input)"`
+	}
+	err := s.client.Mutate(ctx, &m, map[string]any{"input": input})
+	return m.Studio, err
+}
@@ -0,0 +1,52 @@
+package stash
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hasura/go-graphql-client"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindStudios(t *testing.T) {
+	doer := &captureEndpoint{
+		t: t,
+		response: `{"data": {"findStudios": {
+			"count": 1,
+			"studios": [
+				{
+					"id": "2",
+					"name": "Studio 2",
+					"aliases": [],
+					"url": "https://example.com",
+					"parent_studio": {"id": "1", "name": "Network"},
+					"child_studios": [{"id": "3", "name": "Studio 3"}],
+					"scene_count": 20,
+					"gallery_count": 4
+				}
+			]
+		}}}`,
+	}
+	client := graphql.NewClient("https://example.com/graph", doer)
+	s := stash{client}
+
+	studios, count, err := s.Studios(context.Background(), FindFilter{}, StudioFilter{
+		Parents: &MultiCriterion{Modifier: CriterionModifierIsNull},
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+	require.Equal(t, []StudioDetail{{
+		ID:           "2",
+		Name:         "Studio 2",
+		Aliases:      []string{},
+		URL:          "https://example.com",
+		ParentStudio: Studio{ID: "1", Name: "Network"},
+		ChildStudios: []Studio{{ID: "3", Name: "Studio 3"}},
+		SceneCount:   20,
+		GalleryCount: 4,
+	}}, studios)
+	require.Contains(t, doer.body, `findStudios(filter: $filter, studio_filter: $studio_filter)`)
+	require.Contains(t, doer.body, `scene_count(depth: -1)`)
+	require.Contains(t, doer.body, `"parents":{"value":null,"modifier":"IS_NULL"}`)
+}
@@ -0,0 +1,139 @@
+package stash
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/hasura/go-graphql-client"
+	"github.com/hasura/go-graphql-client/pkg/jsonutil"
+)
+
+type JobStatusUpdateType string
+
+const (
+	JobStatusUpdateAdd    JobStatusUpdateType = "ADD"
+	JobStatusUpdateRemove JobStatusUpdateType = "REMOVE"
+	JobStatusUpdateUpdate JobStatusUpdateType = "UPDATE"
+)
+
+// JobStatusUpdate is sent when a job is added to the job queue, changes, or is removed from the queue once done.
+type JobStatusUpdate struct {
+	Type JobStatusUpdateType `graphql:"type"`
+	Job  Job                 `graphql:"job"`
+}
+
+type LogLevel string
+
+const (
+	LogLevelTrace    LogLevel = "Trace"
+	LogLevelDebug    LogLevel = "Debug"
+	LogLevelInfo     LogLevel = "Info"
+	LogLevelProgress LogLevel = "Progress"
+	LogLevelWarning  LogLevel = "Warning"
+	LogLevelError    LogLevel = "Error"
+)
+
+type LogEntry struct {
+	Time    time.Time `graphql:"time"`
+	Level   LogLevel  `graphql:"level"`
+	Message string    `graphql:"message"`
+}
+
+// subscriberRetryDelay is how long a Subscriber waits before reconnecting.
+const subscriberRetryDelay = 2 * time.Second
+
+// Subscriber receives events from stash over a websocket.  Handlers are registered for each kind of event before
+// calling Run, and are called from their own goroutines as events arrive.
+type Subscriber struct {
+	client     *graphql.SubscriptionClient
+	retryDelay time.Duration
+}
+
+// NewSubscriber returns a Subscriber for the websocket GraphQL endpoint at url.  Header is sent when connecting, and
+// is typically used for the API key.
+func NewSubscriber(url string, header http.Header) *Subscriber {
+	client := graphql.NewSubscriptionClient(url).
+		WithProtocol(graphql.GraphQLWS).
+		WithWebSocketOptions(graphql.WebsocketOptions{HTTPHeader: header}).
+		WithRetryDelay(subscriberRetryDelay).
+		WithRetryTimeout(0).
+		WithExitWhenNoSubscription(false)
+	return &Subscriber{client: client, retryDelay: subscriberRetryDelay}
+}
+
+// Jobs calls handler for each change to the job queue.
+func (s *Subscriber) Jobs(handler func(JobStatusUpdate)) error {
+	var sub struct {
+		JobsSubscribe JobStatusUpdate `graphql:"jobsSubscribe"`
+	}
+	_, err := s.client.Subscribe(&sub, nil, func(data []byte, err error) error {
+		if err != nil {
+			return err
+		}
+		var event struct {
+			JobsSubscribe JobStatusUpdate `graphql:"jobsSubscribe"`
+		}
+		if err := jsonutil.UnmarshalGraphQL(data, &event); err != nil {
+			return err
+		}
+		handler(event.JobsSubscribe)
+		return nil
+	})
+	return err
+}
+
+// ScanComplete calls handler each time a scan finishes.
+func (s *Subscriber) ScanComplete(handler func()) error {
+	var sub struct {
+		ScanCompleteSubscribe bool `graphql:"scanCompleteSubscribe"`
+	}
+	_, err := s.client.Subscribe(&sub, nil, func(data []byte, err error) error {
+		if err != nil {
+			return err
+		}
+		handler()
+		return nil
+	})
+	return err
+}
+
+// Logs calls handler with each batch of entries written to the stash log.
+func (s *Subscriber) Logs(handler func([]LogEntry)) error {
+	var sub struct {
+		LoggingSubscribe []LogEntry `graphql:"loggingSubscribe"`
+	}
+	_, err := s.client.Subscribe(&sub, nil, func(data []byte, err error) error {
+		if err != nil {
+			return err
+		}
+		var event struct {
+			LoggingSubscribe []LogEntry `graphql:"loggingSubscribe"`
+		}
+		if err := jsonutil.UnmarshalGraphQL(data, &event); err != nil {
+			return err
+		}
+		handler(event.LoggingSubscribe)
+		return nil
+	})
+	return err
+}
+
+// Run connects to stash and delivers events until ctx is done.  The connection is retried whenever it is lost, such
+// as when stash restarts, and subscriptions are resumed once reconnected.
+func (s *Subscriber) Run(ctx context.Context) error {
+	for {
+		err := s.client.RunWithContext(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil && s.client.IsUnauthorized(err) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(s.retryDelay):
+		}
+	}
+}
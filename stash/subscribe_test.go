@@ -0,0 +1,119 @@
+package stash
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/stretchr/testify/require"
+)
+
+type wsMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// subscriptionServer is a minimal graphql-transport-ws server that answers each subscription with a single event.  The
+// first connection is dropped after its event, as happens when stash restarts.
+func subscriptionServer(t *testing.T, event string) (*httptest.Server, *atomic.Int32) {
+	var connections atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("ApiKey") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{Subprotocols: []string{"graphql-transport-ws"}})
+		if err != nil {
+			return
+		}
+		defer conn.CloseNow()
+		n := connections.Add(1)
+
+		ctx := r.Context()
+		for {
+			var msg wsMessage
+			if err := wsjson.Read(ctx, conn, &msg); err != nil {
+				return
+			}
+			switch msg.Type {
+			case "connection_init":
+				wsjson.Write(ctx, conn, wsMessage{Type: "connection_ack"})
+			case "subscribe":
+				wsjson.Write(ctx, conn, wsMessage{ID: msg.ID, Type: "next", Payload: json.RawMessage(event)})
+				if n == 1 {
+					return
+				}
+			}
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &connections
+}
+
+func newTestSubscriber(srv *httptest.Server) *Subscriber {
+	s := NewSubscriber("ws"+strings.TrimPrefix(srv.URL, "http"), http.Header{"ApiKey": {"secret"}})
+	s.retryDelay = 10 * time.Millisecond
+	s.client.WithRetryDelay(10 * time.Millisecond)
+	return s
+}
+
+func TestSubscriberReconnects(t *testing.T) {
+	srv, connections := subscriptionServer(t, `{"data": {"scanCompleteSubscribe": true}}`)
+	s := newTestSubscriber(srv)
+
+	events := make(chan struct{}, 2)
+	require.NoError(t, s.ScanComplete(func() { events <- struct{}{} }))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- s.Run(ctx) }()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-events:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for event %d", i+1)
+		}
+	}
+	require.GreaterOrEqual(t, connections.Load(), int32(2))
+
+	cancel()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscriber did not stop")
+	}
+}
+
+func TestSubscriberJobs(t *testing.T) {
+	srv, _ := subscriptionServer(t, `{"data": {"jobsSubscribe": {
+		"type": "UPDATE",
+		"job": {"id": "1", "status": "RUNNING", "description": "Scanning...", "progress": 0.5, "addTime": "2024-07-01T10:00:00Z"}
+	}}}`)
+	s := newTestSubscriber(srv)
+
+	updates := make(chan JobStatusUpdate, 2)
+	require.NoError(t, s.Jobs(func(u JobStatusUpdate) { updates <- u }))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Run(ctx)
+
+	select {
+	case u := <-updates:
+		require.Equal(t, JobStatusUpdateUpdate, u.Type)
+		require.Equal(t, "1", u.Job.ID)
+		require.Equal(t, 0.5, *u.Job.Progress)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for job update")
+	}
+}
@@ -19,6 +19,29 @@ func (t Tag) EntityID() string {
 	return t.ID
 }
 
+// TagDetail is a tag as listed in the tags browser.  Unlike Tag, which is embedded in other entities, it carries its
+// position in the tag hierarchy along with content counts.  Counts only include content tagged directly.
+type TagDetail struct {
+	ID             string   `graphql:"id"`
+	Name           string   `graphql:"name"`
+	Description    string   `graphql:"description"`
+	Aliases        []string `graphql:"aliases"`
+	SceneCount     int      `graphql:"scene_count"`
+	GalleryCount   int      `graphql:"gallery_count"`
+	PerformerCount int      `graphql:"performer_count"`
+	Parents        []Tag    `graphql:"parents"`
+	Children       []Tag    `graphql:"children"`
+}
+
+func (t TagDetail) EntityID() string {
+	return t.ID
+}
+
+// Tag returns the ID and Name of the tag as a Tag.
+func (t TagDetail) Tag() Tag {
+	return Tag{ID: t.ID, Name: t.Name}
+}
+
 type findTagQuery struct {
 	Tag Tag `graphql:"findTag(id: $id)"`
 }
@@ -40,18 +63,35 @@ type findTagsQuery struct {
 	} `graphql:"findTags(tag_filter: $tag_filter)"`
 }
 
-type tagFilter struct {
-	Name *StringCriterion `json:"name,omitempty"`
+type TagFilter struct {
+	AND            *TagFilter                  `json:"AND,omitempty"`
+	OR             *TagFilter                  `json:"OR,omitempty"`
+	NOT            *TagFilter                  `json:"NOT,omitempty"`
+	Name           *StringCriterion            `json:"name,omitempty"`
+	Aliases        *StringCriterion            `json:"aliases,omitempty"`
+	Description    *StringCriterion            `json:"description,omitempty"`
+	IsMissing      *string                     `json:"is_missing,omitempty"`
+	SceneCount     *IntCriterion               `json:"scene_count,omitempty"`
+	ImageCount     *IntCriterion               `json:"image_count,omitempty"`
+	GalleryCount   *IntCriterion               `json:"gallery_count,omitempty"`
+	PerformerCount *IntCriterion               `json:"performer_count,omitempty"`
+	MarkerCount    *IntCriterion               `json:"marker_count,omitempty"`
+	Parents        *HierarchicalMultiCriterion `json:"parents,omitempty"`
+	Children       *HierarchicalMultiCriterion `json:"children,omitempty"`
+	ParentCount    *IntCriterion               `json:"parent_count,omitempty"`
+	ChildCount     *IntCriterion               `json:"child_count,omitempty"`
+	CreatedAt      *TimestampCriterion         `json:"created_at,omitempty"`
+	UpdatedAt      *TimestampCriterion         `json:"updated_at,omitempty"`
 }
 
-func (tagFilter) GetGraphQLType() string {
+func (TagFilter) GetGraphQLType() string {
 	return "TagFilterType"
 }
 
 func (s stash) TagFindByName(ctx context.Context, name string) (Tag, error) {
 	resp := findTagsQuery{}
 	err := s.client.Query(ctx, &resp, map[string]any{
-		"tag_filter": tagFilter{
+		"tag_filter": TagFilter{
 			Name: &StringCriterion{
 				Value:    name,
 				Modifier: CriterionModifierEquals,
@@ -72,6 +112,26 @@ func (s stash) TagFindByName(ctx context.Context, name string) (Tag, error) {
 	}
 }
 
+type tagsQuery struct {
+	FindTags struct {
+		Count int         `graphql:"count"`
+		Tags  []TagDetail `graphql:"tags"`
+	} `graphql:"findTags(filter: $filter, tag_filter: $tag_filter)"`
+}
+
+// Tags returns a page of tags matching the given filters along with the total count of matches.
+func (s stash) Tags(ctx context.Context, filter FindFilter, tagFilter TagFilter) ([]TagDetail, int, error) {
+	resp := tagsQuery{}
+	err := s.client.Query(ctx, &resp, map[string]any{
+		"filter":     filter,
+		"tag_filter": tagFilter,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return resp.FindTags.Tags, resp.FindTags.Count, nil
+}
+
 type allTagsQuery struct {
 	Tags []Tag `graphql:"allTags"`
 }
@@ -102,3 +162,53 @@ func (s stash) TagCreate(ctx context.Context, tag TagCreate) (Tag, error) {
 	err := s.client.Mutate(ctx, &m, map[string]any{"name": graphql.String(tag.Name)})
 	return m.Tag, err
 }
+
+// TagUpdate is the input for updating a tag.  Aliases and ParentIDs are pointers so that they can be cleared by
+// pointing to an empty slice; a nil value leaves them unchanged.
+type TagUpdate struct {
+	ID          graphql.ID    `json:"id"`
+	Name        *string       `json:"name,omitempty"`
+	Description *string       `json:"description,omitempty"`
+	Aliases     *[]string     `json:"aliases,omitempty"`
+	ParentIDs   *[]graphql.ID `json:"parent_ids,omitempty"`
+}
+
+func (TagUpdate) GetGraphQLType() string {
+	return "TagUpdateInput"
+}
+
+func (s stash) TagUpdate(ctx context.Context, tag TagUpdate) (TagDetail, error) {
+	var m struct {
+		Tag TagDetail `graphql:"tagUpdate(input: $input)"`
+	}
+	err := s.client.Mutate(ctx, &m, map[string]any{"input": tag})
+	return m.Tag, err
+}
+
+type TagsMerge struct {
+	Source      []graphql.ID `json:"source"`
+	Destination graphql.ID   `json:"destination"`
+}
+
+func (TagsMerge) GetGraphQLType() string {
+	return "TagsMergeInput"
+}
+
+// TagsMerge merges the source tags into the destination tag.  Content tagged with a source tag is retagged with the
+// destination, source names are added as aliases of the destination, and the source tags are removed.
+func (s stash) TagsMerge(ctx context.Context, merge TagsMerge) (TagDetail, error) {
+	var m struct {
+		Tag TagDetail `graphql:"tagsMerge(input: $input)"`
+	}
+	err := s.client.Mutate(ctx, &m, map[string]any{"input": merge})
+	return m.Tag, err
+}
+
+// TagDelete removes a tag.  Content tagged with it is left in place with the tag removed.
+func (s stash) TagDelete(ctx context.Context, tagID string) (bool, error) {
+	var m struct {
+		TagDestroy bool `graphql:"tagDestroy(input: {id: $id})"`
+	}
+	err := s.client.Mutate(ctx, &m, map[string]any{"id": graphql.ID(tagID)})
+	return m.TagDestroy, err
+}
@@ -45,6 +45,88 @@ func TestTagFindByNameRequest(t *testing.T) {
 	require.NotContains(t, doer.body, `"filter"`)
 }
 
+func TestTags(t *testing.T) {
+	doer := &captureEndpoint{
+		t: t,
+		response: `{"data": {"findTags": {"count": 1, "tags": [{
+			"id": "1",
+			"name": "Foo",
+			"description": "",
+			"aliases": ["Fooo"],
+			"scene_count": 3,
+			"gallery_count": 2,
+			"performer_count": 1,
+			"parents": [],
+			"children": [{"id": "2", "name": "Bar"}]
+		}]}}}`,
+	}
+	client := graphql.NewClient("https://example.com/graph", doer)
+	s := stash{client}
+
+	tags, count, err := s.Tags(context.Background(), FindFilter{}, TagFilter{
+		ParentCount: &IntCriterion{Value: 0, Modifier: CriterionModifierEquals},
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+	require.Equal(t, []TagDetail{{
+		ID:             "1",
+		Name:           "Foo",
+		Aliases:        []string{"Fooo"},
+		SceneCount:     3,
+		GalleryCount:   2,
+		PerformerCount: 1,
+		Parents:        []Tag{},
+		Children:       []Tag{{ID: "2", Name: "Bar"}},
+	}}, tags)
+	require.Contains(t, doer.body, `findTags(filter: $filter, tag_filter: $tag_filter)`)
+	require.Contains(t, doer.body, `"parent_count":{"value":0,"modifier":"EQUALS"}`)
+}
+
+func TestTagUpdate(t *testing.T) {
+	doer := &captureEndpoint{
+		t:        t,
+		response: `{"data": {"tagUpdate": {"id": "1", "name": "Bar"}}}`,
+	}
+	client := graphql.NewClient("https://example.com/graph", doer)
+	s := stash{client}
+
+	name := "Bar"
+	tag, err := s.TagUpdate(context.Background(), TagUpdate{
+		ID:        graphql.ID("1"),
+		Name:      &name,
+		ParentIDs: &[]graphql.ID{},
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, "Bar", tag.Name)
+	require.Contains(t, doer.body, `tagUpdate(input: $input)`)
+	require.Contains(t, doer.body, `"input":{"id":"1","name":"Bar","parent_ids":[]}`)
+}
+
+func TestTagsMergeAndDelete(t *testing.T) {
+	doer := &captureEndpoint{
+		t:        t,
+		response: `{"data": {"tagsMerge": {"id": "2", "name": "Bar"}}}`,
+	}
+	client := graphql.NewClient("https://example.com/graph", doer)
+	s := stash{client}
+
+	tag, err := s.TagsMerge(context.Background(), TagsMerge{
+		Source:      []graphql.ID{"1"},
+		Destination: graphql.ID("2"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "2", tag.ID)
+	require.Contains(t, doer.body, `"input":{"source":["1"],"destination":"2"}`)
+
+	doer.response = `{"data": {"tagDestroy": true}}`
+	ok, err := s.TagDelete(context.Background(), "2")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Contains(t, doer.body, `tagDestroy(input: {id: $id})`)
+}
+
 type captureEndpoint struct {
 	t        *testing.T
 	response string
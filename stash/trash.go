@@ -0,0 +1,213 @@
+package stash
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	trashInfoExt    = ".trashinfo"
+	trashDateLayout = "2006-01-02T15:04:05"
+)
+
+// trash is a trash directory as described by the freedesktop.org trash specification.  Files are moved into its files
+// directory, along with an info file in its info directory that records where they were moved from, so that they can
+// be restored.  Only the home trash is used, so files can't be moved to the trash from other filesystems.
+type trash struct {
+	dir string
+	now func() time.Time
+}
+
+// homeTrash returns the trash in $XDG_DATA_HOME, which defaults to ~/.local/share.
+func homeTrash() (trash, error) {
+	data := os.Getenv("XDG_DATA_HOME")
+	if data == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return trash{}, err
+		}
+		data = filepath.Join(home, ".local", "share")
+	}
+	return trash{dir: filepath.Join(data, "Trash"), now: time.Now}, nil
+}
+
+// trashed is an item in the trash.
+type trashed struct {
+	// name is the name of the item in the files directory, which is also the name of its info file.
+	name    string
+	path    string
+	deleted time.Time
+	// modified is when the info file was written, and orders items deleted within the same second.
+	modified time.Time
+}
+
+func (t trash) files() string {
+	return filepath.Join(t.dir, "files")
+}
+
+func (t trash) info() string {
+	return filepath.Join(t.dir, "info")
+}
+
+// put moves the file or directory at path to the trash.
+func (t trash) put(path string) (trashed, error) {
+	if t.dir == "" {
+		return trashed{}, errors.New("no trash directory")
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return trashed{}, err
+	}
+	if _, err := os.Lstat(abs); err != nil {
+		return trashed{}, err
+	}
+	for _, dir := range []string{t.files(), t.info()} {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return trashed{}, err
+		}
+	}
+
+	item := trashed{path: abs, deleted: t.now()}
+	info, err := t.createInfo(&item)
+	if err != nil {
+		return trashed{}, err
+	}
+	if err := os.Rename(abs, filepath.Join(t.files(), item.name)); err != nil {
+		os.Remove(info)
+		return trashed{}, fmt.Errorf("unable to move %s to the trash: %w", path, err)
+	}
+	return item, nil
+}
+
+// createInfo writes the info file of item, naming item for the first name that isn't already used in the trash.  The
+// info file is created exclusively, as the specification requires, so that items deleted at once can't share a name.
+func (t trash) createInfo(item *trashed) (string, error) {
+	base := filepath.Base(item.path)
+	ext := filepath.Ext(base)
+	content := fmt.Sprintf("[Trash Info]\nPath=%s\nDeletionDate=%s\n",
+		(&url.URL{Path: item.path}).EscapedPath(), item.deleted.Format(trashDateLayout))
+
+	for n := 1; ; n++ {
+		item.name = base
+		if n > 1 {
+			item.name = strings.TrimSuffix(base, ext) + "." + strconv.Itoa(n) + ext
+		}
+		if _, err := os.Lstat(filepath.Join(t.files(), item.name)); err == nil {
+			continue
+		}
+		info := filepath.Join(t.info(), item.name+trashInfoExt)
+		f, err := os.OpenFile(info, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if _, err := f.WriteString(content); err != nil {
+			f.Close()
+			os.Remove(info)
+			return "", err
+		}
+		if err := f.Close(); err != nil {
+			os.Remove(info)
+			return "", err
+		}
+		return info, nil
+	}
+}
+
+// list returns the items in the trash.  Items without a readable info file are skipped.
+func (t trash) list() ([]trashed, error) {
+	if t.dir == "" {
+		return nil, errors.New("no trash directory")
+	}
+	entries, err := os.ReadDir(t.info())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var items []trashed
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), trashInfoExt)
+		if !ok || entry.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(t.info(), entry.Name()))
+		if err != nil {
+			continue
+		}
+		item, err := parseTrashInfo(data)
+		if err != nil {
+			continue
+		}
+		item.name = name
+		if info, err := entry.Info(); err == nil {
+			item.modified = info.ModTime()
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func parseTrashInfo(data []byte) (trashed, error) {
+	var item trashed
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	group := ""
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			group = line
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || group != "[Trash Info]" {
+			continue
+		}
+		switch key {
+		case "Path":
+			path, err := url.PathUnescape(value)
+			if err != nil {
+				return trashed{}, err
+			}
+			item.path = path
+		case "DeletionDate":
+			deleted, err := time.ParseInLocation(trashDateLayout, value, time.Local)
+			if err != nil {
+				return trashed{}, err
+			}
+			item.deleted = deleted
+		}
+	}
+	if item.path == "" {
+		return trashed{}, errors.New("trash info has no path")
+	}
+	return item, nil
+}
+
+// restore moves an item out of the trash to where it was deleted from.
+func (t trash) restore(item trashed) error {
+	if _, err := os.Lstat(item.path); err == nil {
+		return fmt.Errorf("unable to restore %s, as it already exists", item.path)
+	}
+	if err := os.MkdirAll(filepath.Dir(item.path), 0o755); err != nil {
+		return err
+	}
+	if err := os.Rename(filepath.Join(t.files(), item.name), item.path); err != nil {
+		return fmt.Errorf("unable to restore %s: %w", item.path, err)
+	}
+	return os.Remove(filepath.Join(t.info(), item.name+trashInfoExt))
+}
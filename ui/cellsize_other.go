@@ -0,0 +1,8 @@
+//go:build !unix
+
+package ui
+
+// TerminalCellSize returns DefaultCellSize, as the size of cells can't be queried on this platform.
+func TerminalCellSize(fd uintptr) CellSize {
+	return DefaultCellSize
+}
@@ -0,0 +1,15 @@
+//go:build unix
+
+package ui
+
+import "golang.org/x/sys/unix"
+
+// TerminalCellSize returns the size of the cells of the terminal open at fd, or DefaultCellSize if the terminal does
+// not report its size in pixels.
+func TerminalCellSize(fd uintptr) CellSize {
+	ws, err := unix.IoctlGetWinsize(int(fd), unix.TIOCGWINSZ)
+	if err != nil || ws.Col == 0 || ws.Row == 0 || ws.Xpixel == 0 || ws.Ypixel == 0 {
+		return DefaultCellSize
+	}
+	return CellSize{Width: int(ws.Xpixel / ws.Col), Height: int(ws.Ypixel / ws.Row)}
+}
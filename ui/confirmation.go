@@ -8,13 +8,28 @@ import (
 type ConfirmationOption struct {
 	Cmd  tea.Cmd
 	Text string
+	// CheckedCmd is used in place of Cmd when set, and is given the indexes of the items that are checked when the
+	// option is chosen.
+	CheckedCmd func(checked []int) tea.Cmd
 }
 
+// ConfirmationItem is an entry in the checklist of a confirmation, which can be checked or unchecked before an option
+// is chosen.
+type ConfirmationItem struct {
+	Text    string
+	Checked bool
+}
+
+// Confirmation asks for one of a set of options to be chosen.  When Items are given they are shown as a checklist,
+// which is navigated with up and down and toggled with space, while options are selected with left and right.
 type Confirmation struct {
+	Title   string
 	Message string
+	Items   []ConfirmationItem
 	Options []ConfirmationOption
 
 	selected int
+	cursor   int
 }
 
 var (
@@ -24,17 +39,27 @@ var (
 	ConfirmationSelectedStyle = ConfirmationOptionStyle.
 					Bold(true).
 					Foreground(lipgloss.Color("#FFFFFF"))
+
+	ConfirmationItemStyle = lipgloss.NewStyle()
+
+	ConfirmationCursorStyle = ConfirmationItemStyle.
+				Bold(true).
+				Foreground(lipgloss.Color("#FFFFFF"))
 )
 
 func (c Confirmation) Update(msg tea.Msg) (*Confirmation, tea.Cmd) {
+	if len(c.Items) > 0 {
+		return c.updateChecklist(msg)
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.Type {
 		case tea.KeyEnter, tea.KeySpace:
-			return &c, c.Options[c.selected].Cmd
+			return &c, c.choose(c.selected)
 
 		case tea.KeyEsc:
-			return &c, c.Options[0].Cmd
+			return &c, c.choose(0)
 
 		case tea.KeyLeft:
 			c.selected = max(0, c.selected-1)
@@ -58,7 +83,71 @@ func (c Confirmation) Update(msg tea.Msg) (*Confirmation, tea.Cmd) {
 	return &c, nil
 }
 
+func (c Confirmation) updateChecklist(msg tea.Msg) (*Confirmation, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyEnter:
+			return &c, c.choose(c.selected)
+
+		case tea.KeyEsc:
+			return &c, c.choose(0)
+
+		case tea.KeySpace:
+			c.Items = append([]ConfirmationItem(nil), c.Items...)
+			c.Items[c.cursor].Checked = !c.Items[c.cursor].Checked
+
+		case tea.KeyLeft:
+			c.selected = max(0, c.selected-1)
+
+		case tea.KeyRight:
+			c.selected = min(len(c.Options)-1, c.selected+1)
+
+		case tea.KeyUp:
+			c.cursor = max(0, c.cursor-1)
+
+		case tea.KeyDown:
+			c.cursor = min(len(c.Items)-1, c.cursor+1)
+		}
+		switch msg.String() {
+		case "z":
+			c.cursor = max(0, c.cursor-1)
+		case "x":
+			c.cursor = min(len(c.Items)-1, c.cursor+1)
+		}
+	}
+	return &c, nil
+}
+
+// choose returns the command of option i.
+func (c Confirmation) choose(i int) tea.Cmd {
+	option := c.Options[i]
+	if option.CheckedCmd == nil {
+		return option.Cmd
+	}
+	var checked []int
+	for j, item := range c.Items {
+		if item.Checked {
+			checked = append(checked, j)
+		}
+	}
+	return option.CheckedCmd(checked)
+}
+
 func (c Confirmation) View() string {
+	var items []string
+	for i, item := range c.Items {
+		check := "[ ] "
+		if item.Checked {
+			check = "[x] "
+		}
+		if i == c.cursor {
+			items = append(items, ConfirmationCursorStyle.Render("> "+check+item.Text))
+		} else {
+			items = append(items, ConfirmationItemStyle.Render("  "+check+item.Text))
+		}
+	}
+
 	var options []string
 	for i, o := range c.Options {
 		var option string
@@ -69,6 +158,14 @@ func (c Confirmation) View() string {
 		}
 		options = append(options, option)
 	}
+	if len(items) > 0 {
+		return lipgloss.JoinVertical(0,
+			c.Message,
+			lipgloss.JoinVertical(0, items...),
+			"",
+			lipgloss.JoinHorizontal(0, options...),
+		)
+	}
 	return lipgloss.JoinVertical(0,
 		c.Message,
 		lipgloss.JoinHorizontal(0, options...),
@@ -34,3 +34,36 @@ func TestConfirmationZXNavigationAndSpaceConfirm(t *testing.T) {
 	require.Equal(t, "delete", cmd())
 	require.True(t, confirmed)
 }
+
+func TestConfirmationChecklist(t *testing.T) {
+	var accepted []int
+	c := Confirmation{
+		Items: []ConfirmationItem{
+			{Text: "Title", Checked: true},
+			{Text: "Date", Checked: true},
+			{Text: "Studio"},
+		},
+		Options: []ConfirmationOption{
+			{Text: "Cancel", Cmd: func() tea.Msg { return "cancel" }},
+			{Text: "Apply", CheckedCmd: func(checked []int) tea.Cmd {
+				accepted = checked
+				return func() tea.Msg { return "apply" }
+			}},
+		},
+	}
+
+	// Space toggles the item under the cursor rather than confirming.
+	updated, cmd := c.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	require.Nil(t, cmd)
+	updated, cmd = updated.Update(tea.KeyMsg{Type: tea.KeySpace})
+	require.Nil(t, cmd)
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyDown})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeySpace})
+	require.True(t, c.Items[1].Checked)
+
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRight})
+	_, cmd = updated.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	require.Equal(t, "apply", cmd())
+	require.Equal(t, []int{0, 2}, accepted)
+	require.Contains(t, updated.View(), "[x] Studio")
+}
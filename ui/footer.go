@@ -8,6 +8,8 @@ import (
 
 type Footer struct {
 	Background lipgloss.Color
+	// Status describes a task running in the background, and is shown with the spinner until it is cleared.
+	Status string
 
 	loadingCount uint
 	spinner      spinner.Model
@@ -34,9 +36,12 @@ func (f Footer) Render(width int, loading bool) string {
 		Background(f.Background)
 
 	l := ""
-	if loading {
+	if loading || f.Status != "" {
 		l += f.spinner.View()
 	}
+	if f.Status != "" {
+		l += " " + f.Status
+	}
 
 	return style.
 		Padding(0, 1).
@@ -0,0 +1,148 @@
+package ui
+
+import (
+	"slices"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// FormField is a single field of a form.  Fields with Options can only take one of the values given, which are cycled
+// with left and right.
+type FormField struct {
+	Label   string
+	Value   string
+	Options []string
+}
+
+// Form edits a number of fields at once.  Fields are moved between with tab, up and down, and enter moves to the next
+// field or submits from the last one.  ctrl+s submits from any field and esc cancels.
+type Form struct {
+	Title  string
+	Fields []FormField
+	// Submit is called with the value of each field when the form is submitted.
+	Submit func(values []string) tea.Cmd
+	Cancel tea.Cmd
+
+	inputs []textinput.Model
+	focus  int
+}
+
+var (
+	FormLabelStyle = lipgloss.NewStyle().
+			Width(16).
+			Foreground(lipgloss.Color("#888888"))
+
+	FormFocusedLabelStyle = FormLabelStyle.
+				Bold(true).
+				Foreground(lipgloss.Color("#FFFFFF"))
+
+	FormHelpStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#888888"))
+)
+
+// NewForm returns a form for fields, with the first field focused.
+func NewForm(title string, fields []FormField, submit func([]string) tea.Cmd, cancel tea.Cmd) Form {
+	f := Form{
+		Title:  title,
+		Fields: fields,
+		Submit: submit,
+		Cancel: cancel,
+		inputs: make([]textinput.Model, len(fields)),
+	}
+	for i, field := range fields {
+		input := textinput.New()
+		input.Prompt = ""
+		input.Width = 40
+		input.SetValue(field.Value)
+		f.inputs[i] = input
+	}
+	f.focusField(0)
+	return f
+}
+
+// Values returns the current value of each field.
+func (f Form) Values() []string {
+	values := make([]string, len(f.inputs))
+	for i, input := range f.inputs {
+		values[i] = input.Value()
+	}
+	return values
+}
+
+func (f Form) Update(msg tea.Msg) (*Form, tea.Cmd) {
+	if len(f.inputs) == 0 {
+		return &f, nil
+	}
+	f.inputs = slices.Clone(f.inputs)
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "esc":
+			return &f, f.Cancel
+		case "ctrl+s":
+			return &f, f.Submit(f.Values())
+		case "enter":
+			if f.focus == len(f.inputs)-1 {
+				return &f, f.Submit(f.Values())
+			}
+			f.focusField(f.focus + 1)
+			return &f, nil
+		case "tab", "down":
+			f.focusField((f.focus + 1) % len(f.inputs))
+			return &f, nil
+		case "shift+tab", "up":
+			f.focusField((f.focus + len(f.inputs) - 1) % len(f.inputs))
+			return &f, nil
+		}
+
+		if options := f.Fields[f.focus].Options; len(options) > 0 {
+			switch msg.String() {
+			case "left":
+				f.cycleOption(-1)
+			case "right", " ":
+				f.cycleOption(1)
+			}
+			return &f, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	f.inputs[f.focus], cmd = f.inputs[f.focus].Update(msg)
+	return &f, cmd
+}
+
+func (f *Form) focusField(i int) {
+	f.inputs[f.focus].Blur()
+	f.focus = i
+	f.inputs[f.focus].Focus()
+}
+
+// cycleOption moves the focused field to the next or previous of its options.
+func (f *Form) cycleOption(delta int) {
+	options := f.Fields[f.focus].Options
+	i := slices.Index(options, f.inputs[f.focus].Value())
+	i = (i + delta + len(options)) % len(options)
+	f.inputs[f.focus].SetValue(options[i])
+}
+
+func (f Form) View() string {
+	rows := make([]string, len(f.inputs))
+	for i, input := range f.inputs {
+		label := FormLabelStyle.Render(f.Fields[i].Label)
+		value := input.View()
+		if i == f.focus {
+			label = FormFocusedLabelStyle.Render(f.Fields[i].Label)
+		}
+		if len(f.Fields[i].Options) > 0 {
+			value = "‹ " + input.Value() + " ›"
+		}
+		rows[i] = lipgloss.JoinHorizontal(0, label, value)
+	}
+	return lipgloss.JoinVertical(0,
+		lipgloss.JoinVertical(0, rows...),
+		"",
+		FormHelpStyle.Render("tab next · enter submit on last field · ctrl+s submit · esc cancel"),
+	)
+}
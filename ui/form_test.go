@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForm(t *testing.T) {
+	var submitted []string
+	f := NewForm("Performer", []FormField{
+		{Label: "Name", Value: "Jane"},
+		{Label: "Gender", Options: []string{"", "female", "male"}},
+		{Label: "Country"},
+	}, func(values []string) tea.Cmd {
+		submitted = values
+		return func() tea.Msg { return "submit" }
+	}, func() tea.Msg { return "cancel" })
+
+	updated, _ := f.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(" Doe")})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyTab})
+
+	// Fields with options cycle through them rather than taking text.
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	require.Equal(t, []string{"Jane Doe", "male", ""}, updated.Values())
+
+	updated, cmd := updated.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	require.Nil(t, cmd)
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("au")})
+	require.Contains(t, updated.View(), "‹ male ›")
+
+	_, cmd = updated.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	require.Equal(t, "submit", cmd())
+	require.Equal(t, []string{"Jane Doe", "male", "au"}, submitted)
+
+	_, cmd = updated.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	require.Equal(t, "cancel", cmd())
+}
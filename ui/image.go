@@ -0,0 +1,287 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"strings"
+)
+
+// Graphics is a protocol used to draw images in the terminal.
+type Graphics string
+
+const (
+	// GraphicsBlocks draws images with unicode half blocks in 24-bit colour, which works in most terminals at a low
+	// resolution.
+	GraphicsBlocks Graphics = "blocks"
+	GraphicsKitty  Graphics = "kitty"
+	GraphicsSixel  Graphics = "sixel"
+	GraphicsITerm  Graphics = "iterm"
+)
+
+// ParseGraphics returns the graphics protocol with the given name.
+func ParseGraphics(name string) (Graphics, error) {
+	switch g := Graphics(strings.ToLower(name)); g {
+	case GraphicsBlocks, GraphicsKitty, GraphicsSixel, GraphicsITerm:
+		return g, nil
+	}
+	return "", fmt.Errorf("unknown graphics '%s', expected one of kitty, sixel, iterm, blocks", name)
+}
+
+// DetectGraphics returns the best graphics protocol the terminal supports, judged from the environment variables that
+// terminals set to identify themselves.  Half blocks are used when no image protocol is known to be supported.
+func DetectGraphics(getenv func(string) string) Graphics {
+	term, program := getenv("TERM"), getenv("TERM_PROGRAM")
+	switch {
+	case getenv("KITTY_WINDOW_ID") != "", term == "xterm-kitty", term == "xterm-ghostty", program == "ghostty":
+		return GraphicsKitty
+	case program == "iTerm.app", getenv("LC_TERMINAL") == "iTerm2", program == "WezTerm", program == "mintty":
+		return GraphicsITerm
+	case strings.Contains(term, "sixel"), strings.HasPrefix(term, "foot"), strings.HasPrefix(term, "mlterm"),
+		strings.HasPrefix(term, "contour"), getenv("WT_SESSION") != "":
+		return GraphicsSixel
+	}
+	return GraphicsBlocks
+}
+
+// CellSize is the size in pixels of a terminal cell.
+type CellSize struct {
+	Width  int
+	Height int
+}
+
+// DefaultCellSize is used when the terminal does not report the size of its cells.
+var DefaultCellSize = CellSize{Width: 10, Height: 20}
+
+// ImageRenderer draws images in the terminal as lines of text that can be laid out with other views.  Images are
+// drawn one line at a time, so that a line redrawn by the program also redraws its part of the image.
+type ImageRenderer struct {
+	Graphics Graphics
+	Cell     CellSize
+}
+
+// kittyImageID is the ID of the first line of an image drawn with the kitty protocol.  Each line is a separate image,
+// replaced whenever it is redrawn.
+const kittyImageID = 7300
+
+// Render returns img scaled to fit within width by height cells, keeping its aspect ratio.  The result is always height
+// lines of width cells, with the image at the top left.
+func (r ImageRenderer) Render(img image.Image, width, height int) string {
+	if width <= 0 || height <= 0 {
+		return ""
+	}
+	cell := r.Cell
+	if cell.Width <= 0 || cell.Height <= 0 {
+		cell = DefaultCellSize
+	}
+	if r.Graphics == GraphicsBlocks || r.Graphics == "" {
+		// Each cell shows two pixels, one above the other.
+		cell = CellSize{Width: 1, Height: 2}
+	}
+	cols, rows := fitImage(img.Bounds().Size(), width, height, cell)
+	scaled := scaleImage(img, cols*cell.Width, rows*cell.Height)
+
+	lines := make([]string, height)
+	for y := range lines {
+		if y >= rows {
+			lines[y] = r.clearLine(y) + strings.Repeat(" ", width)
+			continue
+		}
+		band := scaled.SubImage(image.Rect(0, y*cell.Height, cols*cell.Width, (y+1)*cell.Height)).(*image.RGBA)
+		padding := strings.Repeat(" ", width-cols)
+		if r.Graphics == GraphicsBlocks || r.Graphics == "" {
+			lines[y] = halfBlocks(band) + padding
+			continue
+		}
+		// The cells are written first, since writing to a cell clears any image drawn in it.  The image is then drawn
+		// over them, with the cursor saved and restored as terminals differ in where they leave it.
+		lines[y] = fmt.Sprintf("%s\x1b[%dD\x1b7%s\x1b8\x1b[%dC%s",
+			strings.Repeat(" ", cols), cols, r.encodeLine(band, y, cols), cols, padding)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Clear returns a sequence that removes any image left on screen that is not cleared by writing over it.
+func (r ImageRenderer) Clear() string {
+	if r.Graphics == GraphicsKitty {
+		return "\x1b_Ga=d,d=A,q=2\x1b\\"
+	}
+	return ""
+}
+
+// clearLine returns a sequence that removes the image drawn on line y, if writing over it does not.
+func (r ImageRenderer) clearLine(y int) string {
+	if r.Graphics == GraphicsKitty {
+		return fmt.Sprintf("\x1b_Ga=d,d=I,i=%d,q=2\x1b\\", kittyImageID+y)
+	}
+	return ""
+}
+
+func (r ImageRenderer) encodeLine(band *image.RGBA, y, cols int) string {
+	switch r.Graphics {
+	case GraphicsKitty:
+		return r.clearLine(y) + kittyImage(band, kittyImageID+y, cols)
+	case GraphicsITerm:
+		return iTermImage(band, cols)
+	case GraphicsSixel:
+		return sixelImage(band)
+	}
+	return ""
+}
+
+// fitImage returns the number of columns and rows of cells that an image of size fits in, keeping its aspect ratio.
+func fitImage(size image.Point, width, height int, cell CellSize) (int, int) {
+	if size.X <= 0 || size.Y <= 0 {
+		return 1, 1
+	}
+	scale := math.Min(float64(width*cell.Width)/float64(size.X), float64(height*cell.Height)/float64(size.Y))
+	cols := int(float64(size.X)*scale/float64(cell.Width) + 0.5)
+	rows := int(float64(size.Y)*scale/float64(cell.Height) + 0.5)
+	return max(1, min(cols, width)), max(1, min(rows, height))
+}
+
+// scaleImage returns img resized to width by height pixels.  Each pixel is the average of the pixels it covers, so that
+// detail is not lost to aliasing when shrinking large screenshots.
+func scaleImage(img image.Image, width, height int) *image.RGBA {
+	src := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := src.Min.Y + y*src.Dy()/height
+		y1 := max(src.Min.Y+(y+1)*src.Dy()/height, y0+1)
+		for x := 0; x < width; x++ {
+			x0 := src.Min.X + x*src.Dx()/width
+			x1 := max(src.Min.X+(x+1)*src.Dx()/width, x0+1)
+			var r, g, b, n uint32
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, _ := img.At(sx, sy).RGBA()
+					r, g, b, n = r+cr, g+cg, b+cb, n+1
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{uint8(r / n >> 8), uint8(g / n >> 8), uint8(b / n >> 8), 0xff})
+		}
+	}
+	return dst
+}
+
+// halfBlocks draws a band two pixels high as a line of upper half blocks, with the top pixel as the foreground and the
+// bottom pixel as the background.
+func halfBlocks(band *image.RGBA) string {
+	var sb strings.Builder
+	b := band.Bounds()
+	for x := b.Min.X; x < b.Max.X; x++ {
+		top, bottom := band.RGBAAt(x, b.Min.Y), band.RGBAAt(x, b.Max.Y-1)
+		fmt.Fprintf(&sb, "\x1b[38;2;%d;%d;%d;48;2;%d;%d;%dm▀", top.R, top.G, top.B, bottom.R, bottom.G, bottom.B)
+	}
+	sb.WriteString("\x1b[0m")
+	return sb.String()
+}
+
+func encodePNG(img image.Image) string {
+	var buf bytes.Buffer
+	// Encoding an in-memory RGBA image cannot fail.
+	_ = png.Encode(&buf, img)
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+// kittyImage transmits band as a PNG and displays it over cols cells, without moving the cursor.  The data is sent in
+// chunks of 4096 bytes as the protocol requires.
+func kittyImage(band image.Image, id, cols int) string {
+	data := encodePNG(band)
+	var sb strings.Builder
+	for i := 0; i < len(data); i += 4096 {
+		chunk := data[i:min(i+4096, len(data))]
+		more := 0
+		if i+4096 < len(data) {
+			more = 1
+		}
+		if i == 0 {
+			fmt.Fprintf(&sb, "\x1b_Ga=T,f=100,i=%d,c=%d,r=1,C=1,q=2,m=%d;%s\x1b\\", id, cols, more, chunk)
+		} else {
+			fmt.Fprintf(&sb, "\x1b_Gm=%d;%s\x1b\\", more, chunk)
+		}
+	}
+	return sb.String()
+}
+
+// iTermImage displays band inline over cols cells with the iTerm2 protocol.
+func iTermImage(band image.Image, cols int) string {
+	data := encodePNG(band)
+	return fmt.Sprintf("\x1b]1337;File=inline=1;width=%d;height=1;preserveAspectRatio=0;size=%d:%s\a",
+		cols, base64.StdEncoding.DecodedLen(len(data)), data)
+}
+
+// sixelImage encodes band as sixel graphics, with colours reduced to a 6x6x6 cube.
+func sixelImage(band *image.RGBA) string {
+	b := band.Bounds()
+	w, h := b.Dx(), b.Dy()
+	colors := make([]int, w*h)
+	used := make([]bool, 216)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := band.RGBAAt(b.Min.X+x, b.Min.Y+y)
+			i := (int(c.R)*5+127)/255*36 + (int(c.G)*5+127)/255*6 + (int(c.B)*5+127)/255
+			colors[y*w+x] = i
+			used[i] = true
+		}
+	}
+
+	var sb strings.Builder
+	// P2=1 leaves pixels that are not set unchanged, rather than filling them with the background.
+	fmt.Fprintf(&sb, "\x1bP0;1;0q\"1;1;%d;%d", w, h)
+	for i, ok := range used {
+		if ok {
+			fmt.Fprintf(&sb, "#%d;2;%d;%d;%d", i, i/36*20, i/6%6*20, i%6*20)
+		}
+	}
+
+	// Each band of six rows is drawn once per colour in it, returning to the start of the band between colours.
+	for top := 0; top < h; top += 6 {
+		bits := make(map[int][]byte)
+		for dy := 0; dy < 6 && top+dy < h; dy++ {
+			for x := 0; x < w; x++ {
+				i := colors[(top+dy)*w+x]
+				if bits[i] == nil {
+					bits[i] = make([]byte, w)
+				}
+				bits[i][x] |= 1 << dy
+			}
+		}
+		first := true
+		for i := range used {
+			if bits[i] == nil {
+				continue
+			}
+			if !first {
+				sb.WriteByte('$')
+			}
+			first = false
+			fmt.Fprintf(&sb, "#%d", i)
+			writeSixelRuns(&sb, bits[i])
+		}
+		sb.WriteByte('-')
+	}
+	sb.WriteString("\x1b\\")
+	return sb.String()
+}
+
+// writeSixelRuns writes a row of sixels, compressing repeated sixels with run lengths.
+func writeSixelRuns(sb *strings.Builder, bits []byte) {
+	for x := 0; x < len(bits); {
+		n := 1
+		for x+n < len(bits) && bits[x+n] == bits[x] {
+			n++
+		}
+		c := byte('?' + bits[x])
+		if n > 3 {
+			fmt.Fprintf(sb, "!%d%c", n, c)
+		} else {
+			sb.WriteString(strings.Repeat(string(c), n))
+		}
+		x += n
+	}
+}
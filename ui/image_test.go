@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGraphics(t *testing.T) {
+	g, err := ParseGraphics("Kitty")
+	require.NoError(t, err)
+	require.Equal(t, GraphicsKitty, g)
+
+	_, err = ParseGraphics("ascii")
+	require.EqualError(t, err, "unknown graphics 'ascii', expected one of kitty, sixel, iterm, blocks")
+}
+
+func TestDetectGraphics(t *testing.T) {
+	tests := []struct {
+		env      map[string]string
+		expected Graphics
+	}{
+		{map[string]string{"TERM": "xterm-256color"}, GraphicsBlocks},
+		{map[string]string{"TERM": "xterm-kitty"}, GraphicsKitty},
+		{map[string]string{"KITTY_WINDOW_ID": "1", "TERM": "screen"}, GraphicsKitty},
+		{map[string]string{"TERM_PROGRAM": "iTerm.app"}, GraphicsITerm},
+		{map[string]string{"TERM": "foot"}, GraphicsSixel},
+		{map[string]string{"TERM": "xterm-sixel"}, GraphicsSixel},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.expected, DetectGraphics(func(key string) string { return test.env[key] }), test.env)
+	}
+}
+
+func testImage(width, height int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{uint8(x), uint8(y), 0x80, 0xff})
+		}
+	}
+	return img
+}
+
+func TestImageRendererBlocks(t *testing.T) {
+	r := ImageRenderer{Graphics: GraphicsBlocks}
+
+	// A wide image fills the width, and the lines below it are blank.
+	lines := strings.Split(r.Render(testImage(200, 100), 20, 10), "\n")
+	require.Len(t, lines, 10)
+	for _, line := range lines {
+		require.Equal(t, 20, ansi.StringWidth(line))
+	}
+	require.Equal(t, 20, strings.Count(lines[0], "▀"))
+	require.Equal(t, strings.Repeat(" ", 20), lines[9])
+
+	// A tall image fills the height, and is padded to the width.
+	lines = strings.Split(r.Render(testImage(100, 400), 20, 10), "\n")
+	require.Len(t, lines, 10)
+	require.Equal(t, 5, strings.Count(lines[9], "▀"))
+	require.Equal(t, 20, ansi.StringWidth(lines[9]))
+}
+
+func TestImageRendererProtocols(t *testing.T) {
+	img := testImage(64, 32)
+	cell := CellSize{Width: 8, Height: 16}
+
+	kitty := ImageRenderer{Graphics: GraphicsKitty, Cell: cell}.Render(img, 10, 4)
+	lines := strings.Split(kitty, "\n")
+	require.Len(t, lines, 4)
+	require.Contains(t, lines[0], "\x1b_Ga=T,f=100,i=7300,c=10,r=1")
+	require.Contains(t, lines[3], "\x1b_Ga=d,d=I,i=7303")
+	for _, line := range lines {
+		require.Equal(t, 10, ansi.StringWidth(line))
+	}
+
+	sixel := ImageRenderer{Graphics: GraphicsSixel, Cell: cell}.Render(img, 10, 4)
+	require.Contains(t, sixel, "\x1bP0;1;0q\"1;1;80;16")
+
+	iterm := ImageRenderer{Graphics: GraphicsITerm, Cell: cell}.Render(img, 10, 4)
+	require.Contains(t, iterm, "\x1b]1337;File=inline=1;width=10;height=1")
+}
@@ -25,12 +25,16 @@ type Column struct {
 type Row struct {
 	Values     []string
 	Background *lipgloss.Color
+
+	// Marked rows are rendered with the MarkedBackground of the table, unless Background is also set.
+	Marked bool
 }
 
 type Table struct {
 	Cols []Column
 
-	AltBackground lipgloss.Color
+	AltBackground    lipgloss.Color
+	MarkedBackground lipgloss.Color
 }
 
 func (t *Table) Render(maxWidth int, rows []Row) string {
@@ -42,6 +46,8 @@ func (t *Table) Render(maxWidth int, rows []Row) string {
 		rowStyle := lipgloss.NewStyle()
 		if row.Background != nil {
 			rowStyle = rowStyle.Background(row.Background)
+		} else if row.Marked {
+			rowStyle = rowStyle.Background(t.MarkedBackground)
 		} else if x%2 == 0 {
 			rowStyle = rowStyle.Background(t.AltBackground)
 		}